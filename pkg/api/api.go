@@ -217,10 +217,14 @@ func (hs *HTTPServer) registerRoutes() {
 				orgRoute.Get("/list/", routing.Wrap(hs.StorageService.List))
 				orgRoute.Get("/list/*", routing.Wrap(hs.StorageService.List))
 				orgRoute.Get("/read/*", routing.Wrap(hs.StorageService.Read))
+				orgRoute.Get("/versions/*", routing.Wrap(hs.StorageService.ListVersions))
+				orgRoute.Get("/version/*", routing.Wrap(hs.StorageService.ReadVersion))
+				orgRoute.Get("/diff/*", routing.Wrap(hs.StorageService.DiffVersions))
 
 				if hs.Features.IsEnabled(featuremgmt.FlagStorageLocalUpload) {
 					orgRoute.Delete("/delete/*", reqSignedIn, routing.Wrap(hs.StorageService.Delete))
 					orgRoute.Post("/upload", reqSignedIn, routing.Wrap(hs.StorageService.Upload))
+					orgRoute.Post("/restore/*", reqSignedIn, routing.Wrap(hs.StorageService.RestoreVersion))
 				}
 			})
 		}
@@ -530,6 +534,11 @@ func (hs *HTTPServer) registerRoutes() {
 			adminRoute.Post("/export", reqGrafanaAdmin, routing.Wrap(hs.ExportService.HandleRequestExport))
 		}
 
+		if hs.SearchV2Service != nil && hs.Features.IsEnabled(featuremgmt.FlagPanelTitleSearch) {
+			adminRoute.Get("/search/index", reqGrafanaAdmin, routing.Wrap(hs.SearchV2Service.HandleGetIndexStats))
+			adminRoute.Post("/search/reindex", reqGrafanaAdmin, routing.Wrap(hs.SearchV2Service.HandleReIndex))
+		}
+
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningReloadDashboards))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
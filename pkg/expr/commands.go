@@ -155,6 +155,10 @@ func (gr *ReduceCommand) NeedsVars() []string {
 func (gr *ReduceCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
 	newRes := mathexp.Results{}
 	for _, val := range vars[gr.VarToReduce].Values {
+		if _, ok := val.(mathexp.NoData); ok {
+			newRes.Values = append(newRes.Values, val)
+			continue
+		}
 		series, ok := val.(mathexp.Series)
 		if !ok {
 			return newRes, fmt.Errorf("can only reduce type series, got type %v", val.Type())
@@ -180,11 +184,16 @@ type ResampleCommand struct {
 
 // NewResampleCommand creates a new ResampleCMD.
 func NewResampleCommand(refID, rawWindow, varToResample string, downsampler string, upsampler string, tr TimeRange) (*ResampleCommand, error) {
-	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	if _, err := mathexp.GetDownsampleFunc(downsampler); err != nil {
+		return nil, fmt.Errorf("invalid resample downsampler for refId %v: %w", refID, err)
+	}
+	if err := mathexp.ValidateUpsampler(upsampler); err != nil {
+		return nil, fmt.Errorf("invalid resample upsampler for refId %v: %w", refID, err)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
@@ -249,6 +258,10 @@ func (gr *ResampleCommand) NeedsVars() []string {
 func (gr *ResampleCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
 	newRes := mathexp.Results{}
 	for _, val := range vars[gr.VarToResample].Values {
+		if _, ok := val.(mathexp.NoData); ok {
+			newRes.Values = append(newRes.Values, val)
+			continue
+		}
 		series, ok := val.(mathexp.Series)
 		if !ok {
 			return newRes, fmt.Errorf("can only resample type series, got type %v", val.Type())
@@ -276,6 +289,10 @@ const (
 	TypeResample
 	// TypeClassicConditions is the CMDType for the classic condition operation.
 	TypeClassicConditions
+	// TypeThreshold is the CMDType for the threshold operation.
+	TypeThreshold
+	// TypeJoin is the CMDType for the join operation.
+	TypeJoin
 )
 
 func (gt CommandType) String() string {
@@ -288,6 +305,10 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
+	case TypeJoin:
+		return "join"
 	default:
 		return "unknown"
 	}
@@ -304,6 +325,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeResample, nil
 	case "classic_conditions":
 		return TypeClassicConditions, nil
+	case "threshold":
+		return TypeThreshold, nil
+	case "join":
+		return TypeJoin, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
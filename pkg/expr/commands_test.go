@@ -89,3 +89,65 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 		})
 	}
 }
+
+func Test_UnmarshalResampleCommand_Modes(t *testing.T) {
+	var tests = []struct {
+		name        string
+		downsampler string
+		upsampler   string
+		isError     bool
+	}{
+		{
+			name:        "existing modes",
+			downsampler: "mean",
+			upsampler:   "pad",
+		},
+		{
+			name:        "last and linear",
+			downsampler: "last",
+			upsampler:   "linear",
+		},
+		{
+			name:        "percentile",
+			downsampler: "p99.9",
+			upsampler:   "fillna",
+		},
+		{
+			name:        "error when downsampler is not known",
+			downsampler: "test",
+			upsampler:   "fillna",
+			isError:     true,
+		},
+		{
+			name:        "error when upsampler is not known",
+			downsampler: "median",
+			upsampler:   "test",
+			isError:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := fmt.Sprintf(`{ "expression" : "$A", "window": "1m", "downsampler": %q, "upsampler": %q }`, test.downsampler, test.upsampler)
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+			cmd, err := UnmarshalResampleCommand(&rawNode{
+				RefID:      "A",
+				Query:      qmap,
+				QueryType:  "",
+				TimeRange:  TimeRange{},
+				DataSource: nil,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NotNil(t, cmd)
+			require.Equal(t, test.downsampler, cmd.Downsampler)
+			require.Equal(t, test.upsampler, cmd.Upsampler)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// Join modes.
+const (
+	// JoinOnTime joins series on their timestamps.
+	JoinOnTime = "time"
+	// JoinOnLabels joins numbers on their labels.
+	JoinOnLabels = "labels"
+)
+
+// JoinCommand is an expression command that merges the values of several
+// variables into a single wide frame.
+//
+// When joining on time, each series becomes a field of the frame and there is a
+// row for each timestamp of any of the series (an outer join).
+// When joining on labels, there is a row for each distinct set of labels, a string field
+// for each label, and a number field for each variable.
+type JoinCommand struct {
+	VarsToJoin []string
+	JoinOn     string
+	// Labels are the labels that numbers are joined on when joining on labels.
+	// If empty, all labels are used.
+	Labels []string
+	refID  string
+}
+
+// NewJoinCommand creates a new JoinCommand.
+func NewJoinCommand(refID string, varsToJoin []string, joinOn string, labels []string) (*JoinCommand, error) {
+	if len(varsToJoin) == 0 {
+		return nil, fmt.Errorf("no variables specified to join for refId %v", refID)
+	}
+	switch joinOn {
+	case JoinOnTime:
+		if len(labels) > 0 {
+			return nil, fmt.Errorf("labels can only be specified when joining on labels for refId %v", refID)
+		}
+	case JoinOnLabels:
+	default:
+		return nil, fmt.Errorf("join on '%v' is not supported for refId %v, expected '%v' or '%v'", joinOn, refID, JoinOnTime, JoinOnLabels)
+	}
+	return &JoinCommand{
+		VarsToJoin: varsToJoin,
+		JoinOn:     joinOn,
+		Labels:     labels,
+		refID:      refID,
+	}, nil
+}
+
+// joinCommandJSON is the JSON model of the join command in Grafana's frontend query.
+type joinCommandJSON struct {
+	Expressions []string `json:"expressions"`
+	JoinOn      string   `json:"joinOn"`
+	Labels      []string `json:"labels"`
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	jsonFromM, err := json.Marshal(rn.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remarshal join command body for refId %v: %w", rn.RefID, err)
+	}
+	var cmdJSON joinCommandJSON
+	if err := json.Unmarshal(jsonFromM, &cmdJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal join command body for refId %v: %w", rn.RefID, err)
+	}
+	varsToJoin := make([]string, 0, len(cmdJSON.Expressions))
+	for _, v := range cmdJSON.Expressions {
+		varsToJoin = append(varsToJoin, strings.TrimPrefix(v, "$"))
+	}
+	joinOn := cmdJSON.JoinOn
+	if joinOn == "" {
+		joinOn = JoinOnTime
+	}
+	return NewJoinCommand(rn.RefID, varsToJoin, joinOn, cmdJSON.Labels)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (jc *JoinCommand) NeedsVars() []string {
+	return jc.VarsToJoin
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (jc *JoinCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	var frame *data.Frame
+	var err error
+	if jc.JoinOn == JoinOnLabels {
+		frame, err = jc.joinOnLabels(vars)
+	} else {
+		frame, err = jc.joinOnTime(vars)
+	}
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	return mathexp.Results{
+		Values: mathexp.Values{mathexp.NewTableData(frame)},
+	}, nil
+}
+
+func (jc *JoinCommand) joinOnTime(vars mathexp.Vars) (*data.Frame, error) {
+	var series []mathexp.Series
+	var refIDs []string
+	timeIdx := map[int64]time.Time{}
+	for _, refID := range jc.VarsToJoin {
+		for _, val := range vars[refID].Values {
+			s, ok := val.(mathexp.Series)
+			if !ok {
+				return nil, fmt.Errorf("can only join type series on time, got type %v from refId %v", val.Type(), refID)
+			}
+			for i := 0; i < s.Len(); i++ {
+				t := s.GetTime(i)
+				timeIdx[t.UnixNano()] = t
+			}
+			series = append(series, s)
+			refIDs = append(refIDs, refID)
+		}
+	}
+
+	times := make([]time.Time, 0, len(timeIdx))
+	for _, t := range timeIdx {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	rowByTime := make(map[int64]int, len(times))
+	for i, t := range times {
+		rowByTime[t.UnixNano()] = i
+	}
+
+	fields := make([]*data.Field, 0, len(series)+1)
+	fields = append(fields, data.NewField("Time", nil, times))
+	for i, s := range series {
+		name := s.GetName()
+		if name == "" {
+			name = refIDs[i]
+		}
+		var labels data.Labels
+		if s.GetLabels() != nil {
+			labels = s.GetLabels().Copy()
+		}
+		vals := make([]*float64, len(times))
+		for j := 0; j < s.Len(); j++ {
+			t, f := s.GetPoint(j)
+			vals[rowByTime[t.UnixNano()]] = f
+		}
+		fields = append(fields, data.NewField(name, labels, vals))
+	}
+	return data.NewFrame(jc.refID, fields...), nil
+}
+
+func (jc *JoinCommand) joinOnLabels(vars mathexp.Vars) (*data.Frame, error) {
+	labelNames := jc.Labels
+	if len(labelNames) == 0 {
+		names := map[string]struct{}{}
+		for _, refID := range jc.VarsToJoin {
+			for _, val := range vars[refID].Values {
+				for k := range val.GetLabels() {
+					names[k] = struct{}{}
+				}
+			}
+		}
+		for k := range names {
+			labelNames = append(labelNames, k)
+		}
+		sort.Strings(labelNames)
+	}
+
+	keyOf := func(ls data.Labels) data.Labels {
+		key := data.Labels{}
+		for _, name := range labelNames {
+			key[name] = ls[name]
+		}
+		return key
+	}
+
+	rows := map[string]int{}
+	var keys []data.Labels
+	values := make([]map[int]*float64, len(jc.VarsToJoin))
+	for i, refID := range jc.VarsToJoin {
+		values[i] = map[int]*float64{}
+		for _, val := range vars[refID].Values {
+			n, ok := val.(mathexp.Number)
+			if !ok {
+				return nil, fmt.Errorf("can only join type number on labels, got type %v from refId %v", val.Type(), refID)
+			}
+			key := keyOf(n.GetLabels())
+			row, ok := rows[key.String()]
+			if !ok {
+				row = len(keys)
+				rows[key.String()] = row
+				keys = append(keys, key)
+			}
+			if _, dup := values[i][row]; dup {
+				return nil, fmt.Errorf("found duplicate numbers for the labels {%s} in refId %v", key, refID)
+			}
+			values[i][row] = n.GetFloat64Value()
+		}
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return keys[order[i]].String() < keys[order[j]].String() })
+
+	fields := make([]*data.Field, 0, len(labelNames)+len(jc.VarsToJoin))
+	for _, name := range labelNames {
+		vals := make([]string, len(order))
+		for i, row := range order {
+			vals[i] = keys[row][name]
+		}
+		fields = append(fields, data.NewField(name, nil, vals))
+	}
+	for i, refID := range jc.VarsToJoin {
+		vals := make([]*float64, len(order))
+		for j, row := range order {
+			vals[j] = values[i][row]
+		}
+		fields = append(fields, data.NewField(refID, nil, vals))
+	}
+	return data.NewFrame(jc.refID, fields...), nil
+}
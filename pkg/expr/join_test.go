@@ -0,0 +1,82 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func newTestSeries(name string, labels data.Labels, points map[int64]float64) mathexp.Series {
+	s := mathexp.NewSeries(name, labels, 0)
+	for _, ts := range []int64{0, 10, 20, 30} {
+		if v, ok := points[ts]; ok {
+			f := v
+			s.AppendPoint(time.Unix(ts, 0), &f)
+		}
+	}
+	return s
+}
+
+func TestJoinCommand_Execute(t *testing.T) {
+	t.Run("join on time is an outer join", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinOnTime, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{newTestSeries("A", data.Labels{"host": "a"}, map[int64]float64{0: 1, 10: 2})}},
+			"B": mathexp.Results{Values: mathexp.Values{newTestSeries("B", nil, map[int64]float64{10: 20, 20: 30})}},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		expected := data.NewFrame("C",
+			data.NewField("Time", nil, []time.Time{time.Unix(0, 0), time.Unix(10, 0), time.Unix(20, 0)}),
+			data.NewField("A", data.Labels{"host": "a"}, []*float64{fp(1), fp(2), nil}),
+			data.NewField("B", nil, []*float64{nil, fp(20), fp(30)}),
+		)
+		require.Equal(t, expected, res.Values[0].AsDataFrame())
+	})
+
+	t.Run("join on labels", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinOnLabels, []string{"host"})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				newTestNumber(data.Labels{"host": "b", "job": "x"}, fp(2)),
+				newTestNumber(data.Labels{"host": "a", "job": "x"}, fp(1)),
+			}},
+			"B": mathexp.Results{Values: mathexp.Values{
+				newTestNumber(data.Labels{"host": "a"}, fp(10)),
+				newTestNumber(data.Labels{"host": "c"}, fp(30)),
+			}},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		expected := data.NewFrame("C",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("A", nil, []*float64{fp(1), fp(2), nil}),
+			data.NewField("B", nil, []*float64{fp(10), nil, fp(30)}),
+		)
+		require.Equal(t, expected, res.Values[0].AsDataFrame())
+	})
+
+	t.Run("error on duplicate labels", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", []string{"A"}, JoinOnLabels, []string{"host"})
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				newTestNumber(data.Labels{"host": "a", "job": "x"}, fp(2)),
+				newTestNumber(data.Labels{"host": "a", "job": "y"}, fp(1)),
+			}},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("error on unknown join mode", func(t *testing.T) {
+		_, err := NewJoinCommand("C", []string{"A"}, "value", nil)
+		require.Error(t, err)
+	})
+}
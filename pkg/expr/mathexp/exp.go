@@ -117,6 +117,8 @@ func (e *State) walkUnary(node *parse.UnaryNode) (Results, error) {
 			newVal, err = e.unaryNumber(rt, node.OpStr)
 		case Series:
 			newVal, err = e.unarySeries(rt, node.OpStr)
+		case NoData:
+			newVal = rt
 		default:
 			return newResults, fmt.Errorf("can not perform a unary operation on type %v", rt.Type())
 		}
@@ -248,7 +250,23 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := union(ar, br)
+	// an operation on no data has no data, with the notices of both operands
+	if ar.isNoData() || br.isNoData() {
+		var notices []data.Notice
+		for _, v := range append(ar.Values, br.Values...) {
+			notices = append(notices, noticesOf(v)...)
+		}
+		return Results{Values{NewNoData(notices...)}}, nil
+	}
+	var unions []*Union
+	if node.Matching != nil {
+		unions, err = matchUnion(ar, br, node.Matching)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = union(ar, br)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
@@ -317,6 +335,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 		}
 		res.Values = append(res.Values, value)
 	}
+	if notices := unmatchedNotices(node.OpStr, ar, br, unions); len(notices) > 0 {
+		// the notices explain why there is no data if nothing matched
+		if len(res.Values) == 0 {
+			res.Values = append(res.Values, NewNoData())
+		}
+		for _, value := range res.Values {
+			value.AsDataFrame().AppendNotices(notices...)
+		}
+	}
 	return res, nil
 }
 
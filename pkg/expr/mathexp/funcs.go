@@ -68,6 +68,44 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"diff": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      diff,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"moving_sum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeScalar},
+		Return: parse.TypeSeriesSet,
+		Check:  checkWindowArg,
+		F:      movingSum,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeScalar},
+		Return: parse.TypeSeriesSet,
+		Check:  checkWindowArg,
+		F:      movingAvg,
+	},
+	"time_shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		Check:  checkDurationArg,
+		F:      timeShift,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -229,6 +267,8 @@ func perFloat(e *State, val Value, floatF func(x float64) float64) (Value, error
 			newSeries.SetPoint(i, t, &nF)
 		}
 		newVal = newSeries
+	case parse.TypeNoData:
+		newVal = val
 	default:
 		// TODO: Should we deal with TypeString, TypeVariantSet?
 	}
@@ -258,6 +298,8 @@ func perNullableFloat(e *State, val Value, floatF func(x *float64) *float64) (Va
 			newSeries.SetPoint(i, t, floatF(f))
 		}
 		newVal = newSeries
+	case parse.TypeNoData:
+		newVal = val
 	default:
 		// TODO: Should we deal with TypeString, TypeVariantSet?
 	}
@@ -0,0 +1,256 @@
+package mathexp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// The functions in this file operate over the points of a series rather than on
+// each point independently. They only accept a SeriesSet and always return a SeriesSet,
+// so they can be chained (e.g. moving_avg(rate($A), 5)).
+// Points are expected to be sorted by time from oldest to newest.
+
+// checkWindowArg is a parse time check that the window argument of a rolling
+// function is a positive integer constant.
+func checkWindowArg(t *parse.Tree, f *parse.FuncNode) error {
+	n, ok := f.Args[1].(*parse.ScalarNode)
+	if !ok {
+		return fmt.Errorf("%s: window must be a number, got %v", f.Name, f.Args[1])
+	}
+	if !n.IsUint || n.Uint64 < 1 {
+		return fmt.Errorf("%s: window must be a positive integer, got %v", f.Name, n.Text)
+	}
+	return nil
+}
+
+// checkDurationArg is a parse time check that the duration argument is a valid duration string.
+func checkDurationArg(t *parse.Tree, f *parse.FuncNode) error {
+	n, ok := f.Args[1].(*parse.StringNode)
+	if !ok {
+		return fmt.Errorf("%s: offset must be a duration string, got %v", f.Name, f.Args[1])
+	}
+	if _, err := parseSignedDuration(n.Text); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// parseSignedDuration parses a duration such as "1h", "7d" or "-30m".
+func parseSignedDuration(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	d, err := gtime.ParseDuration(strings.TrimPrefix(s, "-"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// perSeries applies seriesF to each Series in varSet. It returns an error if
+// any of the values is not a Series.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		if _, ok := res.(NoData); ok {
+			newRes.Values = append(newRes.Values, res)
+			continue
+		}
+		s, ok := res.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("%s: expected %v, got %v", name, parse.TypeSeriesSet, res.Type())
+		}
+		newRes.Values = append(newRes.Values, seriesF(s))
+	}
+	return newRes, nil
+}
+
+// windowFromResults extracts the window size from the Scalar result of the window argument.
+func windowFromResults(name string, window Results) (int, error) {
+	if len(window.Values) != 1 {
+		return 0, fmt.Errorf("%s: window must be a single scalar", name)
+	}
+	sc, ok := window.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: window must be a scalar, got %v", name, window.Values[0].Type())
+	}
+	f := sc.GetFloat64Value()
+	if f == nil || *f < 1 {
+		return 0, fmt.Errorf("%s: window must be a positive integer", name)
+	}
+	return int(*f), nil
+}
+
+// pairwise builds a new series by calling pointF with each non-null point and the
+// closest previous non-null point. The first point has no predecessor and is dropped.
+// If the current point is null, the resulting point is null.
+func pairwise(e *State, s Series, pointF func(prevT, curT time.Time, prev, cur float64) *float64) Series {
+	newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+	var prevT time.Time
+	var prev *float64
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		if i == 0 {
+			prevT, prev = t, f
+			continue
+		}
+		if f == nil || prev == nil {
+			newSeries.AppendPoint(t, nil)
+			if f != nil {
+				prevT, prev = t, f
+			}
+			continue
+		}
+		newSeries.AppendPoint(t, pointF(prevT, t, *prev, *f))
+		prevT, prev = t, f
+	}
+	return newSeries
+}
+
+// counterIncrease returns the increase between two samples of a monotonic counter.
+// A decrease is treated as a counter reset, in which case the current value is the increase.
+func counterIncrease(prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// diff returns the difference between each point and the previous point of each Series.
+func diff(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "diff", varSet, func(s Series) Series {
+		return pairwise(e, s, func(_, _ time.Time, prev, cur float64) *float64 {
+			d := cur - prev
+			return &d
+		})
+	})
+}
+
+// delta returns the increase between each point and the previous point of each Series,
+// treating the series as a counter: a decrease in value is handled as a counter reset.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return pairwise(e, s, func(_, _ time.Time, prev, cur float64) *float64 {
+			d := counterIncrease(prev, cur)
+			return &d
+		})
+	})
+}
+
+// rate returns the per-second rate of increase between each point and the previous point
+// of each Series, treating the series as a counter (see delta).
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return pairwise(e, s, func(prevT, curT time.Time, prev, cur float64) *float64 {
+			elapsed := curT.Sub(prevT).Seconds()
+			if elapsed <= 0 {
+				return nil
+			}
+			r := counterIncrease(prev, cur) / elapsed
+			return &r
+		})
+	})
+}
+
+// cumsum returns the running total of each Series. Null points are kept as null
+// and do not contribute to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		sum := float64(0)
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			v := sum
+			newSeries.SetPoint(i, t, &v)
+		}
+		return newSeries
+	})
+}
+
+// movingWindow calls windowF with the non-null values of the current point and
+// up to size-1 previous points. If all of these are null, the resulting point is null.
+func movingWindow(e *State, s Series, size int, windowF func(vals []float64) float64) Series {
+	newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+	vals := make([]float64, 0, size)
+	for i := 0; i < s.Len(); i++ {
+		vals = vals[:0]
+		start := i - size + 1
+		if start < 0 {
+			start = 0
+		}
+		for j := start; j <= i; j++ {
+			if f := s.GetValue(j); f != nil {
+				vals = append(vals, *f)
+			}
+		}
+		t := s.GetTime(i)
+		if len(vals) == 0 {
+			newSeries.SetPoint(i, t, nil)
+			continue
+		}
+		v := windowF(vals)
+		newSeries.SetPoint(i, t, &v)
+	}
+	return newSeries
+}
+
+// movingSum returns the sum of the last window points for each point of each Series.
+func movingSum(e *State, varSet Results, window Results) (Results, error) {
+	size, err := windowFromResults("moving_sum", window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_sum", varSet, func(s Series) Series {
+		return movingWindow(e, s, size, func(vals []float64) float64 {
+			sum := float64(0)
+			for _, v := range vals {
+				sum += v
+			}
+			return sum
+		})
+	})
+}
+
+// movingAvg returns the mean of the last window points for each point of each Series.
+func movingAvg(e *State, varSet Results, window Results) (Results, error) {
+	size, err := windowFromResults("moving_avg", window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		return movingWindow(e, s, size, func(vals []float64) float64 {
+			sum := float64(0)
+			for _, v := range vals {
+				sum += v
+			}
+			return sum / float64(len(vals))
+		})
+	})
+}
+
+// timeShift moves every point of each Series by offset, e.g. time_shift($A, "1d")
+// makes yesterday's values line up with today's.
+func timeShift(e *State, varSet Results, offset string) (Results, error) {
+	d, err := parseSignedDuration(offset)
+	if err != nil {
+		return Results{}, fmt.Errorf("time_shift: %w", err)
+	}
+	return perSeries(e, "time_shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
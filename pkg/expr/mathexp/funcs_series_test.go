@@ -0,0 +1,187 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(30)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(70)},
+					tp{time.Unix(40, 0), float64Pointer(5)}),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "diff returns the difference to the previous non-null point",
+			expr:      "diff($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(40)},
+					tp{time.Unix(40, 0), float64Pointer(-65)}),
+			}},
+		},
+		{
+			name:      "delta handles counter resets",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(40)},
+					tp{time.Unix(40, 0), float64Pointer(5)}),
+			}},
+		},
+		{
+			name:      "rate is per second",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(2)},
+					tp{time.Unix(40, 0), float64Pointer(0.5)}),
+			}},
+		},
+		{
+			name:      "cumsum skips null points",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(40)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(110)},
+					tp{time.Unix(40, 0), float64Pointer(115)}),
+			}},
+		},
+		{
+			name:      "moving_sum over two points",
+			expr:      "moving_sum($A, 2)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(40)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), float64Pointer(70)},
+					tp{time.Unix(40, 0), float64Pointer(75)}),
+			}},
+		},
+		{
+			name:      "moving_avg over three points ignores nulls",
+			expr:      "moving_avg($A, 3)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(20)},
+					tp{time.Unix(30, 0), float64Pointer(50)},
+					tp{time.Unix(40, 0), float64Pointer(37.5)}),
+			}},
+		},
+		{
+			name:      "time_shift moves points by the offset",
+			expr:      `time_shift($A, "-10s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(-10, 0), float64Pointer(10)},
+					tp{time.Unix(0, 0), float64Pointer(30)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(20, 0), float64Pointer(70)},
+					tp{time.Unix(30, 0), float64Pointer(5)}),
+			}},
+		},
+		{
+			name:      "functions can be chained",
+			expr:      "cumsum(diff($A))",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(60)},
+					tp{time.Unix(40, 0), float64Pointer(-5)}),
+			}},
+		},
+		{
+			name: "rate on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{[]Value{makeNumber("", nil, float64Pointer(1))}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with non integer window should error",
+			expr:     "moving_avg($A, 1.5)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_sum with zero window should error",
+			expr:     "moving_sum($A, 0)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "time_shift with invalid duration should error",
+			expr:     `time_shift($A, "yesterday")`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package mathexp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// matchUnion creates Union objects for a binary operation with an explicit
+// matching clause such as "on(host)" or "ignoring(instance) group_left".
+// Values are matched when their labels are equal after applying the clause.
+// Scalars have no labels and are combined with every value of the other side.
+func matchUnion(aResults, bResults Results, m *parse.VectorMatching) ([]*Union, error) {
+	if hasScalar(aResults) || hasScalar(bResults) {
+		return union(aResults, bResults), nil
+	}
+
+	// many is the side whose values may share a match group, and one is the
+	// side whose values must be unique per match group.
+	many, one := aResults.Values, bResults.Values
+	if m.Card == parse.CardOneToMany {
+		many, one = one, many
+	}
+
+	oneSide := "right"
+	if m.Card == parse.CardOneToMany {
+		oneSide = "left"
+	}
+	oneBySig := make(map[string]Value, len(one))
+	for _, v := range one {
+		sig := matchSignature(v.GetLabels(), m)
+		if _, ok := oneBySig[sig]; ok {
+			return nil, fmt.Errorf("found duplicate series for the match group {%s} on the %s hand side of the operation; many-to-many matching is not allowed", sig, oneSide)
+		}
+		oneBySig[sig] = v
+	}
+
+	unions := []*Union{}
+	seenMany := make(map[string]struct{}, len(many))
+	for _, v := range many {
+		sig := matchSignature(v.GetLabels(), m)
+		o, ok := oneBySig[sig]
+		if !ok {
+			continue
+		}
+		if m.Card == parse.CardOneToOne {
+			if _, dup := seenMany[sig]; dup {
+				return nil, fmt.Errorf("found duplicate series for the match group {%s} on the left hand side of the operation; many-to-one matching must be explicit (group_left/group_right)", sig)
+			}
+			seenMany[sig] = struct{}{}
+		}
+		u := &Union{
+			Labels: matchResultLabels(v.GetLabels(), o.GetLabels(), m),
+			A:      v,
+			B:      o,
+		}
+		if m.Card == parse.CardOneToMany {
+			u.A, u.B = o, v
+		}
+		unions = append(unions, u)
+	}
+	return unions, nil
+}
+
+func hasScalar(r Results) bool {
+	for _, v := range r.Values {
+		if v.Type() == parse.TypeScalar {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSignature returns a string that is equal for labels that belong to the same match group.
+func matchSignature(ls data.Labels, m *parse.VectorMatching) string {
+	return matchLabels(ls, m).String()
+}
+
+// matchLabels returns the subset of ls that is used for matching.
+func matchLabels(ls data.Labels, m *parse.VectorMatching) data.Labels {
+	matched := data.Labels{}
+	if m.On {
+		for _, name := range m.MatchingLabels {
+			if v, ok := ls[name]; ok {
+				matched[name] = v
+			}
+		}
+		return matched
+	}
+	for k, v := range ls {
+		matched[k] = v
+	}
+	for _, name := range m.MatchingLabels {
+		delete(matched, name)
+	}
+	return matched
+}
+
+// matchResultLabels returns the labels of the result of a matched operation.
+// For one-to-one matching these are the labels used for matching. Otherwise the labels
+// of the "many" side are kept and the labels listed in the group clause are copied from the "one" side.
+func matchResultLabels(many, one data.Labels, m *parse.VectorMatching) data.Labels {
+	if m.Card == parse.CardOneToOne {
+		return matchLabels(many, m)
+	}
+	labels := many.Copy()
+	for _, name := range m.Include {
+		if v, ok := one[name]; ok && v != "" {
+			labels[name] = v
+		} else {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
+
+// unmatchedNotices returns a warning notice for each side of a binary operation that has
+// Series or Numbers which are not part of any of the unions and are therefore dropped.
+func unmatchedNotices(op string, aResults, bResults Results, unions []*Union) []data.Notice {
+	matched := make(map[*data.Frame]struct{}, len(unions)*2)
+	for _, u := range unions {
+		matched[u.A.AsDataFrame()] = struct{}{}
+		matched[u.B.AsDataFrame()] = struct{}{}
+	}
+
+	var notices []data.Notice
+	for _, side := range []struct {
+		name    string
+		results Results
+	}{{"left", aResults}, {"right", bResults}} {
+		var unmatched []string
+		for _, v := range side.results.Values {
+			if v.Type() == parse.TypeScalar {
+				continue
+			}
+			if _, ok := matched[v.AsDataFrame()]; !ok {
+				unmatched = append(unmatched, "{"+v.GetLabels().String()+"}")
+			}
+		}
+		if len(unmatched) == 0 {
+			continue
+		}
+		notices = append(notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text: fmt.Sprintf("%d series on the %s hand side of the %s operation had no match and were dropped: %s",
+				len(unmatched), side.name, op, strings.Join(unmatched, ", ")),
+		})
+	}
+	return notices
+}
@@ -0,0 +1,208 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryMatching(t *testing.T) {
+	aSeries := func(labels data.Labels, v float64) Series {
+		return makeSeries("", labels, tp{time.Unix(5, 0), float64Pointer(v)})
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+		notices   int
+	}{
+		{
+			name: "on matches series with extra labels",
+			expr: "$A + on(host) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a", "job": "x"}, 1),
+					aSeries(data.Labels{"host": "b", "job": "x"}, 2),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"host": "a", "dc": "eu"}, 10),
+					aSeries(data.Labels{"host": "b", "dc": "us"}, 20),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				aSeries(data.Labels{"host": "a"}, 11),
+				aSeries(data.Labels{"host": "b"}, 22),
+			}},
+		},
+		{
+			name: "ignoring drops the ignored labels from the result",
+			expr: "$A * ignoring(job, dc) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeNumber("", data.Labels{"host": "a", "job": "x"}, float64Pointer(2)),
+				}},
+				"B": Results{[]Value{
+					makeNumber("", data.Labels{"host": "a", "dc": "eu"}, float64Pointer(3)),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(6)),
+			}},
+		},
+		{
+			name: "group_left keeps labels of the many side and includes labels of the one side",
+			expr: "$A / on(dc) group_left(region) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a", "dc": "eu1"}, 10),
+					aSeries(data.Labels{"host": "b", "dc": "eu1"}, 20),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"dc": "eu1", "region": "eu"}, 10),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				aSeries(data.Labels{"host": "a", "dc": "eu1", "region": "eu"}, 1),
+				aSeries(data.Labels{"host": "b", "dc": "eu1", "region": "eu"}, 2),
+			}},
+		},
+		{
+			name: "group_right keeps the operand order",
+			expr: "$B - on(dc) group_right $A",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a", "dc": "eu1"}, 1),
+					aSeries(data.Labels{"host": "b", "dc": "eu1"}, 2),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"dc": "eu1"}, 10),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				aSeries(data.Labels{"host": "a", "dc": "eu1"}, 9),
+				aSeries(data.Labels{"host": "b", "dc": "eu1"}, 8),
+			}},
+		},
+		{
+			name: "many-to-one without group_left should error",
+			expr: "$A + on(dc) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a", "dc": "eu1"}, 1),
+					aSeries(data.Labels{"host": "b", "dc": "eu1"}, 2),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"dc": "eu1"}, 10),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "unmatched series are reported as notices",
+			expr: "$A + on(host) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a"}, 1),
+					aSeries(data.Labels{"host": "b"}, 2),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"host": "a"}, 10),
+					aSeries(data.Labels{"host": "c"}, 30),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			notices:   2,
+		},
+		{
+			name: "no data with notices if nothing matched",
+			expr: "$A + on(host) $B",
+			vars: Vars{
+				"A": Results{[]Value{
+					aSeries(data.Labels{"host": "a"}, 1),
+				}},
+				"B": Results{[]Value{
+					aSeries(data.Labels{"host": "c"}, 30),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			notices:   2,
+		},
+		{
+			name:     "label in both on and group clause should error",
+			expr:     "$A + on(dc) group_left(dc) $B",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "missing label list should error",
+			expr:     "$A + on $B",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e == nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars)
+			tt.execErrIs(t, err)
+			if tt.results.Values != nil {
+				require.Equal(t, tt.results, res)
+			}
+			if tt.notices > 0 {
+				require.NotEmpty(t, res.Values)
+				for _, v := range res.Values {
+					require.Len(t, v.AsDataFrame().Meta.Notices, tt.notices)
+				}
+			}
+		})
+	}
+}
+
+func TestBinaryMatchingNoData(t *testing.T) {
+	vars := Vars{
+		"A": Results{[]Value{
+			makeSeries("", data.Labels{"host": "a"}, tp{time.Unix(5, 0), float64Pointer(1)}),
+		}},
+		"B": Results{[]Value{
+			makeSeries("", data.Labels{"host": "c"}, tp{time.Unix(5, 0), float64Pointer(30)}),
+		}},
+	}
+
+	for _, expr := range []string{"$A + on(host) $B", "($A + on(host) $B) * 2", "-($A + on(host) $B)", "abs($A + on(host) $B)"} {
+		t.Run(expr, func(t *testing.T) {
+			e, err := New(expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", vars)
+			require.NoError(t, err)
+			require.Len(t, res.Values, 1)
+			require.IsType(t, NoData{}, res.Values[0])
+			require.Empty(t, res.Values[0].AsDataFrame().Fields)
+			require.Len(t, res.Values[0].AsDataFrame().Meta.Notices, 2)
+		})
+	}
+}
+
+func TestParseMatchingString(t *testing.T) {
+	e, err := New(`$A + ignoring(job, "k8s.pod") group_left(region) $B`)
+	require.NoError(t, err)
+	require.Equal(t, "$A + ignoring(job, k8s.pod) group_left(region) $B", e.Tree.String())
+}
@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -298,6 +299,54 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching is the optional label matching clause of the operation (e.g. "on(host)").
+	// When nil, the default union behavior is used.
+	Matching *VectorMatching
+}
+
+// MatchCardinality describes the cardinality relationship of the two sides of a binary operation.
+type MatchCardinality int
+
+const (
+	// CardOneToOne matches each value on one side with at most one value on the other side.
+	CardOneToOne MatchCardinality = iota
+	// CardManyToOne matches many values on the left side with one value on the right side (group_left).
+	CardManyToOne
+	// CardOneToMany matches one value on the left side with many values on the right side (group_right).
+	CardOneToMany
+)
+
+// VectorMatching describes how the values on each side of a binary operation are matched by their labels.
+type VectorMatching struct {
+	Card MatchCardinality
+	// On is true if only MatchingLabels are used for matching ("on"), and false if
+	// all labels except MatchingLabels are used ("ignoring").
+	On             bool
+	MatchingLabels []string
+	// Include holds labels of the "one" side that are copied to the result
+	// when the cardinality is many-to-one or one-to-many.
+	Include []string
+}
+
+// String returns the string representation of the VectorMatching as written in an expression.
+func (m *VectorMatching) String() string {
+	var sb strings.Builder
+	if m.On {
+		sb.WriteString("on")
+	} else {
+		sb.WriteString("ignoring")
+	}
+	sb.WriteString("(" + strings.Join(m.MatchingLabels, ", ") + ")")
+	switch m.Card {
+	case CardManyToOne:
+		sb.WriteString(" group_left")
+	case CardOneToMany:
+		sb.WriteString(" group_right")
+	}
+	if len(m.Include) > 0 {
+		sb.WriteString("(" + strings.Join(m.Include, ", ") + ")")
+	}
+	return sb.String()
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -306,17 +355,35 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
-	return nil
+	if b.Matching != nil && b.Matching.On {
+		for _, include := range b.Matching.Include {
+			for _, l := range b.Matching.MatchingLabels {
+				if include == l {
+					return fmt.Errorf("parse: label %q must not occur in on and group clause at once", l)
+				}
+			}
+		}
+	}
+	if err := b.Args[0].Check(t); err != nil {
+		return err
+	}
+	return b.Args[1].Check(t)
 }
 
 // Return returns the result type of the BinaryNode so it fulfills the Node interface.
@@ -401,6 +468,10 @@ const (
 	TypeSeriesSet
 	// TypeVariantSet is a collection of the same type Number, Series, or Scalar.
 	TypeVariantSet
+	// TypeTableData is a data frame that is not a Number, Series, or Scalar, such as a joined wide frame.
+	TypeTableData
+	// TypeNoData is an empty result, such as a binary operation where no series matched.
+	TypeNoData
 )
 
 // String returns a string representation of the ReturnType.
@@ -416,6 +487,10 @@ func (f ReturnType) String() string {
 		return "scalar"
 	case TypeVariantSet:
 		return "variant"
+	case TypeTableData:
+		return "tableData"
+	case TypeNoData:
+		return "noData"
 	default:
 		return "unknown"
 	}
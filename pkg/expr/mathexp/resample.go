@@ -2,20 +2,120 @@ package mathexp
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
+// Upsampling modes of Resample, used when there are no points in an interval.
+const (
+	// UpsamplerPad uses the last seen value.
+	UpsamplerPad = "pad"
+	// UpsamplerBackfilling uses the next value.
+	UpsamplerBackfilling = "backfilling"
+	// UpsamplerFillNA uses null.
+	UpsamplerFillNA = "fillna"
+	// UpsamplerLinear interpolates between the last seen and the next value.
+	UpsamplerLinear = "linear"
+)
+
+// GetDownsampleFunc returns the function that reduces the points within an interval
+// to a single value for the given downsampler. Besides sum, mean, min and max, it supports
+// first, last, count, median, and percentiles written as "p" followed by a number
+// between 0 and 100 (e.g. "p95" or "p99.9").
+func GetDownsampleFunc(downsampler string) (ReducerFunc, error) {
+	switch downsampler {
+	case "sum":
+		return Sum, nil
+	case "mean":
+		return Avg, nil
+	case "min":
+		return Min, nil
+	case "max":
+		return Max, nil
+	case "first":
+		return First, nil
+	case "last":
+		return Last, nil
+	case "count":
+		return Count, nil
+	case "median":
+		return func(fv *Float64Field) *float64 { return Percentile(fv, 50) }, nil
+	}
+	if strings.HasPrefix(downsampler, "p") {
+		p, err := strconv.ParseFloat(strings.TrimPrefix(downsampler, "p"), 64)
+		if err == nil && p >= 0 && p <= 100 {
+			return func(fv *Float64Field) *float64 { return Percentile(fv, p) }, nil
+		}
+	}
+	return nil, fmt.Errorf("downsampling %v not implemented", downsampler)
+}
+
+// ValidateUpsampler returns an error if upsampler is not a supported upsampling mode.
+func ValidateUpsampler(upsampler string) error {
+	switch upsampler {
+	case UpsamplerPad, UpsamplerBackfilling, UpsamplerFillNA, UpsamplerLinear:
+		return nil
+	default:
+		return fmt.Errorf("upsampling %v not implemented", upsampler)
+	}
+}
+
+// First returns the first value of the field, or NaN if the field is empty.
+func First(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	return fv.GetValue(0)
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of the values in the field,
+// linearly interpolating between the closest ranks. NaN is returned if the field is empty
+// or contains null or NaN values.
+func Percentile(fv *Float64Field, p float64) *float64 {
+	vals := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			nan := math.NaN()
+			return &nan
+		}
+		vals = append(vals, *v)
+	}
+	if len(vals) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	sort.Float64s(vals)
+	rank := p / 100 * float64(len(vals)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	f := vals[lower] + (vals[upper]-vals[lower])*(rank-float64(lower))
+	return &f
+}
+
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler string, upsampler string, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
+	downsample, err := GetDownsampleFunc(downsampler)
+	if err != nil {
+		return s, err
+	}
+	if err := ValidateUpsampler(upsampler); err != nil {
+		return s, err
+	}
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -32,45 +132,44 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler strin
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
 			vals = append(vals, v)
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
 			switch upsampler {
-			case "pad":
+			case UpsamplerPad:
 				if lastSeen != nil {
 					value = lastSeen
 				} else {
 					value = nil
 				}
-			case "backfilling":
+			case UpsamplerBackfilling:
 				if sIdx == s.Len() { // no vals left
 					value = nil
 				} else {
 					_, value = s.GetPoint(sIdx)
 				}
-			case "fillna":
+			case UpsamplerFillNA:
 				value = nil
-			default:
-				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
+			case UpsamplerLinear:
+				if lastSeen == nil || sIdx == s.Len() { // nothing to interpolate between
+					value = nil
+					break
+				}
+				nextTime, next := s.GetPoint(sIdx)
+				if next == nil {
+					value = nil
+					break
+				}
+				ratio := float64(t.Sub(lastSeenTime)) / float64(nextTime.Sub(lastSeenTime))
+				f := *lastSeen + (*next-*lastSeen)*ratio
+				value = &f
 			}
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case "sum":
-				tmp = Sum(&ff)
-			case "mean":
-				tmp = Avg(&ff)
-			case "min":
-				tmp = Min(&ff)
-			case "max":
-				tmp = Max(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = downsample(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
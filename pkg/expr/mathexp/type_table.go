@@ -0,0 +1,43 @@
+package mathexp
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// TableData holds a data frame that is not a Series, Number, or Scalar,
+// for example the wide frame returned by a join. It can be returned from
+// an expression, but math and reduce operations can not be performed on it.
+type TableData struct {
+	Frame *data.Frame
+}
+
+// NewTableData returns a TableData holding frame.
+func NewTableData(frame *data.Frame) TableData {
+	return TableData{Frame: frame}
+}
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (t TableData) Type() parse.ReturnType { return parse.TypeTableData }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (t TableData) Value() interface{} { return t }
+
+// GetLabels returns nil since a table has no labels of its own, only its fields do.
+func (t TableData) GetLabels() data.Labels { return nil }
+
+func (t TableData) SetLabels(ls data.Labels) {}
+
+func (t TableData) GetMeta() interface{} {
+	if t.Frame.Meta == nil {
+		return nil
+	}
+	return t.Frame.Meta.Custom
+}
+
+func (t TableData) SetMeta(v interface{}) {
+	t.Frame.SetMeta(&data.FrameMeta{Custom: v})
+}
+
+// AsDataFrame returns the underlying *data.Frame.
+func (t TableData) AsDataFrame() *data.Frame { return t.Frame }
@@ -10,6 +10,15 @@ type Results struct {
 	Values Values
 }
 
+// isNoData returns true if the results are a single NoData value.
+func (r Results) isNoData() bool {
+	if len(r.Values) != 1 {
+		return false
+	}
+	_, ok := r.Values[0].(NoData)
+	return ok
+}
+
 // Values is a slice of Value interfaces
 type Values []Value
 
@@ -124,6 +133,50 @@ func (n Number) SetMeta(v interface{}) {
 	n.Frame.SetMeta(&data.FrameMeta{Custom: v})
 }
 
+// NoData is an empty result. Its frame has no fields, and may carry notices about why there is no data.
+type NoData struct{ Frame *data.Frame }
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (n NoData) Type() parse.ReturnType { return parse.TypeNoData }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (n NoData) Value() interface{} { return n }
+
+func (n NoData) GetLabels() data.Labels { return nil }
+
+func (n NoData) SetLabels(ls data.Labels) {}
+
+func (n NoData) GetMeta() interface{} {
+	if n.Frame.Meta == nil {
+		return nil
+	}
+	return n.Frame.Meta.Custom
+}
+
+func (n NoData) SetMeta(v interface{}) {
+	n.Frame.SetMeta(&data.FrameMeta{Custom: v})
+}
+
+// AsDataFrame returns the underlying *data.Frame.
+func (n NoData) AsDataFrame() *data.Frame { return n.Frame }
+
+// NewNoData returns an empty result carrying the given notices.
+func NewNoData(notices ...data.Notice) NoData {
+	frame := data.NewFrame("")
+	if len(notices) > 0 {
+		frame.AppendNotices(notices...)
+	}
+	return NoData{frame}
+}
+
+// noticesOf returns the notices of the value, so that they are kept when the value is replaced.
+func noticesOf(v Value) []data.Notice {
+	if meta := v.AsDataFrame().Meta; meta != nil {
+		return meta.Notices
+	}
+	return nil
+}
+
 // FloatField is a *float64 or a float64 data.Field with methods to always
 // get a *float64.
 type Float64Field data.Field
@@ -323,3 +323,11 @@ func TestSeriesName(t *testing.T) {
 		})
 	}
 }
+
+func TestTableDataMeta(t *testing.T) {
+	table := NewTableData(data.NewFrame("joined"))
+	require.Nil(t, table.GetMeta())
+
+	table.SetMeta("custom")
+	require.Equal(t, "custom", table.GetMeta())
+}
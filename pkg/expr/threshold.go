@@ -0,0 +1,186 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// Threshold evaluator types.
+const (
+	ThresholdIsAbove        = "gt"
+	ThresholdIsBelow        = "lt"
+	ThresholdIsWithinRange  = "within_range"
+	ThresholdIsOutsideRange = "outside_range"
+)
+
+// ThresholdEvaluator is the JSON model of a threshold such as "above 80" or "within 10 and 20".
+type ThresholdEvaluator struct {
+	Type   string    `json:"type"`
+	Params []float64 `json:"params"`
+}
+
+func (te ThresholdEvaluator) validate() error {
+	switch te.Type {
+	case ThresholdIsAbove, ThresholdIsBelow:
+		if len(te.Params) != 1 {
+			return fmt.Errorf("threshold type '%v' requires exactly one parameter, got %v", te.Type, len(te.Params))
+		}
+	case ThresholdIsWithinRange, ThresholdIsOutsideRange:
+		if len(te.Params) != 2 {
+			return fmt.Errorf("threshold type '%v' requires exactly two parameters, got %v", te.Type, len(te.Params))
+		}
+	default:
+		return fmt.Errorf("'%v' is not a recognized threshold type, expected one of: %v", te.Type,
+			strings.Join([]string{ThresholdIsAbove, ThresholdIsBelow, ThresholdIsWithinRange, ThresholdIsOutsideRange}, ", "))
+	}
+	return nil
+}
+
+// eval returns true if v meets the threshold. The bounds of ranges are exclusive
+// and can be given in any order.
+func (te ThresholdEvaluator) eval(v float64) bool {
+	switch te.Type {
+	case ThresholdIsAbove:
+		return v > te.Params[0]
+	case ThresholdIsBelow:
+		return v < te.Params[0]
+	case ThresholdIsWithinRange:
+		lower, upper := te.Params[0], te.Params[1]
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+		return v > lower && v < upper
+	case ThresholdIsOutsideRange:
+		lower, upper := te.Params[0], te.Params[1]
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+		return v < lower || v > upper
+	}
+	return false
+}
+
+// ThresholdCommand is an expression command that checks whether each number of a
+// variable meets a threshold. The result is 1 for numbers that meet the threshold,
+// 0 for numbers that don't, and null for null numbers.
+//
+// If an UnloadEvaluator is set, the command applies hysteresis: numbers whose labels
+// are in LoadedDimensions (i.e. that met the threshold at the previous evaluation)
+// keep meeting it until the UnloadEvaluator is met instead.
+type ThresholdCommand struct {
+	ReferenceVar     string
+	Evaluator        ThresholdEvaluator
+	UnloadEvaluator  *ThresholdEvaluator
+	LoadedDimensions []data.Labels
+	refID            string
+}
+
+// NewThresholdCommand creates a new ThresholdCommand.
+func NewThresholdCommand(refID, referenceVar string, evaluator ThresholdEvaluator, unloadEvaluator *ThresholdEvaluator, loaded []data.Labels) (*ThresholdCommand, error) {
+	if err := evaluator.validate(); err != nil {
+		return nil, fmt.Errorf("invalid threshold evaluator for refId %v: %w", refID, err)
+	}
+	if unloadEvaluator != nil {
+		if err := unloadEvaluator.validate(); err != nil {
+			return nil, fmt.Errorf("invalid threshold unload evaluator for refId %v: %w", refID, err)
+		}
+	}
+	return &ThresholdCommand{
+		ReferenceVar:     referenceVar,
+		Evaluator:        evaluator,
+		UnloadEvaluator:  unloadEvaluator,
+		LoadedDimensions: loaded,
+		refID:            refID,
+	}, nil
+}
+
+// thresholdCommandJSON is the JSON model of the threshold command in Grafana's frontend query.
+type thresholdCommandJSON struct {
+	Expression       string              `json:"expression"`
+	Evaluator        *ThresholdEvaluator `json:"evaluator"`
+	UnloadEvaluator  *ThresholdEvaluator `json:"unloadEvaluator"`
+	LoadedDimensions []data.Labels       `json:"loadedDimensions"`
+}
+
+// UnmarshalThresholdCommand creates a ThresholdCommand from Grafana's frontend query.
+func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+	jsonFromM, err := json.Marshal(rn.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remarshal threshold command body for refId %v: %w", rn.RefID, err)
+	}
+	var cmdJSON thresholdCommandJSON
+	if err := json.Unmarshal(jsonFromM, &cmdJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal threshold command body for refId %v: %w", rn.RefID, err)
+	}
+	if cmdJSON.Expression == "" {
+		return nil, fmt.Errorf("no variable specified to threshold for refId %v", rn.RefID)
+	}
+	if cmdJSON.Evaluator == nil {
+		return nil, fmt.Errorf("no threshold evaluator specified for refId %v", rn.RefID)
+	}
+	referenceVar := strings.TrimPrefix(cmdJSON.Expression, "$")
+	return NewThresholdCommand(rn.RefID, referenceVar, *cmdJSON.Evaluator, cmdJSON.UnloadEvaluator, cmdJSON.LoadedDimensions)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (tc *ThresholdCommand) NeedsVars() []string {
+	return []string{tc.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (tc *ThresholdCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[tc.ReferenceVar].Values {
+		var f *float64
+		switch v := val.(type) {
+		case mathexp.Number:
+			f = v.GetFloat64Value()
+		case mathexp.Scalar:
+			f = v.GetFloat64Value()
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v)
+			continue
+		default:
+			return newRes, fmt.Errorf("can only apply a threshold to type number, got type %v; use a reduce expression first", val.Type())
+		}
+
+		var labels data.Labels
+		if val.GetLabels() != nil {
+			labels = val.GetLabels().Copy()
+		}
+		num := mathexp.NewNumber(tc.refID, labels)
+		if f != nil {
+			result := float64(0)
+			if tc.meetsThreshold(labels, *f) {
+				result = 1
+			}
+			num.SetValue(&result)
+		}
+		newRes.Values = append(newRes.Values, num)
+	}
+	return newRes, nil
+}
+
+func (tc *ThresholdCommand) meetsThreshold(labels data.Labels, v float64) bool {
+	if tc.UnloadEvaluator != nil && tc.isLoaded(labels) {
+		return !tc.UnloadEvaluator.eval(v)
+	}
+	return tc.Evaluator.eval(v)
+}
+
+func (tc *ThresholdCommand) isLoaded(labels data.Labels) bool {
+	for _, loaded := range tc.LoadedDimensions {
+		if loaded.Equals(labels) {
+			return true
+		}
+	}
+	return false
+}
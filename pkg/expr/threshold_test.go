@@ -0,0 +1,132 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func newTestNumber(labels data.Labels, f *float64) mathexp.Number {
+	n := mathexp.NewNumber("", labels)
+	n.SetValue(f)
+	return n
+}
+
+func TestUnmarshalThresholdCommand(t *testing.T) {
+	var tests = []struct {
+		name    string
+		query   string
+		isError bool
+	}{
+		{
+			name:  "above",
+			query: `{ "expression": "$B", "evaluator": { "type": "gt", "params": [80] } }`,
+		},
+		{
+			name:  "within range with hysteresis",
+			query: `{ "expression": "$B", "evaluator": { "type": "within_range", "params": [10, 20] }, "unloadEvaluator": { "type": "outside_range", "params": [5, 25] } }`,
+		},
+		{
+			name:    "error when evaluator is missing",
+			query:   `{ "expression": "$B" }`,
+			isError: true,
+		},
+		{
+			name:    "error when evaluator type is not known",
+			query:   `{ "expression": "$B", "evaluator": { "type": "eq", "params": [80] } }`,
+			isError: true,
+		},
+		{
+			name:    "error when range has one parameter",
+			query:   `{ "expression": "$B", "evaluator": { "type": "outside_range", "params": [80] } }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+			cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "C", Query: qmap})
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"B"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestThresholdCommand_Execute(t *testing.T) {
+	vars := mathexp.Vars{
+		"B": mathexp.Results{Values: mathexp.Values{
+			newTestNumber(data.Labels{"host": "a"}, fp(90)),
+			newTestNumber(data.Labels{"host": "b"}, fp(75)),
+			newTestNumber(data.Labels{"host": "c"}, fp(50)),
+			newTestNumber(data.Labels{"host": "d"}, nil),
+		}},
+	}
+
+	t.Run("above", func(t *testing.T) {
+		cmd, err := NewThresholdCommand("C", "B", ThresholdEvaluator{Type: ThresholdIsAbove, Params: []float64{80}}, nil, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{
+			newTestNumber(data.Labels{"host": "a"}, fp(1)),
+			newTestNumber(data.Labels{"host": "b"}, fp(0)),
+			newTestNumber(data.Labels{"host": "c"}, fp(0)),
+			newTestNumber(data.Labels{"host": "d"}, nil),
+		}, setRefID(res.Values, ""))
+	})
+
+	t.Run("hysteresis keeps loaded dimensions until the unload threshold is met", func(t *testing.T) {
+		cmd, err := NewThresholdCommand("C", "B",
+			ThresholdEvaluator{Type: ThresholdIsAbove, Params: []float64{80}},
+			&ThresholdEvaluator{Type: ThresholdIsBelow, Params: []float64{70}},
+			[]data.Labels{{"host": "b"}, {"host": "c"}})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{
+			newTestNumber(data.Labels{"host": "a"}, fp(1)),
+			newTestNumber(data.Labels{"host": "b"}, fp(1)),
+			newTestNumber(data.Labels{"host": "c"}, fp(0)),
+			newTestNumber(data.Labels{"host": "d"}, nil),
+		}, setRefID(res.Values, ""))
+	})
+
+	t.Run("keeps no data", func(t *testing.T) {
+		cmd, err := NewThresholdCommand("C", "B", ThresholdEvaluator{Type: ThresholdIsAbove, Params: []float64{80}}, nil, nil)
+		require.NoError(t, err)
+		noData := mathexp.NewNoData(data.Notice{Text: "no match"})
+		res, err := cmd.Execute(context.Background(), mathexp.Vars{
+			"B": mathexp.Results{Values: mathexp.Values{noData}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{noData}, res.Values)
+	})
+
+	t.Run("error on series", func(t *testing.T) {
+		cmd, err := NewThresholdCommand("C", "B", ThresholdEvaluator{Type: ThresholdIsAbove, Params: []float64{80}}, nil, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), mathexp.Vars{
+			"B": mathexp.Results{Values: mathexp.Values{mathexp.NewSeries("B", nil, 0)}},
+		})
+		require.Error(t, err)
+	})
+}
+
+// setRefID renames the value field of each number so results can be compared with expectations.
+func setRefID(vals mathexp.Values, refID string) mathexp.Values {
+	for _, v := range vals {
+		v.AsDataFrame().Fields[0].Name = refID
+	}
+	return vals
+}
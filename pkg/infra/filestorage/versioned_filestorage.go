@@ -0,0 +1,237 @@
+package filestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var (
+	ErrVersionNotFound = errors.New("version not found")
+)
+
+const (
+	// versions of /a/b.json are saved in the history storage as /a/b.json.versions/<id>
+	versionsFolderSuffix = ".versions"
+
+	// Blob storage lower cases metadata keys
+	versionAuthorPropertyKey  = "gf_version_author"
+	versionMessagePropertyKey = "gf_version_message"
+	versionCreatedPropertyKey = "gf_version_created"
+)
+
+// VersionInfo describes who made a change and why
+type VersionInfo struct {
+	Author  string
+	Message string
+}
+
+type FileVersion struct {
+	ID       string
+	Path     string
+	Author   string
+	Message  string
+	Created  time.Time
+	Size     int64
+	MimeType string
+}
+
+// VersionedFileStorage keeps every saved version of a file, including the current one.
+// Versions are kept after the file is deleted so it can be restored
+type VersionedFileStorage interface {
+	FileStorage
+
+	// UpsertVersion saves the file and records a new version if the contents changed
+	UpsertVersion(ctx context.Context, command *UpsertFileCommand, info VersionInfo) error
+
+	// ListVersions returns the versions of a file, newest first
+	ListVersions(ctx context.Context, path string) ([]*FileVersion, error)
+
+	// GetVersion returns the contents of a single version, or nil if it does not exist
+	GetVersion(ctx context.Context, path string, id string) (*File, error)
+
+	// RestoreVersion saves the contents of an old version as a new version
+	RestoreVersion(ctx context.Context, path string, id string, info VersionInfo) error
+}
+
+type versionedFileStorage struct {
+	FileStorage
+
+	log     log.Logger
+	history FileStorage
+
+	// version IDs must increase even when the clock does not
+	mu     sync.Mutex
+	lastID int64
+}
+
+// NewVersionedStorage saves the history of files in `current` to `history`.  Any backend works
+// for either, but they should not share paths
+func NewVersionedStorage(log log.Logger, current FileStorage, history FileStorage) VersionedFileStorage {
+	return &versionedFileStorage{
+		FileStorage: current,
+		log:         log,
+		history:     history,
+	}
+}
+
+var (
+	_ VersionedFileStorage = (*versionedFileStorage)(nil) // versionedFileStorage implements VersionedFileStorage
+)
+
+func versionsFolder(path string) string {
+	return path + versionsFolderSuffix
+}
+
+// Sortable, so listing the folder returns the versions in order
+func (s *versionedFileStorage) nextVersionID(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := now.UnixNano()
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	return fmt.Sprintf("%020d", id)
+}
+
+func (s *versionedFileStorage) Upsert(ctx context.Context, command *UpsertFileCommand) error {
+	return s.UpsertVersion(ctx, command, VersionInfo{})
+}
+
+func (s *versionedFileStorage) UpsertVersion(ctx context.Context, command *UpsertFileCommand, info VersionInfo) error {
+	if command.Contents == nil {
+		// only the properties change
+		return s.FileStorage.Upsert(ctx, command)
+	}
+	if err := validatePath(command.Path); err != nil {
+		return err
+	}
+
+	existing, err := s.FileStorage.Get(ctx, command.Path)
+	if err != nil {
+		return err
+	}
+	if err := s.FileStorage.Upsert(ctx, command); err != nil {
+		return err
+	}
+	if existing != nil && bytes.Equal(existing.Contents, command.Contents) {
+		return nil
+	}
+
+	now := time.Now()
+	return s.history.Upsert(ctx, &UpsertFileCommand{
+		Path:     Join(versionsFolder(command.Path), s.nextVersionID(now)),
+		MimeType: detectContentType(command.Path, command.MimeType),
+		Contents: command.Contents,
+		Properties: map[string]string{
+			versionAuthorPropertyKey:  info.Author,
+			versionMessagePropertyKey: info.Message,
+			versionCreatedPropertyKey: now.UTC().Format(time.RFC3339Nano),
+		},
+	})
+}
+
+func toFileVersion(path string, f *File) *FileVersion {
+	v := &FileVersion{
+		ID:       f.Name,
+		Path:     path,
+		Author:   f.Properties[versionAuthorPropertyKey],
+		Message:  f.Properties[versionMessagePropertyKey],
+		Created:  f.Created,
+		Size:     f.Size,
+		MimeType: f.MimeType,
+	}
+	if created, err := time.Parse(time.RFC3339Nano, f.Properties[versionCreatedPropertyKey]); err == nil {
+		v.Created = created
+	} else if nanos, err := strconv.ParseInt(f.Name, 10, 64); err == nil {
+		v.Created = time.Unix(0, nanos)
+	}
+	return v
+}
+
+func (s *versionedFileStorage) ListVersions(ctx context.Context, path string) ([]*FileVersion, error) {
+	if err := validatePath(path); err != nil {
+		return nil, err
+	}
+
+	versions := make([]*FileVersion, 0)
+	paging := &Paging{First: 100}
+	for {
+		resp, err := s.history.List(ctx, versionsFolder(path), paging, &ListOptions{WithFiles: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Files {
+			// listing a missing folder may return a file with the same path
+			if getParentFolderPath(f.FullPath) != versionsFolder(path) {
+				continue
+			}
+			versions = append(versions, toFileVersion(path, f))
+		}
+		if !resp.HasMore || resp.LastPath == "" {
+			break
+		}
+		paging = &Paging{First: 100, After: resp.LastPath}
+	}
+
+	// newest first
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+func (s *versionedFileStorage) GetVersion(ctx context.Context, path string, id string) (*File, error) {
+	if err := validatePath(path); err != nil {
+		return nil, err
+	}
+	if id == "" || strings.Contains(id, Delimiter) {
+		return nil, ErrPathInvalid
+	}
+
+	f, err := s.history.Get(ctx, Join(versionsFolder(path), id))
+	if err != nil || f == nil {
+		return nil, err
+	}
+	v := toFileVersion(path, f)
+	f.Name = getName(path)
+	f.FullPath = path
+	f.Created = v.Created
+	f.Modified = v.Created
+	return f, nil
+}
+
+func (s *versionedFileStorage) RestoreVersion(ctx context.Context, path string, id string, info VersionInfo) error {
+	f, err := s.GetVersion(ctx, path, id)
+	if err != nil {
+		return err
+	}
+	if f == nil {
+		return ErrVersionNotFound
+	}
+	if info.Message == "" {
+		info.Message = fmt.Sprintf("Restored version %s", id)
+	}
+	return s.UpsertVersion(ctx, &UpsertFileCommand{
+		Path:     path,
+		MimeType: f.MimeType,
+		Contents: f.Contents,
+	}, info)
+}
+
+func (s *versionedFileStorage) close() error {
+	err := s.FileStorage.close()
+	if historyErr := s.history.close(); err == nil {
+		err = historyErr
+	}
+	return err
+}
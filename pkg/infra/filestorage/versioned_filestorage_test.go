@@ -0,0 +1,77 @@
+package filestorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func newTestVersionedStorage(t *testing.T) VersionedFileStorage {
+	t.Helper()
+	logger := log.New("testVersionedStorage")
+	current, err := blob.OpenBucket(context.Background(), "mem://")
+	require.NoError(t, err)
+	history, err := blob.OpenBucket(context.Background(), "mem://")
+	require.NoError(t, err)
+	return NewVersionedStorage(logger,
+		NewCdkBlobStorage(logger, current, "", nil),
+		NewCdkBlobStorage(logger, history, "", nil))
+}
+
+func TestVersionedStorage(t *testing.T) {
+	ctx := context.Background()
+	s := newTestVersionedStorage(t)
+
+	require.NoError(t, s.UpsertVersion(ctx, &UpsertFileCommand{Path: "/dash/a.json", Contents: []byte(`{"v":1}`)}, VersionInfo{Author: "alice", Message: "first"}))
+	require.NoError(t, s.UpsertVersion(ctx, &UpsertFileCommand{Path: "/dash/a.json", Contents: []byte(`{"v":2}`)}, VersionInfo{Author: "bob"}))
+	// same contents do not add a version
+	require.NoError(t, s.Upsert(ctx, &UpsertFileCommand{Path: "/dash/a.json", Contents: []byte(`{"v":2}`)}))
+	// other files keep their own history
+	require.NoError(t, s.Upsert(ctx, &UpsertFileCommand{Path: "/dash/b.json", Contents: []byte(`{}`)}))
+
+	versions, err := s.ListVersions(ctx, "/dash/a.json")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, "bob", versions[0].Author)
+	require.Equal(t, "alice", versions[1].Author)
+	require.Equal(t, "first", versions[1].Message)
+	require.Equal(t, "/dash/a.json", versions[1].Path)
+	require.True(t, versions[0].Created.After(versions[1].Created))
+
+	old, err := s.GetVersion(ctx, "/dash/a.json", versions[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, `{"v":1}`, string(old.Contents))
+	require.Equal(t, "/dash/a.json", old.FullPath)
+	require.Equal(t, "a.json", old.Name)
+
+	missing, err := s.GetVersion(ctx, "/dash/a.json", "123")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	require.NoError(t, s.RestoreVersion(ctx, "/dash/a.json", versions[1].ID, VersionInfo{Author: "carol"}))
+	current, err := s.Get(ctx, "/dash/a.json")
+	require.NoError(t, err)
+	require.Equal(t, `{"v":1}`, string(current.Contents))
+
+	versions, err = s.ListVersions(ctx, "/dash/a.json")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	require.Equal(t, "carol", versions[0].Author)
+	require.Contains(t, versions[0].Message, "Restored version")
+
+	require.ErrorIs(t, s.RestoreVersion(ctx, "/dash/a.json", "123", VersionInfo{}), ErrVersionNotFound)
+
+	// history survives deleting the file
+	require.NoError(t, s.Delete(ctx, "/dash/a.json"))
+	versions, err = s.ListVersions(ctx, "/dash/a.json")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+
+	versions, err = s.ListVersions(ctx, "/dash/missing.json")
+	require.NoError(t, err)
+	require.Len(t, versions, 0)
+}
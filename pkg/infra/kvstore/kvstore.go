@@ -2,6 +2,7 @@ package kvstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -16,6 +17,7 @@ func ProvideService(sqlStore sqlstore.Store) KVStore {
 	return &kvStoreSQL{
 		sqlStore: sqlStore,
 		log:      log.New("infra.kvstore.sql"),
+		watchers: newWatchers(),
 	}
 }
 
@@ -25,6 +27,26 @@ type KVStore interface {
 	Set(ctx context.Context, orgId int64, namespace string, key string, value string) error
 	Del(ctx context.Context, orgId int64, namespace string, key string) error
 	Keys(ctx context.Context, orgId int64, namespace string, keyPrefix string) ([]Key, error)
+
+	// GetVersioned returns the value with its current version, to be used with CompareAndSwap.
+	GetVersioned(ctx context.Context, orgId int64, namespace string, key string) (VersionedValue, bool, error)
+	// CompareAndSwap sets the value only when the current version of the key is expectedVersion.
+	// Use version 0 to create a key that does not exist. It returns the version of the key
+	// after the call and whether the value was set.
+	CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, expectedVersion int64, value string) (int64, bool, error)
+	// SetWithTTL sets a value that is removed after ttl. Set removes the expiry again.
+	SetWithTTL(ctx context.Context, orgId int64, namespace string, key string, value string, ttl time.Duration) error
+	// GetMany returns the values of the keys that exist.
+	GetMany(ctx context.Context, orgId int64, namespace string, keys []string) (map[string]string, error)
+	// SetMany sets all the values in a single transaction.
+	SetMany(ctx context.Context, orgId int64, namespace string, values map[string]string) error
+	// Watch sends the changes to keys matching keyPrefix until ctx is done. The channel is also
+	// closed when the watcher falls behind, callers should then read the keys and watch again.
+	// Changes made by other Grafana instances are read from the database every few seconds,
+	// changes that are overwritten again between two reads are only sent with their last value.
+	Watch(ctx context.Context, orgId int64, namespace string, keyPrefix string) (<-chan Event, error)
+	// DeleteExpired removes the expired keys and returns how many were removed.
+	DeleteExpired(ctx context.Context) (int64, error)
 }
 
 // WithNamespace returns a kvstore wrapper with fixed orgId and namespace.
@@ -58,3 +80,27 @@ func (kv *NamespacedKVStore) Del(ctx context.Context, key string) error {
 func (kv *NamespacedKVStore) Keys(ctx context.Context, keyPrefix string) ([]Key, error) {
 	return kv.kvStore.Keys(ctx, kv.orgId, kv.namespace, keyPrefix)
 }
+
+func (kv *NamespacedKVStore) GetVersioned(ctx context.Context, key string) (VersionedValue, bool, error) {
+	return kv.kvStore.GetVersioned(ctx, kv.orgId, kv.namespace, key)
+}
+
+func (kv *NamespacedKVStore) CompareAndSwap(ctx context.Context, key string, expectedVersion int64, value string) (int64, bool, error) {
+	return kv.kvStore.CompareAndSwap(ctx, kv.orgId, kv.namespace, key, expectedVersion, value)
+}
+
+func (kv *NamespacedKVStore) SetWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return kv.kvStore.SetWithTTL(ctx, kv.orgId, kv.namespace, key, value, ttl)
+}
+
+func (kv *NamespacedKVStore) GetMany(ctx context.Context, keys []string) (map[string]string, error) {
+	return kv.kvStore.GetMany(ctx, kv.orgId, kv.namespace, keys)
+}
+
+func (kv *NamespacedKVStore) SetMany(ctx context.Context, values map[string]string) error {
+	return kv.kvStore.SetMany(ctx, kv.orgId, kv.namespace, values)
+}
+
+func (kv *NamespacedKVStore) Watch(ctx context.Context, keyPrefix string) (<-chan Event, error) {
+	return kv.kvStore.Watch(ctx, kv.orgId, kv.namespace, keyPrefix)
+}
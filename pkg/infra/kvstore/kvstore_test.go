@@ -6,7 +6,9 @@ package kvstore
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +25,7 @@ func createTestableKVStore(t *testing.T) KVStore {
 	kv := &kvStoreSQL{
 		sqlStore: sqlStore,
 		log:      log.New("infra.kvstore.sql"),
+		watchers: newWatchers(),
 	}
 
 	return kv
@@ -242,3 +245,243 @@ func TestKVStore(t *testing.T) {
 		require.Len(t, keys, 0, "querying a not existing namespace and key should return an empty slice")
 	})
 }
+
+func TestKVStoreCompareAndSwap(t *testing.T) {
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	version, ok, err := kv.CompareAndSwap(ctx, 1, "cas", "key", 0, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(1), version)
+
+	version, ok, err = kv.CompareAndSwap(ctx, 1, "cas", "key", 0, "b")
+	require.NoError(t, err)
+	require.False(t, ok, "creating an existing key should fail")
+	require.Equal(t, int64(1), version)
+
+	version, ok, err = kv.CompareAndSwap(ctx, 1, "cas", "key", 1, "b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(2), version)
+
+	require.NoError(t, kv.Set(ctx, 1, "cas", "key", "c"))
+	value, found, err := kv.GetVersioned(ctx, 1, "cas", "key")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, VersionedValue{Value: "c", Version: 3}, value)
+
+	// setting the same value is not a change
+	require.NoError(t, kv.Set(ctx, 1, "cas", "key", "c"))
+	_, ok, err = kv.CompareAndSwap(ctx, 1, "cas", "key", 2, "d")
+	require.NoError(t, err)
+	require.False(t, ok, "stale version should fail")
+	_, ok, err = kv.CompareAndSwap(ctx, 1, "cas", "key", 3, "d")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	client := WithNamespace(kv, 1, "cas")
+	value, found, err = client.GetVersioned(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "d", value.Value)
+	_, ok, err = client.CompareAndSwap(ctx, "key", value.Version, "e")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestKVStoreCompareAndSwapConcurrentCreate(t *testing.T) {
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	const writers = 5
+	var wg sync.WaitGroup
+	results := make(chan bool, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, ok, err := kv.CompareAndSwap(ctx, 1, "cas", "new", 0, fmt.Sprintf("writer-%d", i))
+			assert.NoError(t, err)
+			results <- ok
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	swapped := 0
+	for ok := range results {
+		if ok {
+			swapped++
+		}
+	}
+	require.Equal(t, 1, swapped, "only one writer should create the key")
+}
+
+func TestKVStoreTTL(t *testing.T) {
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	require.Error(t, kv.SetWithTTL(ctx, 1, "ttl", "key", "value", 0))
+
+	require.NoError(t, kv.SetWithTTL(ctx, 1, "ttl", "expired", "value", time.Millisecond))
+	require.NoError(t, kv.SetWithTTL(ctx, 1, "ttl", "live", "value", time.Hour))
+	require.NoError(t, kv.SetWithTTL(ctx, 1, "ttl", "persisted", "value", time.Millisecond))
+	require.NoError(t, kv.Set(ctx, 1, "ttl", "persisted", "value"))
+	time.Sleep(10 * time.Millisecond)
+
+	_, found, err := kv.Get(ctx, 1, "ttl", "expired")
+	require.NoError(t, err)
+	require.False(t, found, "expired keys are not returned")
+
+	value, found, err := kv.GetVersioned(ctx, 1, "ttl", "live")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotNil(t, value.Expires)
+
+	_, found, err = kv.Get(ctx, 1, "ttl", "persisted")
+	require.NoError(t, err)
+	require.True(t, found, "set removes the expiry")
+
+	keys, err := kv.Keys(ctx, 1, "ttl", "")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	values, err := kv.GetMany(ctx, 1, "ttl", []string{"expired", "live"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"live": "value"}, values)
+
+	// an expired key can be created again
+	_, ok, err := kv.CompareAndSwap(ctx, 1, "ttl", "expired", 0, "again")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, kv.SetWithTTL(ctx, 1, "ttl", "expired", "value", time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := kv.Watch(watchCtx, 1, "ttl", "")
+	require.NoError(t, err)
+
+	deleted, err := kv.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	require.Equal(t, Event{Type: EventTypeExpire, OrgId: 1, Namespace: "ttl", Key: "expired"}, <-events)
+
+	deleted, err = kv.DeleteExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+}
+
+func TestKVStoreBatch(t *testing.T) {
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	values, err := kv.GetMany(ctx, 1, "batch", []string{})
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	many := map[string]string{}
+	keys := []string{"missing"}
+	for i := 0; i < getManyBatchSize+10; i++ {
+		key := fmt.Sprintf("key%d", i)
+		many[key] = fmt.Sprintf("value%d", i)
+		keys = append(keys, key)
+	}
+	require.NoError(t, kv.SetMany(ctx, 1, "batch", many))
+	require.NoError(t, kv.Set(ctx, 2, "batch", "key1", "other org"))
+
+	values, err = kv.GetMany(ctx, 1, "batch", keys)
+	require.NoError(t, err)
+	require.Equal(t, many, values)
+
+	client := WithNamespace(kv, 2, "batch")
+	require.NoError(t, client.SetMany(ctx, map[string]string{"key2": "b"}))
+	values, err = client.GetMany(ctx, []string{"key1", "key2"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key1": "other org", "key2": "b"}, values)
+}
+
+func TestKVStoreWatch(t *testing.T) {
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	events, err := WithNamespace(kv, 1, "watch").Watch(watchCtx, "prefix/")
+	require.NoError(t, err)
+	all, err := kv.Watch(ctx, AllOrganizations, "watch", "")
+	require.NoError(t, err)
+
+	require.NoError(t, kv.Set(ctx, 1, "watch", "prefix/a", "1"))
+	require.NoError(t, kv.Set(ctx, 1, "watch", "other", "1"))
+	require.NoError(t, kv.Set(ctx, 2, "watch", "prefix/a", "1"))
+	require.NoError(t, kv.Set(ctx, 1, "other", "prefix/a", "1"))
+	require.NoError(t, kv.Set(ctx, 1, "watch", "prefix/a", "1"))
+	require.NoError(t, kv.SetMany(ctx, 1, "watch", map[string]string{"prefix/b": "2", "prefix/a": "3"}))
+	require.NoError(t, kv.Del(ctx, 1, "watch", "prefix/b"))
+	require.NoError(t, kv.Del(ctx, 1, "watch", "prefix/missing"))
+
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "prefix/a", Value: "1", Version: 1}, <-events)
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "prefix/a", Value: "3", Version: 2}, <-events)
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "prefix/b", Value: "2", Version: 1}, <-events)
+	require.Equal(t, Event{Type: EventTypeDelete, OrgId: 1, Namespace: "watch", Key: "prefix/b"}, <-events)
+	require.Len(t, events, 0)
+	require.Len(t, all, 6)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		_, open := <-events
+		return !open
+	}, time.Second, 10*time.Millisecond)
+
+	// watchers that fall behind are closed
+	for i := 0; i <= watchBufferSize; i++ {
+		require.NoError(t, kv.Set(ctx, 1, "watch", "prefix/a", fmt.Sprintf("%d", i)))
+	}
+	for range all {
+	}
+}
+
+func TestKVStoreWatchOtherInstances(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	kv := &kvStoreSQL{sqlStore: sqlStore, log: log.New("infra.kvstore.sql"), watchers: newWatchers()}
+	kv.watchers.pollInterval = 10 * time.Millisecond
+	// another Grafana instance using the same database
+	other := &kvStoreSQL{sqlStore: sqlStore, log: log.New("infra.kvstore.sql"), watchers: newWatchers()}
+	ctx := context.Background()
+
+	require.NoError(t, kv.Set(ctx, 1, "watch", "existing", "1"))
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := kv.Watch(watchCtx, 1, "watch", "")
+	require.NoError(t, err)
+
+	next := func() Event {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event received")
+			return Event{}
+		}
+	}
+
+	require.NoError(t, other.Set(ctx, 1, "watch", "a", "1"))
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "a", Value: "1", Version: 1}, next())
+	require.NoError(t, other.Del(ctx, 1, "watch", "existing"))
+	require.Equal(t, Event{Type: EventTypeDelete, OrgId: 1, Namespace: "watch", Key: "existing"}, next())
+	// the expiry is stored with a precision of seconds
+	require.NoError(t, other.SetWithTTL(ctx, 1, "watch", "a", "2", 2*time.Second))
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "a", Value: "2", Version: 2}, next())
+	require.Equal(t, Event{Type: EventTypeExpire, OrgId: 1, Namespace: "watch", Key: "a"}, next())
+	_, err = other.DeleteExpired(ctx)
+	require.NoError(t, err)
+
+	// changes made by this instance are sent once, even though they are read from the database again
+	require.NoError(t, kv.Set(ctx, 1, "watch", "b", "1"))
+	require.Equal(t, Event{Type: EventTypeSet, OrgId: 1, Namespace: "watch", Key: "b", Value: "1", Version: 1}, next())
+	require.NoError(t, kv.Del(ctx, 1, "watch", "b"))
+	require.Equal(t, Event{Type: EventTypeDelete, OrgId: 1, Namespace: "watch", Key: "b"}, next())
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, events, 0)
+}
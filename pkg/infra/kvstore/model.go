@@ -12,6 +12,11 @@ type Item struct {
 	Key       *string
 	Value     string
 
+	// Version is incremented every time the value changes
+	Version int64
+	// Expires is nil for keys that never expire
+	Expires *time.Time
+
 	Created time.Time
 	Updated time.Time
 }
@@ -20,6 +25,10 @@ func (i *Item) TableName() string {
 	return "kv_store"
 }
 
+func (i *Item) expired(now time.Time) bool {
+	return i.Expires != nil && !i.Expires.After(now)
+}
+
 type Key struct {
 	OrgId     int64
 	Namespace string
@@ -29,3 +38,29 @@ type Key struct {
 func (i *Key) TableName() string {
 	return "kv_store"
 }
+
+// VersionedValue is a value together with the version expected by CompareAndSwap.
+type VersionedValue struct {
+	Value   string
+	Version int64
+	Expires *time.Time
+}
+
+type EventType string
+
+const (
+	EventTypeSet    EventType = "set"
+	EventTypeDelete EventType = "delete"
+	EventTypeExpire EventType = "expire"
+)
+
+// Event is a change to a key sent to watchers.
+type Event struct {
+	Type      EventType
+	OrgId     int64
+	Namespace string
+	Key       string
+	// Value and Version are empty for deleted and expired keys
+	Value   string
+	Version int64
+}
@@ -3,20 +3,31 @@ package kvstore
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
+// Keys queried at once by GetMany, below the bind variable limit of every database
+const getManyBatchSize = 500
+
 // kvStoreSQL provides a key/value store backed by the Grafana database
 type kvStoreSQL struct {
 	log      log.Logger
 	sqlStore sqlstore.Store
+	watchers *watchers
 }
 
 // Get an item from the store
 func (kv *kvStoreSQL) Get(ctx context.Context, orgId int64, namespace string, key string) (string, bool, error) {
+	item, found, err := kv.GetVersioned(ctx, orgId, namespace, key)
+	return item.Value, found, err
+}
+
+// GetVersioned gets an item from the store together with its version
+func (kv *kvStoreSQL) GetVersioned(ctx context.Context, orgId int64, namespace string, key string) (VersionedValue, bool, error) {
 	item := Item{
 		OrgId:     &orgId,
 		Namespace: &namespace,
@@ -30,7 +41,7 @@ func (kv *kvStoreSQL) Get(ctx context.Context, orgId int64, namespace string, ke
 			kv.log.Debug("error getting kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "err", err)
 			return err
 		}
-		if !has {
+		if !has || item.expired(time.Now()) {
 			kv.log.Debug("kvstore value not found", "orgId", orgId, "namespace", namespace, "key", key)
 			return nil
 		}
@@ -39,12 +50,99 @@ func (kv *kvStoreSQL) Get(ctx context.Context, orgId int64, namespace string, ke
 		return nil
 	})
 
-	return item.Value, itemFound, err
+	if !itemFound {
+		return VersionedValue{}, false, err
+	}
+	return VersionedValue{Value: item.Value, Version: item.Version, Expires: item.Expires}, true, err
 }
 
 // Set an item in the store
 func (kv *kvStoreSQL) Set(ctx context.Context, orgId int64, namespace string, key string, value string) error {
-	return kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+	return kv.set(ctx, orgId, namespace, key, value, nil)
+}
+
+// SetWithTTL sets an item in the store that expires after ttl
+func (kv *kvStoreSQL) SetWithTTL(ctx context.Context, orgId int64, namespace string, key string, value string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid kvstore ttl %s", ttl)
+	}
+	expires := time.Now().Add(ttl)
+	return kv.set(ctx, orgId, namespace, key, value, &expires)
+}
+
+func (kv *kvStoreSQL) set(ctx context.Context, orgId int64, namespace string, key string, value string, expires *time.Time) error {
+	var event *Event
+	err := kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		var err error
+		event, err = kv.setItem(dbSession, orgId, namespace, key, value, expires)
+		return err
+	})
+	if err == nil && event != nil {
+		kv.watchers.publish(*event)
+	}
+	return err
+}
+
+// setItem inserts or updates a single item. It returns the change for watchers, or nil
+// when nothing changed
+func (kv *kvStoreSQL) setItem(dbSession *sqlstore.DBSession, orgId int64, namespace string, key string, value string, expires *time.Time) (*Event, error) {
+	item := Item{
+		OrgId:     &orgId,
+		Namespace: &namespace,
+		Key:       &key,
+	}
+
+	has, err := dbSession.Get(&item)
+	if err != nil {
+		kv.log.Debug("error checking kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if has && item.Value == value && item.Expires == nil && expires == nil {
+		kv.log.Debug("kvstore value not changed", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+		return nil, nil
+	}
+
+	item.Value = value
+	item.Version++
+	item.Expires = expires
+	item.Updated = now
+
+	if has {
+		_, err = dbSession.ID(item.Id).Cols("value", "version", "expires", "updated").Update(&item)
+		if err != nil {
+			kv.log.Debug("error updating kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
+			return nil, err
+		}
+		kv.log.Debug("kvstore value updated", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+	} else {
+		item.Created = item.Updated
+		_, err = dbSession.Insert(&item)
+		if err != nil {
+			kv.log.Debug("error inserting kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
+			return nil, err
+		}
+		kv.log.Debug("kvstore value inserted", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+	}
+
+	return &Event{
+		Type:      EventTypeSet,
+		OrgId:     orgId,
+		Namespace: namespace,
+		Key:       key,
+		Value:     value,
+		Version:   item.Version,
+	}, nil
+}
+
+// CompareAndSwap sets an item only if it has not changed since expectedVersion was read.
+// Expired and missing items have version 0.
+func (kv *kvStoreSQL) CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, expectedVersion int64, value string) (int64, bool, error) {
+	var version int64
+	var event *Event
+
+	err := kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
 		item := Item{
 			OrgId:     &orgId,
 			Namespace: &namespace,
@@ -53,46 +151,143 @@ func (kv *kvStoreSQL) Set(ctx context.Context, orgId int64, namespace string, ke
 
 		has, err := dbSession.Get(&item)
 		if err != nil {
-			kv.log.Debug("error checking kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
 			return err
 		}
 
-		if has && item.Value == value {
-			kv.log.Debug("kvstore value not changed", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+		now := time.Now()
+		version = item.Version
+		if !has || item.expired(now) {
+			version = 0
+		}
+		if version != expectedVersion {
+			kv.log.Debug("kvstore version mismatch", "orgId", orgId, "namespace", namespace, "key", key, "expected", expectedVersion, "version", version)
 			return nil
 		}
 
+		previous := item.Version
 		item.Value = value
-		item.Updated = time.Now()
+		item.Version++
+		item.Expires = nil
+		item.Updated = now
 
 		if has {
-			_, err = dbSession.ID(item.Id).Update(&item)
+			// the version condition protects against concurrent writers outside this transaction
+			affected, err := dbSession.Where("id = ? AND version = ?", item.Id, previous).Cols("value", "version", "expires", "updated").Update(&item)
 			if err != nil {
-				kv.log.Debug("error updating kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
-			} else {
-				kv.log.Debug("kvstore value updated", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+				return err
+			}
+			if affected == 0 {
+				kv.log.Debug("kvstore value changed concurrently", "orgId", orgId, "namespace", namespace, "key", key)
+				return nil
+			}
+		} else {
+			item.Created = now
+			if _, err := dbSession.Insert(&item); err != nil {
+				return err
 			}
-			return err
 		}
 
-		item.Created = item.Updated
-		_, err = dbSession.Insert(&item)
-		if err != nil {
-			kv.log.Debug("error inserting kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
-		} else {
-			kv.log.Debug("kvstore value inserted", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
+		version = item.Version
+		event = &Event{
+			Type:      EventTypeSet,
+			OrgId:     orgId,
+			Namespace: namespace,
+			Key:       key,
+			Value:     value,
+			Version:   version,
 		}
-		return err
+		return nil
+	})
+	if err != nil {
+		// a concurrent writer created the item first
+		if dialect := kv.sqlStore.GetDialect(); dialect != nil && dialect.IsUniqueConstraintViolation(err) {
+			kv.log.Debug("kvstore value created concurrently", "orgId", orgId, "namespace", namespace, "key", key)
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	if event == nil {
+		return version, false, nil
+	}
+
+	kv.watchers.publish(*event)
+	return version, true, nil
+}
+
+// GetMany gets the items for all the given keys. Missing keys are not in the result
+func (kv *kvStoreSQL) GetMany(ctx context.Context, orgId int64, namespace string, keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		now := time.Now()
+		for start := 0; start < len(keys); start += getManyBatchSize {
+			end := start + getManyBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+
+			var items []Item
+			err := dbSession.Where("org_id = ? AND namespace = ?", orgId, namespace).
+				In(kv.sqlStore.Quote("key"), keys[start:end]).
+				Find(&items)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				if !item.expired(now) {
+					values[*item.Key] = item.Value
+				}
+			}
+		}
+		return nil
 	})
+	return values, err
+}
+
+// SetMany sets all the items in one transaction, so either all or none are saved
+func (kv *kvStoreSQL) SetMany(ctx context.Context, orgId int64, namespace string, values map[string]string) error {
+	// a fixed order avoids deadlocks between concurrent calls
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var events []Event
+	err := kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		events = events[:0]
+		for _, key := range keys {
+			event, err := kv.setItem(dbSession, orgId, namespace, key, values[key], nil)
+			if err != nil {
+				return err
+			}
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		kv.watchers.publish(events...)
+	}
+	return err
 }
 
 // Del deletes an item from the store.
 func (kv *kvStoreSQL) Del(ctx context.Context, orgId int64, namespace string, key string) error {
+	var deleted bool
 	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
 		query := fmt.Sprintf("DELETE FROM kv_store WHERE org_id=? and namespace=? and %s=?", kv.sqlStore.Quote("key"))
-		_, err := dbSession.Exec(query, orgId, namespace, key)
-		return err
+		res, err := dbSession.Exec(query, orgId, namespace, key)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		deleted = err == nil && affected > 0
+		return nil
 	})
+	if err == nil && deleted {
+		kv.watchers.publish(Event{Type: EventTypeDelete, OrgId: orgId, Namespace: namespace, Key: key})
+	}
 	return err
 }
 
@@ -101,7 +296,9 @@ func (kv *kvStoreSQL) Del(ctx context.Context, orgId int64, namespace string, ke
 func (kv *kvStoreSQL) Keys(ctx context.Context, orgId int64, namespace string, keyPrefix string) ([]Key, error) {
 	var keys []Key
 	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
-		query := dbSession.Where("namespace = ?", namespace).And(fmt.Sprintf("%s LIKE ?", kv.sqlStore.Quote("key")), keyPrefix+"%")
+		query := dbSession.Where("namespace = ?", namespace).
+			And(fmt.Sprintf("%s LIKE ?", kv.sqlStore.Quote("key")), keyPrefix+"%").
+			And("(expires IS NULL OR expires > ?)", time.Now())
 		if orgId != AllOrganizations {
 			query.And("org_id = ?", orgId)
 		}
@@ -109,3 +306,61 @@ func (kv *kvStoreSQL) Keys(ctx context.Context, orgId int64, namespace string, k
 	})
 	return keys, err
 }
+
+// Watch returns the changes to the keys matching keyPrefix. Changes made through this store are
+// sent right away, changes made by other Grafana instances when the database is polled next.
+// To watch all organizations the constant 'kvstore.AllOrganizations' can be passed as orgId.
+func (kv *kvStoreSQL) Watch(ctx context.Context, orgId int64, namespace string, keyPrefix string) (<-chan Event, error) {
+	read := func(ctx context.Context) ([]Item, error) {
+		return kv.watchedItems(ctx, orgId, namespace, keyPrefix)
+	}
+	items, err := read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return kv.watchers.add(ctx, orgId, namespace, keyPrefix, items, read), nil
+}
+
+// watchedItems returns all items matching keyPrefix, including expired items that are not deleted yet
+func (kv *kvStoreSQL) watchedItems(ctx context.Context, orgId int64, namespace string, keyPrefix string) ([]Item, error) {
+	var items []Item
+	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		query := dbSession.Where("namespace = ?", namespace).
+			And(fmt.Sprintf("%s LIKE ?", kv.sqlStore.Quote("key")), keyPrefix+"%")
+		if orgId != AllOrganizations {
+			query.And("org_id = ?", orgId)
+		}
+		return query.Find(&items)
+	})
+	return items, err
+}
+
+// DeleteExpired removes all expired items. It is called periodically by the cleanup service
+func (kv *kvStoreSQL) DeleteExpired(ctx context.Context) (int64, error) {
+	var expired []Item
+	var affected int64
+	err := kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		now := time.Now()
+		expired = expired[:0]
+		if err := dbSession.Where("expires <= ?", now).Find(&expired); err != nil {
+			return err
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		var err error
+		affected, err = dbSession.Where("expires <= ?", now).Delete(&Item{})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]Event, 0, len(expired))
+	for _, item := range expired {
+		events = append(events, Event{Type: EventTypeExpire, OrgId: *item.OrgId, Namespace: *item.Namespace, Key: *item.Key})
+	}
+	kv.watchers.publish(events...)
+	return affected, nil
+}
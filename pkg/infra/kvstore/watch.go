@@ -0,0 +1,224 @@
+package kvstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Events buffered per watcher before it is considered too slow
+const watchBufferSize = 100
+
+// How often watchers read the database for changes made by other Grafana instances
+const defaultWatchPollInterval = 5 * time.Second
+
+// readItemsFunc reads all items of a watch, including expired ones
+type readItemsFunc func(ctx context.Context) ([]Item, error)
+
+// seenItem is the last state of a key sent to a watcher
+type seenItem struct {
+	version int64
+	// expired is true once the watcher was told that the key expired
+	expired bool
+}
+
+type watcher struct {
+	orgId     int64
+	namespace string
+	keyPrefix string
+	ch        chan Event
+	// seen is used to send every change only once, whether it was made by this instance
+	// or read from the database
+	seen map[Key]seenItem
+}
+
+func (w *watcher) matches(e Event) bool {
+	return (w.orgId == AllOrganizations || w.orgId == e.OrgId) &&
+		w.namespace == e.Namespace &&
+		strings.HasPrefix(e.Key, w.keyPrefix)
+}
+
+// track records a change made by this instance and reports whether the watcher has not seen it yet
+func (w *watcher) track(e Event) bool {
+	k := Key{OrgId: e.OrgId, Namespace: e.Namespace, Key: e.Key}
+	seen, ok := w.seen[k]
+	if e.Type == EventTypeSet {
+		if ok && !seen.expired && seen.version >= e.Version {
+			return false
+		}
+		w.seen[k] = seenItem{version: e.Version}
+		return true
+	}
+	delete(w.seen, k)
+	return ok && !seen.expired
+}
+
+// diff returns the changes between the items read from the database and what the watcher has
+// seen, and records them. Keys that expired but are not deleted yet stay seen as expired
+func (w *watcher) diff(items []Item, now time.Time) []Event {
+	events := make([]Event, 0)
+	current := make(map[Key]bool, len(items))
+	for _, item := range items {
+		k := itemKey(item)
+		current[k] = true
+		seen, ok := w.seen[k]
+		switch {
+		case item.expired(now):
+			if ok && !seen.expired {
+				events = append(events, Event{Type: EventTypeExpire, OrgId: k.OrgId, Namespace: k.Namespace, Key: k.Key})
+			}
+			w.seen[k] = seenItem{version: item.Version, expired: true}
+		case !ok || seen.expired || seen.version < item.Version:
+			events = append(events, Event{Type: EventTypeSet, OrgId: k.OrgId, Namespace: k.Namespace, Key: k.Key, Value: item.Value, Version: item.Version})
+			w.seen[k] = seenItem{version: item.Version}
+		}
+	}
+	for k, seen := range w.seen {
+		if current[k] {
+			continue
+		}
+		if !seen.expired {
+			events = append(events, Event{Type: EventTypeDelete, OrgId: k.OrgId, Namespace: k.Namespace, Key: k.Key})
+		}
+		delete(w.seen, k)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].OrgId != events[j].OrgId {
+			return events[i].OrgId < events[j].OrgId
+		}
+		return events[i].Key < events[j].Key
+	})
+	return events
+}
+
+// watchers fans out the changes made through the store, and the changes of other Grafana
+// instances read from the database, to Watch callers
+type watchers struct {
+	log          log.Logger
+	pollInterval time.Duration
+
+	mu     sync.Mutex
+	nextID int64
+	byID   map[int64]*watcher
+}
+
+func newWatchers() *watchers {
+	return &watchers{
+		log:          log.New("infra.kvstore.watch"),
+		pollInterval: defaultWatchPollInterval,
+		byID:         make(map[int64]*watcher),
+	}
+}
+
+// add registers a watcher for the items, which are the current state of the watched keys.
+// read is used to poll the database until ctx is done
+func (ws *watchers) add(ctx context.Context, orgId int64, namespace string, keyPrefix string, items []Item, read readItemsFunc) <-chan Event {
+	w := &watcher{
+		orgId:     orgId,
+		namespace: namespace,
+		keyPrefix: keyPrefix,
+		ch:        make(chan Event, watchBufferSize),
+		seen:      make(map[Key]seenItem, len(items)),
+	}
+	for _, item := range items {
+		// expired keys are reported when they are deleted
+		w.seen[itemKey(item)] = seenItem{version: item.Version}
+	}
+
+	ws.mu.Lock()
+	ws.nextID++
+	id := ws.nextID
+	ws.byID[id] = w
+	ws.mu.Unlock()
+
+	go ws.poll(ctx, id, read)
+	return w.ch
+}
+
+// poll reads the watched items from the database until ctx is done or the watcher is closed
+func (ws *watchers) poll(ctx context.Context, id int64, read readItemsFunc) {
+	ticker := time.NewTicker(ws.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ws.remove(id)
+			return
+		case <-ticker.C:
+		}
+
+		items, err := read(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				ws.log.Warn("failed to read kvstore changes", "err", err)
+			}
+			continue
+		}
+		if !ws.sync(id, items, time.Now()) {
+			return
+		}
+	}
+}
+
+// sync sends the differences between the items and what the watcher has seen. It returns
+// false when the watcher is closed
+func (ws *watchers) sync(id int64, items []Item, now time.Time) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	w, ok := ws.byID[id]
+	if !ok {
+		return false
+	}
+	for _, e := range w.diff(items, now) {
+		if !ws.send(id, w, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// send never blocks the writer. Watchers that can't keep up are closed so they know they
+// missed events. It returns false when the watcher was closed. Must be called with ws.mu held
+func (ws *watchers) send(id int64, w *watcher, e Event) bool {
+	select {
+	case w.ch <- e:
+		return true
+	default:
+	}
+	delete(ws.byID, id)
+	close(w.ch)
+	return false
+}
+
+func (ws *watchers) remove(id int64) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if w, ok := ws.byID[id]; ok {
+		delete(ws.byID, id)
+		close(w.ch)
+	}
+}
+
+// publish sends the changes made by this instance
+func (ws *watchers) publish(events ...Event) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for id, w := range ws.byID {
+		for _, e := range events {
+			if !w.matches(e) || !w.track(e) {
+				continue
+			}
+			if !ws.send(id, w, e) {
+				break
+			}
+		}
+	}
+}
+
+func itemKey(item Item) Key {
+	return Key{OrgId: *item.OrgId, Namespace: *item.Namespace, Key: *item.Key}
+}
@@ -0,0 +1,129 @@
+package serverlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// LeaderElection picks one server to run a background loop. When the leader dies
+// or loses its lease, another server takes over once the lease expires.
+type LeaderElection struct {
+	sl            *ServerLockService
+	actionName    string
+	leaseDuration time.Duration
+	retryInterval time.Duration
+
+	mu    sync.Mutex
+	lease *Lease
+}
+
+// NewLeaderElection creates a leader election for `actionName`. Followers try to take the
+// lease every half `leaseDuration`, so failover takes at most one and a half lease durations.
+func (sl *ServerLockService) NewLeaderElection(actionName string, leaseDuration time.Duration) *LeaderElection {
+	return &LeaderElection{
+		sl:            sl,
+		actionName:    actionName,
+		leaseDuration: leaseDuration,
+		retryInterval: leaseDuration / 2,
+	}
+}
+
+// IsLeader reports whether this server currently holds the lease.
+func (le *LeaderElection) IsLeader() bool {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	if le.lease == nil {
+		return false
+	}
+	select {
+	case <-le.lease.Lost():
+		return false
+	default:
+		return true
+	}
+}
+
+// Token returns the fencing token of the current lease, or 0 when this server is not the leader.
+func (le *LeaderElection) Token() int64 {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	if le.lease == nil {
+		return 0
+	}
+	return le.lease.Token()
+}
+
+// Run calls `fn` whenever this server becomes the leader. The context passed to `fn` is
+// cancelled when leadership is lost, after which this server campaigns again. If `fn`
+// returns while still leader, the lease is released and Run returns. Run blocks until
+// `ctx` is done.
+func (le *LeaderElection) Run(ctx context.Context, fn func(ctx context.Context)) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		lease, err := le.sl.AcquireLease(ctx, le.actionName, le.leaseDuration)
+		switch {
+		case err == nil:
+			if done := le.lead(ctx, lease, fn); done {
+				return nil
+			}
+			// lost the lease, campaign again right away
+			continue
+		case errors.Is(err, ErrLeaseHeld):
+		default:
+			le.sl.log.Error("Leader election failed", "actionName", le.actionName, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(le.retryInterval):
+		}
+	}
+}
+
+// lead runs `fn` until it returns or the lease is lost. It returns true when `fn` returned
+// on its own
+func (le *LeaderElection) lead(ctx context.Context, lease *Lease, fn func(ctx context.Context)) bool {
+	le.sl.log.Info("Became leader", "actionName", le.actionName, "token", lease.Token())
+	le.mu.Lock()
+	le.lease = lease
+	le.mu.Unlock()
+	defer func() {
+		le.mu.Lock()
+		le.lease = nil
+		le.mu.Unlock()
+	}()
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		fn(leaderCtx)
+	}()
+
+	lost := false
+	select {
+	case <-finished:
+	case <-lease.Lost():
+		lost = true
+		le.sl.log.Warn("Lost leadership", "actionName", le.actionName, "token", lease.Token())
+		cancel()
+		<-finished
+	case <-ctx.Done():
+		cancel()
+		<-finished
+	}
+
+	if !lost {
+		if err := lease.Release(context.Background()); err != nil {
+			le.sl.log.Error("Failed to release leader lease", "actionName", le.actionName, "error", err)
+		}
+	}
+	return !lost && ctx.Err() == nil
+}
@@ -0,0 +1,221 @@
+package serverlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+var (
+	// ErrLeaseHeld is returned when another server holds an unexpired lease
+	ErrLeaseHeld = errors.New("lease is held by another server")
+)
+
+// The lease is renewed when a third of its duration is left, so a single
+// failed heartbeat doesn't lose it
+const leaseRenewalDivisor = 3
+
+// Lease is a lock held by this server until it is released or expires. It is renewed
+// in the background until Release is called.
+//
+// Expiry is compared with the clock of the server trying to take over the lease, so
+// servers should keep their clocks in sync and leases should be much longer than the
+// expected skew.
+type Lease struct {
+	sl         *ServerLockService
+	id         int64
+	actionName string
+	token      int64
+	duration   time.Duration
+
+	mu      sync.Mutex
+	expires time.Time
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Token is the fencing token of the lease. Every new lease on the same action
+// gets a larger token, so writes guarded by an old token can be rejected.
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Expires returns when the lease expires if it is not renewed.
+func (l *Lease) Expires() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.expires
+}
+
+// Lost is closed when the lease could not be renewed and another server may hold it.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release stops renewing the lease and frees it for other servers.
+func (l *Lease) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	select {
+	case <-l.lost:
+		// nothing left to release
+		return nil
+	default:
+	}
+	l.markLost()
+
+	return l.sl.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		_, err := dbSession.Exec("UPDATE server_lock SET lease_owner = ?, lease_expires = ? WHERE id = ? AND lease_token = ?",
+			"", 0, l.id, l.token)
+		return err
+	})
+}
+
+func (l *Lease) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+func (l *Lease) renewLoop() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.duration / leaseRenewalDivisor)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if time.Now().After(l.Expires()) {
+				l.sl.log.Warn("Lease expired before it could be renewed", "actionName", l.actionName, "token", l.token)
+				l.markLost()
+				return
+			}
+			renewed, err := l.renew()
+			if err != nil {
+				// try again on the next tick while the lease is still valid
+				l.sl.log.Error("Failed to renew lease", "actionName", l.actionName, "token", l.token, "error", err)
+				continue
+			}
+			if !renewed {
+				l.sl.log.Warn("Lease was taken over by another server", "actionName", l.actionName, "token", l.token)
+				l.markLost()
+				return
+			}
+		}
+	}
+}
+
+func (l *Lease) renew() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.duration/leaseRenewalDivisor)
+	defer cancel()
+
+	expires := time.Now().Add(l.duration)
+	var renewed bool
+	err := l.sl.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		res, err := dbSession.Exec("UPDATE server_lock SET lease_expires = ? WHERE id = ? AND lease_token = ?",
+			expires.UnixMilli(), l.id, l.token)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		renewed = affected == 1
+		return err
+	})
+	if err == nil && renewed {
+		l.mu.Lock()
+		l.expires = expires
+		l.mu.Unlock()
+	}
+	return renewed, err
+}
+
+// AcquireLease tries to take the lease for `actionName` for `duration`. It returns ErrLeaseHeld
+// if another server holds it. The lease is renewed until it is released, so `duration` only
+// bounds how long other servers wait when this server dies.
+func (sl *ServerLockService) AcquireLease(ctx context.Context, actionName string, duration time.Duration) (*Lease, error) {
+	rowLock, err := sl.getOrCreate(ctx, actionName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if rowLock.LeaseExpires > now.UnixMilli() {
+		return nil, ErrLeaseHeld
+	}
+
+	expires := now.Add(duration)
+	newToken := rowLock.LeaseToken + 1
+	var acquired bool
+	err = sl.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		// the token check makes sure nobody took the lease since it was read
+		res, err := dbSession.Exec(`UPDATE server_lock SET
+			lease_token = ?,
+			lease_owner = ?,
+			lease_expires = ?
+		WHERE
+			id = ? AND lease_token = ?`, newToken, sl.owner, expires.UnixMilli(), rowLock.Id, rowLock.LeaseToken)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		acquired = affected == 1
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrLeaseHeld
+	}
+
+	lease := &Lease{
+		sl:         sl,
+		id:         rowLock.Id,
+		actionName: actionName,
+		token:      newToken,
+		duration:   duration,
+		expires:    expires,
+		lost:       make(chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go lease.renewLoop()
+	return lease, nil
+}
+
+// ExecuteWithLease runs `fn` while holding the lease for `actionName`, and does nothing if another
+// server holds it. The context passed to `fn` is cancelled if the lease is lost, and the lease is
+// released when `fn` returns. Unlike LockAndExecute this is safe for jobs that run longer than
+// the lease duration.
+func (sl *ServerLockService) ExecuteWithLease(ctx context.Context, actionName string, duration time.Duration, fn func(ctx context.Context, token int64)) error {
+	lease, err := sl.AcquireLease(ctx, actionName, duration)
+	if errors.Is(err, ErrLeaseHeld) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-leaseCtx.Done():
+		}
+	}()
+
+	fn(leaseCtx, lease.Token())
+
+	// the lease must be freed even when ctx is done
+	return lease.Release(context.Background())
+}
@@ -0,0 +1,133 @@
+package serverlock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestLease(t *testing.T) {
+	first := createTestableServerLock(t)
+	first.owner = "first"
+	second := &ServerLockService{SQLStore: first.SQLStore, log: log.New("test-logger"), owner: "second"}
+	ctx := context.Background()
+
+	lease, err := first.AcquireLease(ctx, "test-lease", 300*time.Millisecond)
+	require.NoError(t, err)
+
+	_, err = second.AcquireLease(ctx, "test-lease", 300*time.Millisecond)
+	require.ErrorIs(t, err, ErrLeaseHeld)
+
+	t.Run("lease is renewed while held", func(t *testing.T) {
+		expires := lease.Expires()
+		time.Sleep(500 * time.Millisecond)
+		require.True(t, lease.Expires().After(expires))
+
+		_, err = second.AcquireLease(ctx, "test-lease", 300*time.Millisecond)
+		require.ErrorIs(t, err, ErrLeaseHeld)
+	})
+
+	t.Run("lock and execute on the same action does not affect the lease", func(t *testing.T) {
+		executed := false
+		err := second.LockAndExecute(ctx, "test-lease", time.Millisecond, func(context.Context) {
+			executed = true
+		})
+		require.NoError(t, err)
+		require.True(t, executed)
+
+		expires := lease.Expires()
+		time.Sleep(500 * time.Millisecond)
+		require.True(t, lease.Expires().After(expires))
+		select {
+		case <-lease.Lost():
+			t.Fatal("lease should not be lost")
+		default:
+		}
+	})
+
+	t.Run("released lease can be taken with a larger token", func(t *testing.T) {
+		require.NoError(t, lease.Release(ctx))
+		select {
+		case <-lease.Lost():
+		default:
+			t.Fatal("released lease should be lost")
+		}
+
+		next, err := second.AcquireLease(ctx, "test-lease", time.Minute)
+		require.NoError(t, err)
+		require.Greater(t, next.Token(), lease.Token())
+
+		// a released lease must not free the new holder
+		require.NoError(t, lease.Release(ctx))
+		_, err = first.AcquireLease(ctx, "test-lease", time.Minute)
+		require.ErrorIs(t, err, ErrLeaseHeld)
+		require.NoError(t, next.Release(ctx))
+	})
+
+	t.Run("execute with lease", func(t *testing.T) {
+		var token int64
+		var skippedErr error
+		err := first.ExecuteWithLease(ctx, "test-execute", time.Minute, func(ctx context.Context, leaseToken int64) {
+			token = leaseToken
+			// other servers skip the job while it runs
+			skippedErr = second.ExecuteWithLease(ctx, "test-execute", time.Minute, func(context.Context, int64) {
+				token = -1
+			})
+		})
+		require.NoError(t, err)
+		require.NoError(t, skippedErr)
+		require.Equal(t, int64(1), token)
+
+		err = second.ExecuteWithLease(ctx, "test-execute", time.Minute, func(ctx context.Context, leaseToken int64) {
+			token = leaseToken
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), token)
+	})
+}
+
+func TestLeaderElection(t *testing.T) {
+	first := createTestableServerLock(t)
+	first.owner = "first"
+	second := &ServerLockService{SQLStore: first.SQLStore, log: log.New("test-logger"), owner: "second"}
+
+	var leader atomic.Value
+	run := func(sl *ServerLockService) (*LeaderElection, context.CancelFunc, chan error) {
+		election := sl.NewLeaderElection("test-leader", 200*time.Millisecond)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- election.Run(ctx, func(ctx context.Context) {
+				leader.Store(sl.owner)
+				<-ctx.Done()
+			})
+		}()
+		return election, cancel, done
+	}
+
+	firstElection, stopFirst, firstDone := run(first)
+	require.Eventually(t, firstElection.IsLeader, time.Second, 10*time.Millisecond)
+	require.Equal(t, "first", leader.Load())
+
+	secondElection, stopSecond, secondDone := run(second)
+	defer stopSecond()
+	time.Sleep(300 * time.Millisecond)
+	require.False(t, secondElection.IsLeader())
+	require.Equal(t, int64(0), secondElection.Token())
+
+	// the follower takes over when the leader stops
+	stopFirst()
+	require.ErrorIs(t, <-firstDone, context.Canceled)
+	require.False(t, firstElection.IsLeader())
+	require.Eventually(t, secondElection.IsLeader, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return leader.Load() == "second" }, time.Second, 10*time.Millisecond)
+	require.Greater(t, secondElection.Token(), int64(1))
+
+	stopSecond()
+	require.ErrorIs(t, <-secondDone, context.Canceled)
+}
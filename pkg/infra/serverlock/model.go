@@ -5,5 +5,12 @@ type serverLock struct {
 	Id            int64
 	OperationUID  string `xorm:"operation_uid"`
 	LastExecution int64
-	Version       int64
+	// Version is incremented on every execution of LockAndExecute
+	Version int64
+	// LeaseToken is incremented on every lease and is used as fencing token. It is separate from
+	// Version so that LockAndExecute calls don't invalidate the lease of another server
+	LeaseToken int64
+	// LeaseOwner and LeaseExpires (unix milliseconds) are only set while a lease is held
+	LeaseOwner   string
+	LeaseExpires int64
 }
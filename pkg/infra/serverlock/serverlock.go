@@ -2,24 +2,38 @@ package serverlock
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func ProvideService(sqlStore *sqlstore.SQLStore) *ServerLockService {
 	return &ServerLockService{
 		SQLStore: sqlStore,
 		log:      log.New("infra.lockservice"),
+		owner:    ownerID(),
 	}
 }
 
+// ownerID identifies this server in the leases it holds
+func ownerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, util.GenerateShortUID())
+}
+
 // ServerLockService allows servers in HA mode to claim a lock
 // and execute an function if the server was granted the lock
 type ServerLockService struct {
 	SQLStore *sqlstore.SQLStore
 	log      log.Logger
+	owner    string
 }
 
 // LockAndExecute try to create a lock for this server and only executes the
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/models"
@@ -20,12 +21,14 @@ import (
 )
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
-	shortURLService shorturls.Service, store sqlstore.Store, queryHistoryService queryhistory.Service) *CleanUpService {
+	shortURLService shorturls.Service, store sqlstore.Store, queryHistoryService queryhistory.Service,
+	kvStore kvstore.KVStore) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                 cfg,
 		ServerLockService:   serverLockService,
 		ShortURLService:     shortURLService,
 		QueryHistoryService: queryHistoryService,
+		KVStore:             kvStore,
 		store:               store,
 		log:                 log.New("cleanup"),
 	}
@@ -39,6 +42,7 @@ type CleanUpService struct {
 	ServerLockService   *serverlock.ServerLockService
 	ShortURLService     shorturls.Service
 	QueryHistoryService queryhistory.Service
+	KVStore             kvstore.KVStore
 }
 
 func (srv *CleanUpService) Run(ctx context.Context) error {
@@ -58,6 +62,7 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			srv.expireOldUserInvites(ctx)
 			srv.deleteStaleShortURLs(ctx)
 			srv.deleteStaleQueryHistory(ctx)
+			srv.deleteExpiredKVStoreItems(ctx)
 			err := srv.ServerLockService.LockAndExecute(ctx, "delete old login attempts",
 				time.Minute*10, func(context.Context) {
 					srv.deleteOldLoginAttempts(ctx)
@@ -217,3 +222,12 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		srv.log.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) deleteExpiredKVStoreItems(ctx context.Context) {
+	rowsCount, err := srv.KVStore.DeleteExpired(ctx)
+	if err != nil {
+		srv.log.Error("Problem deleting expired kvstore items", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired kvstore items", "rows affected", rowsCount)
+	}
+}
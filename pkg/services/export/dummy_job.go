@@ -23,8 +23,8 @@ type dummyExportJob struct {
 }
 
 func startDummyExportJob(cfg ExportConfig, broadcaster statusBroadcaster) (Job, error) {
-	if cfg.Format != "git" {
-		return nil, errors.New("only git format is supported")
+	if cfg.Format != "dummy" {
+		return nil, errors.New("only dummy format is supported")
 	}
 
 	job := &dummyExportJob{
@@ -0,0 +1,31 @@
+package export
+
+import (
+	"path"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// Alert rules are saved in a folder for each namespace (folder UID) and rule group
+func exportAlertRules(h *orgExporter) error {
+	var rules []*ngmodels.AlertRule
+	err := h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Table("alert_rule").
+			Where("org_id = ?", h.orgID).
+			OrderBy("namespace_uid ASC, rule_group ASC, id ASC").
+			Find(&rules)
+	})
+	if err != nil {
+		return err
+	}
+	h.job.addCount(len(rules))
+
+	for _, rule := range rules {
+		fpath := path.Join("alerting", "rules", rule.NamespaceUID, rule.RuleGroup, rule.UID+".json")
+		if err := h.writeJSON(fpath, rule, rule.Updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type dashInfo struct {
+	ID        int64  `xorm:"id"`
+	UID       string `xorm:"uid"`
+	Title     string
+	FolderID  int64 `xorm:"folder_id"`
+	IsFolder  bool  `xorm:"is_folder"`
+	Updated   time.Time
+	UpdatedBy int64 `xorm:"updated_by"`
+	Data      []byte
+}
+
+type dashVersionInfo struct {
+	DashboardID int64 `xorm:"dashboard_id"`
+	Version     int
+	Created     time.Time
+	CreatedBy   int64 `xorm:"created_by"`
+	Message     string
+	Data        []byte
+}
+
+// Folder metadata is saved next to the dashboards in the folder
+const folderMetaFile = "__folder.json"
+
+func (h *orgExporter) getDashboards() ([]dashInfo, error) {
+	var dashes []dashInfo
+	err := h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Table("dashboard").
+			Cols("id", "uid", "title", "folder_id", "is_folder", "updated", "updated_by", "data").
+			Where("org_id = ?", h.orgID).
+			OrderBy("id ASC").
+			Find(&dashes)
+	})
+	return dashes, err
+}
+
+// dashboardPaths returns the folder path for every folder ID (including the general folder)
+// and the file path for every dashboard ID.  Paths stay the same for every version of a dashboard
+func (h *orgExporter) dashboardPaths(dashes []dashInfo) (map[int64]string, map[int64]string) {
+	generalDir := "dashboards/general"
+	if h.job.cfg.Git.GeneralAtRoot {
+		generalDir = "dashboards"
+	}
+	folders := map[int64]string{0: generalDir}
+	used := map[string]bool{generalDir: true}
+	for _, d := range dashes {
+		if !d.IsFolder {
+			continue
+		}
+		name := models.SlugifyTitle(d.Title)
+		if name == "" {
+			name = d.UID
+		}
+		dir := path.Join("dashboards", name)
+		if used[dir] {
+			dir = path.Join("dashboards", name+"-"+d.UID)
+		}
+		used[dir] = true
+		folders[d.ID] = dir
+	}
+
+	files := map[int64]string{}
+	for _, d := range dashes {
+		if d.IsFolder {
+			continue
+		}
+		dir, ok := folders[d.FolderID]
+		if !ok {
+			dir = generalDir
+		}
+		name := models.SlugifyTitle(d.Title)
+		if name == "" {
+			name = d.UID
+		}
+		fpath := path.Join(dir, name+".json")
+		if used[fpath] {
+			fpath = path.Join(dir, name+"-"+d.UID+".json")
+		}
+		used[fpath] = true
+		files[d.ID] = fpath
+	}
+	return folders, files
+}
+
+// Writes the dashboard JSON the same way it is stored, but indented and without the internal id
+func (h *orgExporter) writeDashboard(fpath string, data []byte, modified time.Time) error {
+	var dash map[string]interface{}
+	if err := json.Unmarshal(data, &dash); err != nil {
+		return fmt.Errorf("invalid dashboard json in %s: %w", fpath, err)
+	}
+	delete(dash, "id")
+	return h.writeJSON(fpath, dash, modified)
+}
+
+func exportDashboards(h *orgExporter) error {
+	dashes, err := h.getDashboards()
+	if err != nil {
+		return err
+	}
+	h.job.addCount(len(dashes))
+
+	folders, files := h.dashboardPaths(dashes)
+	for _, d := range dashes {
+		if d.IsFolder {
+			err = h.writeJSON(path.Join(folders[d.ID], folderMetaFile), map[string]interface{}{
+				"uid":   d.UID,
+				"title": d.Title,
+			}, d.Updated)
+		} else {
+			err = h.writeDashboard(files[d.ID], d.Data, d.Updated)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportDashboardHistory writes every saved version of every dashboard, oldest first,
+// with a commit for each version by the user who saved it
+func (h *orgExporter) exportDashboardHistory() error {
+	dashes, err := h.getDashboards()
+	if err != nil {
+		return err
+	}
+	_, files := h.dashboardPaths(dashes)
+	titles := make(map[int64]string, len(dashes))
+	for _, d := range dashes {
+		titles[d.ID] = d.Title
+	}
+
+	return h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Table("dashboard_version").
+			Select(`dashboard_version.dashboard_id,
+				dashboard_version.version,
+				dashboard_version.created,
+				dashboard_version.created_by,
+				dashboard_version.message,
+				dashboard_version.data`).
+			Join("INNER", "dashboard", "dashboard.id = dashboard_version.dashboard_id").
+			Where("dashboard.org_id = ? AND dashboard.is_folder = ?", h.orgID, h.job.sql.Dialect.BooleanStr(false)).
+			OrderBy("dashboard_version.created ASC, dashboard_version.id ASC").
+			Iterate(new(dashVersionInfo), func(idx int, bean interface{}) error {
+				v := bean.(*dashVersionInfo)
+				fpath, ok := files[v.DashboardID]
+				if !ok || bytes.Equal(bytes.TrimSpace(v.Data), []byte("null")) {
+					return nil
+				}
+				h.job.addCount(1)
+				if err := h.writeDashboard(fpath, v.Data, v.Created); err != nil {
+					return err
+				}
+				msg := v.Message
+				if msg == "" {
+					msg = fmt.Sprintf("Update %s (version %d)", titles[v.DashboardID], v.Version)
+				}
+				return h.job.target.commit(msg, h.users[v.CreatedBy], v.Created)
+			})
+	})
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// The exported datasource.  Passwords and secure json values are never exported,
+// secureJsonFields lists the keys that need to be set again after an import
+type dataSourceExport struct {
+	UID              string           `json:"uid"`
+	Name             string           `json:"name"`
+	Type             string           `json:"type"`
+	Access           models.DsAccess  `json:"access"`
+	URL              string           `json:"url"`
+	User             string           `json:"user,omitempty"`
+	Database         string           `json:"database,omitempty"`
+	BasicAuth        bool             `json:"basicAuth"`
+	BasicAuthUser    string           `json:"basicAuthUser,omitempty"`
+	WithCredentials  bool             `json:"withCredentials"`
+	IsDefault        bool             `json:"isDefault"`
+	ReadOnly         bool             `json:"readOnly"`
+	JSONData         *simplejson.Json `json:"jsonData,omitempty"`
+	SecureJSONFields map[string]bool  `json:"secureJsonFields,omitempty"`
+	Version          int              `json:"version"`
+}
+
+func exportDataSources(h *orgExporter) error {
+	var dss []*models.DataSource
+	err := h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", h.orgID).Asc("id").Find(&dss)
+	})
+	if err != nil {
+		return err
+	}
+	h.job.addCount(len(dss))
+
+	for _, ds := range dss {
+		out := dataSourceExport{
+			UID:             ds.Uid,
+			Name:            ds.Name,
+			Type:            ds.Type,
+			Access:          ds.Access,
+			URL:             ds.Url,
+			User:            ds.User,
+			Database:        ds.Database,
+			BasicAuth:       ds.BasicAuth,
+			BasicAuthUser:   ds.BasicAuthUser,
+			WithCredentials: ds.WithCredentials,
+			IsDefault:       ds.IsDefault,
+			ReadOnly:        ds.ReadOnly,
+			JSONData:        ds.JsonData,
+			Version:         ds.Version,
+		}
+		if len(ds.SecureJsonData) > 0 || ds.Password != "" || ds.BasicAuthPassword != "" {
+			out.SecureJSONFields = make(map[string]bool, len(ds.SecureJsonData)+2)
+			for k := range ds.SecureJsonData {
+				out.SecureJSONFields[k] = true
+			}
+			if ds.Password != "" {
+				out.SecureJSONFields["password"] = true
+			}
+			if ds.BasicAuthPassword != "" {
+				out.SecureJSONFields["basicAuthPassword"] = true
+			}
+		}
+
+		name := models.SlugifyTitle(ds.Name)
+		if name == "" {
+			name = ds.Uid
+		}
+		err := h.writeJSON(path.Join("datasources", fmt.Sprintf("%s-%s.json", name, ds.Uid)), out, ds.Updated)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+var _ Job = new(exportJob)
+
+// exportJob walks every org and writes its entities to an exportTarget
+type exportJob struct {
+	logger log.Logger
+
+	sql         *sqlstore.SQLStore
+	target      exportTarget
+	statusMu    sync.Mutex
+	status      ExportStatus
+	cfg         ExportConfig
+	broadcaster statusBroadcaster
+	lastBroad   time.Time
+}
+
+func startExportJob(cfg ExportConfig, sql *sqlstore.SQLStore, root string, broadcaster statusBroadcaster) (Job, error) {
+	target, err := newExportTarget(cfg.Format, root)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &exportJob{
+		logger:      log.New("export_job"),
+		sql:         sql,
+		target:      target,
+		cfg:         cfg,
+		broadcaster: broadcaster,
+		status: ExportStatus{
+			Running: true,
+			Target:  fmt.Sprintf("%s export: %s", cfg.Format, target.description()),
+			Started: time.Now().UnixMilli(),
+			Current: 0,
+		},
+	}
+
+	broadcaster(job.status)
+	go job.start()
+	return job, nil
+}
+
+func (e *exportJob) start() {
+	defer func() {
+		e.logger.Info("Finished export job")
+
+		e.statusMu.Lock()
+		defer e.statusMu.Unlock()
+		s := e.status
+		if err := recover(); err != nil {
+			e.logger.Error("export panic", "error", err)
+			s.Status = fmt.Sprintf("ERROR: %v", err)
+		}
+		// Make sure it finishes OK
+		if s.Finished < 10 {
+			s.Finished = time.Now().UnixMilli()
+		}
+		s.Running = false
+		if s.Status == "" {
+			s.Status = "done"
+		}
+		e.status = s
+		e.broadcaster(s)
+	}()
+
+	e.logger.Info("Starting export job", "target", e.target.description())
+
+	err := e.doExport(context.Background())
+	if closeErr := e.target.close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		e.logger.Error("export failed", "error", err)
+		e.statusMu.Lock()
+		e.status.Status = fmt.Sprintf("ERROR: %v", err)
+		e.statusMu.Unlock()
+	}
+}
+
+func (e *exportJob) doExport(ctx context.Context) error {
+	orgs, err := e.getOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	users, err := e.getUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		helper := &orgExporter{
+			job:   e,
+			ctx:   ctx,
+			orgID: org.ID,
+			users: users,
+		}
+		// With multiple orgs each one goes in its own folder
+		if len(orgs) > 1 {
+			helper.orgDir = fmt.Sprintf("org_%d", org.ID)
+		}
+
+		if e.target.keepsHistory() && !e.cfg.Git.ExcludeHistory {
+			if err := helper.exportDashboardHistory(); err != nil {
+				return fmt.Errorf("failed to export dashboard history for org %d: %w", org.ID, err)
+			}
+		}
+
+		for _, exp := range orgExporters {
+			if err := exp.fn(helper); err != nil {
+				return fmt.Errorf("failed to export %s for org %d: %w", exp.name, org.ID, err)
+			}
+			err := e.target.commit(fmt.Sprintf("Export %s (org: %s)", exp.name, org.Name), commitAuthor{}, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type orgInfo struct {
+	ID   int64 `xorm:"id"`
+	Name string
+}
+
+func (e *exportJob) getOrgs(ctx context.Context) ([]orgInfo, error) {
+	var orgs []orgInfo
+	err := e.sql.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Table("org").Cols("id", "name").OrderBy("id ASC").Find(&orgs)
+	})
+	return orgs, err
+}
+
+type userInfo struct {
+	ID    int64 `xorm:"id"`
+	Login string
+	Email string
+	Name  string
+}
+
+func (e *exportJob) getUsers(ctx context.Context) (map[int64]commitAuthor, error) {
+	var users []userInfo
+	err := e.sql.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Table(e.sql.Dialect.Quote("user")).Cols("id", "login", "email", "name").Find(&users)
+	})
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[int64]commitAuthor, len(users))
+	for _, u := range users {
+		name := u.Name
+		if name == "" {
+			name = u.Login
+		}
+		authors[u.ID] = commitAuthor{Name: name, Email: u.Email}
+	}
+	return authors, nil
+}
+
+// Adds to the number of items that will be exported
+func (e *exportJob) addCount(n int) {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+	e.status.Count += int64(n)
+}
+
+// Records an exported item and broadcasts the status at most once a second
+func (e *exportJob) progress(fpath string) {
+	e.statusMu.Lock()
+	now := time.Now()
+	e.status.Changed = now.UnixMilli()
+	e.status.Current++
+	e.status.Last = fpath
+	s := e.status
+	shouldBroadcast := now.Sub(e.lastBroad) > time.Second
+	if shouldBroadcast {
+		e.lastBroad = now
+	}
+	e.statusMu.Unlock()
+
+	if shouldBroadcast {
+		e.broadcaster(s)
+	}
+}
+
+func (e *exportJob) getStatus() ExportStatus {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	return e.status
+}
+
+func (e *exportJob) getConfig() ExportConfig {
+	e.statusMu.Lock()
+	defer e.statusMu.Unlock()
+
+	return e.cfg
+}
+
+// orgExporter exports the entities of a single org
+type orgExporter struct {
+	job    *exportJob
+	ctx    context.Context
+	orgID  int64
+	orgDir string
+	users  map[int64]commitAuthor
+}
+
+func (h *orgExporter) writeJSON(fpath string, v interface{}, modified time.Time) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fpath = path.Join(h.orgDir, fpath)
+	if err := h.job.target.write(fpath, body, modified); err != nil {
+		return err
+	}
+	h.job.progress(fpath)
+	return nil
+}
+
+func (h *orgExporter) withDbSession(callback sqlstore.DBTransactionFunc) error {
+	return h.job.sql.WithDbSession(h.ctx, callback)
+}
+
+// Each entity kind that is exported for every org, in order
+var orgExporters = []struct {
+	name string
+	fn   func(h *orgExporter) error
+}{
+	{name: "dashboards", fn: exportDashboards},
+	{name: "datasources", fn: exportDataSources},
+	{name: "library panels", fn: exportLibraryPanels},
+	{name: "alert rules", fn: exportAlertRules},
+	{name: "preferences", fn: exportPreferences},
+}
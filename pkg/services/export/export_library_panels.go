@@ -0,0 +1,59 @@
+package export
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type libraryPanelInfo struct {
+	ID          int64  `xorm:"id"`
+	UID         string `xorm:"uid"`
+	FolderID    int64  `xorm:"folder_id"`
+	Name        string
+	Kind        int64
+	Type        string
+	Description string
+	Model       []byte
+	Updated     time.Time
+}
+
+func exportLibraryPanels(h *orgExporter) error {
+	var panels []libraryPanelInfo
+	err := h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Table("library_element").
+			Cols("id", "uid", "folder_id", "name", "kind", "type", "description", "model", "updated").
+			Where("org_id = ? AND kind = ?", h.orgID, int64(models.PanelElement)).
+			OrderBy("id ASC").
+			Find(&panels)
+	})
+	if err != nil {
+		return err
+	}
+	h.job.addCount(len(panels))
+
+	for _, p := range panels {
+		model := simplejson.New()
+		if len(p.Model) > 0 {
+			if model, err = simplejson.NewJson(p.Model); err != nil {
+				return fmt.Errorf("invalid library panel model for %s: %w", p.UID, err)
+			}
+		}
+		out := map[string]interface{}{
+			"uid":         p.UID,
+			"name":        p.Name,
+			"type":        p.Type,
+			"description": p.Description,
+			"folderId":    p.FolderID,
+			"model":       model,
+		}
+		if err := h.writeJSON(path.Join("library-panels", p.UID+".json"), out, p.Updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+type preferenceInfo struct {
+	UserID          int64 `xorm:"user_id"`
+	TeamID          int64 `xorm:"team_id"`
+	HomeDashboardID int64 `xorm:"home_dashboard_id"`
+	Timezone        string
+	WeekStart       string
+	Theme           string
+	Updated         time.Time
+	JSONData        string `xorm:"json_data"`
+}
+
+// Preferences are saved for the org, and for each team and user that has any
+func exportPreferences(h *orgExporter) error {
+	var prefs []preferenceInfo
+	err := h.withDbSession(func(sess *sqlstore.DBSession) error {
+		return sess.Table("preferences").
+			Cols("user_id", "team_id", "home_dashboard_id", "timezone", "week_start", "theme", "updated", "json_data").
+			Where("org_id = ?", h.orgID).
+			OrderBy("id ASC").
+			Find(&prefs)
+	})
+	if err != nil {
+		return err
+	}
+	h.job.addCount(len(prefs))
+
+	for _, p := range prefs {
+		out := map[string]interface{}{
+			"homeDashboardId": p.HomeDashboardID,
+			"timezone":        p.Timezone,
+			"weekStart":       p.WeekStart,
+			"theme":           p.Theme,
+		}
+		if p.JSONData != "" {
+			if json.Valid([]byte(p.JSONData)) {
+				out["jsonData"] = json.RawMessage(p.JSONData)
+			} else {
+				// keep the broken value as a string so one bad row does not abort the whole export
+				h.job.logger.Warn("invalid preferences json data", "orgId", h.orgID, "userId", p.UserID, "teamId", p.TeamID)
+				out["jsonData"] = p.JSONData
+			}
+		}
+
+		fpath := path.Join("preferences", "org.json")
+		switch {
+		case p.UserID > 0:
+			fpath = path.Join("preferences", "users", fmt.Sprintf("%d.json", p.UserID))
+		case p.TeamID > 0:
+			fpath = path.Join("preferences", "teams", fmt.Sprintf("%d.json", p.TeamID))
+		}
+		if err := h.writeJSON(fpath, out, p.Updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
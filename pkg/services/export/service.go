@@ -2,8 +2,11 @@ package export
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -11,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type ExportService interface {
@@ -26,12 +30,14 @@ type StandardExport struct {
 	sql    *sqlstore.SQLStore
 	glive  *live.GrafanaLive
 	mutex  sync.Mutex
+	// exports are written below this folder
+	dataDir string
 
 	// updated with mutex
 	exportJob Job
 }
 
-func ProvideService(sql *sqlstore.SQLStore, features featuremgmt.FeatureToggles, gl *live.GrafanaLive) ExportService {
+func ProvideService(sql *sqlstore.SQLStore, features featuremgmt.FeatureToggles, gl *live.GrafanaLive, cfg *setting.Cfg) ExportService {
 	if !features.IsEnabled(featuremgmt.FlagExport) {
 		return &StubExport{}
 	}
@@ -41,6 +47,7 @@ func ProvideService(sql *sqlstore.SQLStore, features featuremgmt.FeatureToggles,
 		glive:     gl,
 		logger:    log.New("export_service"),
 		exportJob: &stoppedJob{},
+		dataDir:   filepath.Join(cfg.DataPath, "export"),
 	}
 }
 
@@ -67,9 +74,16 @@ func (ex *StandardExport) HandleRequestExport(c *models.ReqContext) response.Res
 		return response.Error(http.StatusLocked, "export already running", nil)
 	}
 
-	job, err := startDummyExportJob(cfg, func(s ExportStatus) {
+	broadcast := func(s ExportStatus) {
 		ex.broadcastStatus(c.OrgId, s)
-	})
+	}
+	var job Job
+	if cfg.Format == "dummy" {
+		job, err = startDummyExportJob(cfg, broadcast)
+	} else {
+		root := filepath.Join(ex.dataDir, fmt.Sprintf("%s-%s", cfg.Format, time.Now().Format("20060102-150405")))
+		job, err = startExportJob(cfg, ex.sql, root, broadcast)
+	}
 	if err != nil {
 		ex.logger.Error("failed to start export job", "err", err)
 		return response.Error(http.StatusBadRequest, "failed to start export job", err)
@@ -0,0 +1,255 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Who made a change that is committed to a target with history
+type commitAuthor struct {
+	Name  string
+	Email string
+}
+
+// exportTarget receives the exported files
+type exportTarget interface {
+	// write adds or replaces the file at the slash separated path relative to the export root
+	write(fpath string, body []byte, modified time.Time) error
+
+	// commit records everything written since the previous commit.  Targets without history ignore it
+	commit(msg string, author commitAuthor, when time.Time) error
+
+	// keepsHistory is true when the target can record each version of a file
+	keepsHistory() bool
+
+	// close finishes the export and releases any resources
+	close() error
+
+	// description of where the export is going (no secrets)
+	description() string
+}
+
+func newExportTarget(format string, root string) (exportTarget, error) {
+	switch format {
+	case FormatDir:
+		return newDirTarget(root)
+	case FormatTar:
+		return newTarTarget(root + ".tar.gz")
+	case FormatGit:
+		return newGitTarget(root)
+	}
+	return nil, fmt.Errorf("unsupported export format: %q (expected %s, %s, or %s)", format, FormatDir, FormatTar, FormatGit)
+}
+
+// cleanExportPath makes sure the path can not escape the export root
+func cleanExportPath(fpath string) (string, error) {
+	clean := path.Clean("/" + fpath)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid export path: %q", fpath)
+	}
+	return strings.TrimPrefix(clean, "/"), nil
+}
+
+var _ exportTarget = new(dirTarget)
+
+// dirTarget writes every file to a directory tree on disk
+type dirTarget struct {
+	root string
+}
+
+func newDirTarget(root string) (*dirTarget, error) {
+	if err := os.MkdirAll(root, 0750); err != nil {
+		return nil, err
+	}
+	return &dirTarget{root: root}, nil
+}
+
+func (t *dirTarget) write(fpath string, body []byte, modified time.Time) error {
+	clean, err := cleanExportPath(fpath)
+	if err != nil {
+		return err
+	}
+	fullPath := filepath.Join(t.root, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, body, 0600); err != nil {
+		return err
+	}
+	if !modified.IsZero() {
+		return os.Chtimes(fullPath, modified, modified)
+	}
+	return nil
+}
+
+func (t *dirTarget) commit(msg string, author commitAuthor, when time.Time) error {
+	return nil
+}
+
+func (t *dirTarget) keepsHistory() bool {
+	return false
+}
+
+func (t *dirTarget) close() error {
+	return nil
+}
+
+func (t *dirTarget) description() string {
+	return t.root
+}
+
+var _ exportTarget = new(tarTarget)
+
+// tarTarget writes every file to a gzipped tarball
+type tarTarget struct {
+	fpath string
+	file  *os.File
+	gz    *gzip.Writer
+	tw    *tar.Writer
+}
+
+func newTarTarget(fpath string) (*tarTarget, error) {
+	if err := os.MkdirAll(filepath.Dir(fpath), 0750); err != nil {
+		return nil, err
+	}
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from the data path by the export service
+	file, err := os.OpenFile(fpath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	return &tarTarget{
+		fpath: fpath,
+		file:  file,
+		gz:    gz,
+		tw:    tar.NewWriter(gz),
+	}, nil
+}
+
+func (t *tarTarget) write(fpath string, body []byte, modified time.Time) error {
+	clean, err := cleanExportPath(fpath)
+	if err != nil {
+		return err
+	}
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	err = t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     clean,
+		Mode:     0600,
+		Size:     int64(len(body)),
+		ModTime:  modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.tw.Write(body)
+	return err
+}
+
+func (t *tarTarget) commit(msg string, author commitAuthor, when time.Time) error {
+	return nil
+}
+
+func (t *tarTarget) keepsHistory() bool {
+	return false
+}
+
+func (t *tarTarget) close() error {
+	if err := t.tw.Close(); err != nil {
+		_ = t.file.Close()
+		return err
+	}
+	if err := t.gz.Close(); err != nil {
+		_ = t.file.Close()
+		return err
+	}
+	return t.file.Close()
+}
+
+func (t *tarTarget) description() string {
+	return t.fpath
+}
+
+var _ exportTarget = new(gitTarget)
+
+// gitTarget writes every file to a directory tree that is a local git repository.
+// Each commit becomes a git commit with the given author and time
+type gitTarget struct {
+	dirTarget
+}
+
+func newGitTarget(root string) (*gitTarget, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git export requires the git executable: %w", err)
+	}
+	dir, err := newDirTarget(root)
+	if err != nil {
+		return nil, err
+	}
+	t := &gitTarget{dirTarget: *dir}
+	if _, err := os.Stat(filepath.Join(root, ".git")); os.IsNotExist(err) {
+		if _, err := t.git(nil, "init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *gitTarget) git(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", t.root}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func (t *gitTarget) commit(msg string, author commitAuthor, when time.Time) error {
+	if _, err := t.git(nil, "add", "--all"); err != nil {
+		return err
+	}
+	status, err := t.git(nil, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil // nothing changed
+	}
+	if author.Name == "" {
+		author.Name = "grafana"
+	}
+	if author.Email == "" {
+		author.Email = "grafana@localhost"
+	}
+	if when.IsZero() {
+		when = time.Now()
+	}
+	date := when.Format(time.RFC3339)
+	_, err = t.git([]string{
+		"GIT_AUTHOR_NAME=" + author.Name,
+		"GIT_AUTHOR_EMAIL=" + author.Email,
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + author.Name,
+		"GIT_COMMITTER_EMAIL=" + author.Email,
+		"GIT_COMMITTER_DATE=" + date,
+	}, "commit", "--quiet", "--no-verify", "--no-gpg-sign", "-m", msg)
+	return err
+}
+
+func (t *gitTarget) keepsHistory() bool {
+	return true
+}
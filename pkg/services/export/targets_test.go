@@ -0,0 +1,115 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanExportPath(t *testing.T) {
+	for in, expected := range map[string]string{
+		"a/b.json":         "a/b.json",
+		"/a/b.json":        "a/b.json",
+		"../../etc/passwd": "etc/passwd",
+		"a/../../b.json":   "b.json",
+	} {
+		out, err := cleanExportPath(in)
+		require.NoError(t, err)
+		require.Equal(t, expected, out, in)
+	}
+
+	_, err := cleanExportPath("..")
+	require.Error(t, err)
+}
+
+func TestDirTarget(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "export")
+	target, err := newExportTarget(FormatDir, root)
+	require.NoError(t, err)
+	require.False(t, target.keepsHistory())
+
+	modified := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, target.write("dashboards/general/a.json", []byte(`{}`), modified))
+	require.NoError(t, target.commit("ignored", commitAuthor{}, modified))
+	require.NoError(t, target.close())
+
+	fpath := filepath.Join(root, "dashboards", "general", "a.json")
+	body, err := os.ReadFile(fpath)
+	require.NoError(t, err)
+	require.Equal(t, `{}`, string(body))
+
+	info, err := os.Stat(fpath)
+	require.NoError(t, err)
+	require.True(t, modified.Equal(info.ModTime()))
+}
+
+func TestTarTarget(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "export")
+	target, err := newExportTarget(FormatTar, root)
+	require.NoError(t, err)
+	require.Equal(t, root+".tar.gz", target.description())
+
+	require.NoError(t, target.write("a.json", []byte(`{"a":1}`), time.Time{}))
+	require.NoError(t, target.write("b/c.json", []byte(`{"c":1}`), time.Time{}))
+	require.NoError(t, target.close())
+
+	f, err := os.Open(root + ".tar.gz")
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(body)
+	}
+	require.Equal(t, map[string]string{
+		"a.json":   `{"a":1}`,
+		"b/c.json": `{"c":1}`,
+	}, files)
+}
+
+func TestGitTarget(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := filepath.Join(t.TempDir(), "export")
+	target, err := newExportTarget(FormatGit, root)
+	require.NoError(t, err)
+	require.True(t, target.keepsHistory())
+
+	author := commitAuthor{Name: "Some One", Email: "someone@example.com"}
+	when := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, target.write("a.json", []byte(`{"v":1}`), when))
+	require.NoError(t, target.commit("first", author, when))
+	require.NoError(t, target.write("a.json", []byte(`{"v":2}`), when.Add(time.Hour)))
+	require.NoError(t, target.commit("second", author, when.Add(time.Hour)))
+	// nothing changed, so no commit
+	require.NoError(t, target.commit("empty", author, when.Add(2*time.Hour)))
+	require.NoError(t, target.close())
+
+	out, err := exec.Command("git", "-C", root, "log", "--format=%s|%an|%ae|%at").Output()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("second|Some One|someone@example.com|%d", when.Add(time.Hour).Unix()),
+		fmt.Sprintf("first|Some One|someone@example.com|%d", when.Unix()),
+	}, strings.Split(strings.TrimSpace(string(out)), "\n"))
+}
@@ -13,6 +13,16 @@ type ExportStatus struct {
 	Status   string `json:"status"` // ERROR, SUCCESS, ETC
 }
 
+// Export formats
+const (
+	// FormatDir writes a directory tree
+	FormatDir = "dir"
+	// FormatTar writes a gzipped tarball
+	FormatTar = "tar"
+	// FormatGit writes a local git repository, optionally with a commit for each dashboard version
+	FormatGit = "git"
+)
+
 // Basic export config (for now)
 type ExportConfig struct {
 	Format string          `json:"format"`
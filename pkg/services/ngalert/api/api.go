@@ -13,12 +13,14 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -70,16 +72,23 @@ type API struct {
 	ProvenanceStore      provisioning.ProvisioningStore
 	RuleStore            store.RuleStore
 	InstanceStore        store.InstanceStore
+	StateHistoryStore    store.StateHistoryStore
+	DeliveryStore        store.NotificationDeliveryStore
+	ImageStore           store.ImageStore
 	AlertingStore        AlertingStore
 	AdminConfigStore     store.AdminConfigurationStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
+	Ring                 *sharding.Ring
 	SecretsService       secrets.Service
 	AccessControl        accesscontrol.AccessControl
 	Policies             *provisioning.NotificationPolicyService
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
+	MuteTimings          *provisioning.MuteTimingService
+	AlertRules           *provisioning.AlertRuleService
+	MaintenanceWindows   *provisioning.MaintenanceWindowService
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -99,7 +108,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
 		api.DatasourceCache,
 		NewLotexProm(proxy, logger),
-		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, ac: api.AccessControl},
+		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, ac: api.AccessControl, ring: api.Ring},
 	), m)
 	// Register endpoints for proxying to Cortex Ruler-compatible backends.
 	api.RegisterRulerApiEndpoints(NewForkedRuler(
@@ -117,6 +126,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			ac:              api.AccessControl,
 		},
 	), m)
+	evaluator := eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService)
+	appURL, err := url.Parse(api.Cfg.AppURL)
+	if err != nil {
+		logger.Error("Failed to parse application URL. Continue without it.", "error", err)
+		appURL = nil
+	}
 	api.RegisterTestingApiEndpoints(NewForkedTestingApi(
 		&TestingApiSrv{
 			AlertingProxy:     proxy,
@@ -124,8 +139,25 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			DatasourceCache:   api.DatasourceCache,
 			log:               logger,
 			accessControl:     api.AccessControl,
-			evaluator:         eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService),
+			evaluator:         evaluator,
+			backtesting:       backtesting.NewEngine(evaluator, api.ExpressionService, appURL, log.New("ngalert.backtesting")),
+			cfg:               &api.Cfg.UnifiedAlerting,
+		}), m)
+	api.RegisterHistoryApiEndpoints(NewForkedHistoryApi(
+		&HistorySrv{
+			log:          logger,
+			ruleStore:    api.RuleStore,
+			historyStore: api.StateHistoryStore,
+		}), m)
+	api.RegisterDeliveryApiEndpoints(NewForkedDeliveryApi(
+		&DeliverySrv{
+			log:           logger,
+			deliveryStore: api.DeliveryStore,
 		}), m)
+	api.RegisterImageEndpoints(ImageSrv{
+		log:        logger,
+		imageStore: api.ImageStore,
+	})
 	api.RegisterConfigurationApiEndpoints(NewForkedConfiguration(
 		&AdminSrv{
 			store:     api.AdminConfigStore,
@@ -140,6 +172,11 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			policies:            api.Policies,
 			contactPointService: api.ContactPointService,
 			templates:           api.Templates,
+			muteTimings:         api.MuteTimings,
+			alertRules:          api.AlertRules,
+			maintenanceWindows:  api.MaintenanceWindows,
+			namespaces:          api.RuleStore,
+			ac:                  api.AccessControl,
 		}), m)
 	}
 }
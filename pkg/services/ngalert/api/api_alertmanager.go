@@ -235,6 +235,9 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 	if errors.Is(err, notifier.ErrAlertmanagerNotReady) {
 		return response.Error(http.StatusConflict, err.Error(), err)
 	}
+	if errors.Is(err, notifier.ErrProvisionedObjectModified) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
 
 	return ErrResp(http.StatusInternalServerError, err, "")
 }
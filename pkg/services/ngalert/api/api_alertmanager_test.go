@@ -265,6 +265,39 @@ func TestAlertmanagerConfig(t *testing.T) {
 
 			require.Equal(t, ngmodels.ProvenanceAPI, body.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].Provenance)
 		})
+		t.Run("changing a provisioned route is rejected", func(t *testing.T) {
+			sut := createSut(t, nil)
+			rc := createRequestCtxInOrg(1)
+			setRouteProvenance(t, 1, sut.mam.ProvStore)
+			request := createAmConfigRequest(t)
+			request.AlertmanagerConfig.Route.GroupByStr = []string{"changed"}
+
+			response := sut.RoutePostAlertingConfig(rc, request)
+
+			require.Equal(t, http.StatusConflict, response.Status())
+		})
+		t.Run("keeping a provisioned route unchanged is accepted", func(t *testing.T) {
+			sut := createSut(t, nil)
+			rc := createRequestCtxInOrg(1)
+			setRouteProvenance(t, 1, sut.mam.ProvStore)
+			request := createAmConfigRequest(t)
+
+			response := sut.RoutePostAlertingConfig(rc, request)
+
+			require.Equal(t, http.StatusAccepted, response.Status())
+		})
+		t.Run("removing a provisioned contact point is rejected", func(t *testing.T) {
+			sut := createSut(t, nil)
+			rc := createRequestCtxInOrg(1)
+			request := createAmConfigRequest(t)
+			_ = sut.RoutePostAlertingConfig(rc, request)
+			body := asGettableUserConfig(t, sut.RouteGetAlertingConfig(rc))
+			setContactPointProvenance(t, 1, body.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].UID, sut.mam.ProvStore)
+
+			response := sut.RoutePostAlertingConfig(rc, createAmConfigRequest(t))
+
+			require.Equal(t, http.StatusConflict, response.Status())
+		})
 	})
 }
 
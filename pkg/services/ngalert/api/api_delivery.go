@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// defaultNotificationDeliveriesLimit is the number of attempts returned if the request has no limit.
+	defaultNotificationDeliveriesLimit = 1000
+	// maxNotificationDeliveriesLimit is the maximum number of attempts that can be requested.
+	maxNotificationDeliveriesLimit = 5000
+)
+
+// fingerprintRegexp matches the fingerprint of an alert, the hexadecimal representation of a 64 bit hash.
+var fingerprintRegexp = regexp.MustCompile(`^[0-9a-f]{1,16}$`)
+
+type DeliverySrv struct {
+	log           log.Logger
+	deliveryStore store.NotificationDeliveryStore
+}
+
+func (srv DeliverySrv) RouteGetNotificationDeliveries(c *models.ReqContext) response.Response {
+	query := ngmodels.GetNotificationDeliveriesQuery{
+		OrgID:       c.OrgId,
+		Receiver:    c.Query("receiver"),
+		Integration: c.Query("integration"),
+		Fingerprint: strings.ToLower(c.Query("fingerprint")),
+	}
+
+	if query.Fingerprint != "" && !fingerprintRegexp.MatchString(query.Fingerprint) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("fingerprint must be a hexadecimal string of up to 16 characters, got %q", c.Query("fingerprint")), "invalid fingerprint")
+	}
+
+	switch status := ngmodels.NotificationDeliveryStatus(c.Query("status")); status {
+	case "", ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure:
+		query.Status = status
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("status must be %q or %q, got %q", ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure, status), "invalid status")
+	}
+
+	var err error
+	if query.From, err = getTimeFromRequest(c, "from"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid from")
+	}
+	if query.To, err = getTimeFromRequest(c, "to"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid to")
+	}
+	query.Limit = defaultNotificationDeliveriesLimit
+	if s := c.Query("limit"); s != "" {
+		if query.Limit, err = strconv.Atoi(s); err != nil || query.Limit <= 0 || query.Limit > maxNotificationDeliveriesLimit {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be an integer between 1 and %d, got %q", maxNotificationDeliveriesLimit, s), "invalid limit")
+		}
+	}
+
+	if err := srv.deliveryStore.GetNotificationDeliveries(c.Req.Context(), &query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification deliveries")
+	}
+
+	result := make(apimodels.NotificationDeliveries, 0, len(query.Result))
+	for _, delivery := range query.Result {
+		result = append(result, apimodels.NotificationDelivery{
+			Time:           delivery.AttemptedAt,
+			Receiver:       delivery.Receiver,
+			Integration:    delivery.Integration,
+			IntegrationUID: delivery.IntegrationUID,
+			Fingerprints:   delivery.Fingerprints,
+			Status:         string(delivery.Status),
+			StatusCode:     delivery.StatusCode,
+			Error:          delivery.Error,
+			LatencyMs:      delivery.Latency.Milliseconds(),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
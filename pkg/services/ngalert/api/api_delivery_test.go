@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetNotificationDeliveries(t *testing.T) {
+	orgID := int64(1)
+	attemptedAt := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	deliveryStore := &store.FakeNotificationDeliveryStore{}
+	srv := DeliverySrv{
+		log:           log.NewNopLogger(),
+		deliveryStore: deliveryStore,
+	}
+	for _, d := range []*ngmodels.NotificationDelivery{
+		{OrgID: orgID, Receiver: "ops", Integration: "slack", IntegrationUID: "slack-uid", Fingerprints: []string{"a1b2"}, Status: ngmodels.NotificationDeliverySuccess, Latency: 120 * time.Millisecond, AttemptedAt: attemptedAt},
+		{OrgID: orgID, Receiver: "ops", Integration: "webhook", IntegrationUID: "webhook-uid", Fingerprints: []string{"a1b2"}, Status: ngmodels.NotificationDeliveryFailure, StatusCode: 503, Error: "Webhook response status 503 Service Unavailable", Latency: time.Second, AttemptedAt: attemptedAt.Add(time.Minute)},
+		{OrgID: orgID + 1, Receiver: "ops", Integration: "slack", Status: ngmodels.NotificationDeliverySuccess, AttemptedAt: attemptedAt},
+	} {
+		require.NoError(t, deliveryStore.SaveNotificationDelivery(context.Background(), d))
+	}
+
+	createRequest := func(t *testing.T, query string) *models.ReqContext {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/api/v1/notifications/deliveries?"+query, nil)
+		require.NoError(t, err)
+		return &models.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models.SignedInUser{OrgId: orgID}, IsSignedIn: true}
+	}
+
+	t.Run("should return attempts of the organization, newest first", func(t *testing.T) {
+		resp := srv.RouteGetNotificationDeliveries(createRequest(t, ""))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.NotificationDeliveries
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result, 2)
+		require.Equal(t, apimodels.NotificationDelivery{
+			Time:           attemptedAt.Add(time.Minute),
+			Receiver:       "ops",
+			Integration:    "webhook",
+			IntegrationUID: "webhook-uid",
+			Fingerprints:   []string{"a1b2"},
+			Status:         "failure",
+			StatusCode:     503,
+			Error:          "Webhook response status 503 Service Unavailable",
+			LatencyMs:      1000,
+		}, result[0])
+		require.Equal(t, "slack", result[1].Integration)
+	})
+
+	t.Run("should filter by status", func(t *testing.T) {
+		resp := srv.RouteGetNotificationDeliveries(createRequest(t, "status=success"))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.NotificationDeliveries
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result, 1)
+		require.Equal(t, "slack", result[0].Integration)
+	})
+
+	t.Run("should filter by fingerprint", func(t *testing.T) {
+		resp := srv.RouteGetNotificationDeliveries(createRequest(t, "fingerprint=A1B2&limit=1"))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.NotificationDeliveries
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result, 1)
+		require.Equal(t, "webhook", result[0].Integration)
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"status=pending", "from=yesterday", "limit=-1", "limit=0", "limit=5001", "fingerprint=%25", "fingerprint=a1b2%22", "fingerprint=0123456789abcdef0"} {
+			resp := srv.RouteGetNotificationDeliveries(createRequest(t, query))
+			require.Equal(t, http.StatusBadRequest, resp.Status(), query)
+		}
+	})
+}
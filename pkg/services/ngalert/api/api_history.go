@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// defaultStateHistoryLimit is the number of transitions returned if the request has no limit.
+	defaultStateHistoryLimit = 1000
+	// maxStateHistoryLimit is the maximum number of transitions that can be requested.
+	maxStateHistoryLimit = 5000
+)
+
+type HistorySrv struct {
+	log          log.Logger
+	ruleStore    store.RuleStore
+	historyStore store.StateHistoryStore
+}
+
+func (srv HistorySrv) RouteGetStateHistory(c *models.ReqContext) response.Response {
+	query := ngmodels.GetAlertStateHistoryQuery{
+		OrgID: c.OrgId,
+	}
+
+	for _, s := range c.QueryStrings("matcher") {
+		matcher, err := labels.ParseMatcher(s)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid matcher")
+		}
+		query.Matchers = append(query.Matchers, matcher)
+	}
+
+	var err error
+	if query.From, err = getTimeFromRequest(c, "from"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid from")
+	}
+	if query.To, err = getTimeFromRequest(c, "to"); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid to")
+	}
+	query.Limit = defaultStateHistoryLimit
+	if s := c.Query("limit"); s != "" {
+		if query.Limit, err = strconv.Atoi(s); err != nil || query.Limit <= 0 || query.Limit > maxStateHistoryLimit {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be an integer between 1 and %d, got %q", maxStateHistoryLimit, s), "invalid limit")
+		}
+	}
+
+	// the history is restricted to the rules in the folders the user has access to
+	ruleUIDs, err := srv.visibleRuleUIDs(c)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rules visible to the user")
+	}
+	if ruleUID := c.Query("ruleUID"); ruleUID != "" {
+		if _, ok := ruleUIDs[ruleUID]; !ok {
+			return ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+		}
+		query.RuleUIDs = []string{ruleUID}
+	} else {
+		if len(ruleUIDs) == 0 {
+			return response.JSON(http.StatusOK, apimodels.StateHistory{newStateHistoryFrame(nil)})
+		}
+		for uid := range ruleUIDs {
+			query.RuleUIDs = append(query.RuleUIDs, uid)
+		}
+	}
+
+	if err := srv.historyStore.GetAlertStateHistory(c.Req.Context(), &query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get state history")
+	}
+	return response.JSON(http.StatusOK, apimodels.StateHistory{newStateHistoryFrame(query.Result)})
+}
+
+func (srv HistorySrv) visibleRuleUIDs(c *models.ReqContext) (map[string]struct{}, error) {
+	namespaceMap, err := srv.ruleStore.GetUserVisibleNamespaces(c.Req.Context(), c.OrgId, c.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]struct{})
+	if len(namespaceMap) == 0 {
+		return result, nil
+	}
+	namespaceUIDs := make([]string, 0, len(namespaceMap))
+	for k := range namespaceMap {
+		namespaceUIDs = append(namespaceUIDs, k)
+	}
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.OrgId,
+		NamespaceUIDs: namespaceUIDs,
+	}
+	if err := srv.ruleStore.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return nil, err
+	}
+	for _, rule := range q.Result {
+		result[rule.UID] = struct{}{}
+	}
+	return result, nil
+}
+
+func getTimeFromRequest(c *models.ReqContext, name string) (time.Time, error) {
+	s := c.Query(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("must be a unix timestamp in seconds")
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// newStateHistoryFrame returns a frame with one row per state transition.
+// Labels and evaluation values are encoded as JSON.
+func newStateHistoryFrame(entries []*ngmodels.AlertStateHistoryEntry) *data.Frame {
+	times := make([]time.Time, 0, len(entries))
+	ruleUIDs := make([]string, 0, len(entries))
+	lbs := make([]string, 0, len(entries))
+	previous := make([]string, 0, len(entries))
+	current := make([]string, 0, len(entries))
+	reasons := make([]string, 0, len(entries))
+	values := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// a map of strings is always marshalled successfully
+		labelsJSON, _ := json.Marshal(entry.Labels)
+		times = append(times, entry.EvaluatedAt)
+		ruleUIDs = append(ruleUIDs, entry.RuleUID)
+		lbs = append(lbs, string(labelsJSON))
+		previous = append(previous, string(entry.PreviousState))
+		current = append(current, string(entry.CurrentState))
+		reasons = append(reasons, entry.Reason)
+		values = append(values, entry.EvalValues)
+	}
+	return data.NewFrame("state_history",
+		data.NewField("time", nil, times),
+		data.NewField("ruleUID", nil, ruleUIDs),
+		data.NewField("labels", nil, lbs),
+		data.NewField("previous", nil, previous),
+		data.NewField("current", nil, current),
+		data.NewField("reason", nil, reasons),
+		data.NewField("values", nil, values),
+	)
+}
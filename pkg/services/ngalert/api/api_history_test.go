@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetStateHistory(t *testing.T) {
+	orgID := int64(1)
+	evaluatedAt := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*store.FakeRuleStore, *store.FakeStateHistoryStore, HistorySrv) {
+		ruleStore := store.NewFakeRuleStore(t)
+		historyStore := &store.FakeStateHistoryStore{}
+		srv := HistorySrv{
+			log:          log.NewNopLogger(),
+			ruleStore:    ruleStore,
+			historyStore: historyStore,
+		}
+		return ruleStore, historyStore, srv
+	}
+
+	createRequest := func(t *testing.T, query string) *models.ReqContext {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/api/v1/rules/history?"+query, nil)
+		require.NoError(t, err)
+		return &models.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models.SignedInUser{OrgId: orgID}, IsSignedIn: true}
+	}
+
+	entry := func(ruleUID string, lbs ngmodels.InstanceLabels, previous, current ngmodels.InstanceStateType, offset time.Duration) ngmodels.AlertStateHistoryEntry {
+		return ngmodels.AlertStateHistoryEntry{
+			OrgID:         orgID,
+			RuleUID:       ruleUID,
+			Labels:        lbs,
+			PreviousState: previous,
+			CurrentState:  current,
+			EvalValues:    `{"B":1}`,
+			EvaluatedAt:   evaluatedAt.Add(offset),
+		}
+	}
+
+	readFrame := func(t *testing.T, body []byte) *data.Frame {
+		t.Helper()
+		var frames data.Frames
+		require.NoError(t, json.Unmarshal(body, &frames))
+		require.Len(t, frames, 1)
+		return frames[0]
+	}
+
+	t.Run("should return the history of visible rules, newest first", func(t *testing.T) {
+		ruleStore, historyStore, srv := setup(t)
+		rule := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		historyStore.Entries = []ngmodels.AlertStateHistoryEntry{
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStateNormal, ngmodels.InstanceStatePending, 0),
+			entry("invisible", ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStateNormal, ngmodels.InstanceStatePending, time.Minute),
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStatePending, ngmodels.InstanceStateFiring, 2*time.Minute),
+		}
+
+		r := srv.RouteGetStateHistory(createRequest(t, ""))
+		require.Equal(t, http.StatusOK, r.Status())
+
+		frame := readFrame(t, r.Body())
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, evaluatedAt.Add(2*time.Minute), frame.Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, rule.UID, frame.Fields[1].At(0))
+		require.JSONEq(t, `{"job":"a"}`, frame.Fields[2].At(0).(string))
+		require.Equal(t, string(ngmodels.InstanceStatePending), frame.Fields[3].At(0))
+		require.Equal(t, string(ngmodels.InstanceStateFiring), frame.Fields[4].At(0))
+		require.Equal(t, `{"B":1}`, frame.Fields[6].At(0))
+	})
+
+	t.Run("should filter by label matchers, time range and limit", func(t *testing.T) {
+		ruleStore, historyStore, srv := setup(t)
+		rule := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		historyStore.Entries = []ngmodels.AlertStateHistoryEntry{
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStateNormal, ngmodels.InstanceStateFiring, 0),
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "b"}, ngmodels.InstanceStateNormal, ngmodels.InstanceStateFiring, time.Minute),
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStateFiring, ngmodels.InstanceStateNormal, 2*time.Minute),
+			entry(rule.UID, ngmodels.InstanceLabels{"job": "a"}, ngmodels.InstanceStateNormal, ngmodels.InstanceStateFiring, 3*time.Minute),
+		}
+
+		r := srv.RouteGetStateHistory(createRequest(t, "matcher=job%3D%22a%22&limit=1&to="+strconv.FormatInt(evaluatedAt.Add(2*time.Minute).Unix(), 10)))
+		require.Equal(t, http.StatusOK, r.Status())
+
+		frame := readFrame(t, r.Body())
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, evaluatedAt.Add(2*time.Minute), frame.Fields[0].At(0).(time.Time).UTC())
+	})
+
+	t.Run("should return 404 if the rule is not visible", func(t *testing.T) {
+		_, _, srv := setup(t)
+		r := srv.RouteGetStateHistory(createRequest(t, "ruleUID=unknown"))
+		require.Equal(t, http.StatusNotFound, r.Status())
+	})
+
+	t.Run("should return 400 if a matcher is invalid", func(t *testing.T) {
+		_, _, srv := setup(t)
+		r := srv.RouteGetStateHistory(createRequest(t, "matcher=job"))
+		require.Equal(t, http.StatusBadRequest, r.Status())
+	})
+
+	t.Run("should limit the number of transitions", func(t *testing.T) {
+		ruleStore, historyStore, srv := setup(t)
+		rule := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		for i := 0; i < defaultStateHistoryLimit+1; i++ {
+			historyStore.Entries = append(historyStore.Entries, entry(rule.UID, ngmodels.InstanceLabels{}, ngmodels.InstanceStateNormal, ngmodels.InstanceStateFiring, time.Duration(i)*time.Second))
+		}
+
+		r := srv.RouteGetStateHistory(createRequest(t, ""))
+		require.Equal(t, http.StatusOK, r.Status())
+		require.Equal(t, defaultStateHistoryLimit, readFrame(t, r.Body()).Rows())
+
+		for _, limit := range []string{"0", "-1", strconv.Itoa(maxStateHistoryLimit + 1)} {
+			r := srv.RouteGetStateHistory(createRequest(t, "limit="+limit))
+			require.Equal(t, http.StatusBadRequest, r.Status(), limit)
+		}
+	})
+}
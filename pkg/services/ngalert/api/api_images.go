@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ImageSrv serves the screenshots of alerts that are not uploaded to an external image store.
+type ImageSrv struct {
+	log        log.Logger
+	imageStore store.ImageStore
+}
+
+// RegisterImageEndpoints registers the route of the screenshots. It does not require a signed in user, as the
+// screenshots are linked in notifications. The random token of an image is what grants access to it.
+func (api *API) RegisterImageEndpoints(srv ImageSrv) {
+	api.RouteRegister.Get("/"+image.LocalImagesPath+":token", routing.Wrap(srv.RouteGetImage))
+}
+
+func (srv ImageSrv) RouteGetImage(c *models.ReqContext) response.Response {
+	token := web.Params(c.Req)[":token"]
+	img, err := srv.imageStore.GetImage(c.Req.Context(), token)
+	if errors.Is(err, ngmodels.ErrImageNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get image")
+	}
+	if img.Path == "" {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrImageNotFound, "")
+	}
+	b, err := os.ReadFile(filepath.Clean(img.Path))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrImageNotFound, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to read image")
+	}
+	return response.Respond(http.StatusOK, b).SetHeader("Content-Type", "image/png")
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetImage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "image.png")
+	require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+
+	imageStore := store.NewFakeImageStore()
+	imageStore.Images["token"] = &ngmodels.Image{Token: "token", Path: path}
+	imageStore.Images["removed"] = &ngmodels.Image{Token: "removed", Path: filepath.Join(dir, "removed.png")}
+	imageStore.Images["uploaded"] = &ngmodels.Image{Token: "uploaded", URL: "https://images.example.com/1.png"}
+	srv := ImageSrv{
+		log:        log.NewNopLogger(),
+		imageStore: imageStore,
+	}
+
+	createRequest := func(t *testing.T, token string) *models.ReqContext {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/api/alerting/images/"+token, nil)
+		require.NoError(t, err)
+		req = web.SetURLParams(req, map[string]string{":token": token})
+		return &models.ReqContext{Context: &web.Context{Req: req}}
+	}
+
+	t.Run("should serve the image without a signed in user", func(t *testing.T) {
+		resp := srv.RouteGetImage(createRequest(t, "token"))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, []byte("png"), resp.Body())
+	})
+
+	t.Run("should return 404 if the image does not exist", func(t *testing.T) {
+		for _, token := range []string{"does-not-exist", "removed", "uploaded"} {
+			resp := srv.RouteGetImage(createRequest(t, token))
+			require.Equal(t, http.StatusNotFound, resp.Status(), token)
+		}
+	})
+}
@@ -17,6 +17,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 
@@ -28,6 +29,8 @@ type PrometheusSrv struct {
 	manager state.AlertInstanceManager
 	store   store.RuleStore
 	ac      accesscontrol.AccessControl
+	// ring assigns the rules to the members of the HA cluster, it is nil if the evaluation is not sharded.
+	ring *sharding.Ring
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -58,7 +61,7 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *models.ReqContext) response.Re
 		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, &apimodels.Alert{
 			Labels:      alertState.GetLabels(labelOptions...),
 			Annotations: alertState.Annotations,
-			State:       alertState.State.String(),
+			State:       stateString(alertState),
 			ActiveAt:    &startsAt,
 			Value:       valString,
 		})
@@ -67,6 +70,14 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *models.ReqContext) response.Re
 	return response.JSON(http.StatusOK, alertResponse)
 }
 
+// stateString returns the state of the alert instance as it is shown in the API.
+func stateString(alertState *state.State) string {
+	if alertState.IsSuppressed() {
+		return "Suppressed"
+	}
+	return alertState.State.String()
+}
+
 func formatValues(alertState *state.State) string {
 	var fv string
 	values := alertState.GetLastEvaluationValuesForCondition()
@@ -195,6 +206,17 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 			LastEvaluation: time.Time{},
 		}
 
+		if srv.ring != nil {
+			newRule.Owner = srv.ring.Owner(rule.GetKey())
+		}
+
+		// recording rules do not produce alert instances, they are reported like Prometheus recording rules
+		if rule.IsRecordingRule() {
+			alertingRule.State = ""
+			newRule.Name = rule.Record.Metric
+			newRule.Type = apiv1.RuleTypeRecording
+		}
+
 		for _, alertState := range srv.manager.GetStatesForRuleUID(c.OrgId, rule.UID) {
 			activeAt := alertState.StartsAt
 			valString := ""
@@ -205,7 +227,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 			alert := &apimodels.Alert{
 				Labels:      alertState.GetLabels(labelOptions...),
 				Annotations: alertState.Annotations,
-				State:       alertState.State.String(),
+				State:       stateString(alertState),
 				ActiveAt:    &activeAt,
 				Value:       valString,
 			}
@@ -223,11 +245,15 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 					alertingRule.State = "pending"
 				}
 			case eval.Alerting:
-				alertingRule.State = "firing"
+				if !alertState.IsSuppressed() {
+					alertingRule.State = "firing"
+				}
 			case eval.Error:
 				newRule.Health = "error"
 			case eval.NoData:
 				newRule.Health = "nodata"
+			case eval.Paused:
+				newRule.Health = "paused"
 			}
 
 			if alertState.Error != nil {
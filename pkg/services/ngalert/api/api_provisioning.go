@@ -3,11 +3,14 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -21,6 +24,16 @@ type ProvisioningSrv struct {
 	policies            NotificationPolicyService
 	contactPointService ContactPointService
 	templates           TemplateService
+	muteTimings         MuteTimingService
+	alertRules          AlertRuleService
+	maintenanceWindows  MaintenanceWindowService
+	namespaces          RuleNamespaceStore
+	ac                  accesscontrol.AccessControl
+}
+
+// RuleNamespaceStore returns the folders of alert rules if the user can access them.
+type RuleNamespaceStore interface {
+	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user *models.SignedInUser, withCanSave bool) (*models.Folder, error)
 }
 
 type ContactPointService interface {
@@ -32,6 +45,32 @@ type ContactPointService interface {
 
 type TemplateService interface {
 	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
+	SetTemplate(ctx context.Context, orgID int64, tmpl apimodels.MessageTemplate) (apimodels.MessageTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, name string, p alerting_models.Provenance) error
+}
+
+type MuteTimingService interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]apimodels.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, mt apimodels.MuteTimeInterval, orgID int64) (apimodels.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, mt apimodels.MuteTimeInterval, orgID int64) (apimodels.MuteTimeInterval, error)
+	DeleteMuteTiming(ctx context.Context, name string, orgID int64, p alerting_models.Provenance) error
+}
+
+type AlertRuleService interface {
+	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
+	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, p alerting_models.Provenance) (alerting_models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, p alerting_models.Provenance) (alerting_models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, p alerting_models.Provenance) error
+	GetRuleGroup(ctx context.Context, orgID int64, folderUID, title string) (alerting_models.AlertRuleGroup, map[string]alerting_models.Provenance, error)
+	ReplaceRuleGroup(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, p alerting_models.Provenance) error
+}
+
+type MaintenanceWindowService interface {
+	GetMaintenanceWindows(ctx context.Context, orgID int64) ([]alerting_models.MaintenanceWindow, map[string]alerting_models.Provenance, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (alerting_models.MaintenanceWindow, alerting_models.Provenance, error)
+	CreateMaintenanceWindow(ctx context.Context, window alerting_models.MaintenanceWindow, p alerting_models.Provenance) (alerting_models.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, window alerting_models.MaintenanceWindow, p alerting_models.Provenance) (alerting_models.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, p alerting_models.Provenance) error
 }
 
 type NotificationPolicyService interface {
@@ -60,6 +99,9 @@ func (srv *ProvisioningSrv) RoutePostPolicyTree(c *models.ReqContext, tree apimo
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
+	if errors.Is(err, provisioning.ErrProvenanceMismatch) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
@@ -114,13 +156,321 @@ func (srv *ProvisioningSrv) RouteGetTemplates(c *models.ReqContext) response.Res
 }
 
 func (srv *ProvisioningSrv) RouteGetTemplate(c *models.ReqContext) response.Response {
-	id := web.Params(c.Req)[":ID"]
+	name := web.Params(c.Req)[":name"]
 	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgId)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	if tmpl, ok := templates[id]; ok {
-		return response.JSON(http.StatusOK, apimodels.MessageTemplate{Name: id, Template: tmpl})
+	if tmpl, ok := templates[name]; ok {
+		return response.JSON(http.StatusOK, apimodels.MessageTemplate{Name: name, Template: tmpl})
 	}
 	return response.Empty(http.StatusNotFound)
 }
+
+func (srv *ProvisioningSrv) RoutePutTemplate(c *models.ReqContext, body apimodels.MessageTemplateContent) response.Response {
+	tmpl := apimodels.MessageTemplate{
+		Name:       web.Params(c.Req)[":name"],
+		Template:   body.Template,
+		Provenance: alerting_models.ProvenanceAPI,
+	}
+	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.OrgId, tmpl)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusAccepted, modified)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteTemplate(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	err := srv.templates.DeleteTemplate(c.Req.Context(), c.OrgId, name, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTiming(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	for _, timing := range timings {
+		if name == timing.Name {
+			return response.JSON(http.StatusOK, timing)
+		}
+	}
+	return response.Empty(http.StatusNotFound)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimings(c *models.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, timings)
+}
+
+func (srv *ProvisioningSrv) RoutePostMuteTiming(c *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	mt.Provenance = alerting_models.ProvenanceAPI
+	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.OrgId)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMuteTiming(c *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	mt.Name = web.Params(c.Req)[":name"]
+	mt.Provenance = alerting_models.ProvenanceAPI
+	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.OrgId)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusOK, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.OrgId, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRule(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":UID"]
+	rule, provenance, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	if errResp := srv.authorizeRuleFolder(c, rule.NamespaceUID, accesscontrol.ActionAlertingRuleRead); errResp != nil {
+		return errResp
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRule(rule, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRule(c *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	upstreamModel := ar.UpstreamModel()
+	upstreamModel.OrgID = c.OrgId
+	if errResp := srv.authorizeRuleFolder(c, upstreamModel.NamespaceUID, accesscontrol.ActionAlertingRuleCreate); errResp != nil {
+		return errResp
+	}
+	if errResp := srv.authorizeRuleDatasources(c, &upstreamModel); errResp != nil {
+		return errResp
+	}
+	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusCreated, apimodels.NewAlertRule(createdAlertRule, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRule(c *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	updated := ar.UpstreamModel()
+	updated.OrgID = c.OrgId
+	updated.UID = web.Params(c.Req)[":UID"]
+	existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, updated.UID)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	if errResp := srv.authorizeRuleMove(c, &existing, &updated); errResp != nil {
+		return errResp
+	}
+	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), updated, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRule(updatedAlertRule, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":UID"]
+	existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	if errResp := srv.authorizeRuleFolder(c, existing.NamespaceUID, accesscontrol.ActionAlertingRuleDelete); errResp != nil {
+		return errResp
+	}
+	err = srv.alertRules.DeleteAlertRule(c.Req.Context(), c.OrgId, uid, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *models.ReqContext) response.Response {
+	folderUID := web.Params(c.Req)[":FolderUID"]
+	title := web.Params(c.Req)[":Group"]
+	if errResp := srv.authorizeRuleFolder(c, folderUID, accesscontrol.ActionAlertingRuleRead); errResp != nil {
+		return errResp
+	}
+	group, provenances, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgId, folderUID, title)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRuleGroup(group, provenances))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *models.ReqContext, ag apimodels.AlertRuleGroup) response.Response {
+	ag.FolderUID = web.Params(c.Req)[":FolderUID"]
+	ag.Title = web.Params(c.Req)[":Group"]
+	// replacing the group can create, update and delete rules of the folder
+	if errResp := srv.authorizeRuleFolder(c, ag.FolderUID, accesscontrol.ActionAlertingRuleCreate, accesscontrol.ActionAlertingRuleUpdate, accesscontrol.ActionAlertingRuleDelete); errResp != nil {
+		return errResp
+	}
+	upstreamModel := ag.UpstreamModel()
+	for i := range upstreamModel.Rules {
+		rule := &upstreamModel.Rules[i]
+		rule.NamespaceUID = ag.FolderUID
+		if errResp := srv.authorizeRuleDatasources(c, rule); errResp != nil {
+			return errResp
+		}
+		if rule.UID == "" {
+			continue
+		}
+		// rules can be moved into the group from other folders
+		existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgId, rule.UID)
+		if errors.Is(err, provisioning.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		if errResp := srv.authorizeRuleMove(c, &existing, rule); errResp != nil {
+			return errResp
+		}
+	}
+	err := srv.alertRules.ReplaceRuleGroup(c.Req.Context(), c.OrgId, upstreamModel, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	group, provenances, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgId, ag.FolderUID, ag.Title)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		// the group was replaced by an empty one, which removes it entirely
+		return response.JSON(http.StatusOK, apimodels.AlertRuleGroup{Title: ag.Title, FolderUID: ag.FolderUID, Interval: ag.Interval, Rules: []apimodels.ProvisionedAlertRule{}})
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertRuleGroup(group, provenances))
+}
+
+// authorizeRuleFolder checks that the folder exists and that the user is allowed to perform all actions on the
+// alert rules in it. It returns the error response if not.
+func (srv *ProvisioningSrv) authorizeRuleFolder(c *models.ReqContext, folderUID string, actions ...string) response.Response {
+	readOnly := len(actions) == 1 && actions[0] == accesscontrol.ActionAlertingRuleRead
+	namespace, err := srv.namespaces.GetNamespaceByUID(c.Req.Context(), folderUID, c.OrgId, c.SignedInUser, !readOnly)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(namespace.Uid)
+	evaluators := make([]accesscontrol.Evaluator, 0, len(actions))
+	for _, action := range actions {
+		evaluators = append(evaluators, accesscontrol.EvalPermission(action, scope))
+	}
+	fallback := accesscontrol.ReqOrgAdminOrEditor
+	if readOnly {
+		fallback = accesscontrol.ReqSignedIn
+	}
+	if !accesscontrol.HasAccess(srv.ac, c)(fallback, accesscontrol.EvalAll(evaluators...)) {
+		return ErrResp(http.StatusForbidden, fmt.Errorf("%w to access alert rules in the folder %s", ErrAuthorization, namespace.Title), "")
+	}
+	return nil
+}
+
+// authorizeRuleMove checks that the user can update the rule, or delete it from its folder and create it in the new
+// one if the rule is moved, and that the user can query the data sources of the new rule.
+func (srv *ProvisioningSrv) authorizeRuleMove(c *models.ReqContext, existing *alerting_models.AlertRule, updated *alerting_models.AlertRule) response.Response {
+	if existing.NamespaceUID == updated.NamespaceUID {
+		if errResp := srv.authorizeRuleFolder(c, updated.NamespaceUID, accesscontrol.ActionAlertingRuleUpdate); errResp != nil {
+			return errResp
+		}
+	} else {
+		if errResp := srv.authorizeRuleFolder(c, existing.NamespaceUID, accesscontrol.ActionAlertingRuleDelete); errResp != nil {
+			return errResp
+		}
+		if errResp := srv.authorizeRuleFolder(c, updated.NamespaceUID, accesscontrol.ActionAlertingRuleCreate); errResp != nil {
+			return errResp
+		}
+	}
+	return srv.authorizeRuleDatasources(c, updated)
+}
+
+// authorizeRuleDatasources checks that the user can query all data sources of the rule.
+func (srv *ProvisioningSrv) authorizeRuleDatasources(c *models.ReqContext, rule *alerting_models.AlertRule) response.Response {
+	hasAccess := accesscontrol.HasAccess(srv.ac, c)
+	if !authorizeDatasourceAccessForRule(rule, func(evaluator accesscontrol.Evaluator) bool {
+		return hasAccess(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return ErrResp(http.StatusForbidden, fmt.Errorf("%w to save the alert rule '%s' because the user does not have read permissions for one or many datasources the rule uses", ErrAuthorization, rule.Title), "")
+	}
+	return nil
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindows(c *models.ReqContext) response.Response {
+	windows, provenances, err := srv.maintenanceWindows.GetMaintenanceWindows(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := make(apimodels.MaintenanceWindows, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, apimodels.NewMaintenanceWindow(w, provenances[w.UID]))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindow(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":UID"]
+	window, provenance, err := srv.maintenanceWindows.GetMaintenanceWindow(c.Req.Context(), c.OrgId, uid)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusOK, apimodels.NewMaintenanceWindow(window, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostMaintenanceWindow(c *models.ReqContext, mw apimodels.ProvisionedMaintenanceWindow) response.Response {
+	window := mw.UpstreamModel()
+	window.OrgID = c.OrgId
+	created, err := srv.maintenanceWindows.CreateMaintenanceWindow(c.Req.Context(), window, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusCreated, apimodels.NewMaintenanceWindow(created, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RoutePutMaintenanceWindow(c *models.ReqContext, mw apimodels.ProvisionedMaintenanceWindow) response.Response {
+	window := mw.UpstreamModel()
+	window.OrgID = c.OrgId
+	window.UID = web.Params(c.Req)[":UID"]
+	updated, err := srv.maintenanceWindows.UpdateMaintenanceWindow(c.Req.Context(), window, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusOK, apimodels.NewMaintenanceWindow(updated, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMaintenanceWindow(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":UID"]
+	err := srv.maintenanceWindows.DeleteMaintenanceWindow(c.Req.Context(), c.OrgId, uid, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return provisioningErrResp(err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+// provisioningErrResp maps the errors returned by the provisioning services to HTTP responses.
+func provisioningErrResp(err error) response.Response {
+	switch {
+	case errors.Is(err, provisioning.ErrNotFound), errors.Is(err, store.ErrNoAlertmanagerConfiguration):
+		return ErrResp(http.StatusNotFound, err, "")
+	case errors.Is(err, provisioning.ErrValidation), errors.Is(err, alerting_models.ErrAlertRuleFailedValidation):
+		return ErrResp(http.StatusBadRequest, err, "")
+	case errors.Is(err, provisioning.ErrProvenanceMismatch):
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
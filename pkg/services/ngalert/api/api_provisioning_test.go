@@ -8,6 +8,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	domain "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -18,7 +21,7 @@ import (
 
 func TestProvisioningApi(t *testing.T) {
 	t.Run("successful GET policies returns 200", func(t *testing.T) {
-		sut := createProvisioningSrvSut()
+		sut := createProvisioningSrvSut(t)
 		rc := createTestRequestCtx()
 
 		response := sut.RouteGetPolicyTree(&rc)
@@ -27,7 +30,7 @@ func TestProvisioningApi(t *testing.T) {
 	})
 
 	t.Run("successful POST policies returns 202", func(t *testing.T) {
-		sut := createProvisioningSrvSut()
+		sut := createProvisioningSrvSut(t)
 		rc := createTestRequestCtx()
 		tree := apimodels.Route{}
 
@@ -38,7 +41,7 @@ func TestProvisioningApi(t *testing.T) {
 
 	t.Run("when new policy tree is invalid", func(t *testing.T) {
 		t.Run("POST policies returns 400", func(t *testing.T) {
-			sut := createProvisioningSrvSut()
+			sut := createProvisioningSrvSut(t)
 			sut.policies = &fakeRejectingNotificationPolicyService{}
 			rc := createTestRequestCtx()
 			tree := apimodels.Route{}
@@ -53,7 +56,7 @@ func TestProvisioningApi(t *testing.T) {
 
 	t.Run("when org has no AM config", func(t *testing.T) {
 		t.Run("GET policies returns 404", func(t *testing.T) {
-			sut := createProvisioningSrvSut()
+			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
 			rc.SignedInUser.OrgId = 2
 
@@ -63,7 +66,7 @@ func TestProvisioningApi(t *testing.T) {
 		})
 
 		t.Run("POST policies returns 404", func(t *testing.T) {
-			sut := createProvisioningSrvSut()
+			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
 			rc.SignedInUser.OrgId = 2
 
@@ -75,7 +78,7 @@ func TestProvisioningApi(t *testing.T) {
 
 	t.Run("when an unspecified error occurrs", func(t *testing.T) {
 		t.Run("GET policies returns 500", func(t *testing.T) {
-			sut := createProvisioningSrvSut()
+			sut := createProvisioningSrvSut(t)
 			sut.policies = &fakeFailingNotificationPolicyService{}
 			rc := createTestRequestCtx()
 
@@ -87,7 +90,7 @@ func TestProvisioningApi(t *testing.T) {
 		})
 
 		t.Run("POST policies returns 500", func(t *testing.T) {
-			sut := createProvisioningSrvSut()
+			sut := createProvisioningSrvSut(t)
 			sut.policies = &fakeFailingNotificationPolicyService{}
 			rc := createTestRequestCtx()
 			tree := apimodels.Route{}
@@ -99,12 +102,212 @@ func TestProvisioningApi(t *testing.T) {
 			require.Contains(t, string(response.Body()), "something went wrong")
 		})
 	})
+
+	t.Run("templates", func(t *testing.T) {
+		t.Run("successful PUT returns 202 and records API provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "a"})
+
+			response := sut.RoutePutTemplate(&rc, apimodels.MessageTemplateContent{Template: "content"})
+
+			require.Equal(t, 202, response.Status())
+			tmpl := sut.templates.(*fakeTemplateService).templates["a"]
+			require.Equal(t, "content", tmpl.Template)
+			require.Equal(t, domain.ProvenanceAPI, tmpl.Provenance)
+		})
+
+		t.Run("PUT of an invalid template returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "a"})
+
+			response := sut.RoutePutTemplate(&rc, apimodels.MessageTemplateContent{Template: ""})
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("DELETE of a template provisioned from a file returns 409", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.templates.(*fakeTemplateService).templates["a"] = apimodels.MessageTemplate{Name: "a", Template: "content", Provenance: domain.ProvenanceFile}
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "a"})
+
+			response := sut.RouteDeleteTemplate(&rc)
+
+			require.Equal(t, 409, response.Status())
+		})
+	})
+
+	t.Run("mute timings", func(t *testing.T) {
+		t.Run("successful POST returns 201", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			mt := apimodels.MuteTimeInterval{}
+			mt.Name = "interval"
+
+			response := sut.RoutePostMuteTiming(&rc, mt)
+
+			require.Equal(t, 201, response.Status())
+		})
+
+		t.Run("GET of an unknown mute timing returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "unknown"})
+
+			response := sut.RouteGetMuteTiming(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("PUT of an unknown mute timing returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":name": "unknown"})
+
+			response := sut.RoutePutMuteTiming(&rc, apimodels.MuteTimeInterval{})
+
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("alert rules", func(t *testing.T) {
+		t.Run("successful POST returns 201 with the rule in the org of the user", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := apimodels.ProvisionedAlertRule{UID: "rule", OrgID: 5, Title: "rule", FolderUID: "folder"}
+
+			response := sut.RoutePostAlertRule(&rc, rule)
+
+			require.Equal(t, 201, response.Status())
+			stored := sut.alertRules.(*fakeAlertRuleService).rules["rule"]
+			require.Equal(t, int64(1), stored.OrgID)
+		})
+
+		t.Run("GET of an unknown rule returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "unknown"})
+
+			response := sut.RouteGetAlertRule(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("PUT of a rule provisioned from a file returns 409", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			fakes := sut.alertRules.(*fakeAlertRuleService)
+			fakes.rules["rule"] = domain.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "folder"}
+			fakes.provenances["rule"] = domain.ProvenanceFile
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "rule"})
+
+			response := sut.RoutePutAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "changed", FolderUID: "folder"})
+
+			require.Equal(t, 409, response.Status())
+		})
+
+		t.Run("successful DELETE returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.alertRules.(*fakeAlertRuleService).rules["rule"] = domain.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "folder"}
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "rule"})
+
+			response := sut.RouteDeleteAlertRule(&rc)
+
+			require.Equal(t, 204, response.Status())
+			require.Empty(t, sut.alertRules.(*fakeAlertRuleService).rules)
+		})
+
+		t.Run("PUT rule group uses the folder and group from the path", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":FolderUID": "folder", ":Group": "group"})
+			group := apimodels.AlertRuleGroup{
+				Interval: 120,
+				Rules:    []apimodels.ProvisionedAlertRule{{UID: "rule", Title: "rule"}},
+			}
+
+			response := sut.RoutePutAlertRuleGroup(&rc, group)
+
+			require.Equal(t, 200, response.Status())
+			stored := sut.alertRules.(*fakeAlertRuleService).rules["rule"]
+			require.Equal(t, "folder", stored.NamespaceUID)
+			require.Equal(t, "group", stored.RuleGroup)
+			require.Equal(t, int64(120), stored.IntervalSeconds)
+
+			response = sut.RouteGetAlertRuleGroup(&rc)
+
+			require.Equal(t, 200, response.Status())
+		})
+
+		t.Run("POST to an unknown folder returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "rule", FolderUID: "unknown"})
+
+			require.Equal(t, 404, response.Status())
+			require.Empty(t, sut.alertRules.(*fakeAlertRuleService).rules)
+		})
+
+		t.Run("viewer can read but not change rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.alertRules.(*fakeAlertRuleService).rules["rule"] = domain.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "folder"}
+			rc := createTestRequestCtx()
+			rc.SignedInUser.OrgRole = models.ROLE_VIEWER
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "rule", ":FolderUID": "folder", ":Group": "group"})
+
+			require.Equal(t, 200, sut.RouteGetAlertRule(&rc).Status())
+			require.Equal(t, 403, sut.RoutePostAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "new", FolderUID: "folder"}).Status())
+			require.Equal(t, 403, sut.RoutePutAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "changed", FolderUID: "folder"}).Status())
+			require.Equal(t, 403, sut.RouteDeleteAlertRule(&rc).Status())
+			require.Equal(t, 403, sut.RoutePutAlertRuleGroup(&rc, apimodels.AlertRuleGroup{}).Status())
+			require.Len(t, sut.alertRules.(*fakeAlertRuleService).rules, 1)
+		})
+
+		t.Run("with fine-grained access only rules in permitted folders can be changed", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.ac = acMock.New().WithPermissions([]*ac.Permission{
+				{Action: ac.ActionAlertingRuleRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")},
+				{Action: ac.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")},
+				{Action: ac.ActionAlertingRuleCreate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")},
+			})
+			fakes := sut.alertRules.(*fakeAlertRuleService)
+			fakes.rules["rule"] = domain.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "folder"}
+			fakes.rules["other"] = domain.AlertRule{UID: "other", OrgID: 1, NamespaceUID: "other-folder"}
+			rc := createTestRequestCtx()
+
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "rule"})
+			require.Equal(t, 200, sut.RouteGetAlertRule(&rc).Status())
+			require.Equal(t, 200, sut.RoutePutAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "changed", FolderUID: "folder"}).Status())
+			// moving the rule needs permissions to delete it from its folder and create it in the other folder
+			require.Equal(t, 403, sut.RoutePutAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "moved", FolderUID: "other-folder"}).Status())
+			require.Equal(t, 403, sut.RouteDeleteAlertRule(&rc).Status())
+
+			rc.Req = web.SetURLParams(rc.Req, map[string]string{":UID": "other"})
+			require.Equal(t, 403, sut.RouteGetAlertRule(&rc).Status())
+			require.Equal(t, 403, sut.RoutePutAlertRule(&rc, apimodels.ProvisionedAlertRule{Title: "changed", FolderUID: "other-folder"}).Status())
+			require.Equal(t, "other-folder", fakes.rules["other"].NamespaceUID)
+		})
+	})
 }
 
-func createProvisioningSrvSut() ProvisioningSrv {
+func createProvisioningSrvSut(t *testing.T) ProvisioningSrv {
+	ruleStore := store.NewFakeRuleStore(t)
+	ruleStore.Folders[1] = []*models.Folder{
+		{Id: 1, Uid: "folder", Title: "Folder"},
+		{Id: 2, Uid: "other-folder", Title: "Other folder"},
+	}
 	return ProvisioningSrv{
-		log:      log.NewNopLogger(),
-		policies: newFakeNotificationPolicyService(),
+		log:         log.NewNopLogger(),
+		policies:    newFakeNotificationPolicyService(),
+		templates:   &fakeTemplateService{templates: map[string]apimodels.MessageTemplate{}},
+		muteTimings: &fakeMuteTimingService{},
+		alertRules:  &fakeAlertRuleService{rules: map[string]domain.AlertRule{}, provenances: map[string]domain.Provenance{}},
+		namespaces:  ruleStore,
+		ac:          acMock.New().WithDisabled(),
 	}
 }
 
@@ -113,8 +316,10 @@ func createTestRequestCtx() models.ReqContext {
 		Context: &web.Context{
 			Req: &http.Request{},
 		},
+		IsSignedIn: true,
 		SignedInUser: &models.SignedInUser{
-			OrgId: 1,
+			OrgId:   1,
+			OrgRole: models.ROLE_EDITOR,
 		},
 	}
 }
@@ -170,3 +375,119 @@ func (f *fakeRejectingNotificationPolicyService) GetPolicyTree(ctx context.Conte
 func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree apimodels.Route, p domain.Provenance) error {
 	return fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
 }
+
+type fakeTemplateService struct {
+	templates map[string]apimodels.MessageTemplate
+}
+
+func (f *fakeTemplateService) GetTemplates(ctx context.Context, orgID int64) (map[string]string, error) {
+	result := make(map[string]string, len(f.templates))
+	for name, tmpl := range f.templates {
+		result[name] = tmpl.Template
+	}
+	return result, nil
+}
+
+func (f *fakeTemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl apimodels.MessageTemplate) (apimodels.MessageTemplate, error) {
+	if err := tmpl.Validate(); err != nil {
+		return apimodels.MessageTemplate{}, fmt.Errorf("%w: %s", provisioning.ErrValidation, err.Error())
+	}
+	f.templates[tmpl.Name] = tmpl
+	return tmpl, nil
+}
+
+func (f *fakeTemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, p domain.Provenance) error {
+	if existing, ok := f.templates[name]; ok && existing.Provenance != p {
+		return provisioning.ErrProvenanceMismatch
+	}
+	delete(f.templates, name)
+	return nil
+}
+
+type fakeMuteTimingService struct {
+	timings []apimodels.MuteTimeInterval
+}
+
+func (f *fakeMuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) ([]apimodels.MuteTimeInterval, error) {
+	return f.timings, nil
+}
+
+func (f *fakeMuteTimingService) CreateMuteTiming(ctx context.Context, mt apimodels.MuteTimeInterval, orgID int64) (apimodels.MuteTimeInterval, error) {
+	f.timings = append(f.timings, mt)
+	return mt, nil
+}
+
+func (f *fakeMuteTimingService) UpdateMuteTiming(ctx context.Context, mt apimodels.MuteTimeInterval, orgID int64) (apimodels.MuteTimeInterval, error) {
+	for i := range f.timings {
+		if f.timings[i].Name == mt.Name {
+			f.timings[i] = mt
+			return mt, nil
+		}
+	}
+	return apimodels.MuteTimeInterval{}, provisioning.ErrNotFound
+}
+
+func (f *fakeMuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64, p domain.Provenance) error {
+	return nil
+}
+
+type fakeAlertRuleService struct {
+	rules       map[string]domain.AlertRule
+	provenances map[string]domain.Provenance
+}
+
+func (f *fakeAlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (domain.AlertRule, domain.Provenance, error) {
+	rule, ok := f.rules[ruleUID]
+	if !ok {
+		return domain.AlertRule{}, domain.ProvenanceNone, provisioning.ErrNotFound
+	}
+	return rule, f.provenances[ruleUID], nil
+}
+
+func (f *fakeAlertRuleService) CreateAlertRule(ctx context.Context, rule domain.AlertRule, p domain.Provenance) (domain.AlertRule, error) {
+	f.rules[rule.UID] = rule
+	f.provenances[rule.UID] = p
+	return rule, nil
+}
+
+func (f *fakeAlertRuleService) UpdateAlertRule(ctx context.Context, rule domain.AlertRule, p domain.Provenance) (domain.AlertRule, error) {
+	if _, ok := f.rules[rule.UID]; !ok {
+		return domain.AlertRule{}, provisioning.ErrNotFound
+	}
+	if stored := f.provenances[rule.UID]; stored != domain.ProvenanceNone && stored != p {
+		return domain.AlertRule{}, provisioning.ErrProvenanceMismatch
+	}
+	return f.CreateAlertRule(ctx, rule, p)
+}
+
+func (f *fakeAlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, p domain.Provenance) error {
+	delete(f.rules, ruleUID)
+	delete(f.provenances, ruleUID)
+	return nil
+}
+
+func (f *fakeAlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, folderUID, title string) (domain.AlertRuleGroup, map[string]domain.Provenance, error) {
+	group := domain.AlertRuleGroup{Title: title, FolderUID: folderUID}
+	for _, rule := range f.rules {
+		if rule.NamespaceUID == folderUID && rule.RuleGroup == title {
+			group.Interval = rule.IntervalSeconds
+			group.Rules = append(group.Rules, rule)
+		}
+	}
+	if len(group.Rules) == 0 {
+		return domain.AlertRuleGroup{}, nil, provisioning.ErrNotFound
+	}
+	return group, f.provenances, nil
+}
+
+func (f *fakeAlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group domain.AlertRuleGroup, p domain.Provenance) error {
+	for _, rule := range group.Rules {
+		rule.OrgID = orgID
+		rule.NamespaceUID = group.FolderUID
+		rule.RuleGroup = group.Title
+		rule.IntervalSeconds = group.Interval
+		f.rules[rule.UID] = rule
+		f.provenances[rule.UID] = p
+	}
+	return nil
+}
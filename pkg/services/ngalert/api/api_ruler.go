@@ -41,7 +41,8 @@ type RulerSrv struct {
 }
 
 var (
-	errQuotaReached = errors.New("quota has been exceeded")
+	errQuotaReached        = errors.New("quota has been exceeded")
+	errProvisionedResource = errors.New("request affects provisioned resources")
 )
 
 // RouteDeleteAlertRules deletes all alert rules user is authorized to access in the namespace (request parameter :Namespace)
@@ -100,6 +101,10 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *models.ReqContext) response.Respons
 			logger.Info("user cannot delete one or many alert rules because it does not have access to data sources. Those rules will be skipped", "expected", len(q.Result), "authorized", len(canDelete), "unauthorized", cannotDelete)
 		}
 
+		if err := verifyProvisionedRulesNotAffected(ctx, srv.provenanceStore, c.SignedInUser.OrgId, canDelete); err != nil {
+			return err
+		}
+
 		return srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.OrgId, canDelete...)
 	})
 
@@ -107,6 +112,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *models.ReqContext) response.Respons
 		if errors.Is(err, ErrAuthorization) {
 			return ErrResp(http.StatusUnauthorized, err, "")
 		}
+		if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
 	}
 
@@ -368,6 +376,17 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 			logger.Info("user is not authorized to delete one or many rules in the group. those rules will be skipped", "expected", len(groupChanges.Delete), "authorized", len(authorizedChanges.Delete))
 		}
 
+		affected := make([]string, 0, len(authorizedChanges.Update)+len(authorizedChanges.Delete))
+		for _, update := range authorizedChanges.Update {
+			affected = append(affected, update.Existing.UID)
+		}
+		for _, rule := range authorizedChanges.Delete {
+			affected = append(affected, rule.UID)
+		}
+		if err := verifyProvisionedRulesNotAffected(tranCtx, srv.provenanceStore, c.SignedInUser.OrgId, affected); err != nil {
+			return err
+		}
+
 		logger.Debug("updating database with the authorized changes", "add", len(authorizedChanges.New), "update", len(authorizedChanges.New), "delete", len(authorizedChanges.Delete))
 
 		if len(authorizedChanges.Update) > 0 || len(authorizedChanges.New) > 0 {
@@ -428,6 +447,8 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 			return ErrResp(http.StatusForbidden, err, "")
 		} else if errors.Is(err, ErrAuthorization) {
 			return ErrResp(http.StatusUnauthorized, err, "")
+		} else if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusConflict, err, "")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
@@ -453,6 +474,23 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
 }
 
+// verifyProvisionedRulesNotAffected returns errProvisionedResource if any of the rules with the given UIDs is managed by a provisioning source.
+func verifyProvisionedRulesNotAffected(ctx context.Context, provenanceStore provisioning.ProvisioningStore, orgID int64, ruleUIDs []string) error {
+	if len(ruleUIDs) == 0 {
+		return nil
+	}
+	provenanceRecords, err := provenanceStore.GetProvenances(ctx, orgID, (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for _, uid := range ruleUIDs {
+		if provenance, ok := provenanceRecords[uid]; ok && provenance != ngmodels.ProvenanceNone {
+			return fmt.Errorf("%w: alert rule %s is managed by '%s' provisioning", errProvisionedResource, uid, provenance)
+		}
+	}
+	return nil
+}
+
 func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableExtendedRuleNode {
 	provenance := ngmodels.ProvenanceNone
 	if prov, exists := provenanceRecords[r.ResourceID()]; exists {
@@ -474,6 +512,9 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 			RuleGroup:       r.RuleGroup,
 			NoDataState:     apimodels.NoDataState(r.NoDataState),
 			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
+			Dependencies:    r.Dependencies,
+			Record:          r.Record,
+			IsPaused:        r.IsPaused,
 			Provenance:      provenance,
 		},
 	}
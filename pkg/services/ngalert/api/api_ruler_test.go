@@ -475,6 +475,31 @@ func TestRouteDeleteAlertRules(t *testing.T) {
 			})
 		})
 	})
+	t.Run("when rules are provisioned", func(t *testing.T) {
+		t.Run("editor should not be able to delete them", func(t *testing.T) {
+			ruleStore := store.NewFakeRuleStore(t)
+			orgID := rand.Int63()
+			folder := randFolder()
+			ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+			rulesInFolder := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withOrgID(orgID), withNamespace(folder)))
+			ruleStore.PutRule(context.Background(), rulesInFolder...)
+
+			scheduler := &schedule.FakeScheduleService{}
+			scheduler.On("DeleteAlertRule", mock.Anything).Panic("should not be called")
+
+			ac := acMock.New().WithDisabled()
+			svc := createService(ac, ruleStore, scheduler)
+			err := svc.provenanceStore.SetProvenance(context.Background(), rulesInFolder[0], orgID, models.ProvenanceFile)
+			require.NoError(t, err)
+
+			request := createRequestContext(orgID, models2.ROLE_EDITOR, map[string]string{
+				":Namespace": folder.Title,
+			})
+			response := svc.RouteDeleteAlertRules(request)
+			require.Equalf(t, http.StatusConflict, response.Status(), "Expected 409 but got %d: %v", response.Status(), string(response.Body()))
+			require.Empty(t, getRecordedCommand(ruleStore))
+		})
+	})
 }
 
 func TestRouteGetNamespaceRulesConfig(t *testing.T) {
@@ -108,6 +108,15 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Dependencies:    ruleNode.GrafanaManagedAlert.Dependencies,
+		Record:          ruleNode.GrafanaManagedAlert.Record,
+		IsPaused:        ruleNode.GrafanaManagedAlert.IsPaused,
+	}
+
+	if newAlertRule.Record != nil {
+		if err := newAlertRule.Record.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
 	}
 
 	if ruleNode.ApiRuleNode != nil {
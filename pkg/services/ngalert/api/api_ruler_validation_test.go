@@ -243,6 +243,32 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, time.Duration(api.ApiRuleNode.For), alert.For)
 				require.Equal(t, api.ApiRuleNode.Annotations, alert.Annotations)
 				require.Equal(t, api.ApiRuleNode.Labels, alert.Labels)
+				require.Empty(t, alert.Dependencies)
+			},
+		},
+		{
+			name: "coverts api model with dependencies",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Dependencies = []models.AlertRuleDependency{
+					{RuleUID: util.GenerateShortUID(), Matchers: []string{"severity=critical"}, Equal: []string{"dc"}},
+				}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, api.GrafanaManagedAlert.Dependencies, alert.Dependencies)
+			},
+		},
+		{
+			name: "coverts api model with record",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &models.Record{Metric: "instance:up:rate5m", Target: models.RecordTargetLive}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, api.GrafanaManagedAlert.Record, alert.Record)
+				require.True(t, alert.IsRecordingRule())
 			},
 		},
 		{
@@ -437,6 +463,14 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if record has invalid metric name",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &models.Record{Metric: "invalid-metric", Target: models.RecordTargetRemoteWrite}
+				return &r
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -17,8 +18,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -30,6 +33,8 @@ type TestingApiSrv struct {
 	log               log.Logger
 	accessControl     accesscontrol.AccessControl
 	evaluator         eval.Evaluator
+	backtesting       *backtesting.Engine
+	cfg               *setting.UnifiedAlertingSettings
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -134,3 +139,81 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) RouteBacktestConfig(c *models.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization), "")
+	}
+
+	rule, err := validateBacktestConfig(cmd, c.SignedInUser.OrgId, srv.cfg)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	evalCond := ngmodels.Condition{
+		Condition: rule.Condition,
+		OrgID:     rule.OrgID,
+		Data:      rule.Data,
+	}
+	if err := validateCondition(c.Req.Context(), evalCond, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid condition")
+	}
+
+	frame, err := srv.backtesting.Test(c.Req.Context(), rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to backtest the rule")
+	}
+	return response.JSONStreaming(http.StatusOK, frame)
+}
+
+// validateBacktestConfig converts the payload to a rule that is not saved. Its defaults are the same as the ones of a
+// rule created by the ruler API.
+func validateBacktestConfig(cmd apimodels.BacktestConfig, orgID int64, cfg *setting.UnifiedAlertingSettings) (*ngmodels.AlertRule, error) {
+	interval := time.Duration(cmd.Interval)
+	if interval == 0 {
+		interval = cfg.DefaultRuleEvaluationInterval
+	}
+	if interval < 0 || int64(interval.Seconds())%int64(cfg.BaseInterval.Seconds()) != 0 {
+		return nil, fmt.Errorf("rule evaluation interval (%d second) should be positive number that is multiple of the base interval of %d seconds", int64(interval.Seconds()), int64(cfg.BaseInterval.Seconds()))
+	}
+
+	if len(cmd.Data) == 0 {
+		return nil, fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	noDataState := ngmodels.NoData
+	if cmd.NoDataState != "" {
+		var err error
+		noDataState, err = ngmodels.NoDataStateFromString(string(cmd.NoDataState))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	errorState := ngmodels.AlertingErrState
+	if cmd.ExecErrState != "" {
+		var err error
+		errorState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ngmodels.AlertRule{
+		OrgID:           orgID,
+		Title:           cmd.Title,
+		Condition:       cmd.Condition,
+		Data:            cmd.Data,
+		IntervalSeconds: int64(interval.Seconds()),
+		For:             time.Duration(cmd.For),
+		Labels:          cmd.Labels,
+		Annotations:     cmd.Annotations,
+		NoDataState:     noDataState,
+		ExecErrState:    errorState,
+	}, nil
+}
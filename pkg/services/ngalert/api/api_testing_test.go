@@ -6,17 +6,21 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	models2 "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -266,6 +270,103 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteBacktestConfig(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		IsSignedIn: true,
+		SignedInUser: &models2.SignedInUser{
+			OrgId: 1,
+		},
+	}
+	from := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should return 401 if user cannot query a data source", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		data2 := models.GenerateAlertQuery()
+
+		ac := acMock.New().WithPermissions([]*accesscontrol.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+		})
+
+		evaluator := &eval.FakeEvaluator{}
+		srv := createTestingApiSrv(nil, ac, evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1, data2},
+		})
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 if the interval is not a multiple of the base interval", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &fakes.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+		srv := createTestingApiSrv(ds, nil, evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Interval:  model.Duration(15 * time.Second),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 if the time range requires too many evaluations", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &fakes.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+		srv := createTestingApiSrv(ds, nil, evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(backtesting.MaxEvaluations * 10 * time.Second),
+			Interval:  model.Duration(10 * time.Second),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should evaluate the rule at every interval", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ds := &fakes.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: data1.DatasourceUID},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+		evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything).Return(eval.Results{{State: eval.Alerting}}, nil)
+		srv := createTestingApiSrv(ds, nil, evaluator)
+
+		response := srv.RouteBacktestConfig(rc, definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Hour),
+			Condition: data1.RefID,
+			Data:      []models.AlertQuery{data1},
+			Title:     "test",
+			For:       model.Duration(5 * time.Minute),
+		})
+
+		require.Equal(t, http.StatusOK, response.Status())
+		evaluator.AssertNumberOfCalls(t, "ConditionEval", 61)
+	})
+}
+
 func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator *eval.FakeEvaluator) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -275,5 +376,10 @@ func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator
 		DatasourceCache: ds,
 		accessControl:   ac,
 		evaluator:       evaluator,
+		backtesting:     backtesting.NewEngine(evaluator, nil, nil, log.NewNopLogger()),
+		cfg: &setting.UnifiedAlertingSettings{
+			BaseInterval:                  10 * time.Second,
+			DefaultRuleEvaluationInterval: time.Minute,
+		},
 	}
 }
@@ -63,6 +63,15 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
+	// Grafana State History Paths
+	case http.MethodGet + "/api/v1/rules/history":
+		// the handler returns only the history of rules in the folders the user has access to
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+
+	// Grafana Notification Delivery Paths
+	case http.MethodGet + "/api/v1/notifications/deliveries":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+
 	// Grafana Rules Testing Paths
 	case http.MethodPost + "/api/v1/rule/test/grafana":
 		fallback = middleware.ReqSignedIn
@@ -72,6 +81,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceID}/api/v1/rules/{Namespace}":
@@ -180,16 +193,35 @@ func (api *API) authorize(method, path string) web.Handler {
 		return middleware.ReqOrgAdmin
 
 	// Grafana-only Provisioning Read Paths
+	// the handlers of the alert rule paths check the access to the folder of the rules
 	case http.MethodGet + "/api/provisioning/policies",
 		http.MethodGet + "/api/provisioning/contact-points",
 		http.MethodGet + "/api/provisioning/templates",
-		http.MethodGet + "/api/provisioning/templates/{ID}":
+		http.MethodGet + "/api/provisioning/templates/{name}",
+		http.MethodGet + "/api/provisioning/mute-timings",
+		http.MethodGet + "/api/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/provisioning/alert-rules/{UID}",
+		http.MethodGet + "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodGet + "/api/provisioning/maintenance-windows",
+		http.MethodGet + "/api/provisioning/maintenance-windows/{UID}":
 		return middleware.ReqSignedIn
 
 	case http.MethodPost + "/api/provisioning/policies",
 		http.MethodPost + "/api/provisioning/contact-points",
 		http.MethodPut + "/api/provisioning/contact-points",
-		http.MethodDelete + "/api/provisioning/contact-points/{ID}":
+		http.MethodDelete + "/api/provisioning/contact-points/{ID}",
+		http.MethodPut + "/api/provisioning/templates/{name}",
+		http.MethodDelete + "/api/provisioning/templates/{name}",
+		http.MethodPost + "/api/provisioning/mute-timings",
+		http.MethodPut + "/api/provisioning/mute-timings/{name}",
+		http.MethodDelete + "/api/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/provisioning/alert-rules",
+		http.MethodPut + "/api/provisioning/alert-rules/{UID}",
+		http.MethodDelete + "/api/provisioning/alert-rules/{UID}",
+		http.MethodPut + "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodPost + "/api/provisioning/maintenance-windows",
+		http.MethodPut + "/api/provisioning/maintenance-windows/{UID}",
+		http.MethodDelete + "/api/provisioning/maintenance-windows/{UID}":
 		return middleware.ReqEditorRole
 	}
 
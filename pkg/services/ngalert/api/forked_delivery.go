@@ -0,0 +1,22 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ForkedDeliveryApi always forwards requests to grafana backend
+type ForkedDeliveryApi struct {
+	svc *DeliverySrv
+}
+
+// NewForkedDeliveryApi creates a new ForkedDeliveryApi instance
+func NewForkedDeliveryApi(svc *DeliverySrv) *ForkedDeliveryApi {
+	return &ForkedDeliveryApi{
+		svc: svc,
+	}
+}
+
+func (f *ForkedDeliveryApi) forkRouteGetNotificationDeliveries(c *models.ReqContext) response.Response {
+	return f.svc.RouteGetNotificationDeliveries(c)
+}
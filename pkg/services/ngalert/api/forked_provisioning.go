@@ -50,3 +50,75 @@ func (f *ForkedProvisioningApi) forkRouteGetTemplates(ctx *models.ReqContext) re
 func (f *ForkedProvisioningApi) forkRouteGetTemplate(ctx *models.ReqContext) response.Response {
 	return f.svc.RouteGetTemplate(ctx)
 }
+
+func (f *ForkedProvisioningApi) forkRoutePutTemplate(ctx *models.ReqContext, body apimodels.MessageTemplateContent) response.Response {
+	return f.svc.RoutePutTemplate(ctx, body)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteTemplate(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteDeleteTemplate(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetMuteTiming(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTiming(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetMuteTimings(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimings(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostMuteTiming(ctx *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	return f.svc.RoutePostMuteTiming(ctx, mt)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutMuteTiming(ctx *models.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
+	return f.svc.RoutePutMuteTiming(ctx, mt)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteMuteTiming(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteDeleteMuteTiming(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetAlertRule(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRule(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostAlertRule(ctx *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	return f.svc.RoutePostAlertRule(ctx, ar)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutAlertRule(ctx *models.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	return f.svc.RoutePutAlertRule(ctx, ar)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteAlertRule(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteDeleteAlertRule(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetAlertRuleGroup(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleGroup(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutAlertRuleGroup(ctx *models.ReqContext, ag apimodels.AlertRuleGroup) response.Response {
+	return f.svc.RoutePutAlertRuleGroup(ctx, ag)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetMaintenanceWindows(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRouteGetMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindow(ctx)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePostMaintenanceWindow(ctx *models.ReqContext, mw apimodels.ProvisionedMaintenanceWindow) response.Response {
+	return f.svc.RoutePostMaintenanceWindow(ctx, mw)
+}
+
+func (f *ForkedProvisioningApi) forkRoutePutMaintenanceWindow(ctx *models.ReqContext, mw apimodels.ProvisionedMaintenanceWindow) response.Response {
+	return f.svc.RoutePutMaintenanceWindow(ctx, mw)
+}
+
+func (f *ForkedProvisioningApi) forkRouteDeleteMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteDeleteMaintenanceWindow(ctx)
+}
@@ -20,24 +20,82 @@ import (
 )
 
 type ProvisioningApiForkingService interface {
+	RouteDeleteAlertRule(*models.ReqContext) response.Response
 	RouteDeleteContactpoints(*models.ReqContext) response.Response
+	RouteDeleteMaintenanceWindow(*models.ReqContext) response.Response
+	RouteDeleteMuteTiming(*models.ReqContext) response.Response
+	RouteDeleteTemplate(*models.ReqContext) response.Response
+	RouteGetAlertRule(*models.ReqContext) response.Response
+	RouteGetAlertRuleGroup(*models.ReqContext) response.Response
 	RouteGetContactpoints(*models.ReqContext) response.Response
+	RouteGetMaintenanceWindow(*models.ReqContext) response.Response
+	RouteGetMaintenanceWindows(*models.ReqContext) response.Response
+	RouteGetMuteTiming(*models.ReqContext) response.Response
+	RouteGetMuteTimings(*models.ReqContext) response.Response
 	RouteGetPolicyTree(*models.ReqContext) response.Response
 	RouteGetTemplate(*models.ReqContext) response.Response
 	RouteGetTemplates(*models.ReqContext) response.Response
+	RoutePostAlertRule(*models.ReqContext) response.Response
 	RoutePostContactpoints(*models.ReqContext) response.Response
+	RoutePostMaintenanceWindow(*models.ReqContext) response.Response
+	RoutePostMuteTiming(*models.ReqContext) response.Response
 	RoutePostPolicyTree(*models.ReqContext) response.Response
+	RoutePutAlertRule(*models.ReqContext) response.Response
+	RoutePutAlertRuleGroup(*models.ReqContext) response.Response
 	RoutePutContactpoints(*models.ReqContext) response.Response
+	RoutePutMaintenanceWindow(*models.ReqContext) response.Response
+	RoutePutMuteTiming(*models.ReqContext) response.Response
+	RoutePutTemplate(*models.ReqContext) response.Response
+}
+
+func (f *ForkedProvisioningApi) RouteDeleteAlertRule(ctx *models.ReqContext) response.Response {
+	return f.forkRouteDeleteAlertRule(ctx)
 }
 
 func (f *ForkedProvisioningApi) RouteDeleteContactpoints(ctx *models.ReqContext) response.Response {
 	return f.forkRouteDeleteContactpoints(ctx)
 }
 
+func (f *ForkedProvisioningApi) RouteDeleteMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	return f.forkRouteDeleteMaintenanceWindow(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteDeleteMuteTiming(ctx *models.ReqContext) response.Response {
+	return f.forkRouteDeleteMuteTiming(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteDeleteTemplate(ctx *models.ReqContext) response.Response {
+	return f.forkRouteDeleteTemplate(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteGetAlertRule(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetAlertRule(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteGetAlertRuleGroup(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetAlertRuleGroup(ctx)
+}
+
 func (f *ForkedProvisioningApi) RouteGetContactpoints(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetContactpoints(ctx)
 }
 
+func (f *ForkedProvisioningApi) RouteGetMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetMaintenanceWindow(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteGetMaintenanceWindows(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteGetMuteTiming(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetMuteTiming(ctx)
+}
+
+func (f *ForkedProvisioningApi) RouteGetMuteTimings(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetMuteTimings(ctx)
+}
+
 func (f *ForkedProvisioningApi) RouteGetPolicyTree(ctx *models.ReqContext) response.Response {
 	return f.forkRouteGetPolicyTree(ctx)
 }
@@ -50,6 +108,14 @@ func (f *ForkedProvisioningApi) RouteGetTemplates(ctx *models.ReqContext) respon
 	return f.forkRouteGetTemplates(ctx)
 }
 
+func (f *ForkedProvisioningApi) RoutePostAlertRule(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisionedAlertRule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostAlertRule(ctx, conf)
+}
+
 func (f *ForkedProvisioningApi) RoutePostContactpoints(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EmbeddedContactPoint{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -58,6 +124,22 @@ func (f *ForkedProvisioningApi) RoutePostContactpoints(ctx *models.ReqContext) r
 	return f.forkRoutePostContactpoints(ctx, conf)
 }
 
+func (f *ForkedProvisioningApi) RoutePostMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisionedMaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostMaintenanceWindow(ctx, conf)
+}
+
+func (f *ForkedProvisioningApi) RoutePostMuteTiming(ctx *models.ReqContext) response.Response {
+	conf := apimodels.MuteTimeInterval{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostMuteTiming(ctx, conf)
+}
+
 func (f *ForkedProvisioningApi) RoutePostPolicyTree(ctx *models.ReqContext) response.Response {
 	conf := apimodels.Route{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -66,6 +148,22 @@ func (f *ForkedProvisioningApi) RoutePostPolicyTree(ctx *models.ReqContext) resp
 	return f.forkRoutePostPolicyTree(ctx, conf)
 }
 
+func (f *ForkedProvisioningApi) RoutePutAlertRule(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisionedAlertRule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRule(ctx, conf)
+}
+
+func (f *ForkedProvisioningApi) RoutePutAlertRuleGroup(ctx *models.ReqContext) response.Response {
+	conf := apimodels.AlertRuleGroup{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutAlertRuleGroup(ctx, conf)
+}
+
 func (f *ForkedProvisioningApi) RoutePutContactpoints(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EmbeddedContactPoint{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -74,8 +172,42 @@ func (f *ForkedProvisioningApi) RoutePutContactpoints(ctx *models.ReqContext) re
 	return f.forkRoutePutContactpoints(ctx, conf)
 }
 
+func (f *ForkedProvisioningApi) RoutePutMaintenanceWindow(ctx *models.ReqContext) response.Response {
+	conf := apimodels.ProvisionedMaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutMaintenanceWindow(ctx, conf)
+}
+
+func (f *ForkedProvisioningApi) RoutePutMuteTiming(ctx *models.ReqContext) response.Response {
+	conf := apimodels.MuteTimeInterval{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutMuteTiming(ctx, conf)
+}
+
+func (f *ForkedProvisioningApi) RoutePutTemplate(ctx *models.ReqContext) response.Response {
+	conf := apimodels.MessageTemplateContent{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePutTemplate(ctx, conf)
+}
+
 func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/provisioning/alert-rules/{UID}"),
+			api.authorize(http.MethodDelete, "/api/provisioning/alert-rules/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/provisioning/alert-rules/{UID}",
+				srv.RouteDeleteAlertRule,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/provisioning/contact-points/{ID}"),
 			api.authorize(http.MethodDelete, "/api/provisioning/contact-points/{ID}"),
@@ -86,6 +218,56 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodDelete, "/api/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/provisioning/maintenance-windows/{UID}",
+				srv.RouteDeleteMaintenanceWindow,
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/provisioning/mute-timings/{name}"),
+			api.authorize(http.MethodDelete, "/api/provisioning/mute-timings/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/provisioning/mute-timings/{name}",
+				srv.RouteDeleteMuteTiming,
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/provisioning/templates/{name}"),
+			api.authorize(http.MethodDelete, "/api/provisioning/templates/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/provisioning/templates/{name}",
+				srv.RouteDeleteTemplate,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/provisioning/alert-rules/{UID}"),
+			api.authorize(http.MethodGet, "/api/provisioning/alert-rules/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/alert-rules/{UID}",
+				srv.RouteGetAlertRule,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			api.authorize(http.MethodGet, "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+				srv.RouteGetAlertRuleGroup,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/provisioning/contact-points"),
 			api.authorize(http.MethodGet, "/api/provisioning/contact-points"),
@@ -96,6 +278,46 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodGet, "/api/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/maintenance-windows/{UID}",
+				srv.RouteGetMaintenanceWindow,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/provisioning/maintenance-windows"),
+			api.authorize(http.MethodGet, "/api/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/maintenance-windows",
+				srv.RouteGetMaintenanceWindows,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/provisioning/mute-timings/{name}"),
+			api.authorize(http.MethodGet, "/api/provisioning/mute-timings/{name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/mute-timings/{name}",
+				srv.RouteGetMuteTiming,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/provisioning/mute-timings"),
+			api.authorize(http.MethodGet, "/api/provisioning/mute-timings"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/provisioning/mute-timings",
+				srv.RouteGetMuteTimings,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/provisioning/policies"),
 			api.authorize(http.MethodGet, "/api/provisioning/policies"),
@@ -107,11 +329,11 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/provisioning/templates/{ID}"),
-			api.authorize(http.MethodGet, "/api/provisioning/templates/{ID}"),
+			toMacaronPath("/api/provisioning/templates/{name}"),
+			api.authorize(http.MethodGet, "/api/provisioning/templates/{name}"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/provisioning/templates/{ID}",
+				"/api/provisioning/templates/{name}",
 				srv.RouteGetTemplate,
 				m,
 			),
@@ -126,6 +348,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/provisioning/alert-rules"),
+			api.authorize(http.MethodPost, "/api/provisioning/alert-rules"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/provisioning/alert-rules",
+				srv.RoutePostAlertRule,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/provisioning/contact-points"),
 			api.authorize(http.MethodPost, "/api/provisioning/contact-points"),
@@ -136,6 +368,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/provisioning/maintenance-windows"),
+			api.authorize(http.MethodPost, "/api/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/provisioning/maintenance-windows",
+				srv.RoutePostMaintenanceWindow,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/provisioning/mute-timings"),
+			api.authorize(http.MethodPost, "/api/provisioning/mute-timings"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/provisioning/mute-timings",
+				srv.RoutePostMuteTiming,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/provisioning/policies"),
 			api.authorize(http.MethodPost, "/api/provisioning/policies"),
@@ -146,6 +398,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/provisioning/alert-rules/{UID}"),
+			api.authorize(http.MethodPut, "/api/provisioning/alert-rules/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/provisioning/alert-rules/{UID}",
+				srv.RoutePutAlertRule,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			api.authorize(http.MethodPut, "/api/provisioning/folder/{FolderUID}/rule-groups/{Group}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+				srv.RoutePutAlertRuleGroup,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/provisioning/contact-points"),
 			api.authorize(http.MethodPut, "/api/provisioning/contact-points"),
@@ -156,5 +428,35 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApiForkingServi
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodPut, "/api/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/provisioning/maintenance-windows/{UID}",
+				srv.RoutePutMaintenanceWindow,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/provisioning/mute-timings/{name}"),
+			api.authorize(http.MethodPut, "/api/provisioning/mute-timings/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/provisioning/mute-timings/{name}",
+				srv.RoutePutMuteTiming,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/provisioning/templates/{name}"),
+			api.authorize(http.MethodPut, "/api/provisioning/templates/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/provisioning/templates/{name}",
+				srv.RoutePutTemplate,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
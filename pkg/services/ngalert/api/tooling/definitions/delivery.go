@@ -0,0 +1,75 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/notifications/deliveries deliveries RouteGetNotificationDeliveries
+//
+// Get the attempts of contact points to deliver notifications, newest first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: NotificationDeliveries
+//       400: ValidationError
+
+// swagger:parameters RouteGetNotificationDeliveries
+type GetNotificationDeliveriesParams struct {
+	// Filter the attempts by the name of the contact point.
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Filter the attempts by the type of the integration, e.g. slack or webhook.
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+
+	// Filter the attempts by their outcome.
+	// in: query
+	// required: false
+	// enum: success, failure
+	Status string `json:"status"`
+
+	// Filter the attempts to the notifications that contained the alert with the specified fingerprint, in hexadecimal.
+	// in: query
+	// required: false
+	Fingerprint string `json:"fingerprint"`
+
+	// Unix timestamp in seconds of the oldest attempt to return.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// Unix timestamp in seconds of the newest attempt to return.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// The maximum number of attempts to return, between 1 and 5000. Defaults to 1000.
+	// in: query
+	// required: false
+	Limit int64 `json:"limit"`
+}
+
+// swagger:model
+type NotificationDeliveries []NotificationDelivery
+
+// NotificationDelivery is an attempt of an integration of a contact point to deliver a notification.
+// swagger:model
+type NotificationDelivery struct {
+	Time           time.Time `json:"time"`
+	Receiver       string    `json:"receiver"`
+	Integration    string    `json:"integration"`
+	IntegrationUID string    `json:"integrationUid"`
+	// The fingerprints of the alerts in the notification.
+	Fingerprints []string `json:"fingerprints"`
+	// enum: success, failure
+	Status string `json:"status"`
+	// The HTTP status code of the response of the service, if the attempt failed because of it.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	LatencyMs  int64  `json:"latencyMs"`
+}
@@ -0,0 +1,47 @@
+package definitions
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// swagger:route GET /api/v1/rules/history history RouteGetStateHistory
+//
+// Get the state transitions of alert instances as a data frame, newest first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StateHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetStateHistory
+type GetStateHistoryParams struct {
+	// Filter the history to the instances of the rule with the specified UID.
+	// in: query
+	// required: false
+	RuleUID string `json:"ruleUID"`
+
+	// A list of matchers to filter the history by, all of them must match the labels of an instance
+	// in: query
+	// required: false
+	Matchers []string `json:"matcher"`
+
+	// Unix timestamp in seconds of the oldest transition to return.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// Unix timestamp in seconds of the newest transition to return.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// The maximum number of transitions to return, between 1 and 5000. Defaults to 1000.
+	// in: query
+	// required: false
+	Limit int64 `json:"limit"`
+}
+
+// swagger:model
+type StateHistory = data.Frames
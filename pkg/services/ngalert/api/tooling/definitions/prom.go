@@ -123,6 +123,8 @@ type Rule struct {
 	Type           v1.RuleType `json:"type"`
 	LastEvaluation time.Time   `json:"lastEvaluation"`
 	EvaluationTime float64     `json:"evaluationTime"`
+	// Owner is the member of the HA cluster that evaluates the rule. It is only set if the evaluation is sharded.
+	Owner string `json:"owner,omitempty"`
 }
 
 // Alert has info for an alert.
@@ -0,0 +1,221 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/alert-rules/{UID} provisioning RouteGetAlertRule
+//
+// Get a specific alert rule by UID.
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       404: NotFound
+
+// swagger:route POST /api/provisioning/alert-rules provisioning RoutePostAlertRule
+//
+// Create a new alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedAlertRule
+//       400: ValidationError
+
+// swagger:route PUT /api/provisioning/alert-rules/{UID} provisioning RoutePutAlertRule
+//
+// Update an existing alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       400: ValidationError
+//       404: NotFound
+//       409: ValidationError
+
+// swagger:route DELETE /api/provisioning/alert-rules/{UID} provisioning RouteDeleteAlertRule
+//
+// Delete a specific alert rule by UID.
+//
+//     Responses:
+//       204: Accepted
+//       409: ValidationError
+
+// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule
+type AlertRuleUIDReference struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule
+type AlertRulePayload struct {
+	// in:body
+	Body ProvisionedAlertRule
+}
+
+// ProvisionedAlertRule is an alert rule as it is exposed by the provisioning API.
+// The evaluation interval is not part of the rule, it is defined by the rule group.
+type ProvisionedAlertRule struct {
+	ID  int64  `json:"id"`
+	UID string `json:"uid"`
+	// required: true
+	OrgID int64 `json:"orgID"`
+	// required: true
+	FolderUID string `json:"folderUID"`
+	// required: true
+	RuleGroup string `json:"ruleGroup"`
+	// required: true
+	Title string `json:"title"`
+	// required: true
+	Condition string `json:"condition"`
+	// required: true
+	Data []models.AlertQuery `json:"data"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+	// required: true
+	NoDataState models.NoDataState `json:"noDataState"`
+	// required: true
+	ExecErrState models.ExecutionErrorState `json:"execErrState"`
+	// required: true
+	For         time.Duration     `json:"for"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// Dependencies suppress the alerting instances of the rule while instances of other rules are firing.
+	Dependencies []models.AlertRuleDependency `json:"dependencies,omitempty"`
+	// Record makes the rule a recording rule that writes the result of the condition as a metric.
+	Record *models.Record `json:"record,omitempty"`
+	// IsPaused stops the evaluation of the rule.
+	IsPaused bool `json:"isPaused,omitempty"`
+	// readonly: true
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
+// UpstreamModel converts the rule into the model used by the rest of unified alerting.
+func (a *ProvisionedAlertRule) UpstreamModel() models.AlertRule {
+	return models.AlertRule{
+		ID:           a.ID,
+		UID:          a.UID,
+		OrgID:        a.OrgID,
+		NamespaceUID: a.FolderUID,
+		RuleGroup:    a.RuleGroup,
+		Title:        a.Title,
+		Condition:    a.Condition,
+		Data:         a.Data,
+		Updated:      a.Updated,
+		NoDataState:  a.NoDataState,
+		ExecErrState: a.ExecErrState,
+		For:          a.For,
+		Annotations:  a.Annotations,
+		Labels:       a.Labels,
+		Dependencies: a.Dependencies,
+		Record:       a.Record,
+		IsPaused:     a.IsPaused,
+	}
+}
+
+// NewAlertRule creates the API representation of the given alert rule.
+func NewAlertRule(rule models.AlertRule, provenance models.Provenance) ProvisionedAlertRule {
+	return ProvisionedAlertRule{
+		ID:           rule.ID,
+		UID:          rule.UID,
+		OrgID:        rule.OrgID,
+		FolderUID:    rule.NamespaceUID,
+		RuleGroup:    rule.RuleGroup,
+		Title:        rule.Title,
+		Condition:    rule.Condition,
+		Data:         rule.Data,
+		Updated:      rule.Updated,
+		NoDataState:  rule.NoDataState,
+		ExecErrState: rule.ExecErrState,
+		For:          rule.For,
+		Annotations:  rule.Annotations,
+		Labels:       rule.Labels,
+		Dependencies: rule.Dependencies,
+		Record:       rule.Record,
+		IsPaused:     rule.IsPaused,
+		Provenance:   provenance,
+	}
+}
+
+// swagger:route GET /api/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning RouteGetAlertRuleGroup
+//
+// Get a rule group.
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       404: NotFound
+
+// swagger:route PUT /api/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning RoutePutAlertRuleGroup
+//
+// Replace the rules and the interval of a rule group.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       400: ValidationError
+//       409: ValidationError
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+type FolderUIDPathParam struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+type RuleGroupPathParam struct {
+	// in:path
+	Group string `json:"Group"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroup
+type AlertRuleGroupPayload struct {
+	// in:body
+	Body AlertRuleGroup
+}
+
+// AlertRuleGroup is a rule group as it is exposed by the provisioning API.
+// swagger:model
+type AlertRuleGroup struct {
+	// readonly: true
+	Title string `json:"title"`
+	// readonly: true
+	FolderUID string `json:"folderUid"`
+	// Interval is the evaluation interval of all rules in the group, in seconds.
+	Interval int64                  `json:"interval"`
+	Rules    []ProvisionedAlertRule `json:"rules"`
+}
+
+// UpstreamModel converts the rule group into the model used by the rest of unified alerting.
+func (a *AlertRuleGroup) UpstreamModel() models.AlertRuleGroup {
+	rules := make([]models.AlertRule, 0, len(a.Rules))
+	for _, rule := range a.Rules {
+		rules = append(rules, rule.UpstreamModel())
+	}
+	return models.AlertRuleGroup{
+		Title:     a.Title,
+		FolderUID: a.FolderUID,
+		Interval:  a.Interval,
+		Rules:     rules,
+	}
+}
+
+// NewAlertRuleGroup creates the API representation of the given rule group.
+func NewAlertRuleGroup(group models.AlertRuleGroup, provenances map[string]models.Provenance) AlertRuleGroup {
+	rules := make([]ProvisionedAlertRule, 0, len(group.Rules))
+	for _, rule := range group.Rules {
+		rules = append(rules, NewAlertRule(rule, provenances[rule.UID]))
+	}
+	return AlertRuleGroup{
+		Title:     group.Title,
+		FolderUID: group.FolderUID,
+		Interval:  group.Interval,
+		Rules:     rules,
+	}
+}
@@ -0,0 +1,119 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/maintenance-windows provisioning RouteGetMaintenanceWindows
+//
+// Get all the maintenance windows.
+//
+//     Responses:
+//       200: MaintenanceWindows
+
+// swagger:route GET /api/provisioning/maintenance-windows/{UID} provisioning RouteGetMaintenanceWindow
+//
+// Get a specific maintenance window by UID.
+//
+//     Responses:
+//       200: ProvisionedMaintenanceWindow
+//       404: NotFound
+
+// swagger:route POST /api/provisioning/maintenance-windows provisioning RoutePostMaintenanceWindow
+//
+// Create a new maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedMaintenanceWindow
+//       400: ValidationError
+
+// swagger:route PUT /api/provisioning/maintenance-windows/{UID} provisioning RoutePutMaintenanceWindow
+//
+// Replace an existing maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedMaintenanceWindow
+//       400: ValidationError
+//       404: NotFound
+//       409: ValidationError
+
+// swagger:route DELETE /api/provisioning/maintenance-windows/{UID} provisioning RouteDeleteMaintenanceWindow
+//
+// Delete a specific maintenance window by UID.
+//
+//     Responses:
+//       204: Accepted
+//       409: ValidationError
+
+// swagger:parameters RouteGetMaintenanceWindow RoutePutMaintenanceWindow RouteDeleteMaintenanceWindow
+type MaintenanceWindowUIDReference struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow
+type MaintenanceWindowPayload struct {
+	// in:body
+	Body ProvisionedMaintenanceWindow
+}
+
+// swagger:model
+type MaintenanceWindows []ProvisionedMaintenanceWindow
+
+// ProvisionedMaintenanceWindow is a period during which the evaluation of a rule, or of all rules of a folder, is
+// skipped. The instances of the rules are in the Paused state while the window is active.
+// swagger:model
+type ProvisionedMaintenanceWindow struct {
+	UID string `json:"uid"`
+	// required: true
+	Title string `json:"title"`
+	// FolderUID pauses all rules of the folder. Exactly one of FolderUID and RuleUID must be set.
+	FolderUID string `json:"folderUID,omitempty"`
+	// RuleUID pauses a single rule. Exactly one of FolderUID and RuleUID must be set.
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Ranges are one-off periods of time. The end of a range is exclusive.
+	Ranges []models.MaintenanceTimeRange `json:"ranges,omitempty"`
+	// TimeIntervals are recurring periods of time in UTC, in the format of the time intervals of mute timings.
+	TimeIntervals []timeinterval.TimeInterval `json:"timeIntervals,omitempty"`
+	// readonly: true
+	Updated time.Time `json:"updated"`
+	// readonly: true
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
+// UpstreamModel converts the window into the model used by the rest of unified alerting.
+func (w *ProvisionedMaintenanceWindow) UpstreamModel() models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		UID:           w.UID,
+		Title:         w.Title,
+		NamespaceUID:  w.FolderUID,
+		RuleUID:       w.RuleUID,
+		Ranges:        w.Ranges,
+		TimeIntervals: w.TimeIntervals,
+		Updated:       w.Updated,
+	}
+}
+
+// NewMaintenanceWindow creates the API representation of the given maintenance window.
+func NewMaintenanceWindow(window models.MaintenanceWindow, provenance models.Provenance) ProvisionedMaintenanceWindow {
+	return ProvisionedMaintenanceWindow{
+		UID:           window.UID,
+		Title:         window.Title,
+		FolderUID:     window.NamespaceUID,
+		RuleUID:       window.RuleUID,
+		Ranges:        window.Ranges,
+		TimeIntervals: window.TimeIntervals,
+		Updated:       window.Updated,
+		Provenance:    provenance,
+	}
+}
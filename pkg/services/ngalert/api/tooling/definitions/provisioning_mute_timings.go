@@ -0,0 +1,94 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/provisioning/mute-timings provisioning RouteGetMuteTimings
+//
+// Get all the mute timings.
+//
+//     Responses:
+//       200: MuteTimings
+
+// swagger:route GET /api/provisioning/mute-timings/{name} provisioning RouteGetMuteTiming
+//
+// Get a mute timing.
+//
+//     Responses:
+//       200: MuteTimeInterval
+//       404: NotFound
+
+// swagger:route POST /api/provisioning/mute-timings provisioning RoutePostMuteTiming
+//
+// Create a new mute timing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MuteTimeInterval
+//       400: ValidationError
+
+// swagger:route PUT /api/provisioning/mute-timings/{name} provisioning RoutePutMuteTiming
+//
+// Replace an existing mute timing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MuteTimeInterval
+//       400: ValidationError
+//       404: NotFound
+//       409: ValidationError
+
+// swagger:route DELETE /api/provisioning/mute-timings/{name} provisioning RouteDeleteMuteTiming
+//
+// Delete a mute timing.
+//
+//     Responses:
+//       204: Accepted
+//       400: ValidationError
+//       409: ValidationError
+
+// swagger:parameters RouteGetMuteTiming RoutePutMuteTiming RouteDeleteMuteTiming
+type RouteGetMuteTimingParam struct {
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePostMuteTiming RoutePutMuteTiming
+type MuteTimingPayload struct {
+	// in:body
+	Body MuteTimeInterval
+}
+
+// swagger:model
+type MuteTimings []MuteTimeInterval
+
+// MuteTimeInterval is a named set of time intervals in which notifications are muted.
+type MuteTimeInterval struct {
+	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+	Provenance              models.Provenance `json:"provenance,omitempty"`
+}
+
+func (mt *MuteTimeInterval) ResourceType() string {
+	return "muteTimeInterval"
+}
+
+func (mt *MuteTimeInterval) ResourceID() string {
+	return mt.MuteTimeInterval.Name
+}
+
+func (mt *MuteTimeInterval) Validate() error {
+	if mt.Name == "" {
+		return fmt.Errorf("mute time interval must have a name")
+	}
+	// the time intervals themselves are validated when they are unmarshalled
+	return nil
+}
@@ -1,5 +1,14 @@
 package definitions
 
+import (
+	"fmt"
+	tmpltext "text/template"
+
+	"github.com/prometheus/alertmanager/template"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
 // swagger:route GET /api/provisioning/templates provisioning RouteGetTemplates
 //
 // Get all message templates.
@@ -8,7 +17,7 @@ package definitions
 //       200: []MessageTemplate
 //       400: ValidationError
 
-// swagger:route GET /api/provisioning/templates/{ID} provisioning RouteGetTemplate
+// swagger:route GET /api/provisioning/templates/{name} provisioning RouteGetTemplate
 //
 // Get a message template.
 //
@@ -16,9 +25,70 @@ package definitions
 //       200: MessageTemplate
 //       404: NotFound
 
-type MessageTemplate struct {
-	Name     string
+// swagger:route PUT /api/provisioning/templates/{name} provisioning RoutePutTemplate
+//
+// Create or update a message template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: MessageTemplate
+//       400: ValidationError
+//       409: ValidationError
+
+// swagger:route DELETE /api/provisioning/templates/{name} provisioning RouteDeleteTemplate
+//
+// Delete a message template.
+//
+//     Responses:
+//       204: Accepted
+//       409: ValidationError
+
+// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
+type RouteGetTemplateParam struct {
+	// Template Name
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePutTemplate
+type MessageTemplatePayload struct {
+	// in:body
+	Body MessageTemplateContent
+}
+
+type MessageTemplateContent struct {
 	Template string
 }
 
+type MessageTemplate struct {
+	Name       string
+	Template   string
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
 type NotFound struct{}
+
+func (t *MessageTemplate) ResourceType() string {
+	return "template"
+}
+
+func (t *MessageTemplate) ResourceID() string {
+	return t.Name
+}
+
+func (t *MessageTemplate) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("template must have a name")
+	}
+	if t.Template == "" {
+		return fmt.Errorf("template must have content")
+	}
+
+	tmpl := tmpltext.New("").Funcs(tmpltext.FuncMap(template.DefaultFuncs))
+	if _, err := tmpl.Parse(t.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
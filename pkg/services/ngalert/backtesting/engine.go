@@ -0,0 +1,123 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// MaxEvaluations is the maximum number of evaluations of a single backtest. Every evaluation queries the data sources
+// of the rule, so the time range and the interval of a backtest are limited.
+const MaxEvaluations = 1000
+
+var ErrInvalidInputData = errors.New("invalid input data")
+
+// Engine simulates the evaluation of an alert rule over a historical time range.
+type Engine struct {
+	evaluator         eval.Evaluator
+	expressionService *expr.Service
+	externalURL       *url.URL
+	log               log.Logger
+}
+
+func NewEngine(evaluator eval.Evaluator, expressionService *expr.Service, externalURL *url.URL, logger log.Logger) *Engine {
+	return &Engine{
+		evaluator:         evaluator,
+		expressionService: expressionService,
+		externalURL:       externalURL,
+		log:               logger,
+	}
+}
+
+// Test evaluates the rule at every interval of the rule from the beginning to the end of the time range, both inclusive.
+// The results are processed by the same state machine the scheduler uses, so that the For duration and the NoData and
+// Error handling of the rule apply. Nothing is persisted and no notifications are sent.
+// It returns a frame with the time of each evaluation and a field per alert instance with the state of the instance at
+// that time. The value is null at the times the instance did not exist.
+func (e *Engine) Test(ctx context.Context, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be a positive duration", ErrInvalidInputData)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: the beginning of the time range must be before its end", ErrInvalidInputData)
+	}
+	length := int(to.Sub(from)/interval) + 1
+	if length > MaxEvaluations {
+		return nil, fmt.Errorf("%w: the time range requires %d evaluations but at most %d are allowed, use a shorter time range or a longer interval", ErrInvalidInputData, length, MaxEvaluations)
+	}
+
+	logger := e.log.New("rule_uid", rule.UID, "from", from, "to", to, "interval", interval)
+	logger.Debug("starting backtesting of alert rule", "evaluations", length)
+
+	now := from
+	manager := state.NewDryRunManager(logger, e.externalURL, func() time.Time { return now })
+	condition := &models.Condition{
+		Condition: rule.Condition,
+		OrgID:     rule.OrgID,
+		Data:      rule.Data,
+	}
+
+	times := make([]time.Time, 0, length)
+	fields := make(map[string]*data.Field)
+	for idx := 0; idx < length; idx++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		now = from.Add(time.Duration(idx) * interval)
+		results, err := e.evaluator.ConditionEval(condition, now, e.expressionService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate rule at %s: %w", now.Format(time.RFC3339), err)
+		}
+		manager.ProcessEvalResults(ctx, rule, results)
+		times = append(times, now)
+
+		// the cache also contains the instances that were not part of this evaluation but are not stale yet
+		for _, s := range manager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			field, ok := fields[s.CacheId]
+			if !ok {
+				field = data.NewFieldFromFieldType(data.FieldTypeNullableString, length)
+				field.Name = "state"
+				field.Labels = instanceLabels(s.Labels)
+				fields[s.CacheId] = field
+			}
+			value := s.State.String()
+			field.Set(idx, &value)
+		}
+	}
+
+	frame := data.NewFrame("backtesting", data.NewField("time", nil, times))
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		frame.Fields = append(frame.Fields, fields[id])
+	}
+	return frame, nil
+}
+
+// instanceLabels returns the labels of the state without the labels that identify the rule, which are meaningless for
+// a rule that is not saved.
+func instanceLabels(labels data.Labels) data.Labels {
+	result := make(data.Labels, len(labels))
+	for k, v := range labels {
+		if k == models.RuleUIDLabel || k == models.NamespaceUIDLabel {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEngine_Test(t *testing.T) {
+	from := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Minute)
+
+	newRule := func(mutators ...func(*models.AlertRule)) *models.AlertRule {
+		rule := &models.AlertRule{
+			OrgID:           1,
+			Title:           "test",
+			Condition:       "A",
+			IntervalSeconds: 60,
+			NoDataState:     models.NoData,
+			ExecErrState:    models.AlertingErrState,
+		}
+		for _, m := range mutators {
+			m(rule)
+		}
+		return rule
+	}
+
+	t.Run("should apply the For duration of the rule", func(t *testing.T) {
+		engine := newTestEngine(func(now time.Time) eval.Results {
+			return eval.Results{{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: now}}
+		})
+		rule := newRule(func(rule *models.AlertRule) {
+			rule.For = 2 * time.Minute
+		})
+
+		frame, err := engine.Test(context.Background(), rule, from, to)
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 5, frame.Rows())
+		require.Equal(t, from, frame.Fields[0].At(0))
+		require.Equal(t, to, frame.Fields[0].At(4))
+		require.Equal(t, data.Labels{"instance": "a", "alertname": "test"}, frame.Fields[1].Labels)
+		require.Equal(t, []string{"Pending", "Pending", "Alerting", "Alerting", "Alerting"}, fieldStates(frame.Fields[1]))
+	})
+
+	t.Run("should apply the NoData state of the rule", func(t *testing.T) {
+		engine := newTestEngine(func(now time.Time) eval.Results {
+			if now.Before(from.Add(2 * time.Minute)) {
+				return eval.Results{{State: eval.Alerting, EvaluatedAt: now}}
+			}
+			return eval.Results{{State: eval.NoData, EvaluatedAt: now}}
+		})
+
+		frame, err := engine.Test(context.Background(), newRule(func(rule *models.AlertRule) {
+			rule.NoDataState = models.OK
+		}), from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, []string{"Alerting", "Alerting", "Normal", "Normal", "Normal"}, fieldStates(frame.Fields[1]))
+
+		frame, err = engine.Test(context.Background(), newRule(), from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, []string{"Alerting", "Alerting", "NoData", "NoData", "NoData"}, fieldStates(frame.Fields[1]))
+	})
+
+	t.Run("should apply the execution error state of the rule", func(t *testing.T) {
+		engine := newTestEngine(func(now time.Time) eval.Results {
+			return eval.Results{{State: eval.Error, Error: errors.New("failed"), EvaluatedAt: now}}
+		})
+
+		frame, err := engine.Test(context.Background(), newRule(func(rule *models.AlertRule) {
+			rule.ExecErrState = models.ErrorErrState
+		}), from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, []string{"Error", "Error", "Error", "Error", "Error"}, fieldStates(frame.Fields[1]))
+	})
+
+	t.Run("should keep instances until they become stale", func(t *testing.T) {
+		engine := newTestEngine(func(now time.Time) eval.Results {
+			results := eval.Results{{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: now}}
+			if now.Equal(from) {
+				results = append(results, eval.Result{Instance: data.Labels{"instance": "b"}, State: eval.Normal, EvaluatedAt: now})
+			}
+			return results
+		})
+
+		frame, err := engine.Test(context.Background(), newRule(), from, to)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, []string{"Normal", "Normal", "Normal", "Normal", "Normal"}, fieldStates(frame.Fields[1]))
+		require.Equal(t, []string{"Normal", "Normal", "Normal", "", ""}, fieldStates(frame.Fields[2]))
+	})
+
+	t.Run("should fail if the time range requires too many evaluations", func(t *testing.T) {
+		engine := newTestEngine(nil)
+		_, err := engine.Test(context.Background(), newRule(), from, from.Add(MaxEvaluations*time.Minute))
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail if the time range is empty", func(t *testing.T) {
+		engine := newTestEngine(nil)
+		_, err := engine.Test(context.Background(), newRule(), to, from)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail if evaluation fails", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		engine := NewEngine(&fakeEvaluator{err: expectedErr}, nil, nil, log.New("test"))
+		_, err := engine.Test(context.Background(), newRule(), from, to)
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func newTestEngine(evalFn func(now time.Time) eval.Results) *Engine {
+	return NewEngine(&fakeEvaluator{evalFn: evalFn}, nil, nil, log.New("test"))
+}
+
+// fieldStates returns the values of a state field, null values are returned as empty strings.
+func fieldStates(field *data.Field) []string {
+	result := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			result = append(result, "")
+			continue
+		}
+		result = append(result, v.(string))
+	}
+	return result
+}
+
+type fakeEvaluator struct {
+	evalFn func(now time.Time) eval.Results
+	err    error
+}
+
+func (f *fakeEvaluator) ConditionEval(_ *models.Condition, now time.Time, _ *expr.Service) (eval.Results, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.evalFn(now), nil
+}
+
+func (f *fakeEvaluator) QueriesAndExpressionsEval(_ int64, _ []models.AlertQuery, _ time.Time, _ *expr.Service) (*backend.QueryDataResponse, error) {
+	return nil, errors.New("not implemented")
+}
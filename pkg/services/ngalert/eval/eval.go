@@ -4,6 +4,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"runtime/debug"
 	"sort"
@@ -131,10 +132,15 @@ const (
 	// Error is the eval state for an alert rule condition
 	// that evaluated to Error.
 	Error
+
+	// Paused is the state of the alert instances of a rule
+	// that is paused or in a maintenance window. The condition
+	// is not evaluated, so results never have this state.
+	Paused
 )
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Paused"}[s]
 }
 
 // AlertExecCtx is the context provided for executing an alert condition.
@@ -253,7 +259,12 @@ type NumberValueCapture struct {
 }
 
 func executeCondition(ctx AlertExecCtx, c *models.Condition, now time.Time, exprService *expr.Service, dsCacheService datasources.CacheService, secretsService secrets.Service) ExecutionResults {
-	execResp, err := executeQueriesAndExpressions(ctx, c.Data, now, exprService, dsCacheService, secretsService)
+	queries, err := withLoadedDimensions(c.Data, c.LoadedDimensions)
+	if err != nil {
+		return ExecutionResults{Error: err}
+	}
+
+	execResp, err := executeQueriesAndExpressions(ctx, queries, now, exprService, dsCacheService, secretsService)
 	if err != nil {
 		return ExecutionResults{Error: err}
 	}
@@ -336,6 +347,42 @@ func executeCondition(ctx AlertExecCtx, c *models.Condition, now time.Time, expr
 	return result
 }
 
+// withLoadedDimensions returns a copy of the queries in which the threshold expressions are given the dimensions
+// that met the condition at the previous evaluation, so that they can apply their unload threshold.
+func withLoadedDimensions(queries []models.AlertQuery, loaded []data.Labels) ([]models.AlertQuery, error) {
+	if len(loaded) == 0 {
+		return queries, nil
+	}
+	result := make([]models.AlertQuery, 0, len(queries))
+	for _, q := range queries {
+		if !expr.IsDataSource(q.DatasourceUID) {
+			result = append(result, q)
+			continue
+		}
+		var model map[string]interface{}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal query model: %w", err)
+		}
+		if model["type"] != "threshold" {
+			result = append(result, q)
+			continue
+		}
+		model["loadedDimensions"] = loaded
+		raw, err := json.Marshal(model)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal query model: %w", err)
+		}
+		result = append(result, models.AlertQuery{
+			RefID:             q.RefID,
+			QueryType:         q.QueryType,
+			RelativeTimeRange: q.RelativeTimeRange,
+			DatasourceUID:     q.DatasourceUID,
+			Model:             raw,
+		})
+	}
+	return result, nil
+}
+
 func executeQueriesAndExpressions(ctx AlertExecCtx, data []models.AlertQuery, now time.Time, exprService *expr.Service, dsCacheService datasources.CacheService, secretsService secrets.Service) (resp *backend.QueryDataResponse, err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -1,6 +1,7 @@
 package eval
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -8,6 +9,12 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 	ptr "github.com/xorcare/pointer"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestEvaluateExecutionResult(t *testing.T) {
@@ -340,3 +347,46 @@ func TestEvaluateExecutionResultsNoData(t *testing.T) {
 		require.ElementsMatch(t, []string{"A,B", "C"}, refIDs)
 	})
 }
+
+func TestConditionEvalLoadedDimensions(t *testing.T) {
+	cfg := &setting.Cfg{ExpressionsEnabled: true}
+	cfg.UnifiedAlerting.EvaluationTimeout = time.Minute
+	evaluator := NewEvaluator(cfg, log.NewNopLogger(), nil, fakes.NewFakeSecretsService())
+	exprService := expr.ProvideService(cfg, nil, nil)
+
+	condition := func(loaded []data.Labels) *models.Condition {
+		return &models.Condition{
+			Condition: "B",
+			OrgID:     1,
+			Data: []models.AlertQuery{
+				{
+					RefID:         "A",
+					DatasourceUID: expr.DatasourceUID,
+					Model:         json.RawMessage(`{ "type": "math", "expression": "5" }`),
+				},
+				{
+					RefID:         "B",
+					DatasourceUID: expr.DatasourceUID,
+					Model: json.RawMessage(`{ "type": "threshold", "expression": "$A",
+						"evaluator": { "type": "gt", "params": [10] },
+						"unloadEvaluator": { "type": "lt", "params": [3] } }`),
+				},
+			},
+			LoadedDimensions: loaded,
+		}
+	}
+
+	t.Run("should be normal if the dimension was not loaded", func(t *testing.T) {
+		results, err := evaluator.ConditionEval(condition(nil), time.Now(), exprService)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, Normal, results[0].State)
+	})
+
+	t.Run("should keep alerting until the unload threshold is met if the dimension was loaded", func(t *testing.T) {
+		results, err := evaluator.ConditionEval(condition([]data.Labels{{}}), time.Now(), exprService)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, Alerting, results[0].State)
+	})
+}
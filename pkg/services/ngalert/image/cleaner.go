@@ -0,0 +1,71 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const imageCleanupInterval = time.Hour
+
+// Cleaner periodically deletes the screenshots that are older than the configured retention, along with their
+// files on the local disk.
+type Cleaner struct {
+	store     store.ImageStore
+	retention time.Duration
+	interval  time.Duration
+	log       log.Logger
+	timeNow   func() time.Time
+}
+
+func NewCleaner(imageStore store.ImageStore, retention time.Duration, logger log.Logger) *Cleaner {
+	return &Cleaner{
+		store:     imageStore,
+		retention: retention,
+		interval:  imageCleanupInterval,
+		log:       logger,
+		timeNow:   time.Now,
+	}
+}
+
+// Run deletes outdated images right away and then once per interval until the context is cancelled.
+// Nothing is deleted if no retention is configured.
+func (c *Cleaner) Run(ctx context.Context) error {
+	if c.retention <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.cleanup(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *Cleaner) cleanup(ctx context.Context) {
+	before := c.timeNow().Add(-c.retention)
+	deleted, err := c.store.DeleteImagesBefore(ctx, before)
+	if err != nil {
+		c.log.Error("failed to delete outdated images", "before", before, "error", err)
+		return
+	}
+	for _, image := range deleted {
+		if image.Path == "" {
+			continue
+		}
+		// the file may already be gone with the rest of the temp data
+		if err := os.Remove(filepath.Clean(image.Path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			c.log.Warn("failed to delete image file", "path", image.Path, "error", err)
+		}
+	}
+	c.log.Debug("deleted outdated images", "before", before, "deleted", len(deleted))
+}
@@ -0,0 +1,52 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestCleaner(t *testing.T) {
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	t.Run("should delete images older than the retention and their files", func(t *testing.T) {
+		dir := t.TempDir()
+		outdatedPath := filepath.Join(dir, "outdated.png")
+		recentPath := filepath.Join(dir, "recent.png")
+		require.NoError(t, os.WriteFile(outdatedPath, []byte("png"), 0600))
+		require.NoError(t, os.WriteFile(recentPath, []byte("png"), 0600))
+
+		images := store.NewFakeImageStore()
+		images.Images["outdated"] = &ngmodels.Image{Token: "outdated", Path: outdatedPath, CreatedAt: now.Add(-retention - time.Second)}
+		images.Images["missing"] = &ngmodels.Image{Token: "missing", Path: filepath.Join(dir, "missing.png"), CreatedAt: now.Add(-retention - time.Second)}
+		images.Images["recent"] = &ngmodels.Image{Token: "recent", Path: recentPath, CreatedAt: now}
+		cleaner := NewCleaner(images, retention, log.NewNopLogger())
+		cleaner.timeNow = func() time.Time { return now }
+
+		cleaner.cleanup(context.Background())
+
+		require.Len(t, images.Images, 1)
+		require.Contains(t, images.Images, "recent")
+		require.NoFileExists(t, outdatedPath)
+		require.FileExists(t, recentPath)
+	})
+
+	t.Run("should not delete anything if retention is not set", func(t *testing.T) {
+		images := store.NewFakeImageStore()
+		images.Images["outdated"] = &ngmodels.Image{Token: "outdated", CreatedAt: now.Add(-365 * 24 * time.Hour)}
+		cleaner := NewCleaner(images, 0, log.NewNopLogger())
+		cleaner.timeNow = func() time.Time { return now }
+
+		require.NoError(t, cleaner.Run(context.Background()))
+		require.Len(t, images.Images, 1)
+	})
+}
@@ -0,0 +1,161 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	screenshotWidth  = 1000
+	screenshotHeight = 500
+)
+
+// LocalImagesPath is the path, relative to the app URL, under which Grafana serves the screenshots that are not
+// uploaded to an external image store.
+const LocalImagesPath = "api/alerting/images/"
+
+var (
+	// ErrNoPanel is returned when the alert rule is not linked to a panel.
+	ErrNoPanel = errors.New("alert rule is not linked to a panel")
+	// ErrScreenshotsUnavailable is returned when screenshots are disabled or the image renderer is not available.
+	ErrScreenshotsUnavailable = errors.New("screenshots are unavailable")
+)
+
+// ImageService takes screenshots of the panels alert rules are linked to.
+type ImageService interface {
+	// NewImage takes a screenshot of the panel the rule is linked to and stores it.
+	NewImage(ctx context.Context, rule *ngmodels.AlertRule) (*ngmodels.Image, error)
+}
+
+// Renderer renders the panels of dashboards as images.
+type Renderer interface {
+	IsAvailable() bool
+	Render(ctx context.Context, opts rendering.Opts, session rendering.Session) (*rendering.RenderResult, error)
+}
+
+// DashboardStore provides the dashboards the panels belong to.
+type DashboardStore interface {
+	GetDashboard(ctx context.Context, query *models.GetDashboardQuery) error
+}
+
+// ScreenshotImageService renders the panel an alert rule is linked to with the DashboardUIDAnnotation and
+// PanelIDAnnotation annotations and stores the screenshot in the image store.
+type ScreenshotImageService struct {
+	renderer   Renderer
+	uploader   imguploader.ImageUploader
+	dashboards DashboardStore
+	images     store.ImageStore
+	appURL     *url.URL
+	timeout    time.Duration
+	log        log.Logger
+}
+
+// NewScreenshotImageService creates a new ScreenshotImageService. The uploader can be nil, in which case the
+// screenshots are kept on the local disk and served by Grafana under the app URL.
+func NewScreenshotImageService(cfg setting.UnifiedAlertingSettings, appURL *url.URL, renderer Renderer, uploader imguploader.ImageUploader,
+	dashboards DashboardStore, images store.ImageStore, logger log.Logger) *ScreenshotImageService {
+	return &ScreenshotImageService{
+		renderer:   renderer,
+		uploader:   uploader,
+		dashboards: dashboards,
+		images:     images,
+		appURL:     appURL,
+		timeout:    cfg.ScreenshotsCaptureTimeout,
+		log:        logger,
+	}
+}
+
+// NewImage renders the panel and stores the screenshot. If an uploader is configured, the screenshot is uploaded
+// to the external image store to get a public URL. Otherwise, or if the upload fails, the screenshot is linked to
+// with its URL in Grafana.
+func (s *ScreenshotImageService) NewImage(ctx context.Context, rule *ngmodels.AlertRule) (*ngmodels.Image, error) {
+	dashboardUID, ok := rule.Annotations[ngmodels.DashboardUIDAnnotation]
+	if !ok || dashboardUID == "" {
+		return nil, ErrNoPanel
+	}
+	panelID, err := strconv.ParseInt(rule.Annotations[ngmodels.PanelIDAnnotation], 10, 64)
+	if err != nil {
+		return nil, ErrNoPanel
+	}
+	if !s.renderer.IsAvailable() {
+		return nil, ErrScreenshotsUnavailable
+	}
+
+	query := &models.GetDashboardQuery{Uid: dashboardUID, OrgId: rule.OrgID}
+	if err := s.dashboards.GetDashboard(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to get dashboard %s: %w", dashboardUID, err)
+	}
+
+	opts := rendering.Opts{
+		TimeoutOpts: rendering.TimeoutOpts{
+			Timeout: s.timeout,
+		},
+		AuthOpts: rendering.AuthOpts{
+			OrgID:   rule.OrgID,
+			OrgRole: models.ROLE_ADMIN,
+		},
+		Width:           screenshotWidth,
+		Height:          screenshotHeight,
+		Path:            fmt.Sprintf("d-solo/%s/%s?orgId=%d&panelId=%d", dashboardUID, query.Result.Slug, rule.OrgID, panelID),
+		ConcurrentLimit: setting.AlertingRenderLimit,
+		Theme:           models.ThemeDark,
+	}
+
+	logger := s.log.New("rule_uid", rule.UID, "dashboard_uid", dashboardUID, "panel_id", panelID)
+	start := time.Now()
+	result, err := s.renderer.Render(ctx, opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render panel: %w", err)
+	}
+	logger.Debug("rendered panel", "path", result.FilePath, "took", time.Since(start))
+
+	image := &ngmodels.Image{Path: result.FilePath}
+	if s.uploader != nil {
+		url, err := s.uploader.Upload(ctx, result.FilePath)
+		if err != nil {
+			logger.Warn("failed to upload screenshot to external image store", "path", result.FilePath, "error", err)
+		} else {
+			image.URL = url
+		}
+	}
+	if !image.HasURL() && s.appURL != nil {
+		token, err := store.NewImageToken()
+		if err != nil {
+			return nil, err
+		}
+		image.Token = token
+		image.URL = LocalURL(s.appURL, token)
+	}
+
+	if err := s.images.SaveImage(ctx, image); err != nil {
+		return nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	return image, nil
+}
+
+// LocalURL returns the URL of the image with the given token in Grafana.
+func LocalURL(appURL *url.URL, token string) string {
+	u := *appURL
+	u.Path = path.Join(u.Path, LocalImagesPath, token)
+	return u.String()
+}
+
+// NotAvailableImageService is used when screenshots are disabled.
+type NotAvailableImageService struct{}
+
+func (NotAvailableImageService) NewImage(_ context.Context, _ *ngmodels.AlertRule) (*ngmodels.Image, error) {
+	return nil, ErrScreenshotsUnavailable
+}
@@ -0,0 +1,121 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeUploader struct {
+	url string
+	err error
+}
+
+func (f *fakeUploader) Upload(_ context.Context, _ string) (string, error) {
+	return f.url, f.err
+}
+
+func TestScreenshotImageService_NewImage(t *testing.T) {
+	cfg := setting.UnifiedAlertingSettings{ScreenshotsCaptureTimeout: 5 * time.Second}
+	dashboards := &FakeDashboardStore{Dashboards: map[string]*models.Dashboard{
+		"dashboard": {Uid: "dashboard", Slug: "my-dashboard", OrgId: 1},
+	}}
+	rule := &ngmodels.AlertRule{
+		OrgID: 1,
+		UID:   "rule",
+		Annotations: map[string]string{
+			ngmodels.DashboardUIDAnnotation: "dashboard",
+			ngmodels.PanelIDAnnotation:      "2",
+		},
+	}
+
+	t.Run("should render the panel and save the image", func(t *testing.T) {
+		renderer := NewFakeRenderer(t.TempDir())
+		images := store.NewFakeImageStore()
+		s := NewScreenshotImageService(cfg, nil, renderer, nil, dashboards, images, log.New("test"))
+
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.NotEmpty(t, image.Token)
+		require.FileExists(t, image.Path)
+		require.False(t, image.HasURL())
+		require.Equal(t, image, images.Images[image.Token])
+
+		require.Len(t, renderer.Requests, 1)
+		opts := renderer.Requests[0]
+		require.Equal(t, "d-solo/dashboard/my-dashboard?orgId=1&panelId=2", opts.Path)
+		require.Equal(t, int64(1), opts.OrgID)
+		require.Equal(t, 5*time.Second, opts.Timeout)
+	})
+
+	t.Run("should upload the image if an uploader is configured", func(t *testing.T) {
+		images := store.NewFakeImageStore()
+		uploader := &fakeUploader{url: "https://images.example.com/1.png"}
+		s := NewScreenshotImageService(cfg, nil, NewFakeRenderer(t.TempDir()), uploader, dashboards, images, log.New("test"))
+
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.Equal(t, "https://images.example.com/1.png", image.URL)
+	})
+
+	t.Run("should keep the image if the upload fails", func(t *testing.T) {
+		images := store.NewFakeImageStore()
+		uploader := &fakeUploader{err: errors.New("failed")}
+		s := NewScreenshotImageService(cfg, nil, NewFakeRenderer(t.TempDir()), uploader, dashboards, images, log.New("test"))
+
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.Empty(t, image.URL)
+		require.FileExists(t, image.Path)
+	})
+
+	t.Run("should link the image in Grafana if it is not uploaded", func(t *testing.T) {
+		images := store.NewFakeImageStore()
+		appURL, err := url.Parse("https://grafana.example.com/sub/")
+		require.NoError(t, err)
+		uploader := &fakeUploader{err: errors.New("failed")}
+		s := NewScreenshotImageService(cfg, appURL, NewFakeRenderer(t.TempDir()), uploader, dashboards, images, log.New("test"))
+
+		image, err := s.NewImage(context.Background(), rule)
+		require.NoError(t, err)
+		require.Len(t, image.Token, 32)
+		require.Equal(t, "https://grafana.example.com/sub/api/alerting/images/"+image.Token, image.URL)
+		require.FileExists(t, image.Path)
+		require.Equal(t, image, images.Images[image.Token])
+	})
+
+	t.Run("should fail if the rule is not linked to a panel", func(t *testing.T) {
+		renderer := NewFakeRenderer(t.TempDir())
+		s := NewScreenshotImageService(cfg, nil, renderer, nil, dashboards, store.NewFakeImageStore(), log.New("test"))
+
+		_, err := s.NewImage(context.Background(), &ngmodels.AlertRule{OrgID: 1})
+		require.ErrorIs(t, err, ErrNoPanel)
+		require.Empty(t, renderer.Requests)
+	})
+
+	t.Run("should fail if the renderer is not available", func(t *testing.T) {
+		renderer := NewFakeRenderer(t.TempDir())
+		renderer.Unavailable = true
+		s := NewScreenshotImageService(cfg, nil, renderer, nil, dashboards, store.NewFakeImageStore(), log.New("test"))
+
+		_, err := s.NewImage(context.Background(), rule)
+		require.ErrorIs(t, err, ErrScreenshotsUnavailable)
+	})
+
+	t.Run("should fail if the dashboard does not exist", func(t *testing.T) {
+		s := NewScreenshotImageService(cfg, nil, NewFakeRenderer(t.TempDir()), nil, &FakeDashboardStore{}, store.NewFakeImageStore(), log.New("test"))
+
+		_, err := s.NewImage(context.Background(), rule)
+		require.ErrorIs(t, err, models.ErrDashboardNotFound)
+	})
+}
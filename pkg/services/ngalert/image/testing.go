@@ -0,0 +1,84 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/rendering"
+)
+
+// transparentPNG is a 1x1 transparent PNG image.
+const transparentPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+// FakeRenderer writes a tiny PNG image to a directory instead of rendering the panel, and records the requests.
+type FakeRenderer struct {
+	Dir         string
+	Unavailable bool
+	Err         error
+
+	mtx      sync.Mutex
+	Requests []rendering.Opts
+}
+
+func NewFakeRenderer(dir string) *FakeRenderer {
+	return &FakeRenderer{Dir: dir}
+}
+
+func (f *FakeRenderer) IsAvailable() bool {
+	return !f.Unavailable
+}
+
+func (f *FakeRenderer) Render(_ context.Context, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Requests = append(f.Requests, opts)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	b, err := base64.StdEncoding.DecodeString(transparentPNG)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(f.Dir, fmt.Sprintf("%d.png", len(f.Requests)))
+	if err := ioutil.WriteFile(path, b, os.FileMode(0600)); err != nil {
+		return nil, err
+	}
+	return &rendering.RenderResult{FilePath: path}, nil
+}
+
+// FakeDashboardStore returns the dashboards it was created with.
+type FakeDashboardStore struct {
+	Dashboards map[string]*models.Dashboard
+}
+
+func (f *FakeDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	d, ok := f.Dashboards[query.Uid]
+	if !ok || d.OrgId != query.OrgId {
+		return models.ErrDashboardNotFound
+	}
+	query.Result = d
+	return nil
+}
+
+// FakeImageService returns the same image for every rule and counts the calls.
+type FakeImageService struct {
+	Image *ngmodels.Image
+	Err   error
+
+	mtx   sync.Mutex
+	Calls int
+}
+
+func (f *FakeImageService) NewImage(_ context.Context, _ *ngmodels.AlertRule) (*ngmodels.Image, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Calls++
+	return f.Image, f.Err
+}
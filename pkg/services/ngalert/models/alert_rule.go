@@ -8,6 +8,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
@@ -111,6 +115,94 @@ type AlertRule struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	// Dependencies are the conditions under which the alerting instances of the rule are suppressed.
+	Dependencies []AlertRuleDependency
+	// Record makes the rule a recording rule. It is nil for alert rules.
+	Record *Record
+	// IsPaused stops the evaluation of the rule. The instances of a paused rule are in the Paused state.
+	IsPaused bool
+}
+
+// RecordTarget is the destination of the metrics written by recording rules.
+type RecordTarget string
+
+const (
+	// RecordTargetRemoteWrite writes the metric to the Prometheus remote write endpoint configured for recording rules.
+	RecordTargetRemoteWrite RecordTarget = "remote_write"
+	// RecordTargetLive publishes the metric to the Grafana Live channel stream/alerting/<metric> of the organization.
+	RecordTargetLive RecordTarget = "live"
+)
+
+// Record turns an alert rule into a recording rule. Recording rules are evaluated on the same schedule as alert rules,
+// but instead of producing alert instances the result of the query or expression referenced by the condition is
+// written as a metric to the target.
+type Record struct {
+	// Metric is the name of the written metric.
+	Metric string       `json:"metric" yaml:"metric"`
+	Target RecordTarget `json:"target" yaml:"target"`
+}
+
+// Validate checks that the metric name is valid and the target is known.
+func (r *Record) Validate() error {
+	if !model.IsValidMetricName(model.LabelValue(r.Metric)) {
+		return fmt.Errorf("invalid metric name %q", r.Metric)
+	}
+	switch r.Target {
+	case RecordTargetRemoteWrite, RecordTargetLive:
+		return nil
+	default:
+		return fmt.Errorf("unknown record target %q", r.Target)
+	}
+}
+
+func (r *Record) FromDB(b []byte) error {
+	return json.Unmarshal(b, r)
+}
+
+func (r *Record) ToDB() ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// AlertRuleDependency declares that the alerting instances of a rule are suppressed while an instance of another rule is firing.
+// The firing instance must belong to the rule RuleUID, if it is set, and its labels must match all Matchers. At least one of them is required.
+// If Equal is set, only the instances that have the same values of these labels as the firing instance are suppressed.
+type AlertRuleDependency struct {
+	RuleUID  string   `json:"rule_uid,omitempty" yaml:"rule_uid,omitempty"`
+	Matchers []string `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Equal    []string `json:"equal,omitempty" yaml:"equal,omitempty"`
+}
+
+// LabelMatchers parses the matchers of the dependency.
+func (d AlertRuleDependency) LabelMatchers() (labels.Matchers, error) {
+	matchers := make(labels.Matchers, 0, len(d.Matchers))
+	for _, s := range d.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// Validate checks that the dependency refers to a rule or has valid matchers.
+func (d AlertRuleDependency) Validate() error {
+	if d.RuleUID == "" && len(d.Matchers) == 0 {
+		return errors.New("either rule UID or matchers must be specified")
+	}
+	_, err := d.LabelMatchers()
+	return err
+}
+
+// AlertRuleGroup is a named group of alert rules within a folder. All rules of a group are evaluated with the same interval.
+type AlertRuleGroup struct {
+	Title     string
+	FolderUID string
+	Interval  int64
+	Rules     []AlertRule
 }
 
 type LabelOption func(map[string]string)
@@ -125,6 +217,11 @@ func WithoutInternalLabels() LabelOption {
 	}
 }
 
+// IsRecordingRule returns true if the rule writes its results as a metric instead of producing alert instances.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
+}
+
 // GetLabels returns the labels specified as part of the alert rule.
 func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	labels := alertRule.Labels
@@ -214,9 +311,12 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For          time.Duration
+	Annotations  map[string]string
+	Labels       map[string]string
+	Dependencies []AlertRuleDependency
+	Record       *Record
+	IsPaused     bool
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -281,6 +381,10 @@ type Condition struct {
 
 	// Data is an array of data source queries and/or server side expressions.
 	Data []AlertQuery `json:"data"`
+
+	// LoadedDimensions are the labels of the results that met the condition at the previous evaluation.
+	// Threshold expressions with an unload threshold use them to apply hysteresis.
+	LoadedDimensions []data.Labels `json:"-"`
 }
 
 // IsValid checks the condition's validity.
@@ -291,7 +395,7 @@ func (c Condition) IsValid() bool {
 
 // PatchPartialAlertRule patches `ruleToPatch` by `existingRule` following the rule that if a field of `ruleToPatch` is empty or has the default value, it is populated by the value of the corresponding field from `existingRule`.
 // There are several exceptions:
-// 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
+// 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations, AlertRule.Labels, AlertRule.Dependencies, AlertRule.Record and AlertRule.IsPaused
 // 2. There are fields that are patched together:
 //    - AlertRule.Condition and AlertRule.Data
 // If either of the pair is specified, neither is patched.
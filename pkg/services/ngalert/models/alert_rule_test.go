@@ -80,6 +80,85 @@ func TestErrStateFromString(t *testing.T) {
 	})
 }
 
+func TestAlertRuleDependency_Validate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dependency AlertRuleDependency
+		expectErr  bool
+	}{
+		{
+			name:       "should accept rule UID",
+			dependency: AlertRuleDependency{RuleUID: "dc-down", Equal: []string{"dc"}},
+		},
+		{
+			name:       "should accept matchers",
+			dependency: AlertRuleDependency{Matchers: []string{`alertname="dc-down"`, "severity=~critical|major"}},
+		},
+		{
+			name:       "should fail if neither rule UID nor matchers are specified",
+			dependency: AlertRuleDependency{Equal: []string{"dc"}},
+			expectErr:  true,
+		},
+		{
+			name:       "should fail if a matcher is invalid",
+			dependency: AlertRuleDependency{RuleUID: "dc-down", Matchers: []string{"dc"}},
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dependency.Validate()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			matchers, err := tc.dependency.LabelMatchers()
+			require.NoError(t, err)
+			require.Len(t, matchers, len(tc.dependency.Matchers))
+		})
+	}
+}
+
+func TestRecord_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		record    Record
+		expectErr bool
+	}{
+		{
+			name:   "should accept valid metric name and remote write target",
+			record: Record{Metric: "instance:up:rate5m", Target: RecordTargetRemoteWrite},
+		},
+		{
+			name:   "should accept live target",
+			record: Record{Metric: "up", Target: RecordTargetLive},
+		},
+		{
+			name:      "should fail if metric name is invalid",
+			record:    Record{Metric: "1-up", Target: RecordTargetLive},
+			expectErr: true,
+		},
+		{
+			name:      "should fail if target is unknown",
+			record:    Record{Metric: "up", Target: "unknown"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.record.Validate()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestPatchPartialAlertRule(t *testing.T) {
 	t.Run("patches", func(t *testing.T) {
 		testCases := []struct {
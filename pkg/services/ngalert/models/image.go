@@ -0,0 +1,28 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ImageTokenAnnotation is the annotation of an alert that references the screenshot taken when the alert started firing.
+const ImageTokenAnnotation = "__alertImageToken__"
+
+var ErrImageNotFound = errors.New("image not found")
+
+// Image is a screenshot of the panel an alert rule is linked to.
+type Image struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	Token string `xorm:"token"`
+	// Path is the location of the image on the local disk. The file is removed with the image, or earlier after the
+	// temp data lifetime.
+	Path string `xorm:"path"`
+	// URL is the public URL of the image in the external image store if it was uploaded, or its URL in Grafana.
+	URL       string    `xorm:"url"`
+	CreatedAt time.Time `xorm:"created_at"`
+}
+
+// HasURL returns true if the image can be linked to.
+func (i *Image) HasURL() bool {
+	return i.URL != ""
+}
@@ -31,6 +31,8 @@ const (
 	InstanceStateNoData InstanceStateType = "NoData"
 	// InstanceStateError is for a erroring alert.
 	InstanceStateError InstanceStateType = "Error"
+	// InstanceStatePaused is for an alert whose rule is paused or in a maintenance window.
+	InstanceStatePaused InstanceStateType = "Paused"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -40,7 +42,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNormal ||
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
-		i == InstanceStateError
+		i == InstanceStateError ||
+		i == InstanceStatePaused
 }
 
 // SaveAlertInstanceCommand is the query for saving a new alert instance.
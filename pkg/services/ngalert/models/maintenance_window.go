@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+var (
+	// ErrMaintenanceWindowNotFound is an error for an unknown maintenance window.
+	ErrMaintenanceWindowNotFound = errors.New("could not find maintenance window")
+	// ErrMaintenanceWindowFailedValidation is an error for an invalid maintenance window.
+	ErrMaintenanceWindowFailedValidation = errors.New("invalid maintenance window")
+)
+
+// MaintenanceWindow is a period during which the evaluation of alert rules is skipped. It applies either to a single
+// rule or to all rules of a folder. The instances of the rules are in the Paused state while the window is active.
+type MaintenanceWindow struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	OrgID int64  `xorm:"org_id"`
+	UID   string `xorm:"uid"`
+	Title string
+	// NamespaceUID is the UID of the folder whose rules are paused during the window.
+	NamespaceUID string `xorm:"namespace_uid"`
+	// RuleUID is the UID of the rule that is paused during the window.
+	RuleUID string `xorm:"rule_uid"`
+	// Ranges are one-off periods, such as a planned maintenance of a data source.
+	Ranges []MaintenanceTimeRange
+	// TimeIntervals are recurring periods in the format of the mute timings of the Alertmanager, such as every
+	// Sunday between 02:00 and 04:00 UTC.
+	TimeIntervals []timeinterval.TimeInterval
+	Updated       time.Time
+}
+
+// MaintenanceTimeRange is an absolute period of time. The end is exclusive.
+type MaintenanceTimeRange struct {
+	Start time.Time `json:"start" yaml:"start"`
+	End   time.Time `json:"end" yaml:"end"`
+}
+
+// Validate checks that the window applies either to a folder or to a rule and that it has valid periods.
+func (w *MaintenanceWindow) Validate() error {
+	if w.Title == "" {
+		return fmt.Errorf("%w: title must not be empty", ErrMaintenanceWindowFailedValidation)
+	}
+	if (w.NamespaceUID == "") == (w.RuleUID == "") {
+		return fmt.Errorf("%w: either folder UID or rule UID must be specified", ErrMaintenanceWindowFailedValidation)
+	}
+	if len(w.Ranges) == 0 && len(w.TimeIntervals) == 0 {
+		return fmt.Errorf("%w: at least one range or time interval must be specified", ErrMaintenanceWindowFailedValidation)
+	}
+	for _, r := range w.Ranges {
+		if !r.Start.Before(r.End) {
+			return fmt.Errorf("%w: start of range %s must be before its end %s", ErrMaintenanceWindowFailedValidation, r.Start, r.End)
+		}
+	}
+	return nil
+}
+
+// AppliesTo returns true if the window pauses the rule.
+func (w *MaintenanceWindow) AppliesTo(rule *AlertRule) bool {
+	if w.OrgID != rule.OrgID {
+		return false
+	}
+	if w.RuleUID != "" {
+		return w.RuleUID == rule.UID
+	}
+	return w.NamespaceUID == rule.NamespaceUID
+}
+
+// IsActive returns true if the time is within one of the ranges or time intervals of the window.
+// Time intervals are evaluated in UTC, like the mute timings of the Alertmanager.
+func (w *MaintenanceWindow) IsActive(t time.Time) bool {
+	for _, r := range w.Ranges {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			return true
+		}
+	}
+	for _, ti := range w.TimeIntervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *MaintenanceWindow) ResourceType() string {
+	return "maintenanceWindow"
+}
+
+func (w *MaintenanceWindow) ResourceID() string {
+	return w.UID
+}
+
+// ListMaintenanceWindowsQuery is the query for listing maintenance windows. If OrgID is zero, the windows of all
+// organizations except ExcludeOrgs are returned.
+type ListMaintenanceWindowsQuery struct {
+	OrgID       int64
+	ExcludeOrgs []int64
+
+	Result []*MaintenanceWindow
+}
+
+// GetMaintenanceWindowQuery is the query for retrieving a maintenance window by UID and organisation ID.
+type GetMaintenanceWindowQuery struct {
+	OrgID int64
+	UID   string
+
+	Result *MaintenanceWindow
+}
+
+// PauseReason returns why the evaluation of the rule is skipped at the given time, or an empty string if the rule
+// is to be evaluated.
+func PauseReason(rule *AlertRule, windows []*MaintenanceWindow, t time.Time) string {
+	if rule.IsPaused {
+		return "rule is paused"
+	}
+	for _, w := range windows {
+		if w.AppliesTo(rule) && w.IsActive(t) {
+			return fmt.Sprintf("maintenance window %q is active", w.Title)
+		}
+	}
+	return ""
+}
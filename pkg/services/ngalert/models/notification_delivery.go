@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationDeliveryStatus is the outcome of an attempt to deliver a notification.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailure NotificationDeliveryStatus = "failure"
+)
+
+// NotificationDelivery is a single attempt of an integration of a contact point to deliver a notification.
+type NotificationDelivery struct {
+	ID    int64 `xorm:"pk autoincr 'id'"`
+	OrgID int64 `xorm:"org_id"`
+	// Receiver is the name of the contact point.
+	Receiver string
+	// Integration is the type of the integration, e.g. slack or webhook.
+	Integration    string
+	IntegrationUID string `xorm:"integration_uid"`
+	// Fingerprints are the fingerprints of the alerts in the notification.
+	Fingerprints []string
+	Status       NotificationDeliveryStatus
+	// StatusCode is the HTTP status code of the response of the service, if the delivery failed because of it.
+	StatusCode int
+	Error      string
+	Latency    time.Duration `xorm:"-"`
+	// LatencyMs is the latency in milliseconds as it is stored in the database.
+	LatencyMs   int64     `xorm:"latency_ms"`
+	AttemptedAt time.Time `xorm:"-"`
+	// AttemptedAtUnix is the time of the attempt in milliseconds since epoch as it is stored in the database.
+	AttemptedAtUnix int64 `xorm:"attempted_at"`
+}
+
+// GetNotificationDeliveriesQuery is the query for retrieving the delivery attempts of notifications within an organization.
+type GetNotificationDeliveriesQuery struct {
+	OrgID       int64
+	Receiver    string
+	Integration string
+	Status      NotificationDeliveryStatus
+	// Fingerprint restricts the attempts to the notifications that contained the alert with the given fingerprint.
+	Fingerprint string
+	From        time.Time
+	To          time.Time
+	// Limit is the maximum number of attempts to return, newest first. Zero means no limit.
+	Limit int
+
+	Result []*NotificationDelivery
+}
+
+// NotificationRetry is a notification that could not be delivered by an integration and whose delivery is retried
+// from the retry queue. The integration is identified like in the notification log of the Alertmanager, by the name
+// of the receiver, the type of the integration and its index within the receiver.
+type NotificationRetry struct {
+	ID               int64 `xorm:"pk autoincr 'id'"`
+	OrgID            int64 `xorm:"org_id"`
+	Receiver         string
+	Integration      string
+	IntegrationIndex int
+	GroupKey         string
+	GroupLabels      map[string]string
+	// Alerts contains the JSON encoded alerts of the notification.
+	Alerts    string
+	Attempts  int
+	LastError string
+	// NextAttemptAtUnix is the time of the next attempt in milliseconds since epoch.
+	NextAttemptAtUnix int64 `xorm:"next_attempt_at"`
+	// CreatedAtUnix is the time the notification was queued in milliseconds since epoch.
+	CreatedAtUnix int64 `xorm:"created_at"`
+}
+
+// ClaimNotificationRetriesQuery is the query for claiming the queued notifications of an organization that are due.
+type ClaimNotificationRetriesQuery struct {
+	OrgID int64
+	Now   time.Time
+	// ClaimUntil is the time until which claimed notifications are not returned by other queries, unless they are
+	// rescheduled or deleted before.
+	ClaimUntil time.Time
+	Limit      int
+
+	Result []*NotificationRetry
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// AlertStateHistoryEntry is a single state transition of an alert instance.
+type AlertStateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	Labels        InstanceLabels
+	LabelsHash    string
+	PreviousState InstanceStateType
+	CurrentState  InstanceStateType
+	// Reason is the error that caused the transition, or the rule that suppresses the instance, if any.
+	Reason string
+	// EvalValues contains the JSON encoded values of the reduce and math expressions at the time of the evaluation.
+	EvalValues  string
+	EvaluatedAt time.Time `xorm:"-"`
+	// EvaluatedAtUnix is the evaluation time in milliseconds since epoch as it is stored in the database.
+	EvaluatedAtUnix int64 `xorm:"evaluated_at"`
+}
+
+// SaveAlertStateHistoryCommand is the command for storing state transitions of alert instances.
+type SaveAlertStateHistoryCommand struct {
+	Entries []AlertStateHistoryEntry
+}
+
+// GetAlertStateHistoryQuery is the query for retrieving state transitions of alert instances within an organization.
+// Entries can be filtered by rules and by label matchers, all matchers must match the labels of the instance.
+type GetAlertStateHistoryQuery struct {
+	OrgID int64
+	// RuleUIDs restricts the entries to the instances of the given rules. Empty means all rules of the organization.
+	RuleUIDs []string
+	Matchers labels.Matchers
+	From     time.Time
+	To       time.Time
+	// Limit is the maximum number of entries to return, newest first. Zero means no limit.
+	Limit int
+
+	Result []*AlertStateHistoryEntry
+}
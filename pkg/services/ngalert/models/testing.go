@@ -149,6 +149,7 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		IsPaused:        r.IsPaused,
 	}
 
 	if r.DashboardUID != nil {
@@ -186,5 +187,18 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
+
+	for _, d := range r.Dependencies {
+		result.Dependencies = append(result.Dependencies, AlertRuleDependency{
+			RuleUID:  d.RuleUID,
+			Matchers: append([]string(nil), d.Matchers...),
+			Equal:    append([]string(nil), d.Equal...),
+		})
+	}
+
 	return &result
 }
@@ -8,6 +8,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/imguploader"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -15,16 +16,21 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
@@ -33,7 +39,7 @@ import (
 func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService, routeRegister routing.RouteRegister,
 	sqlStore *sqlstore.SQLStore, kvStore kvstore.KVStore, expressionService *expr.Service, dataProxy *datasourceproxy.DataSourceProxyService,
 	quotaService *quota.QuotaService, secretsService secrets.Service, notificationService notifications.Service, m *metrics.NGAlert,
-	folderService dashboards.FolderService, ac accesscontrol.AccessControl) (*AlertNG, error) {
+	folderService dashboards.FolderService, ac accesscontrol.AccessControl, live *live.GrafanaLive, renderService rendering.Service) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                 cfg,
 		DataSourceCache:     dataSourceCache,
@@ -49,6 +55,8 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 		NotificationService: notificationService,
 		folderService:       folderService,
 		accesscontrol:       ac,
+		Live:                live,
+		renderService:       renderService,
 	}
 
 	if ng.IsDisabled() {
@@ -75,16 +83,43 @@ type AlertNG struct {
 	SecretsService      secrets.Service
 	Metrics             *metrics.NGAlert
 	NotificationService notifications.Service
+	Live                *live.GrafanaLive
 	Log                 log.Logger
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	historyCleaner      *state.HistoryCleaner
+	deliveryLogCleaner  *notifier.DeliveryLogCleaner
+	imageCleaner        *image.Cleaner
 	folderService       dashboards.FolderService
+	renderService       rendering.Service
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	accesscontrol        accesscontrol.AccessControl
 }
 
+// imageService returns the service that takes screenshots of the panels of alerting rules, if screenshots are enabled.
+func (ng *AlertNG) imageService(store *store.DBstore, appURL *url.URL) image.ImageService {
+	cfg := ng.Cfg.UnifiedAlerting
+	if !cfg.ScreenshotsCapture {
+		return image.NotAvailableImageService{}
+	}
+	if ng.renderService == nil {
+		ng.Log.Warn("Screenshots are enabled but the image renderer is not available")
+		return image.NotAvailableImageService{}
+	}
+	var uploader imguploader.ImageUploader
+	if cfg.ScreenshotsUploadExternalImageStorage {
+		u, err := imguploader.NewImageUploader()
+		if err != nil {
+			ng.Log.Error("Failed to create the external image storage, screenshots are not uploaded", "error", err)
+		} else {
+			uploader = u
+		}
+	}
+	return image.NewScreenshotImageService(cfg, appURL, ng.renderService, uploader, ng.SQLStore, store, ng.Log.New("component", "screenshots"))
+}
+
 func (ng *AlertNG) init() error {
 	var err error
 
@@ -109,6 +144,11 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 
+	var ring *sharding.Ring
+	if ng.Cfg.UnifiedAlerting.HARuleSharding {
+		ring = sharding.NewRing(sharding.NewPeerMembership(ng.MultiOrgAlertmanager.Peer()))
+	}
+
 	schedCfg := schedule.SchedulerCfg{
 		C:                       clock.New(),
 		BaseInterval:            ng.Cfg.UnifiedAlerting.BaseInterval,
@@ -124,6 +164,9 @@ func (ng *AlertNG) init() error {
 		AdminConfigPollInterval: ng.Cfg.UnifiedAlerting.AdminConfigPollInterval,
 		DisabledOrgs:            ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		MinRuleInterval:         ng.Cfg.UnifiedAlerting.MinInterval,
+		RecordingWriter:         recording.NewMetricWriter(ng.Cfg.UnifiedAlerting, ng.liveStreams(), ng.Log),
+		Ring:                    ring,
+		MaintenanceWindowStore:  store,
 	}
 
 	appUrl, err := url.Parse(ng.Cfg.AppURL)
@@ -131,16 +174,22 @@ func (ng *AlertNG) init() error {
 		ng.Log.Error("Failed to parse application URL. Continue without it.", "error", err)
 		appUrl = nil
 	}
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.SQLStore)
+	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, store, ng.SQLStore, ng.imageService(store, appUrl))
 	scheduler := schedule.NewScheduler(schedCfg, ng.ExpressionService, appUrl, stateManager)
 
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
+	ng.historyCleaner = state.NewHistoryCleaner(store, ng.Cfg.UnifiedAlerting.StateHistoryRetention, ng.Log)
+	ng.deliveryLogCleaner = notifier.NewDeliveryLogCleaner(store, ng.Cfg.UnifiedAlerting.DeliveryLogRetention, ng.Log)
+	ng.imageCleaner = image.NewCleaner(store, ng.Cfg.UnifiedAlerting.ScreenshotsRetention, ng.Log)
 
 	// Provisioning
 	policyService := provisioning.NewNotificationPolicyService(store, store, store, ng.Log)
 	contactPointService := provisioning.NewContactPointService(store, ng.SecretsService, store, store, ng.Log)
 	templateService := provisioning.NewTemplateService(store, store, store, ng.Log)
+	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
+	alertRuleService := provisioning.NewAlertRuleService(store, store, store, int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()), ng.Log)
+	maintenanceWindowService := provisioning.NewMaintenanceWindowService(store, store, store, ng.Log)
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -153,16 +202,23 @@ func (ng *AlertNG) init() error {
 		SecretsService:       ng.SecretsService,
 		TransactionManager:   store,
 		InstanceStore:        store,
+		StateHistoryStore:    store,
+		DeliveryStore:        store,
+		ImageStore:           store,
 		RuleStore:            store,
 		AlertingStore:        store,
 		AdminConfigStore:     store,
 		ProvenanceStore:      store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
+		Ring:                 ring,
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
+		MuteTimings:          muteTimingService,
+		AlertRules:           alertRuleService,
+		MaintenanceWindows:   maintenanceWindowService,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -184,9 +240,26 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.MultiOrgAlertmanager.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.historyCleaner.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.deliveryLogCleaner.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.imageCleaner.Run(subCtx)
+	})
 	return children.Wait()
 }
 
+// liveStreams returns the managed streams recording rules publish to, or nil if Grafana Live is not available.
+func (ng *AlertNG) liveStreams() recording.StreamProvider {
+	if ng.Live == nil || ng.Live.ManagedStreamRunner == nil {
+		return nil
+	}
+	return ng.Live.ManagedStreamRunner
+}
+
 // IsDisabled returns true if the alerting service is disable for this instance.
 func (ng *AlertNG) IsDisabled() bool {
 	if ng.Cfg == nil {
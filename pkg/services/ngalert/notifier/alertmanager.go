@@ -80,6 +80,14 @@ func init() {
 	}
 }
 
+// AlertingStore is the store of the Alertmanager configurations, of the images attached to notifications and of the
+// delivery attempts of notifications.
+type AlertingStore interface {
+	store.AlertingStore
+	store.ImageStore
+	store.NotificationDeliveryStore
+}
+
 type ClusterPeer interface {
 	AddState(string, cluster.State, prometheus.Registerer) cluster.ClusterChannel
 	Position() int
@@ -90,7 +98,7 @@ type Alertmanager struct {
 	logger log.Logger
 
 	Settings            *setting.Cfg
-	Store               store.AlertingStore
+	Store               AlertingStore
 	fileStore           *FileStore
 	Metrics             *metrics.Alertmanager
 	NotificationService notifications.Service
@@ -122,13 +130,16 @@ type Alertmanager struct {
 
 	reloadConfigMtx sync.RWMutex
 	config          *apimodels.PostableUserConfig
-	configHash      [16]byte
-	orgID           int64
+	// integrations are the integrations of the receivers of the current configuration by the name of the receiver.
+	// They deliver the notifications of the retry queue.
+	integrations map[string][]notify.Integration
+	configHash   [16]byte
+	orgID        int64
 
 	decryptFn channels.GetDecryptedValueFn
 }
 
-func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store store.AlertingStore, kvStore kvstore.KVStore,
+func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, kvStore kvstore.KVStore,
 	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager) (*Alertmanager, error) {
 	am := &Alertmanager{
 		Settings:            cfg,
@@ -206,6 +217,14 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store s
 		return nil, fmt.Errorf("unable to initialize the alert provider component of alerting: %w", err)
 	}
 
+	if cfg.UnifiedAlerting.DeliveryRetryQueue {
+		am.wg.Add(1)
+		go func() {
+			defer am.wg.Done()
+			am.runRetryQueue(retryQueueInterval)
+		}()
+	}
+
 	return am, nil
 }
 
@@ -438,6 +457,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 	am.config = cfg
 	am.configHash = md5.Sum(rawConfig)
+	am.integrations = integrationsMap
 
 	return nil
 }
@@ -468,7 +488,7 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		integrations = append(integrations, notify.NewIntegration(am.newDeliveryLogNotifier(receiver.Name, r, n), n, r.Type, i))
 	}
 	return integrations, nil
 }
@@ -499,7 +519,7 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 			SecureSettings:        secureSettings,
 		}
 	)
-	factoryConfig, err := channels.NewFactoryConfig(cfg, am.NotificationService, am.decryptFn, tmpl)
+	factoryConfig, err := channels.NewFactoryConfig(cfg, am.NotificationService, am.decryptFn, tmpl, am.Store)
 	if err != nil {
 		return nil, InvalidReceiverError{
 			Receiver: r,
@@ -673,7 +693,11 @@ func (am *Alertmanager) createReceiverStage(name string, integrations []notify.I
 		var s notify.MultiStage
 		s = append(s, notify.NewWaitStage(wait))
 		s = append(s, notify.NewDedupStage(&integrations[i], notificationLog, recv))
-		s = append(s, notify.NewRetryStage(integrations[i], name, am.stageMetrics))
+		var retry notify.Stage = notify.NewRetryStage(integrations[i], name, am.stageMetrics)
+		if am.Settings.UnifiedAlerting.DeliveryRetryQueue {
+			retry = am.newRetryQueueStage(retry, integrations[i], recv)
+		}
+		s = append(s, retry)
 		s = append(s, notify.NewSetNotifiesStage(notificationLog, recv))
 
 		fs = append(fs, s)
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -27,6 +28,9 @@ func (e AlertmanagerConfigRejectedError) Error() string {
 	return fmt.Sprintf("failed to save and apply Alertmanager configuration: %s", e.Inner.Error())
 }
 
+// ErrProvisionedObjectModified is returned when a configuration change affects objects managed by a provisioning source.
+var ErrProvisionedObjectModified = errors.New("cannot modify provisioned object")
+
 type configurationStore interface {
 	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
 }
@@ -102,6 +106,16 @@ func (moa *MultiOrgAlertmanager) ApplyAlertmanagerConfiguration(ctx context.Cont
 		}
 	}
 
+	if query.Result != nil {
+		// A broken configuration must always be replaceable, so provisioned objects are only checked if it can be read.
+		previous, err := Load([]byte(query.Result.AlertmanagerConfiguration))
+		if err != nil {
+			moa.logger.Warn("failed to load the last known configuration, skipping the check of provisioned objects", "org", org, "err", err)
+		} else if err := moa.checkProvisionedObjectsUnchanged(ctx, org, previous, &config); err != nil {
+			return err
+		}
+	}
+
 	if err := moa.Crypto.LoadSecureSettings(ctx, org, config.AlertmanagerConfig.Receivers); err != nil {
 		return err
 	}
@@ -148,3 +162,98 @@ func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config def
 	}
 	return config, nil
 }
+
+// checkProvisionedObjectsUnchanged makes sure that a new configuration does not change or remove
+// the route, contact points, templates or mute timings that are managed by a provisioning source.
+func (moa *MultiOrgAlertmanager) checkProvisionedObjectsUnchanged(ctx context.Context, org int64, previous, next *definitions.PostableUserConfig) error {
+	if previous.AlertmanagerConfig.Route != nil {
+		provenance, err := moa.ProvStore.GetProvenance(ctx, previous.AlertmanagerConfig.Route, org)
+		if err != nil {
+			return err
+		}
+		if provenance != models.ProvenanceNone && !routesEqual(previous.AlertmanagerConfig.Route, next.AlertmanagerConfig.Route) {
+			return fmt.Errorf("%w: notification policy tree is managed by '%s' provisioning", ErrProvisionedObjectModified, provenance)
+		}
+	}
+
+	cpProvs, err := moa.ProvStore.GetProvenances(ctx, org, (&definitions.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	nextReceivers := next.GetGrafanaReceiverMap()
+	for uid, existing := range previous.GetGrafanaReceiverMap() {
+		provenance, ok := cpProvs[uid]
+		if !ok || provenance == models.ProvenanceNone {
+			continue
+		}
+		updated, ok := nextReceivers[uid]
+		if !ok || updated.Name != existing.Name || updated.Type != existing.Type ||
+			updated.DisableResolveMessage != existing.DisableResolveMessage ||
+			len(updated.SecureSettings) > 0 || !jsonEqual(updated.Settings, existing.Settings) {
+			return fmt.Errorf("%w: contact point '%s' is managed by '%s' provisioning", ErrProvisionedObjectModified, existing.Name, provenance)
+		}
+	}
+
+	templateProvs, err := moa.ProvStore.GetProvenances(ctx, org, (&definitions.MessageTemplate{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for name, provenance := range templateProvs {
+		if provenance == models.ProvenanceNone {
+			continue
+		}
+		existing, ok := previous.TemplateFiles[name]
+		if !ok {
+			continue
+		}
+		if updated, ok := next.TemplateFiles[name]; !ok || updated != existing {
+			return fmt.Errorf("%w: template '%s' is managed by '%s' provisioning", ErrProvisionedObjectModified, name, provenance)
+		}
+	}
+
+	muteTimingProvs, err := moa.ProvStore.GetProvenances(ctx, org, (&definitions.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for _, existing := range previous.AlertmanagerConfig.MuteTimeIntervals {
+		provenance, ok := muteTimingProvs[existing.Name]
+		if !ok || provenance == models.ProvenanceNone {
+			continue
+		}
+		found := false
+		for _, updated := range next.AlertmanagerConfig.MuteTimeIntervals {
+			if updated.Name == existing.Name {
+				found = jsonEqual(updated, existing)
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: mute timing '%s' is managed by '%s' provisioning", ErrProvisionedObjectModified, existing.Name, provenance)
+		}
+	}
+
+	return nil
+}
+
+// routesEqual compares two notification policy trees, ignoring the provenance attached to the root.
+func routesEqual(a, b *definitions.Route) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	left, right := *a, *b
+	left.Provenance, right.Provenance = models.ProvenanceNone, models.ProvenanceNone
+	return jsonEqual(left, right)
+}
+
+// jsonEqual compares two objects by their JSON representation.
+func jsonEqual(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
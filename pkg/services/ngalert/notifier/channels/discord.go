@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -22,6 +23,7 @@ type DiscordNotifier struct {
 	log                log.Logger
 	ns                 notifications.WebhookSender
 	tmpl               *template.Template
+	images             ImageStore
 	Content            string
 	AvatarURL          string
 	WebhookURL         string
@@ -58,10 +60,10 @@ func DiscordFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewDiscordNotifier(cfg, fc.NotificationService, fc.Template), nil
+	return NewDiscordNotifier(cfg, fc.NotificationService, fc.ImageStore, fc.Template), nil
 }
 
-func NewDiscordNotifier(config *DiscordConfig, ns notifications.WebhookSender, t *template.Template) *DiscordNotifier {
+func NewDiscordNotifier(config *DiscordConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template) *DiscordNotifier {
 	return &DiscordNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -77,6 +79,7 @@ func NewDiscordNotifier(config *DiscordConfig, ns notifications.WebhookSender, t
 		log:                log.New("alerting.notifier.discord"),
 		ns:                 ns,
 		tmpl:               t,
+		images:             images,
 		UseDiscordUsername: config.UseDiscordUsername,
 	}
 }
@@ -127,6 +130,19 @@ func (d DiscordNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	ruleURL := joinUrlPath(d.tmpl.ExternalURL.String(), "/alerting/list", d.log)
 	embed.Set("url", ruleURL)
 
+	// an embed can only have one image, use the first one that can be linked to
+	err := withStoredImages(ctx, d.log, d.images,
+		func(index int, image *ngmodels.Image) error {
+			if !image.HasURL() {
+				return nil
+			}
+			embed.Set("image", map[string]interface{}{"url": image.URL})
+			return errImagesDone
+		}, as...)
+	if err != nil {
+		d.log.Warn("failed to get the images of the alerts", "err", err)
+	}
+
 	bodyJSON.Set("embeds", []interface{}{embed})
 
 	if tmplErr != nil {
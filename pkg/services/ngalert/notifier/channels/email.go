@@ -5,12 +5,14 @@ import (
 	"errors"
 	"net/url"
 	"path"
+	"path/filepath"
 
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -25,6 +27,7 @@ type EmailNotifier struct {
 	log         log.Logger
 	ns          notifications.EmailSender
 	tmpl        *template.Template
+	images      ImageStore
 }
 
 type EmailConfig struct {
@@ -42,7 +45,7 @@ func EmailFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewEmailNotifier(cfg, fc.NotificationService, fc.Template), nil
+	return NewEmailNotifier(cfg, fc.NotificationService, fc.ImageStore, fc.Template), nil
 }
 
 func NewEmailConfig(config *NotificationChannelConfig) (*EmailConfig, error) {
@@ -62,7 +65,7 @@ func NewEmailConfig(config *NotificationChannelConfig) (*EmailConfig, error) {
 
 // NewEmailNotifier is the constructor function
 // for the EmailNotifier.
-func NewEmailNotifier(config *EmailConfig, ns notifications.EmailSender, t *template.Template) *EmailNotifier {
+func NewEmailNotifier(config *EmailConfig, ns notifications.EmailSender, images ImageStore, t *template.Template) *EmailNotifier {
 	return &EmailNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -77,6 +80,7 @@ func NewEmailNotifier(config *EmailConfig, ns notifications.EmailSender, t *temp
 		log:         log.New("alerting.notifier.email"),
 		ns:          ns,
 		tmpl:        t,
+		images:      images,
 	}
 }
 
@@ -100,6 +104,22 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		en.log.Debug("failed to parse external URL", "url", en.tmpl.ExternalURL.String(), "err", err.Error())
 	}
 
+	// images that cannot be linked to are embedded in the email
+	var embeddedFiles []string
+	err = withStoredImages(ctx, en.log, en.images,
+		func(index int, image *ngmodels.Image) error {
+			if image.HasURL() {
+				data.Alerts[index].ImageURL = image.URL
+			} else if image.Path != "" {
+				data.Alerts[index].EmbeddedImage = filepath.Base(image.Path)
+				embeddedFiles = append(embeddedFiles, image.Path)
+			}
+			return nil
+		}, as...)
+	if err != nil {
+		en.log.Warn("failed to get the images of the alerts", "err", err)
+	}
+
 	cmd := &models.SendEmailCommandSync{
 		SendEmailCommand: models.SendEmailCommand{
 			Subject: title,
@@ -115,9 +135,10 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 				"RuleUrl":           ruleURL,
 				"AlertPageUrl":      alertPageURL,
 			},
-			To:          en.Addresses,
-			SingleEmail: en.SingleEmail,
-			Template:    "ng_alert_notification",
+			EmbeddedFiles: embeddedFiles,
+			To:            en.Addresses,
+			SingleEmail:   en.SingleEmail,
+			Template:      "ng_alert_notification",
 		},
 	}
 
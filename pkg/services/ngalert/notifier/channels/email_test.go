@@ -52,7 +52,7 @@ func TestEmailNotifier(t *testing.T) {
 			Settings: settingsJSON,
 		})
 		require.NoError(t, err)
-		emailNotifier := NewEmailNotifier(cfg, emailSender, tmpl)
+		emailNotifier := NewEmailNotifier(cfg, emailSender, &fakeImageStore{}, tmpl)
 
 		alerts := []*types.Alert{
 			{
@@ -103,6 +103,48 @@ func TestEmailNotifier(t *testing.T) {
 			},
 		}, expected)
 	})
+
+	t.Run("with images it should link or embed them", func(t *testing.T) {
+		settingsJSON, err := simplejson.NewJson([]byte(`{"addresses": "someops@example.com"}`))
+		require.NoError(t, err)
+
+		emailSender := mockNotificationService()
+		cfg, err := NewEmailConfig(&NotificationChannelConfig{
+			Name:     "ops",
+			Type:     "email",
+			Settings: settingsJSON,
+		})
+		require.NoError(t, err)
+		images := newFakeImageStore(t)
+		emailNotifier := NewEmailNotifier(cfg, emailSender, images, tmpl)
+
+		alerts := []*types.Alert{
+			{
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "FiringOne"},
+					Annotations: model.LabelSet{"__alertImageToken__": "test-image-1"},
+				},
+			},
+			{
+				Alert: model.Alert{
+					Labels:      model.LabelSet{"alertname": "FiringTwo"},
+					Annotations: model.LabelSet{"__alertImageToken__": "test-image-2"},
+				},
+			},
+		}
+
+		ok, err := emailNotifier.Notify(context.Background(), alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Equal(t, []string{images.Images[1].Path}, emailSender.EmailSync.EmbeddedFiles)
+		sent := emailSender.EmailSync.Data["Alerts"].(ExtendedAlerts)
+		require.Len(t, sent, 2)
+		require.Equal(t, "https://www.example.com/test-image-1.png", sent[0].ImageURL)
+		require.Empty(t, sent[0].EmbeddedImage)
+		require.Empty(t, sent[1].ImageURL)
+		require.Equal(t, "test-image-2.png", sent[1].EmbeddedImage)
+	})
 }
 
 func TestEmailNotifierIntegration(t *testing.T) {
@@ -142,6 +184,28 @@ func TestEmailNotifierIntegration(t *testing.T) {
 				"Firing: AlwaysFiring at warning",
 			},
 		},
+		{
+			name: "alerts with images",
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "FiringOne", "severity": "warning"},
+						Annotations: model.LabelSet{"__alertImageToken__": "test-image-1"},
+					},
+				},
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "FiringTwo", "severity": "critical"},
+						Annotations: model.LabelSet{"__alertImageToken__": "test-image-2"},
+					},
+				},
+			},
+			expSubject: "[FIRING:2]  ",
+			expSnippets: []string{
+				`src="https://www.example.com/test-image-1.png"`,
+				`src="cid:test-image-2.png"`,
+			},
+		},
 		{
 			name: "multiple alerts with templated message",
 			alerts: []*types.Alert{
@@ -289,7 +353,7 @@ func createSut(t *testing.T, messageTmpl string, emailTmpl *template.Template, n
 		Settings: settingsJSON,
 	})
 	require.NoError(t, err)
-	emailNotifier := NewEmailNotifier(cfg, ns, emailTmpl)
+	emailNotifier := NewEmailNotifier(cfg, ns, newFakeImageStore(t), emailTmpl)
 
 	return emailNotifier
 }
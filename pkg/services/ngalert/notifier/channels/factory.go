@@ -13,10 +13,11 @@ type FactoryConfig struct {
 	NotificationService notifications.Service
 	DecryptFunc         GetDecryptedValueFn
 	Template            *template.Template
+	ImageStore          ImageStore
 }
 
 func NewFactoryConfig(config *NotificationChannelConfig, notificationService notifications.Service,
-	decryptFunc GetDecryptedValueFn, template *template.Template) (FactoryConfig, error) {
+	decryptFunc GetDecryptedValueFn, template *template.Template, imageStore ImageStore) (FactoryConfig, error) {
 	if config.Settings == nil {
 		return FactoryConfig{}, errors.New("no settings supplied")
 	}
@@ -30,6 +31,7 @@ func NewFactoryConfig(config *NotificationChannelConfig, notificationService not
 		NotificationService: notificationService,
 		DecryptFunc:         decryptFunc,
 		Template:            template,
+		ImageStore:          imageStore,
 	}, nil
 }
 
@@ -0,0 +1,94 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ImageStore provides the screenshots taken for alerts.
+type ImageStore interface {
+	GetImage(ctx context.Context, token string) (*ngmodels.Image, error)
+}
+
+// errImagesDone stops the iteration of withStoredImages without an error.
+var errImagesDone = errors.New("images done")
+
+type forEachImageFunc func(index int, image *ngmodels.Image) error
+
+// getImage returns the image referenced by the alert, or nil if the alert has no image or the image cannot be found.
+func getImage(ctx context.Context, l log.Logger, imageStore ImageStore, alert *types.Alert) (*ngmodels.Image, error) {
+	token := string(alert.Annotations[model.LabelName(ngmodels.ImageTokenAnnotation)])
+	if token == "" || imageStore == nil {
+		return nil, nil
+	}
+	img, err := imageStore.GetImage(ctx, token)
+	if errors.Is(err, ngmodels.ErrImageNotFound) {
+		l.Warn("image of the alert not found", "alert", alert.String(), "token", token)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// withStoredImages calls forEachFunc for each alert that has an image, with the index of the alert and its image.
+// Alerts whose image cannot be found are skipped, as are alerts whose image was already passed to forEachFunc,
+// since all alerts of an evaluation share the same screenshot. If forEachFunc returns an error, the iteration stops
+// and the error is returned, unless it is errImagesDone.
+func withStoredImages(ctx context.Context, l log.Logger, imageStore ImageStore, forEachFunc forEachImageFunc, alerts ...*types.Alert) error {
+	seen := make(map[string]struct{}, len(alerts))
+	for i, alert := range alerts {
+		token := string(alert.Annotations[model.LabelName(ngmodels.ImageTokenAnnotation)])
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		img, err := getImage(ctx, l, imageStore, alert)
+		if err != nil {
+			return err
+		}
+		if img == nil {
+			continue
+		}
+		seen[token] = struct{}{}
+		if err := forEachFunc(i, img); err != nil {
+			if errors.Is(err, errImagesDone) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileField writes the file as a form file. It returns false if the file does not exist.
+func writeFileField(w *multipart.Writer, name, path string) (bool, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	fw, err := w.CreateFormFile(name, filepath.Base(path))
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return false, err
+	}
+	return true, nil
+}
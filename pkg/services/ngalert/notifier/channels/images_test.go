@@ -0,0 +1,46 @@
+package channels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestWithStoredImages(t *testing.T) {
+	images := newFakeImageStore(t)
+	newAlert := func(token string) *types.Alert {
+		return &types.Alert{
+			Alert: model.Alert{
+				Annotations: model.LabelSet{model.LabelName(ngmodels.ImageTokenAnnotation): model.LabelValue(token)},
+			},
+		}
+	}
+	alerts := []*types.Alert{
+		newAlert("test-image-1"),
+		newAlert("test-image-1"),
+		newAlert(""),
+		newAlert("does-not-exist"),
+		newAlert("test-image-2"),
+		newAlert("test-image-1"),
+	}
+
+	var indexes []int
+	var tokens []string
+	err := withStoredImages(context.Background(), log.NewNopLogger(), images,
+		func(index int, image *ngmodels.Image) error {
+			indexes = append(indexes, index)
+			tokens = append(tokens, image.Token)
+			return nil
+		}, alerts...)
+	require.NoError(t, err)
+
+	// alerts that share the image of a previous alert are skipped
+	require.Equal(t, []int{0, 4}, indexes)
+	require.Equal(t, []string{"test-image-1", "test-image-2"}, tokens)
+}
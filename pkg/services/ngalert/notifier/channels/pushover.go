@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
@@ -38,6 +39,7 @@ type PushoverNotifier struct {
 	tmpl             *template.Template
 	log              log.Logger
 	ns               notifications.WebhookSender
+	images           ImageStore
 }
 
 type PushoverConfig struct {
@@ -63,7 +65,7 @@ func PushoverFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewPushoverNotifier(cfg, fc.NotificationService, fc.Template), nil
+	return NewPushoverNotifier(cfg, fc.NotificationService, fc.ImageStore, fc.Template), nil
 }
 
 func NewPushoverConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedValueFn) (*PushoverConfig, error) {
@@ -102,7 +104,7 @@ func NewPushoverConfig(config *NotificationChannelConfig, decryptFunc GetDecrypt
 }
 
 // NewSlackNotifier is the constructor for the Slack notifier
-func NewPushoverNotifier(config *PushoverConfig, ns notifications.WebhookSender, t *template.Template) *PushoverNotifier {
+func NewPushoverNotifier(config *PushoverConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template) *PushoverNotifier {
 	return &PushoverNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -126,6 +128,7 @@ func NewPushoverNotifier(config *PushoverConfig, ns notifications.WebhookSender,
 		tmpl:             t,
 		log:              log.New("alerting.notifier.pushover"),
 		ns:               ns,
+		images:           images,
 	}
 }
 
@@ -260,6 +263,28 @@ func (pn *PushoverNotifier) genPushoverBody(ctx context.Context, as ...*types.Al
 	if err != nil {
 		return nil, b, err
 	}
+
+	// Pushover supports one attachment per message, use the first image that is on disk
+	if pn.Upload {
+		err = withStoredImages(ctx, pn.log, pn.images,
+			func(index int, image *ngmodels.Image) error {
+				if image.Path == "" {
+					return nil
+				}
+				attached, err := writeFileField(w, "attachment", image.Path)
+				if err != nil {
+					return err
+				}
+				if !attached {
+					pn.log.Warn("image of the alert is no longer on disk", "path", image.Path)
+					return nil
+				}
+				return errImagesDone
+			}, as...)
+		if err != nil {
+			return nil, b, err
+		}
+	}
 	if err := w.Close(); err != nil {
 		return nil, b, err
 	}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/template"
@@ -28,8 +29,9 @@ var SlackAPIEndpoint = "https://slack.com/api/chat.postMessage"
 // alert notification to Slack.
 type SlackNotifier struct {
 	*Base
-	log  log.Logger
-	tmpl *template.Template
+	log    log.Logger
+	tmpl   *template.Template
+	images ImageStore
 
 	URL            *url.URL
 	Username       string
@@ -67,7 +69,7 @@ func SlackFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewSlackNotifier(cfg, fc.Template), nil
+	return NewSlackNotifier(cfg, fc.ImageStore, fc.Template), nil
 }
 
 func NewSlackConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedValueFn) (*SlackConfig, error) {
@@ -125,7 +127,7 @@ func NewSlackConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedV
 }
 
 // NewSlackNotifier is the constructor for the Slack notifier
-func NewSlackNotifier(config *SlackConfig, t *template.Template) *SlackNotifier {
+func NewSlackNotifier(config *SlackConfig, images ImageStore, t *template.Template) *SlackNotifier {
 	return &SlackNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -147,6 +149,7 @@ func NewSlackNotifier(config *SlackConfig, t *template.Template) *SlackNotifier
 		Title:          config.Title,
 		log:            log.New("alerting.notifier.slack"),
 		tmpl:           t,
+		images:         images,
 	}
 }
 
@@ -171,6 +174,7 @@ type attachment struct {
 	FooterIcon string              `json:"footer_icon"`
 	Color      string              `json:"color,omitempty"`
 	Ts         int64               `json:"ts,omitempty"`
+	ImageURL   string              `json:"image_url,omitempty"`
 }
 
 // Notify sends an alert notification to Slack.
@@ -242,7 +246,7 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) error {
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		logger.Error("Slack API request failed", "url", request.URL.String(), "statusCode", resp.Status, "body", string(body))
-		return fmt.Errorf("request to Slack API failed with status code %d", resp.StatusCode)
+		return fmt.Errorf("request to Slack API failed with %w", statusCodeError(resp.StatusCode))
 	}
 
 	// Slack responds to some requests with a JSON document, that might contain an error.
@@ -298,6 +302,19 @@ func (sn *SlackNotifier) buildSlackMessage(ctx context.Context, as []*types.Aler
 		sn.log.Warn("failed to template Slack message", "err", tmplErr.Error())
 	}
 
+	// an attachment can only have one image, use the first one that can be linked to
+	err := withStoredImages(ctx, sn.log, sn.images,
+		func(index int, image *ngmodels.Image) error {
+			if !image.HasURL() {
+				return nil
+			}
+			req.Attachments[0].ImageURL = image.URL
+			return errImagesDone
+		}, as...)
+	if err != nil {
+		sn.log.Warn("failed to get the images of the alerts", "err", err)
+	}
+
 	mentionsBuilder := strings.Builder{}
 	appendSpace := func() {
 		if mentionsBuilder.Len() > 0 {
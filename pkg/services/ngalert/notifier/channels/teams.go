@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 )
 
@@ -24,6 +25,7 @@ type TeamsNotifier struct {
 	tmpl         *template.Template
 	log          log.Logger
 	ns           notifications.WebhookSender
+	images       ImageStore
 }
 
 type TeamsConfig struct {
@@ -42,7 +44,7 @@ func TeamsFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewTeamsNotifier(cfg, fc.NotificationService, fc.Template), nil
+	return NewTeamsNotifier(cfg, fc.NotificationService, fc.ImageStore, fc.Template), nil
 }
 
 func NewTeamsConfig(config *NotificationChannelConfig) (*TeamsConfig, error) {
@@ -60,7 +62,7 @@ func NewTeamsConfig(config *NotificationChannelConfig) (*TeamsConfig, error) {
 }
 
 // NewTeamsNotifier is the constructor for Teams notifier.
-func NewTeamsNotifier(config *TeamsConfig, ns notifications.WebhookSender, t *template.Template) *TeamsNotifier {
+func NewTeamsNotifier(config *TeamsConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template) *TeamsNotifier {
 	return &TeamsNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -76,6 +78,7 @@ func NewTeamsNotifier(config *TeamsConfig, ns notifications.WebhookSender, t *te
 		log:          log.New("alerting.notifier.teams"),
 		ns:           ns,
 		tmpl:         t,
+		images:       images,
 	}
 }
 
@@ -87,6 +90,28 @@ func (tn *TeamsNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	ruleURL := joinUrlPath(tn.tmpl.ExternalURL.String(), "/alerting/list", tn.log)
 
 	title := tmpl(tn.Title)
+	sections := []map[string]interface{}{
+		{
+			"title": tmpl(tn.SectionTitle),
+			"text":  tmpl(tn.Message),
+		},
+	}
+
+	var images []map[string]interface{}
+	err := withStoredImages(ctx, tn.log, tn.images,
+		func(index int, image *ngmodels.Image) error {
+			if image.HasURL() {
+				images = append(images, map[string]interface{}{"image": image.URL})
+			}
+			return nil
+		}, as...)
+	if err != nil {
+		tn.log.Warn("failed to get the images of the alerts", "err", err)
+	}
+	if len(images) > 0 {
+		sections = append(sections, map[string]interface{}{"images": images})
+	}
+
 	body := map[string]interface{}{
 		"@type":    "MessageCard",
 		"@context": "http://schema.org/extensions",
@@ -95,12 +120,7 @@ func (tn *TeamsNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		"summary":    title,
 		"title":      title,
 		"themeColor": getAlertStatusColor(types.Alerts(as...).Status()),
-		"sections": []map[string]interface{}{
-			{
-				"title": tmpl(tn.SectionTitle),
-				"text":  tmpl(tn.Message),
-			},
-		},
+		"sections":   sections,
 		"potentialAction": []map[string]interface{}{
 			{
 				"@context": "http://schema.org",
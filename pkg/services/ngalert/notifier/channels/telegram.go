@@ -9,13 +9,15 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 )
 
 var (
-	TelegramAPIURL = "https://api.telegram.org/bot%s/sendMessage"
+	TelegramAPIURL      = "https://api.telegram.org/bot%s/sendMessage"
+	TelegramAPIPhotoURL = "https://api.telegram.org/bot%s/sendPhoto"
 )
 
 // TelegramNotifier is responsible for sending
@@ -28,6 +30,7 @@ type TelegramNotifier struct {
 	log      log.Logger
 	ns       notifications.WebhookSender
 	tmpl     *template.Template
+	images   ImageStore
 }
 
 type TelegramConfig struct {
@@ -45,7 +48,7 @@ func TelegramFactory(fc FactoryConfig) (NotificationChannel, error) {
 			Cfg:    *fc.Config,
 		}
 	}
-	return NewTelegramNotifier(config, fc.NotificationService, fc.Template), nil
+	return NewTelegramNotifier(config, fc.NotificationService, fc.ImageStore, fc.Template), nil
 }
 
 func NewTelegramConfig(config *NotificationChannelConfig, fn GetDecryptedValueFn) (*TelegramConfig, error) {
@@ -66,7 +69,7 @@ func NewTelegramConfig(config *NotificationChannelConfig, fn GetDecryptedValueFn
 }
 
 // NewTelegramNotifier is the constructor for the Telegram notifier
-func NewTelegramNotifier(config *TelegramConfig, ns notifications.WebhookSender, t *template.Template) *TelegramNotifier {
+func NewTelegramNotifier(config *TelegramConfig, ns notifications.WebhookSender, images ImageStore, t *template.Template) *TelegramNotifier {
 	return &TelegramNotifier{
 		Base: NewBase(&models.AlertNotification{
 			Uid:                   config.UID,
@@ -81,6 +84,7 @@ func NewTelegramNotifier(config *TelegramConfig, ns notifications.WebhookSender,
 		tmpl:     t,
 		log:      log.New("alerting.notifier.telegram"),
 		ns:       ns,
+		images:   images,
 	}
 }
 
@@ -133,9 +137,64 @@ func (tn *TelegramNotifier) Notify(ctx context.Context, as ...*types.Alert) (boo
 		return false, err
 	}
 
+	// the images are sent as separate messages after the text, a failure does not fail the notification
+	// as retrying it would send the text again
+	err = withStoredImages(ctx, tn.log, tn.images,
+		func(index int, image *ngmodels.Image) error {
+			return tn.sendPhoto(ctx, msg["chat_id"], image)
+		}, as...)
+	if err != nil {
+		tn.log.Warn("failed to send the images of the alerts to Telegram", "err", err)
+	}
+
 	return true, nil
 }
 
+// sendPhoto sends the image to the chat. The file is uploaded if it is still on disk, otherwise Telegram
+// downloads it from its URL.
+func (tn *TelegramNotifier) sendPhoto(ctx context.Context, chatID string, image *ngmodels.Image) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	boundary := GetBoundary()
+	if boundary != "" {
+		if err := w.SetBoundary(boundary); err != nil {
+			return err
+		}
+	}
+
+	if err := writeField(w, "chat_id", chatID); err != nil {
+		return err
+	}
+	attached := false
+	if image.Path != "" {
+		var err error
+		if attached, err = writeFileField(w, "photo", image.Path); err != nil {
+			return err
+		}
+	}
+	if !attached {
+		if !image.HasURL() {
+			return nil
+		}
+		if err := writeField(w, "photo", image.URL); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	cmd := &models.SendWebhookSync{
+		Url:        fmt.Sprintf(TelegramAPIPhotoURL, tn.BotToken),
+		Body:       body.String(),
+		HttpMethod: "POST",
+		HttpHeader: map[string]string{
+			"Content-Type": w.FormDataContentType(),
+		},
+	}
+	return tn.ns.SendWebhookSync(ctx, cmd)
+}
+
 func (tn *TelegramNotifier) buildTelegramMessage(ctx context.Context, as []*types.Alert) (map[string]string, error) {
 	var tmplErr error
 	tmpl, _ := TmplText(ctx, tn.tmpl, as, tn.log, &tmplErr)
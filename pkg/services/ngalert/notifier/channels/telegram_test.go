@@ -110,7 +110,7 @@ func TestTelegramNotifier(t *testing.T) {
 			require.NoError(t, err)
 			ctx := notify.WithGroupKey(context.Background(), "alertname")
 			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
-			pn := NewTelegramNotifier(cfg, webhookSender, tmpl)
+			pn := NewTelegramNotifier(cfg, webhookSender, &fakeImageStore{}, tmpl)
 			msg, err := pn.buildTelegramMessage(ctx, c.alerts)
 			if c.expMsgError != nil {
 				require.Error(t, err)
@@ -123,3 +123,41 @@ func TestTelegramNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegramNotifier_Images(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	settingsJSON, err := simplejson.NewJson([]byte(`{"bottoken": "abcdefgh0123456789", "chatid": "someid"}`))
+	require.NoError(t, err)
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	cfg, err := NewTelegramConfig(&NotificationChannelConfig{
+		Name:           "telegram_testing",
+		Type:           "telegram",
+		Settings:       settingsJSON,
+		SecureSettings: map[string][]byte{},
+	}, secretsService.GetDecryptedValue)
+	require.NoError(t, err)
+
+	webhookSender := mockNotificationService()
+	pn := NewTelegramNotifier(cfg, webhookSender, newFakeImageStore(t), tmpl)
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+	ok, err := pn.Notify(ctx, &types.Alert{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "alert1"},
+			Annotations: model.LabelSet{"__alertImageToken__": "test-image-2"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the photo is sent after the message
+	require.Equal(t, "https://api.telegram.org/botabcdefgh0123456789/sendPhoto", webhookSender.Webhook.Url)
+	require.Contains(t, webhookSender.Webhook.Body, "name=\"chat_id\"\r\n\r\nsomeid")
+	require.Contains(t, webhookSender.Webhook.Body, "name=\"photo\"; filename=\"test-image-2.png\"")
+}
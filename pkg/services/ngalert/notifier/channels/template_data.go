@@ -29,6 +29,10 @@ type ExtendedAlert struct {
 	DashboardURL string      `json:"dashboardURL"`
 	PanelURL     string      `json:"panelURL"`
 	ValueString  string      `json:"valueString"`
+	// ImageURL is the public URL of the screenshot of the panel, if it has one.
+	ImageURL string `json:"imageURL,omitempty"`
+	// EmbeddedImage is the name of the file of the screenshot of the panel when it is embedded in the notification.
+	EmbeddedImage string `json:"embeddedImage,omitempty"`
 }
 
 type ExtendedAlerts []ExtendedAlert
@@ -2,9 +2,16 @@ package channels
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 // mockTimeNow replaces function timeNow to return constant time.
@@ -48,3 +55,32 @@ func (ns *notificationServiceMock) SendEmailCommandHandler(ctx context.Context,
 }
 
 func mockNotificationService() *notificationServiceMock { return &notificationServiceMock{} }
+
+// fakeImageStore returns the images it was created with by their token.
+type fakeImageStore struct {
+	Images []*ngmodels.Image
+}
+
+func (f *fakeImageStore) GetImage(_ context.Context, token string) (*ngmodels.Image, error) {
+	for _, image := range f.Images {
+		if image.Token == token {
+			return image, nil
+		}
+	}
+	return nil, ngmodels.ErrImageNotFound
+}
+
+// newFakeImageStore returns an image store with the image "test-image-1", which has a URL and is on disk, and the
+// image "test-image-2", which is only on disk.
+func newFakeImageStore(t *testing.T) *fakeImageStore {
+	t.Helper()
+	dir := t.TempDir()
+	store := &fakeImageStore{}
+	for i, url := range []string{"https://www.example.com/test-image-1.png", ""} {
+		token := fmt.Sprintf("test-image-%d", i+1)
+		path := filepath.Join(dir, token+".png")
+		require.NoError(t, os.WriteFile(path, []byte("test"), 0600))
+		store.Images = append(store.Images, &ngmodels.Image{Token: token, Path: path, URL: url})
+	}
+	return store
+}
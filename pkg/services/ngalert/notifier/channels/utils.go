@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -16,6 +17,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -54,6 +56,27 @@ func (e receiverInitError) Error() string {
 
 func (e receiverInitError) Unwrap() error { return e.Err }
 
+// statusCodeError is returned when the receiving service responds with a status code other than 2xx.
+type statusCodeError int
+
+func (e statusCodeError) Error() string {
+	return fmt.Sprintf("status code %d", int(e))
+}
+
+// StatusCode returns the HTTP status code of the unsuccessful response that caused the error of a notifier,
+// or 0 if the error was not caused by a response, e.g. because the service was unreachable.
+func StatusCode(err error) int {
+	var codeErr statusCodeError
+	if errors.As(err, &codeErr) {
+		return int(codeErr)
+	}
+	var webhookErr notifications.WebhookResponseError
+	if errors.As(err, &webhookErr) {
+		return webhookErr.StatusCode
+	}
+	return 0
+}
+
 func getAlertStatusColor(status model.AlertStatus) string {
 	if status == model.AlertFiring {
 		return ColorAlertFiring
@@ -130,7 +153,7 @@ var sendHTTPRequest = func(ctx context.Context, url *url.URL, cfg httpCfg, logge
 	if resp.StatusCode/100 != 2 {
 		logger.Warn("HTTP request failed", "url", request.URL.String(), "statusCode", resp.Status, "body",
 			string(respBody))
-		return nil, fmt.Errorf("failed to send HTTP request - status code %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to send HTTP request - %w", statusCodeError(resp.StatusCode))
 	}
 
 	logger.Debug("Sending HTTP request succeeded", "url", request.URL.String(), "statusCode", resp.Status)
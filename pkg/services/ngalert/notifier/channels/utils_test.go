@@ -0,0 +1,18 @@
+package channels
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+func TestStatusCode(t *testing.T) {
+	require.Equal(t, 429, StatusCode(fmt.Errorf("failed to send HTTP request - %w", statusCodeError(429))))
+	require.Equal(t, 503, StatusCode(fmt.Errorf("send notification to Opsgenie: %w", notifications.WebhookResponseError{StatusCode: 503, Status: "503 Service Unavailable"})))
+	require.Zero(t, StatusCode(errors.New("connection refused")))
+	require.Zero(t, StatusCode(nil))
+}
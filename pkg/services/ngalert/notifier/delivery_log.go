@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const deliveryLogCleanupInterval = time.Hour
+
+// deliveryLogNotifier records every attempt of an integration to deliver a notification in the delivery log.
+type deliveryLogNotifier struct {
+	notifier       notify.Notifier
+	store          store.NotificationDeliveryStore
+	orgID          int64
+	receiver       string
+	integration    string
+	integrationUID string
+	logger         log.Logger
+}
+
+func (am *Alertmanager) newDeliveryLogNotifier(receiver string, r *apimodels.PostableGrafanaReceiver, n notify.Notifier) *deliveryLogNotifier {
+	return &deliveryLogNotifier{
+		notifier:       n,
+		store:          am.Store,
+		orgID:          am.orgID,
+		receiver:       receiver,
+		integration:    r.Type,
+		integrationUID: r.UID,
+		logger:         am.logger,
+	}
+}
+
+func (n *deliveryLogNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.notifier.Notify(ctx, alerts...)
+	delivery := &ngmodels.NotificationDelivery{
+		OrgID:          n.orgID,
+		Receiver:       n.receiver,
+		Integration:    n.integration,
+		IntegrationUID: n.integrationUID,
+		Fingerprints:   make([]string, 0, len(alerts)),
+		Status:         ngmodels.NotificationDeliverySuccess,
+		Latency:        time.Since(start),
+		AttemptedAt:    start,
+	}
+	for _, a := range alerts {
+		delivery.Fingerprints = append(delivery.Fingerprints, a.Fingerprint().String())
+	}
+	if err != nil {
+		delivery.Status = ngmodels.NotificationDeliveryFailure
+		delivery.StatusCode = channels.StatusCode(err)
+		delivery.Error = err.Error()
+	}
+	// the attempt is recorded even if the notification timed out
+	if err := n.store.SaveNotificationDelivery(context.Background(), delivery); err != nil {
+		n.logger.Error("failed to save notification delivery", "receiver", n.receiver, "integration", n.integration, "uid", n.integrationUID, "error", err)
+	}
+	return retry, err
+}
+
+// DeliveryLogCleaner periodically deletes the delivery attempts that are older than the configured retention.
+type DeliveryLogCleaner struct {
+	store     store.NotificationDeliveryStore
+	retention time.Duration
+	interval  time.Duration
+	log       log.Logger
+	timeNow   func() time.Time
+}
+
+func NewDeliveryLogCleaner(deliveryStore store.NotificationDeliveryStore, retention time.Duration, logger log.Logger) *DeliveryLogCleaner {
+	return &DeliveryLogCleaner{
+		store:     deliveryStore,
+		retention: retention,
+		interval:  deliveryLogCleanupInterval,
+		log:       logger,
+		timeNow:   time.Now,
+	}
+}
+
+// Run deletes outdated attempts right away and then once per interval until the context is cancelled.
+// Nothing is deleted if no retention is configured.
+func (c *DeliveryLogCleaner) Run(ctx context.Context) error {
+	if c.retention <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.cleanup(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *DeliveryLogCleaner) cleanup(ctx context.Context) {
+	before := c.timeNow().Add(-c.retention)
+	deleted, err := c.store.DeleteNotificationDeliveriesBefore(ctx, before)
+	if err != nil {
+		c.log.Error("failed to delete outdated notification deliveries", "before", before, "error", err)
+		return
+	}
+	c.log.Debug("deleted outdated notification deliveries", "before", before, "deleted", deleted)
+}
@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// fakeNotifier returns the given errors in order and succeeds once they are exhausted.
+type fakeNotifier struct {
+	mtx          sync.Mutex
+	errs         []error
+	sendResolved bool
+	calls        [][]*types.Alert
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, alerts ...*types.Alert) (bool, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.calls = append(n.calls, alerts)
+	if len(n.errs) == 0 {
+		return false, nil
+	}
+	err := n.errs[0]
+	n.errs = n.errs[1:]
+	return true, err
+}
+
+func (n *fakeNotifier) SendResolved() bool {
+	return n.sendResolved
+}
+
+func newDeliveryTestAlert(name string, endsAt time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": model.LabelValue(name)},
+			StartsAt: endsAt.Add(-time.Hour),
+			EndsAt:   endsAt,
+		},
+	}
+}
+
+func TestDeliveryLogNotifier(t *testing.T) {
+	deliveryStore := &store.FakeNotificationDeliveryStore{}
+	inner := &fakeNotifier{errs: []error{notifications.WebhookResponseError{StatusCode: 503, Status: "503 Service Unavailable"}}}
+	n := &deliveryLogNotifier{
+		notifier:       inner,
+		store:          deliveryStore,
+		orgID:          1,
+		receiver:       "ops",
+		integration:    "webhook",
+		integrationUID: "webhook-uid",
+		logger:         log.NewNopLogger(),
+	}
+	alert := newDeliveryTestAlert("test", time.Now().Add(time.Hour))
+
+	retry, err := n.Notify(context.Background(), alert)
+	require.True(t, retry)
+	require.Error(t, err)
+	_, err = n.Notify(context.Background(), alert)
+	require.NoError(t, err)
+
+	require.Len(t, deliveryStore.Deliveries, 2)
+	failed := deliveryStore.Deliveries[0]
+	require.Equal(t, int64(1), failed.OrgID)
+	require.Equal(t, "ops", failed.Receiver)
+	require.Equal(t, "webhook", failed.Integration)
+	require.Equal(t, "webhook-uid", failed.IntegrationUID)
+	require.Equal(t, []string{alert.Fingerprint().String()}, failed.Fingerprints)
+	require.Equal(t, ngmodels.NotificationDeliveryFailure, failed.Status)
+	require.Equal(t, 503, failed.StatusCode)
+	require.Equal(t, "Webhook response status 503 Service Unavailable", failed.Error)
+	require.False(t, failed.AttemptedAt.IsZero())
+
+	succeeded := deliveryStore.Deliveries[1]
+	require.Equal(t, ngmodels.NotificationDeliverySuccess, succeeded.Status)
+	require.Zero(t, succeeded.StatusCode)
+	require.Empty(t, succeeded.Error)
+}
+
+func TestDeliveryLogCleaner(t *testing.T) {
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	deliveryStore := &store.FakeNotificationDeliveryStore{
+		Deliveries: []ngmodels.NotificationDelivery{
+			{ID: 1, AttemptedAt: now.Add(-48 * time.Hour)},
+			{ID: 2, AttemptedAt: now.Add(-time.Hour)},
+		},
+	}
+	cleaner := NewDeliveryLogCleaner(deliveryStore, 24*time.Hour, log.NewNopLogger())
+	cleaner.timeNow = func() time.Time { return now }
+
+	cleaner.cleanup(context.Background())
+	require.Len(t, deliveryStore.Deliveries, 1)
+	require.Equal(t, int64(2), deliveryStore.Deliveries[0].ID)
+
+	t.Run("should not run without retention", func(t *testing.T) {
+		cleaner := NewDeliveryLogCleaner(deliveryStore, 0, log.NewNopLogger())
+		require.NoError(t, cleaner.Run(context.Background()))
+		require.Len(t, deliveryStore.Deliveries, 1)
+	})
+}
@@ -0,0 +1,240 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	gokit_log "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// retryQueueInterval is how often the retry queue is checked for notifications that are due.
+	retryQueueInterval = 30 * time.Second
+	// retryQueueBatchSize is the maximum number of notifications delivered per check.
+	retryQueueBatchSize = 10
+	// retryQueueNotifyTimeout is the timeout of a single attempt to deliver a queued notification.
+	retryQueueNotifyTimeout = 30 * time.Second
+	// retryQueueClaimTimeout is how long a claimed notification is not delivered by other instances. It has to be
+	// longer than the delivery of a full batch.
+	retryQueueClaimTimeout   = 10 * time.Minute
+	retryQueueInitialBackoff = time.Minute
+	retryQueueMaxBackoff     = time.Hour
+)
+
+// retryQueueStage queues the notifications that its retry stage failed to deliver before the flush of the
+// aggregation group timed out. The queued notifications are delivered by the retry queue of the Alertmanager,
+// which survives restarts. Notifications that failed with an unrecoverable error are not queued.
+type retryQueueStage struct {
+	stage       notify.Stage
+	integration notify.Integration
+	store       store.NotificationDeliveryStore
+	orgID       int64
+	recv        *nflogpb.Receiver
+	logger      log.Logger
+}
+
+func (am *Alertmanager) newRetryQueueStage(stage notify.Stage, integration notify.Integration, recv *nflogpb.Receiver) *retryQueueStage {
+	return &retryQueueStage{
+		stage:       stage,
+		integration: integration,
+		store:       am.Store,
+		orgID:       am.orgID,
+		recv:        recv,
+		logger:      am.logger,
+	}
+}
+
+func (s *retryQueueStage) Exec(ctx context.Context, l gokit_log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	ctx, alerts, err := s.stage.Exec(ctx, l, alerts...)
+	// the retry stage gives up on unrecoverable errors without waiting for the context to be done
+	if err == nil || ctx.Err() == nil {
+		return ctx, alerts, err
+	}
+
+	retry, qerr := s.newNotificationRetry(ctx, alerts)
+	if qerr == nil && retry == nil {
+		return ctx, alerts, err
+	}
+	if qerr == nil {
+		qerr = s.store.SaveNotificationRetry(context.Background(), retry)
+	}
+	if qerr != nil {
+		s.logger.Error("failed to queue notification", "receiver", s.recv.GroupName, "integration", s.recv.Integration, "error", qerr)
+		return ctx, alerts, err
+	}
+	s.logger.Warn("queued notification for retry", "receiver", s.recv.GroupName, "integration", s.recv.Integration, "error", err)
+	// the notification is considered sent, so that it is not sent again by the next flush as well
+	return ctx, alerts, nil
+}
+
+// newNotificationRetry returns the queued notification of the given alerts, or nil if there is nothing to deliver.
+func (s *retryQueueStage) newNotificationRetry(ctx context.Context, alerts []*types.Alert) (*ngmodels.NotificationRetry, error) {
+	groupKey, ok := notify.GroupKey(ctx)
+	if !ok {
+		return nil, nil
+	}
+	groupLabels, _ := notify.GroupLabels(ctx)
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = time.Now()
+	}
+
+	queued := make([]*types.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		resolved := a.ResolvedAt(now)
+		if resolved && !s.integration.SendResolved() {
+			continue
+		}
+		alert := *a
+		if !resolved {
+			// firing alerts are queued without an end, so that they are still firing when they are delivered
+			alert.EndsAt = time.Time{}
+		}
+		queued = append(queued, &alert)
+	}
+	if len(queued) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(queued)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(groupLabels))
+	for k, v := range groupLabels {
+		labels[string(k)] = string(v)
+	}
+	return &ngmodels.NotificationRetry{
+		OrgID:             s.orgID,
+		Receiver:          s.recv.GroupName,
+		Integration:       s.recv.Integration,
+		IntegrationIndex:  int(s.recv.Idx),
+		GroupKey:          groupKey,
+		GroupLabels:       labels,
+		Alerts:            string(b),
+		NextAttemptAtUnix: now.Add(retryQueueBackoff(0)).UnixMilli(),
+		CreatedAtUnix:     now.UnixMilli(),
+	}, nil
+}
+
+// retryQueueBackoff returns the time until the next attempt after the given number of attempts.
+func retryQueueBackoff(attempts int) time.Duration {
+	backoff := retryQueueInitialBackoff
+	for i := 0; i < attempts && backoff < retryQueueMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > retryQueueMaxBackoff {
+		return retryQueueMaxBackoff
+	}
+	return backoff
+}
+
+// runRetryQueue delivers the queued notifications that are due once per interval until the Alertmanager is stopped.
+func (am *Alertmanager) runRetryQueue(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			am.processRetryQueue(context.Background(), time.Now())
+		case <-am.stopc:
+			return
+		}
+	}
+}
+
+func (am *Alertmanager) processRetryQueue(ctx context.Context, now time.Time) {
+	// the integrations are not built until the configuration is applied
+	if !am.Ready() {
+		return
+	}
+	q := &ngmodels.ClaimNotificationRetriesQuery{
+		OrgID:      am.orgID,
+		Now:        now,
+		ClaimUntil: now.Add(retryQueueClaimTimeout),
+		Limit:      retryQueueBatchSize,
+	}
+	if err := am.Store.ClaimNotificationRetries(ctx, q); err != nil {
+		am.logger.Error("failed to claim queued notifications", "error", err)
+		return
+	}
+	for _, retry := range q.Result {
+		am.retryNotification(ctx, retry, now)
+	}
+}
+
+func (am *Alertmanager) retryNotification(ctx context.Context, retry *ngmodels.NotificationRetry, now time.Time) {
+	logger := am.logger.New("receiver", retry.Receiver, "integration", retry.Integration, "attempt", retry.Attempts+1)
+
+	integration, ok := am.queuedIntegration(retry)
+	if !ok {
+		logger.Warn("dropping queued notification because the integration no longer exists")
+		am.deleteNotificationRetry(ctx, retry, logger)
+		return
+	}
+	var alerts []*types.Alert
+	if err := json.Unmarshal([]byte(retry.Alerts), &alerts); err != nil {
+		logger.Error("dropping queued notification with invalid alerts", "error", err)
+		am.deleteNotificationRetry(ctx, retry, logger)
+		return
+	}
+
+	groupLabels := make(model.LabelSet, len(retry.GroupLabels))
+	for k, v := range retry.GroupLabels {
+		groupLabels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	notifyCtx := notify.WithReceiverName(ctx, retry.Receiver)
+	notifyCtx = notify.WithGroupKey(notifyCtx, retry.GroupKey)
+	notifyCtx = notify.WithGroupLabels(notifyCtx, groupLabels)
+	notifyCtx = notify.WithNow(notifyCtx, now)
+	notifyCtx, cancel := context.WithTimeout(notifyCtx, retryQueueNotifyTimeout)
+	defer cancel()
+
+	_, err := integration.Notify(notifyCtx, alerts...)
+	if err == nil {
+		logger.Info("delivered queued notification")
+		am.deleteNotificationRetry(ctx, retry, logger)
+		return
+	}
+
+	retry.Attempts++
+	retry.LastError = err.Error()
+	if retry.Attempts >= am.Settings.UnifiedAlerting.DeliveryRetryQueueMaxAttempts {
+		logger.Error("dropping queued notification after the maximum number of attempts", "error", err)
+		am.deleteNotificationRetry(ctx, retry, logger)
+		return
+	}
+	retry.NextAttemptAtUnix = now.Add(retryQueueBackoff(retry.Attempts)).UnixMilli()
+	if err := am.Store.SaveNotificationRetry(ctx, retry); err != nil {
+		logger.Error("failed to reschedule queued notification", "error", err)
+		return
+	}
+	logger.Warn("failed to deliver queued notification", "error", err, "next_attempt", time.UnixMilli(retry.NextAttemptAtUnix))
+}
+
+// queuedIntegration returns the integration of the current configuration that a notification was queued for.
+func (am *Alertmanager) queuedIntegration(retry *ngmodels.NotificationRetry) (notify.Integration, bool) {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+	for _, integration := range am.integrations[retry.Receiver] {
+		if integration.Index() == retry.IntegrationIndex && integration.Name() == retry.Integration {
+			return integration, true
+		}
+	}
+	return notify.Integration{}, false
+}
+
+func (am *Alertmanager) deleteNotificationRetry(ctx context.Context, retry *ngmodels.NotificationRetry, logger log.Logger) {
+	if err := am.Store.DeleteNotificationRetry(ctx, retry.ID); err != nil {
+		logger.Error("failed to delete queued notification", "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	gokit_log "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/nflog/nflogpb"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeRetryQueueStore keeps queued notifications in memory, the other stores are not used by the retry queue.
+type fakeRetryQueueStore struct {
+	store.AlertingStore
+	store.ImageStore
+	*store.FakeNotificationDeliveryStore
+}
+
+func TestRetryQueueStage(t *testing.T) {
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	recv := &nflogpb.Receiver{GroupName: "ops", Integration: "webhook", Idx: 1}
+	firing := newDeliveryTestAlert("firing", now.Add(time.Hour))
+	resolved := newDeliveryTestAlert("resolved", now.Add(-time.Minute))
+
+	newStage := func(err error, sendResolved bool) (*retryQueueStage, *store.FakeNotificationDeliveryStore) {
+		deliveryStore := &store.FakeNotificationDeliveryStore{}
+		n := &fakeNotifier{sendResolved: sendResolved}
+		return &retryQueueStage{
+			stage: notify.StageFunc(func(ctx context.Context, _ gokit_log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+				return ctx, alerts, err
+			}),
+			integration: notify.NewIntegration(n, n, "webhook", 1),
+			store:       deliveryStore,
+			orgID:       1,
+			recv:        recv,
+			logger:      log.NewNopLogger(),
+		}, deliveryStore
+	}
+
+	newContext := func() (context.Context, context.CancelFunc) {
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+		ctx = notify.WithNow(ctx, now)
+		return context.WithCancel(ctx)
+	}
+
+	t.Run("should queue the notification when the retry stage timed out", func(t *testing.T) {
+		stage, deliveryStore := newStage(errors.New("notify retry canceled after 3 attempts"), false)
+		ctx, cancel := newContext()
+		cancel()
+
+		_, alerts, err := stage.Exec(ctx, gokit_log.NewNopLogger(), firing, resolved)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+
+		require.Len(t, deliveryStore.Retries, 1)
+		retry := deliveryStore.Retries[0]
+		require.Equal(t, int64(1), retry.OrgID)
+		require.Equal(t, "ops", retry.Receiver)
+		require.Equal(t, "webhook", retry.Integration)
+		require.Equal(t, 1, retry.IntegrationIndex)
+		require.Equal(t, "{}:{alertname=\"test\"}", retry.GroupKey)
+		require.Equal(t, map[string]string{"alertname": "test"}, retry.GroupLabels)
+		require.Equal(t, now.Add(retryQueueInitialBackoff).UnixMilli(), retry.NextAttemptAtUnix)
+
+		var queued []*types.Alert
+		require.NoError(t, json.Unmarshal([]byte(retry.Alerts), &queued))
+		// the resolved alert is not sent by the integration and the firing alert does not resolve while queued
+		require.Len(t, queued, 1)
+		require.Equal(t, firing.Labels, queued[0].Labels)
+		require.True(t, queued[0].EndsAt.IsZero())
+	})
+
+	t.Run("should not queue the notification on unrecoverable errors", func(t *testing.T) {
+		stage, deliveryStore := newStage(errors.New("notify retry canceled due to unrecoverable error after 1 attempts"), true)
+		ctx, cancel := newContext()
+		defer cancel()
+
+		_, _, err := stage.Exec(ctx, gokit_log.NewNopLogger(), firing)
+		require.Error(t, err)
+		require.Empty(t, deliveryStore.Retries)
+	})
+
+	t.Run("should not queue delivered notifications", func(t *testing.T) {
+		stage, deliveryStore := newStage(nil, true)
+		ctx, cancel := newContext()
+		cancel()
+
+		_, _, err := stage.Exec(ctx, gokit_log.NewNopLogger(), firing)
+		require.NoError(t, err)
+		require.Empty(t, deliveryStore.Retries)
+	})
+}
+
+func TestProcessRetryQueue(t *testing.T) {
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	alerts, err := json.Marshal([]*types.Alert{newDeliveryTestAlert("test", time.Time{})})
+	require.NoError(t, err)
+
+	setup := func(errs ...error) (*Alertmanager, *fakeNotifier, *store.FakeNotificationDeliveryStore) {
+		deliveryStore := &store.FakeNotificationDeliveryStore{}
+		n := &fakeNotifier{errs: errs}
+		am := &Alertmanager{
+			logger:   log.NewNopLogger(),
+			Settings: &setting.Cfg{UnifiedAlerting: setting.UnifiedAlertingSettings{DeliveryRetryQueueMaxAttempts: 2}},
+			Store:    &fakeRetryQueueStore{FakeNotificationDeliveryStore: deliveryStore},
+			orgID:    1,
+			config:   &apimodels.PostableUserConfig{},
+			integrations: map[string][]notify.Integration{
+				"ops": {notify.NewIntegration(n, n, "webhook", 0)},
+			},
+		}
+		require.NoError(t, deliveryStore.SaveNotificationRetry(context.Background(), &ngmodels.NotificationRetry{
+			OrgID:             1,
+			Receiver:          "ops",
+			Integration:       "webhook",
+			GroupKey:          "{}:{alertname=\"test\"}",
+			Alerts:            string(alerts),
+			NextAttemptAtUnix: now.UnixMilli(),
+		}))
+		return am, n, deliveryStore
+	}
+
+	t.Run("should delete delivered notifications", func(t *testing.T) {
+		am, n, deliveryStore := setup()
+		am.processRetryQueue(context.Background(), now)
+		require.Len(t, n.calls, 1)
+		require.Len(t, n.calls[0], 1)
+		require.Empty(t, deliveryStore.Retries)
+	})
+
+	t.Run("should reschedule failed notifications until the maximum number of attempts", func(t *testing.T) {
+		am, n, deliveryStore := setup(errors.New("unavailable"), errors.New("still unavailable"))
+		am.processRetryQueue(context.Background(), now)
+		require.Len(t, deliveryStore.Retries, 1)
+		retry := deliveryStore.Retries[0]
+		require.Equal(t, 1, retry.Attempts)
+		require.Equal(t, "unavailable", retry.LastError)
+		require.Equal(t, now.Add(2*retryQueueInitialBackoff).UnixMilli(), retry.NextAttemptAtUnix)
+
+		// not due yet
+		am.processRetryQueue(context.Background(), now.Add(time.Minute))
+		require.Len(t, n.calls, 1)
+
+		am.processRetryQueue(context.Background(), now.Add(2*time.Minute))
+		require.Len(t, n.calls, 2)
+		require.Empty(t, deliveryStore.Retries)
+	})
+
+	t.Run("should drop notifications of integrations that no longer exist", func(t *testing.T) {
+		am, n, deliveryStore := setup()
+		am.integrations = map[string][]notify.Integration{}
+		am.processRetryQueue(context.Background(), now)
+		require.Empty(t, n.calls)
+		require.Empty(t, deliveryStore.Retries)
+	})
+}
+
+func TestRetryQueueBackoff(t *testing.T) {
+	require.Equal(t, time.Minute, retryQueueBackoff(0))
+	require.Equal(t, 4*time.Minute, retryQueueBackoff(2))
+	require.Equal(t, time.Hour, retryQueueBackoff(10))
+	require.Equal(t, time.Hour, retryQueueBackoff(1000))
+}
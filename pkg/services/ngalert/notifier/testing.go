@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -70,6 +71,18 @@ func (f *FakeConfigStore) SaveAlertmanagerConfigurationWithCallback(_ context.Co
 	return nil
 }
 
+func (f *FakeConfigStore) GetImage(_ context.Context, _ string) (*models.Image, error) {
+	return nil, models.ErrImageNotFound
+}
+
+func (f *FakeConfigStore) SaveImage(_ context.Context, _ *models.Image) error {
+	return errors.New("not implemented")
+}
+
+func (f *FakeConfigStore) DeleteImagesBefore(_ context.Context, _ time.Time) ([]*models.Image, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (f *FakeConfigStore) UpdateAlertmanagerConfiguration(_ context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	if config, exists := f.configs[cmd.OrgID]; exists && config.ConfigurationHash == cmd.FetchedConfigurationHash {
 		f.configs[cmd.OrgID] = &models.AlertConfiguration{
@@ -101,15 +114,17 @@ func (f *FakeOrgStore) GetOrgs(_ context.Context) ([]int64, error) {
 }
 
 type FakeKVStore struct {
-	mtx   sync.Mutex
-	store map[int64]map[string]map[string]string
+	mtx      sync.Mutex
+	store    map[int64]map[string]map[string]string
+	versions map[string]int64
 }
 
 func NewFakeKVStore(t *testing.T) *FakeKVStore {
 	t.Helper()
 
 	return &FakeKVStore{
-		store: map[int64]map[string]map[string]string{},
+		store:    map[int64]map[string]map[string]string{},
+		versions: map[string]int64{},
 	}
 }
 
@@ -145,6 +160,7 @@ func (fkv *FakeKVStore) Set(_ context.Context, orgId int64, namespace string, ke
 	}
 
 	fkv.store[orgId][namespace][key] = value
+	fkv.versions[fakeVersionKey(orgId, namespace, key)]++
 
 	return nil
 }
@@ -188,6 +204,69 @@ func (fkv *FakeKVStore) Keys(ctx context.Context, orgID int64, namespace string,
 	return keys, nil
 }
 
+func fakeVersionKey(orgId int64, namespace string, key string) string {
+	return fmt.Sprintf("%d/%s/%s", orgId, namespace, key)
+}
+
+func (fkv *FakeKVStore) GetVersioned(ctx context.Context, orgId int64, namespace string, key string) (kvstore.VersionedValue, bool, error) {
+	value, ok, err := fkv.Get(ctx, orgId, namespace, key)
+	if !ok || err != nil {
+		return kvstore.VersionedValue{}, ok, err
+	}
+	fkv.mtx.Lock()
+	defer fkv.mtx.Unlock()
+	return kvstore.VersionedValue{Value: value, Version: fkv.versions[fakeVersionKey(orgId, namespace, key)]}, true, nil
+}
+
+func (fkv *FakeKVStore) CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, expectedVersion int64, value string) (int64, bool, error) {
+	current, _, err := fkv.GetVersioned(ctx, orgId, namespace, key)
+	if err != nil || current.Version != expectedVersion {
+		return current.Version, false, err
+	}
+	if err := fkv.Set(ctx, orgId, namespace, key, value); err != nil {
+		return 0, false, err
+	}
+	return expectedVersion + 1, true, nil
+}
+
+// SetWithTTL ignores the ttl, the fake never expires keys
+func (fkv *FakeKVStore) SetWithTTL(ctx context.Context, orgId int64, namespace string, key string, value string, _ time.Duration) error {
+	return fkv.Set(ctx, orgId, namespace, key, value)
+}
+
+func (fkv *FakeKVStore) GetMany(ctx context.Context, orgId int64, namespace string, keys []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range keys {
+		if value, ok, _ := fkv.Get(ctx, orgId, namespace, key); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (fkv *FakeKVStore) SetMany(ctx context.Context, orgId int64, namespace string, values map[string]string) error {
+	for key, value := range values {
+		if err := fkv.Set(ctx, orgId, namespace, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch never sends events
+func (fkv *FakeKVStore) Watch(ctx context.Context, _ int64, _ string, _ string) (<-chan kvstore.Event, error) {
+	ch := make(chan kvstore.Event)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (fkv *FakeKVStore) DeleteExpired(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
 type fakeState struct {
 	data string
 }
@@ -195,3 +274,27 @@ type fakeState struct {
 func (fs *fakeState) MarshalBinary() ([]byte, error) {
 	return []byte(fs.data), nil
 }
+
+func (f *FakeConfigStore) SaveNotificationDelivery(_ context.Context, _ *models.NotificationDelivery) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetNotificationDeliveries(_ context.Context, _ *models.GetNotificationDeliveriesQuery) error {
+	return nil
+}
+
+func (f *FakeConfigStore) DeleteNotificationDeliveriesBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *FakeConfigStore) SaveNotificationRetry(_ context.Context, _ *models.NotificationRetry) error {
+	return nil
+}
+
+func (f *FakeConfigStore) ClaimNotificationRetries(_ context.Context, _ *models.ClaimNotificationRetriesQuery) error {
+	return nil
+}
+
+func (f *FakeConfigStore) DeleteNotificationRetry(_ context.Context, _ int64) error {
+	return nil
+}
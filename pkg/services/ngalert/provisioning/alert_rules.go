@@ -0,0 +1,299 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AlertRuleService struct {
+	defaultInterval int64
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewAlertRuleService(ruleStore RuleStore, provenanceStore ProvisioningStore, xact TransactionManager,
+	defaultInterval int64, log log.Logger) *AlertRuleService {
+	return &AlertRuleService{
+		defaultInterval: defaultInterval,
+		ruleStore:       ruleStore,
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetAlertRule returns the alert rule with the given UID together with its provenance.
+func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
+	query := &models.GetAlertRuleByUIDQuery{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	err := service.ruleStore.GetAlertRuleByUID(ctx, query)
+	if err != nil && !errors.Is(err, models.ErrAlertRuleNotFound) {
+		return models.AlertRule{}, models.ProvenanceNone, err
+	}
+	if query.Result == nil {
+		return models.AlertRule{}, models.ProvenanceNone, fmt.Errorf("%w: alert rule '%s'", ErrNotFound, ruleUID)
+	}
+	provenance, err := service.provenanceStore.GetProvenance(ctx, query.Result, orgID)
+	if err != nil {
+		return models.AlertRule{}, models.ProvenanceNone, err
+	}
+	return *query.Result, provenance, nil
+}
+
+// CreateAlertRule creates a new alert rule. A UID is generated if the rule does not have one yet.
+func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	if rule.UID == "" {
+		rule.UID = util.GenerateShortUID()
+	}
+	if rule.IntervalSeconds == 0 {
+		interval, err := service.ruleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.IntervalSeconds = interval
+	}
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{rule})
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdateAlertRule replaces an existing alert rule. The rule must not be managed by a different provisioning source.
+func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	existing, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := checkProvenance(storedProvenance, provenance); err != nil {
+		return models.AlertRule{}, err
+	}
+	if rule.IntervalSeconds == 0 {
+		interval, err := service.ruleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.IntervalSeconds = interval
+	}
+	rule.ID = existing.ID
+	rule.Version = existing.Version + 1
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := service.ruleStore.UpdateAlertRules(ctx, []store.UpdateRule{
+			{
+				Existing: &existing,
+				New:      rule,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// DeleteAlertRule deletes the alert rule with the given UID. The rule must not be managed by a different provisioning source.
+func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
+	rule := &models.AlertRule{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, orgID)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(storedProvenance, provenance); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, ruleUID)
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.DeleteProvenance(ctx, rule, orgID)
+	})
+}
+
+// GetRuleGroup returns all alert rules of the given rule group together with the interval of the group
+// and the provenance of each rule, keyed by rule UID.
+func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, folderUID, title string) (models.AlertRuleGroup, map[string]models.Provenance, error) {
+	rules, err := service.listRuleGroup(ctx, orgID, folderUID, title)
+	if err != nil {
+		return models.AlertRuleGroup{}, nil, err
+	}
+	if len(rules) == 0 {
+		return models.AlertRuleGroup{}, nil, fmt.Errorf("%w: rule group '%s' in folder '%s'", ErrNotFound, title, folderUID)
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return models.AlertRuleGroup{}, nil, err
+	}
+	group := models.AlertRuleGroup{
+		Title:     title,
+		FolderUID: folderUID,
+		Interval:  rules[0].IntervalSeconds,
+		Rules:     make([]models.AlertRule, 0, len(rules)),
+	}
+	groupProvenances := make(map[string]models.Provenance, len(rules))
+	for _, rule := range rules {
+		group.Rules = append(group.Rules, *rule)
+		groupProvenances[rule.UID] = provenances[rule.UID]
+	}
+	return group, groupProvenances, nil
+}
+
+// ReplaceRuleGroup replaces the content of a rule group with the given one. Rules of the group that are not part of
+// the new definition are deleted, rules with an unknown UID are created and all others are updated. The interval of
+// the group is applied to every rule. None of the affected rules may be managed by a different provisioning source.
+func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, provenance models.Provenance) error {
+	if group.Title == "" || group.FolderUID == "" {
+		return fmt.Errorf("%w: rule group must have a title and a folder", ErrValidation)
+	}
+	if group.Interval < 0 {
+		return fmt.Errorf("%w: rule group interval must not be negative", ErrValidation)
+	}
+	if group.Interval == 0 {
+		group.Interval = service.defaultInterval
+	}
+
+	existingRules, err := service.listRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+	if err != nil {
+		return err
+	}
+	toDelete := make(map[string]*models.AlertRule, len(existingRules))
+	for _, rule := range existingRules {
+		toDelete[rule.UID] = rule
+	}
+
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+
+	var inserts []models.AlertRule
+	var updates []store.UpdateRule
+	for _, rule := range group.Rules {
+		rule.OrgID = orgID
+		rule.NamespaceUID = group.FolderUID
+		rule.RuleGroup = group.Title
+		rule.IntervalSeconds = group.Interval
+
+		if rule.UID == "" {
+			rule.UID = util.GenerateShortUID()
+			inserts = append(inserts, rule)
+			continue
+		}
+
+		existing, ok := toDelete[rule.UID]
+		if ok {
+			delete(toDelete, rule.UID)
+		} else {
+			// the rule might be moved into this group from another one
+			query := &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: rule.UID}
+			err := service.ruleStore.GetAlertRuleByUID(ctx, query)
+			if err != nil && !errors.Is(err, models.ErrAlertRuleNotFound) {
+				return err
+			}
+			existing = query.Result
+		}
+		if existing == nil {
+			inserts = append(inserts, rule)
+			continue
+		}
+		if err := checkProvenance(provenances[rule.UID], provenance); err != nil {
+			return err
+		}
+		rule.ID = existing.ID
+		rule.Version = existing.Version + 1
+		updates = append(updates, store.UpdateRule{
+			Existing: existing,
+			New:      rule,
+		})
+	}
+
+	deleteUIDs := make([]string, 0, len(toDelete))
+	for uid := range toDelete {
+		if err := checkProvenance(provenances[uid], provenance); err != nil {
+			return err
+		}
+		deleteUIDs = append(deleteUIDs, uid)
+	}
+
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if len(deleteUIDs) > 0 {
+			if err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, deleteUIDs...); err != nil {
+				return err
+			}
+			for _, uid := range deleteUIDs {
+				if err := service.provenanceStore.DeleteProvenance(ctx, toDelete[uid], orgID); err != nil {
+					return err
+				}
+			}
+		}
+		if len(updates) > 0 {
+			if err := service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+				return err
+			}
+			for i := range updates {
+				if err := service.provenanceStore.SetProvenance(ctx, &updates[i].New, orgID, provenance); err != nil {
+					return err
+				}
+			}
+		}
+		if len(inserts) > 0 {
+			if err := service.ruleStore.InsertAlertRules(ctx, inserts); err != nil {
+				return err
+			}
+			for i := range inserts {
+				if err := service.provenanceStore.SetProvenance(ctx, &inserts[i], orgID, provenance); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (service *AlertRuleService) listRuleGroup(ctx context.Context, orgID int64, folderUID, title string) ([]*models.AlertRule, error) {
+	query := &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{folderUID},
+		RuleGroup:     title,
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// ruleGroupInterval returns the interval of an existing rule group, or the default interval if the group does not
+// exist yet.
+func (service *AlertRuleService) ruleGroupInterval(ctx context.Context, orgID int64, folderUID, title string) (int64, error) {
+	rules, err := service.listRuleGroup(ctx, orgID, folderUID, title)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return service.defaultInterval, nil
+	}
+	return rules[0].IntervalSeconds, nil
+}
@@ -0,0 +1,213 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleService(t *testing.T) {
+	t.Run("creating a rule keeps the given uid and stores its provenance", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rule := *models.AlertRuleGen(withOrg(1))()
+
+		created, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Equal(t, rule.UID, created.UID)
+
+		inserts := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+		require.Len(t, inserts, 1)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &created, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, provenance)
+	})
+
+	t.Run("creating a rule without uid and interval generates the uid and uses the default interval", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+		rule := *models.AlertRuleGen(withOrg(1))()
+		rule.UID = ""
+		rule.IntervalSeconds = 0
+
+		created, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.Equal(t, sut.defaultInterval, created.IntervalSeconds)
+	})
+
+	t.Run("getting an unknown rule returns ErrNotFound", func(t *testing.T) {
+		sut, _ := createAlertRuleServiceSut(t)
+
+		_, _, err := sut.GetAlertRule(context.Background(), 1, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("updating a rule bumps its version", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := models.AlertRuleGen(withOrg(1))()
+		ruleStore.PutRule(context.Background(), existing)
+		updated := *models.CopyRule(existing)
+		updated.Title = "updated"
+
+		result, err := sut.UpdateAlertRule(context.Background(), updated, models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Equal(t, existing.Version+1, result.Version)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]store.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+	})
+
+	t.Run("updating or deleting a rule from a different provisioning source fails", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := models.AlertRuleGen(withOrg(1))()
+		ruleStore.PutRule(context.Background(), existing)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), existing, 1, models.ProvenanceFile))
+
+		_, err := sut.UpdateAlertRule(context.Background(), *existing, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteAlertRule(context.Background(), 1, existing.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+
+	t.Run("deleting a rule removes it and its provenance", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := models.AlertRuleGen(withOrg(1))()
+		ruleStore.PutRule(context.Background(), existing)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), existing, 1, models.ProvenanceFile))
+
+		err := sut.DeleteAlertRule(context.Background(), 1, existing.UID, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, _, err = sut.GetAlertRule(context.Background(), 1, existing.UID)
+		require.ErrorIs(t, err, ErrNotFound)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), existing, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("creating a rule without interval in an existing group uses the interval of the group", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		existing := models.AlertRuleGen(withOrg(1), withGroup("folder", "group", 120))()
+		ruleStore.PutRule(context.Background(), existing)
+		rule := *models.AlertRuleGen(withOrg(1), withGroup("folder", "group", 0))()
+
+		created, err := sut.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, int64(120), created.IntervalSeconds)
+	})
+
+	t.Run("getting a rule group returns its rules, interval and provenances", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rules := models.GenerateAlertRules(3, models.AlertRuleGen(withOrg(1), withGroup("folder", "group", 120)))
+		ruleStore.PutRule(context.Background(), rules...)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), rules[0], 1, models.ProvenanceAPI))
+
+		group, provenances, err := sut.GetRuleGroup(context.Background(), 1, "folder", "group")
+		require.NoError(t, err)
+		require.Equal(t, int64(120), group.Interval)
+		require.Len(t, group.Rules, 3)
+		require.Equal(t, models.ProvenanceAPI, provenances[rules[0].UID])
+		require.Equal(t, models.ProvenanceNone, provenances[rules[1].UID])
+
+		_, _, err = sut.GetRuleGroup(context.Background(), 1, "folder", "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("replacing a rule group updates, creates and deletes rules", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rules := models.GenerateAlertRules(2, models.AlertRuleGen(withOrg(1), withGroup("folder", "group", 60)))
+		ruleStore.PutRule(context.Background(), rules...)
+		kept := *models.CopyRule(rules[0])
+		added := *models.AlertRuleGen(withOrg(1))()
+		added.UID = ""
+
+		err := sut.ReplaceRuleGroup(context.Background(), 1, models.AlertRuleGroup{
+			Title:     "group",
+			FolderUID: "folder",
+			Interval:  120,
+			Rules:     []models.AlertRule{kept, added},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]store.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]store.UpdateRule)
+		require.Len(t, update, 1)
+		require.Equal(t, kept.UID, update[0].New.UID)
+		require.Equal(t, int64(120), update[0].New.IntervalSeconds)
+		require.Equal(t, kept.Version+1, update[0].New.Version)
+
+		inserts := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+		require.Len(t, inserts, 1)
+		insert := inserts[0].([]models.AlertRule)
+		require.Len(t, insert, 1)
+		require.NotEmpty(t, insert[0].UID)
+		require.Equal(t, "folder", insert[0].NamespaceUID)
+		require.Equal(t, "group", insert[0].RuleGroup)
+		require.Equal(t, int64(120), insert[0].IntervalSeconds)
+
+		_, _, err = sut.GetAlertRule(context.Background(), 1, rules[1].UID)
+		require.ErrorIs(t, err, ErrNotFound)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &insert[0], 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("replacing a rule group that contains rules of a different provisioning source fails", func(t *testing.T) {
+		sut, ruleStore := createAlertRuleServiceSut(t)
+		rules := models.GenerateAlertRules(2, models.AlertRuleGen(withOrg(1), withGroup("folder", "group", 60)))
+		ruleStore.PutRule(context.Background(), rules...)
+		require.NoError(t, sut.provenanceStore.SetProvenance(context.Background(), rules[1], 1, models.ProvenanceFile))
+
+		err := sut.ReplaceRuleGroup(context.Background(), 1, models.AlertRuleGroup{
+			Title:     "group",
+			FolderUID: "folder",
+			Rules:     []models.AlertRule{*rules[0]},
+		}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		_, _, err = sut.GetAlertRule(context.Background(), 1, rules[1].UID)
+		require.NoError(t, err)
+	})
+}
+
+func createAlertRuleServiceSut(t *testing.T) (*AlertRuleService, *store.FakeRuleStore) {
+	ruleStore := store.NewFakeRuleStore(t)
+	return &AlertRuleService{
+		defaultInterval: 60,
+		ruleStore:       ruleStore,
+		provenanceStore: NewFakeProvisioningStore(),
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+	}, ruleStore
+}
+
+func withOrg(orgID int64) func(rule *models.AlertRule) {
+	return func(rule *models.AlertRule) {
+		rule.OrgID = orgID
+	}
+}
+
+func withGroup(folderUID, group string, interval int64) func(rule *models.AlertRule) {
+	return func(rule *models.AlertRule) {
+		rule.NamespaceUID = folderUID
+		rule.RuleGroup = group
+		rule.IntervalSeconds = interval
+	}
+}
@@ -0,0 +1,54 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// cfgRevision is an Alertmanager configuration together with the hash it was read with,
+// so it can only be saved if nobody changed it in between.
+type cfgRevision struct {
+	cfg              *definitions.PostableUserConfig
+	concurrencyToken string
+	version          string
+}
+
+func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigStore) (*cfgRevision, error) {
+	q := models.GetLatestAlertmanagerConfigurationQuery{
+		OrgID: orgID,
+	}
+	if err := store.GetLatestAlertmanagerConfiguration(ctx, &q); err != nil {
+		return nil, err
+	}
+	if q.Result == nil {
+		return nil, fmt.Errorf("no alertmanager configuration present in this org")
+	}
+
+	cfg, err := DeserializeAlertmanagerConfig([]byte(q.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfgRevision{
+		cfg:              cfg,
+		concurrencyToken: q.Result.ConfigurationHash,
+		version:          q.Result.ConfigurationVersion,
+	}, nil
+}
+
+func (r *cfgRevision) save(ctx context.Context, orgID int64, store AMConfigStore) error {
+	serialized, err := SerializeAlertmanagerConfig(*r.cfg)
+	if err != nil {
+		return err
+	}
+	return store.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      r.version,
+		FetchedConfigurationHash:  r.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	})
+}
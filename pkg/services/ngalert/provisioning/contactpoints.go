@@ -132,7 +132,11 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		extractedSecrets[k] = encryptedValue
 	}
 
-	contactPoint.UID = util.GenerateShortUID()
+	if contactPoint.UID == "" {
+		contactPoint.UID = util.GenerateShortUID()
+	} else if _, exists := cfg.GetGrafanaReceiverMap()[contactPoint.UID]; exists {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: contact point with uid '%s' already exists", ErrValidation, contactPoint.UID)
+	}
 	grafanaReceiver := &apimodels.PostableGrafanaReceiver{
 		UID:                   contactPoint.UID,
 		Name:                  contactPoint.Name,
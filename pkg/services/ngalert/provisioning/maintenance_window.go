@@ -0,0 +1,137 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type MaintenanceWindowService struct {
+	store MaintenanceWindowStore
+	prov  ProvisioningStore
+	xact  TransactionManager
+	log   log.Logger
+}
+
+func NewMaintenanceWindowService(store MaintenanceWindowStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		store: store,
+		prov:  prov,
+		xact:  xact,
+		log:   log,
+	}
+}
+
+// GetMaintenanceWindows returns all maintenance windows within the specified org together with their provenance,
+// keyed by UID.
+func (svc *MaintenanceWindowService) GetMaintenanceWindows(ctx context.Context, orgID int64) ([]models.MaintenanceWindow, map[string]models.Provenance, error) {
+	query := &models.ListMaintenanceWindowsQuery{OrgID: orgID}
+	if err := svc.store.ListMaintenanceWindows(ctx, query); err != nil {
+		return nil, nil, err
+	}
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&models.MaintenanceWindow{}).ResourceType())
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make([]models.MaintenanceWindow, 0, len(query.Result))
+	for _, w := range query.Result {
+		result = append(result, *w)
+	}
+	return result, provenances, nil
+}
+
+// GetMaintenanceWindow returns the maintenance window with the given UID together with its provenance.
+func (svc *MaintenanceWindowService) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (models.MaintenanceWindow, models.Provenance, error) {
+	query := &models.GetMaintenanceWindowQuery{OrgID: orgID, UID: uid}
+	err := svc.store.GetMaintenanceWindow(ctx, query)
+	if errors.Is(err, models.ErrMaintenanceWindowNotFound) {
+		return models.MaintenanceWindow{}, models.ProvenanceNone, fmt.Errorf("%w: maintenance window '%s'", ErrNotFound, uid)
+	}
+	if err != nil {
+		return models.MaintenanceWindow{}, models.ProvenanceNone, err
+	}
+	provenance, err := svc.prov.GetProvenance(ctx, query.Result, orgID)
+	if err != nil {
+		return models.MaintenanceWindow{}, models.ProvenanceNone, err
+	}
+	return *query.Result, provenance, nil
+}
+
+// CreateMaintenanceWindow adds a new maintenance window. A UID is generated if the window does not have one yet.
+func (svc *MaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, window models.MaintenanceWindow, provenance models.Provenance) (models.MaintenanceWindow, error) {
+	if err := window.Validate(); err != nil {
+		return models.MaintenanceWindow{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if window.UID == "" {
+		window.UID = util.GenerateShortUID()
+	} else {
+		_, _, err := svc.GetMaintenanceWindow(ctx, window.OrgID, window.UID)
+		if err == nil {
+			return models.MaintenanceWindow{}, fmt.Errorf("%w: a maintenance window with this UID already exists", ErrValidation)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return models.MaintenanceWindow{}, err
+		}
+	}
+
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.SaveMaintenanceWindow(ctx, &window); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &window, window.OrgID, provenance)
+	})
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	return window, nil
+}
+
+// UpdateMaintenanceWindow replaces an existing maintenance window. The window must not be managed by a different
+// provisioning source.
+func (svc *MaintenanceWindowService) UpdateMaintenanceWindow(ctx context.Context, window models.MaintenanceWindow, provenance models.Provenance) (models.MaintenanceWindow, error) {
+	if err := window.Validate(); err != nil {
+		return models.MaintenanceWindow{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	existing, storedProvenance, err := svc.GetMaintenanceWindow(ctx, window.OrgID, window.UID)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	if err := checkProvenance(storedProvenance, provenance); err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+
+	window.ID = existing.ID
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.SaveMaintenanceWindow(ctx, &window); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &window, window.OrgID, provenance)
+	})
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	return window, nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window with the given UID. The window must not be managed by a
+// different provisioning source.
+func (svc *MaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	target := &models.MaintenanceWindow{OrgID: orgID, UID: uid}
+	storedProvenance, err := svc.prov.GetProvenance(ctx, target, orgID)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(storedProvenance, provenance); err != nil {
+		return err
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteMaintenanceWindow(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.prov.DeleteProvenance(ctx, target, orgID)
+	})
+}
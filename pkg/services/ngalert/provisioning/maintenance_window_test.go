@@ -0,0 +1,96 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowService(t *testing.T) {
+	t.Run("created maintenance windows are returned with their provenance", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+
+		created, err := sut.CreateMaintenanceWindow(context.Background(), createMaintenanceWindow(""), models.ProvenanceFile)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+
+		windows, provenances, err := sut.GetMaintenanceWindows(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, created.UID, windows[0].UID)
+		require.Equal(t, models.ProvenanceFile, provenances[created.UID])
+	})
+
+	t.Run("creating an invalid maintenance window fails", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		window := createMaintenanceWindow("")
+		window.RuleUID = "rule"
+
+		_, err := sut.CreateMaintenanceWindow(context.Background(), window, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("creating a maintenance window with an existing UID fails", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		_, err := sut.CreateMaintenanceWindow(context.Background(), createMaintenanceWindow("upgrade"), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.CreateMaintenanceWindow(context.Background(), createMaintenanceWindow("upgrade"), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("updating an unknown maintenance window returns ErrNotFound", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+
+		_, err := sut.UpdateMaintenanceWindow(context.Background(), createMaintenanceWindow("unknown"), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("updating or deleting a maintenance window from a different provisioning source fails", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		_, err := sut.CreateMaintenanceWindow(context.Background(), createMaintenanceWindow("upgrade"), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateMaintenanceWindow(context.Background(), createMaintenanceWindow("upgrade"), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteMaintenanceWindow(context.Background(), 1, "upgrade", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+
+	t.Run("deleted maintenance windows are no longer returned", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		_, err := sut.CreateMaintenanceWindow(context.Background(), createMaintenanceWindow("upgrade"), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteMaintenanceWindow(context.Background(), 1, "upgrade", models.ProvenanceAPI))
+
+		_, _, err = sut.GetMaintenanceWindow(context.Background(), 1, "upgrade")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func createMaintenanceWindowSvcSut() *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		store: &store.FakeMaintenanceWindowStore{},
+		prov:  NewFakeProvisioningStore(),
+		xact:  newNopTransactionManager(),
+		log:   log.NewNopLogger(),
+	}
+}
+
+func createMaintenanceWindow(uid string) models.MaintenanceWindow {
+	start := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	return models.MaintenanceWindow{
+		OrgID:        1,
+		UID:          uid,
+		Title:        "database upgrade",
+		NamespaceUID: "folder",
+		Ranges:       []models.MaintenanceTimeRange{{Start: start, End: start.Add(time.Hour)}},
+	}
+}
@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuteTimingService(t *testing.T) {
+	t.Run("service returns no mute timings for default config", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+
+		result, err := sut.GetMuteTimings(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("created mute timings are returned with their provenance", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		mt := createMuteTiming("weekends", models.ProvenanceFile)
+
+		_, err := sut.CreateMuteTiming(context.Background(), mt, 1)
+		require.NoError(t, err)
+
+		result, err := sut.GetMuteTimings(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "weekends", result[0].Name)
+		require.Equal(t, models.ProvenanceFile, result[0].Provenance)
+	})
+
+	t.Run("creating a mute timing twice fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		mt := createMuteTiming("weekends", models.ProvenanceAPI)
+
+		_, err := sut.CreateMuteTiming(context.Background(), mt, 1)
+		require.NoError(t, err)
+		_, err = sut.CreateMuteTiming(context.Background(), mt, 1)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("creating a mute timing without name fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+
+		_, err := sut.CreateMuteTiming(context.Background(), createMuteTiming("", models.ProvenanceAPI), 1)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("updating an unknown mute timing fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+
+		_, err := sut.UpdateMuteTiming(context.Background(), createMuteTiming("weekends", models.ProvenanceAPI), 1)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("updating a mute timing from a different provisioning source fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		_, err := sut.CreateMuteTiming(context.Background(), createMuteTiming("weekends", models.ProvenanceFile), 1)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateMuteTiming(context.Background(), createMuteTiming("weekends", models.ProvenanceAPI), 1)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+
+	t.Run("deleting a mute timing removes it and its provenance", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		mt := createMuteTiming("weekends", models.ProvenanceFile)
+		_, err := sut.CreateMuteTiming(context.Background(), mt, 1)
+		require.NoError(t, err)
+
+		err = sut.DeleteMuteTiming(context.Background(), "weekends", 1, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		result, err := sut.GetMuteTimings(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, result)
+		provenance, err := sut.prov.GetProvenance(context.Background(), &mt, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("deleting a mute timing used by a policy fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		_, err := sut.CreateMuteTiming(context.Background(), createMuteTiming("weekends", models.ProvenanceAPI), 1)
+		require.NoError(t, err)
+		revision, err := getLastConfiguration(context.Background(), 1, sut.config)
+		require.NoError(t, err)
+		revision.cfg.AlertmanagerConfig.Route.Routes[0].MuteTimeIntervals = []string{"weekends"}
+		require.NoError(t, revision.save(context.Background(), 1, sut.config))
+
+		err = sut.DeleteMuteTiming(context.Background(), "weekends", 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createMuteTimingSvcSut() *MuteTimingService {
+	return &MuteTimingService{
+		config: newFakeAMConfigStore(),
+		prov:   NewFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+	}
+}
+
+func createMuteTiming(name string, provenance models.Provenance) definitions.MuteTimeInterval {
+	return definitions.MuteTimeInterval{
+		MuteTimeInterval: config.MuteTimeInterval{
+			Name: name,
+		},
+		Provenance: provenance,
+	}
+}
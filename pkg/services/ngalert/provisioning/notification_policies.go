@@ -78,6 +78,14 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		return err
 	}
 
+	storedProvenance, err := nps.provenanceStore.GetProvenance(ctx, &tree, orgID)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(storedProvenance, p); err != nil {
+		return err
+	}
+
 	cfg.AlertmanagerConfig.Config.Route = &tree
 
 	serialized, err := SerializeAlertmanagerConfig(*cfg)
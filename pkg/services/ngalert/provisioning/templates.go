@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -23,6 +24,7 @@ func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact Trans
 		log:    log,
 	}
 }
+
 func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[string]string, error) {
 	q := models.GetLatestAlertmanagerConfigurationQuery{
 		OrgID: orgID,
@@ -46,3 +48,64 @@ func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[st
 
 	return cfg.TemplateFiles, nil
 }
+
+func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error) {
+	if err := tmpl.Validate(); err != nil {
+		return definitions.MessageTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	storedProvenance, err := t.prov.GetProvenance(ctx, &tmpl, orgID)
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+	if err := checkProvenance(storedProvenance, tmpl.Provenance); err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	if revision.cfg.TemplateFiles == nil {
+		revision.cfg.TemplateFiles = map[string]string{}
+	}
+	revision.cfg.TemplateFiles[tmpl.Name] = tmpl.Template
+
+	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := revision.save(ctx, orgID, t.config); err != nil {
+			return err
+		}
+		return t.prov.SetProvenance(ctx, &tmpl, orgID, tmpl.Provenance)
+	})
+	if err != nil {
+		return definitions.MessageTemplate{}, err
+	}
+
+	return tmpl, nil
+}
+
+func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, provenance models.Provenance) error {
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return err
+	}
+
+	target := &definitions.MessageTemplate{Name: name}
+	storedProvenance, err := t.prov.GetProvenance(ctx, target, orgID)
+	if err != nil {
+		return err
+	}
+	if err := checkProvenance(storedProvenance, provenance); err != nil {
+		return err
+	}
+
+	delete(revision.cfg.TemplateFiles, name)
+
+	return t.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := revision.save(ctx, orgID, t.config); err != nil {
+			return err
+		}
+		return t.prov.DeleteProvenance(ctx, target, orgID)
+	})
+}
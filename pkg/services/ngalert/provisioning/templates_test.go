@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
 	mock "github.com/stretchr/testify/mock"
@@ -138,3 +139,79 @@ var brokenConfig = `
 		}]
 	}
 }`
+
+func TestTemplateServiceUpdates(t *testing.T) {
+	t.Run("set template adds it to the config", func(t *testing.T) {
+		sut := createTemplateServiceSutWithFakeConfig()
+		tmpl := definitions.MessageTemplate{
+			Name:     "my-template",
+			Template: `{{ define "my-template" }}{{ .Status }}{{ end }}`,
+		}
+
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+		require.NoError(t, err)
+
+		result, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, tmpl.Template, result["my-template"])
+	})
+
+	t.Run("set template rejects invalid templates", func(t *testing.T) {
+		sut := createTemplateServiceSutWithFakeConfig()
+		tmpl := definitions.MessageTemplate{
+			Name:     "my-template",
+			Template: `{{ define "my-template" }}`,
+		}
+
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("set template rejects changes from a different provisioning source", func(t *testing.T) {
+		sut := createTemplateServiceSutWithFakeConfig()
+		tmpl := definitions.MessageTemplate{
+			Name:       "my-template",
+			Template:   "template",
+			Provenance: models.ProvenanceFile,
+		}
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+		require.NoError(t, err)
+
+		tmpl.Provenance = models.ProvenanceAPI
+		_, err = sut.SetTemplate(context.Background(), 1, tmpl)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+	})
+
+	t.Run("delete template removes it from the config", func(t *testing.T) {
+		sut := createTemplateServiceSutWithFakeConfig()
+		tmpl := definitions.MessageTemplate{
+			Name:       "my-template",
+			Template:   "template",
+			Provenance: models.ProvenanceFile,
+		}
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+		require.NoError(t, err)
+
+		err = sut.DeleteTemplate(context.Background(), 1, tmpl.Name, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		err = sut.DeleteTemplate(context.Background(), 1, tmpl.Name, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		result, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, result)
+		provenance, err := sut.prov.GetProvenance(context.Background(), &tmpl, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+}
+
+func createTemplateServiceSutWithFakeConfig() *TemplateService {
+	return &TemplateService{
+		config: newFakeAMConfigStore(),
+		prov:   NewFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+	}
+}
@@ -1,5 +1,20 @@
 package provisioning
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
 
 var ErrValidation = fmt.Errorf("invalid object specification")
+var ErrNotFound = fmt.Errorf("object not found")
+var ErrProvenanceMismatch = fmt.Errorf("object is managed by a different provisioning source")
+
+// checkProvenance makes sure that objects provisioned by one source are not changed by another.
+// Objects without provenance can be taken over by any source.
+func checkProvenance(stored models.Provenance, requested models.Provenance) error {
+	if stored != requested && stored != models.ProvenanceNone {
+		return fmt.Errorf("%w: cannot change provenance from '%s' to '%s'", ErrProvenanceMismatch, stored, requested)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package recording
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// FakeWriter records the writes of recording rules.
+type FakeWriter struct {
+	mtx    sync.Mutex
+	Writes []FakeWrite
+}
+
+// FakeWrite is a single write recorded by FakeWriter.
+type FakeWrite struct {
+	RuleUID     string
+	EvaluatedAt time.Time
+	Frames      data.Frames
+}
+
+func (w *FakeWriter) Write(_ context.Context, rule *models.AlertRule, evaluatedAt time.Time, frames data.Frames) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.Writes = append(w.Writes, FakeWrite{RuleUID: rule.UID, EvaluatedAt: evaluatedAt, Frames: frames})
+	return nil
+}
+
+// GetWrites returns a copy of the recorded writes.
+func (w *FakeWriter) GetWrites() []FakeWrite {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	result := make([]FakeWrite, len(w.Writes))
+	copy(result, w.Writes)
+	return result
+}
@@ -0,0 +1,192 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// LiveNamespace is the namespace of the stream scope the metrics of recording rules with the live target are published to.
+const LiveNamespace = "alerting"
+
+const remoteWriteTimeout = 10 * time.Second
+
+var ErrRemoteWriteNotConfigured = errors.New("remote write endpoint for recording rules is not configured")
+
+// Writer writes the results of recording rules as metrics.
+type Writer interface {
+	Write(ctx context.Context, rule *models.AlertRule, evaluatedAt time.Time, frames data.Frames) error
+}
+
+// StreamProvider provides the managed streams of Grafana Live.
+type StreamProvider interface {
+	GetOrCreateStream(orgID int64, scope string, namespace string) (*managedstream.NamespaceStream, error)
+}
+
+// MetricWriter writes the results of recording rules to the target of the rule.
+type MetricWriter struct {
+	remoteWriteURL      string
+	remoteWriteUser     string
+	remoteWritePassword string
+	streams             StreamProvider
+	httpClient          *http.Client
+	log                 log.Logger
+}
+
+// NewMetricWriter creates a new MetricWriter. Streams can be nil, in which case recording rules with the live target fail to write.
+func NewMetricWriter(cfg setting.UnifiedAlertingSettings, streams StreamProvider, logger log.Logger) *MetricWriter {
+	return &MetricWriter{
+		remoteWriteURL:      cfg.RecordingRulesRemoteWriteURL,
+		remoteWriteUser:     cfg.RecordingRulesRemoteWriteUser,
+		remoteWritePassword: cfg.RecordingRulesRemoteWritePassword,
+		streams:             streams,
+		httpClient:          &http.Client{Timeout: remoteWriteTimeout},
+		log:                 logger,
+	}
+}
+
+// Write writes one sample per series in the frames. The sample is the last value of the series and is timestamped with the evaluation time.
+func (w *MetricWriter) Write(ctx context.Context, rule *models.AlertRule, evaluatedAt time.Time, frames data.Frames) error {
+	if !rule.IsRecordingRule() {
+		return fmt.Errorf("rule %s is not a recording rule", rule.UID)
+	}
+	series := SeriesFromFrames(rule.Record.Metric, rule.Labels, evaluatedAt, frames)
+	if len(series) == 0 {
+		w.log.Debug("recording rule produced no series", "uid", rule.UID, "metric", rule.Record.Metric)
+		return nil
+	}
+	switch rule.Record.Target {
+	case models.RecordTargetRemoteWrite:
+		return w.remoteWrite(ctx, series)
+	case models.RecordTargetLive:
+		return w.publish(ctx, rule.OrgID, rule.Record.Metric, series)
+	default:
+		return fmt.Errorf("unknown record target %q", rule.Record.Target)
+	}
+}
+
+func (w *MetricWriter) remoteWrite(ctx context.Context, series []prompb.TimeSeries) error {
+	if w.remoteWriteURL == "" {
+		return ErrRemoteWriteNotConfigured
+	}
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return fmt.Errorf("error converting time series to bytes: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.remoteWriteURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error constructing remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.remoteWriteUser != "" {
+		req.SetBasicAuth(w.remoteWriteUser, w.remoteWritePassword)
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending remote write request: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response code %d from remote write endpoint", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *MetricWriter) publish(ctx context.Context, orgID int64, metric string, series []prompb.TimeSeries) error {
+	if w.streams == nil {
+		return errors.New("grafana live is not available")
+	}
+	stream, err := w.streams.GetOrCreateStream(orgID, live.ScopeStream, LiveNamespace)
+	if err != nil {
+		return err
+	}
+	return stream.Push(ctx, metric, frameFromSeries(metric, series))
+}
+
+// SeriesFromFrames converts the numeric fields of the frames to time series with the given metric name.
+// Each series has a single sample with the last non-null value of the field at the evaluation time.
+// The labels of the series are the labels of the rule merged with the labels of the field, the latter take precedence.
+func SeriesFromFrames(metric string, ruleLabels map[string]string, evaluatedAt time.Time, frames data.Frames) []prompb.TimeSeries {
+	result := make([]prompb.TimeSeries, 0)
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			value, ok := lastValue(field)
+			if !ok {
+				continue
+			}
+			lbs := make(map[string]string, len(ruleLabels)+len(field.Labels))
+			for k, v := range ruleLabels {
+				lbs[k] = v
+			}
+			for k, v := range field.Labels {
+				lbs[k] = v
+			}
+			result = append(result, prompb.TimeSeries{
+				Labels:  promLabels(metric, lbs),
+				Samples: []prompb.Sample{{Timestamp: evaluatedAt.UnixMilli(), Value: value}},
+			})
+		}
+	}
+	return result
+}
+
+func lastValue(field *data.Field) (float64, bool) {
+	for i := field.Len() - 1; i >= 0; i-- {
+		if _, ok := field.ConcreteAt(i); !ok {
+			continue
+		}
+		v, err := field.FloatAt(i)
+		if err != nil {
+			return 0, false
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+func promLabels(metric string, lbs map[string]string) []prompb.Label {
+	result := make([]prompb.Label, 0, len(lbs)+1)
+	result = append(result, prompb.Label{Name: "__name__", Value: metric})
+	for k, v := range lbs {
+		result = append(result, prompb.Label{Name: k, Value: v})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// frameFromSeries builds a wide frame with a single row at the evaluation time and one field per series.
+func frameFromSeries(metric string, series []prompb.TimeSeries) *data.Frame {
+	frame := data.NewFrame(metric, data.NewField("time", nil, []time.Time{time.UnixMilli(series[0].Samples[0].Timestamp)}))
+	for _, s := range series {
+		lbs := data.Labels{}
+		for _, l := range s.Labels {
+			if l.Name == "__name__" {
+				continue
+			}
+			lbs[l.Name] = l.Value
+		}
+		frame.Fields = append(frame.Fields, data.NewField("value", lbs, []float64{s.Samples[0].Value}))
+	}
+	return frame
+}
@@ -0,0 +1,117 @@
+package recording
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSeriesFromFrames(t *testing.T) {
+	evaluatedAt := time.Unix(1000, 0)
+	one := 1.0
+	frames := data.Frames{
+		data.NewFrame("",
+			data.NewField("time", nil, []time.Time{evaluatedAt.Add(-time.Minute), evaluatedAt}),
+			data.NewField("value", data.Labels{"instance": "a", "team": "field"}, []*float64{&one, nil}),
+		),
+		data.NewFrame("",
+			data.NewField("value", data.Labels{"instance": "b"}, []float64{2}),
+		),
+		data.NewFrame("",
+			data.NewField("value", data.Labels{"instance": "c"}, []*float64{nil}),
+		),
+	}
+
+	series := SeriesFromFrames("test_metric", map[string]string{"team": "rule"}, evaluatedAt, frames)
+
+	require.Equal(t, []prompb.TimeSeries{
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: "test_metric"},
+				{Name: "instance", Value: "a"},
+				{Name: "team", Value: "field"},
+			},
+			Samples: []prompb.Sample{{Timestamp: evaluatedAt.UnixMilli(), Value: 1}},
+		},
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: "test_metric"},
+				{Name: "instance", Value: "b"},
+				{Name: "team", Value: "rule"},
+			},
+			Samples: []prompb.Sample{{Timestamp: evaluatedAt.UnixMilli(), Value: 2}},
+		},
+	}, series)
+}
+
+func TestMetricWriter_Write(t *testing.T) {
+	evaluatedAt := time.Unix(1000, 0)
+	frames := data.Frames{data.NewFrame("", data.NewField("value", data.Labels{"instance": "a"}, []float64{42}))}
+	rule := &models.AlertRule{
+		UID:    "test",
+		OrgID:  1,
+		Record: &models.Record{Metric: "test_metric", Target: models.RecordTargetRemoteWrite},
+	}
+
+	t.Run("should send series to remote write endpoint", func(t *testing.T) {
+		var received prompb.WriteRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "user", user)
+			require.Equal(t, "password", password)
+			compressed, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			b, err := snappy.Decode(nil, compressed)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(b, &received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		writer := NewMetricWriter(setting.UnifiedAlertingSettings{
+			RecordingRulesRemoteWriteURL:      server.URL,
+			RecordingRulesRemoteWriteUser:     "user",
+			RecordingRulesRemoteWritePassword: "password",
+		}, nil, log.NewNopLogger())
+
+		require.NoError(t, writer.Write(context.Background(), rule, evaluatedAt, frames))
+		require.Len(t, received.Timeseries, 1)
+		require.Equal(t, []prompb.Sample{{Timestamp: evaluatedAt.UnixMilli(), Value: 42}}, received.Timeseries[0].Samples)
+	})
+
+	t.Run("should fail if remote write endpoint responds with error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		writer := NewMetricWriter(setting.UnifiedAlertingSettings{RecordingRulesRemoteWriteURL: server.URL}, nil, log.NewNopLogger())
+		require.Error(t, writer.Write(context.Background(), rule, evaluatedAt, frames))
+	})
+
+	t.Run("should fail if remote write endpoint is not configured", func(t *testing.T) {
+		writer := NewMetricWriter(setting.UnifiedAlertingSettings{}, nil, log.NewNopLogger())
+		require.ErrorIs(t, writer.Write(context.Background(), rule, evaluatedAt, frames), ErrRemoteWriteNotConfigured)
+	})
+
+	t.Run("should fail if live is not available", func(t *testing.T) {
+		writer := NewMetricWriter(setting.UnifiedAlertingSettings{}, nil, log.NewNopLogger())
+		liveRule := models.CopyRule(rule)
+		liveRule.Record.Target = models.RecordTargetLive
+		require.Error(t, writer.Write(context.Background(), liveRule, evaluatedAt, frames))
+	})
+}
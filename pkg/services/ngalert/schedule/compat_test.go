@@ -117,6 +117,16 @@ func Test_stateToPostableAlert(t *testing.T) {
 					result = stateToPostableAlert(alertState, appURL)
 					require.Equal(t, expected, result.Annotations)
 				})
+
+				t.Run("add the image token if it has an image", func(t *testing.T) {
+					alertState := randomState(tc.state)
+					alertState.Annotations = randomMapOfStrings()
+					alertState.Image = &ngModels.Image{Token: util.GenerateShortUID()}
+
+					result := stateToPostableAlert(alertState, appURL)
+
+					require.Equal(t, alertState.Image.Token, result.Annotations[ngModels.ImageTokenAnnotation])
+				})
 			})
 
 			switch tc.state {
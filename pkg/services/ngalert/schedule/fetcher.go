@@ -23,3 +23,28 @@ func (sch *schedule) getAlertRules(ctx context.Context, disabledOrgs []int64) []
 	}
 	return q.Result
 }
+
+// updateMaintenanceWindows replaces the maintenance windows known to the scheduler. The previous windows are kept if
+// they cannot be fetched.
+func (sch *schedule) updateMaintenanceWindows(ctx context.Context, disabledOrgs []int64) {
+	if sch.maintenanceWindowStore == nil {
+		return
+	}
+	q := models.ListMaintenanceWindowsQuery{
+		ExcludeOrgs: disabledOrgs,
+	}
+	if err := sch.maintenanceWindowStore.ListMaintenanceWindows(ctx, &q); err != nil {
+		sch.log.Error("failed to fetch maintenance windows", "err", err)
+		return
+	}
+	sch.maintenanceWindowsMtx.Lock()
+	defer sch.maintenanceWindowsMtx.Unlock()
+	sch.maintenanceWindows = q.Result
+}
+
+// pauseReason returns why the evaluation of the rule is skipped at the given time, or an empty string if it is not.
+func (sch *schedule) pauseReason(rule *models.AlertRule, t time.Time) string {
+	sch.maintenanceWindowsMtx.RLock()
+	defer sch.maintenanceWindowsMtx.RUnlock()
+	return models.PauseReason(rule, sch.maintenanceWindows, t)
+}
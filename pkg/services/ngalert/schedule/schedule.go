@@ -16,7 +16,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 
@@ -84,6 +86,16 @@ type schedule struct {
 
 	stateManager *state.Manager
 
+	recordingWriter recording.Writer
+
+	// ring assigns the alert rules to the members of the HA cluster. If it is nil, all rules are evaluated.
+	ring *sharding.Ring
+
+	// maintenanceWindows are fetched on every tick, like the alert rules. Rules with an active window are not evaluated.
+	maintenanceWindowStore store.MaintenanceWindowStore
+	maintenanceWindowsMtx  sync.RWMutex
+	maintenanceWindows     []*models.MaintenanceWindow
+
 	appURL *url.URL
 
 	multiOrgNotifier *notifier.MultiOrgAlertmanager
@@ -117,6 +129,9 @@ type SchedulerCfg struct {
 	AdminConfigPollInterval time.Duration
 	DisabledOrgs            map[int64]struct{}
 	MinRuleInterval         time.Duration
+	RecordingWriter         recording.Writer
+	Ring                    *sharding.Ring
+	MaintenanceWindowStore  store.MaintenanceWindowStore
 }
 
 // NewScheduler returns a new schedule.
@@ -148,6 +163,9 @@ func NewScheduler(cfg SchedulerCfg, expressionService *expr.Service, appURL *url
 		adminConfigPollInterval: cfg.AdminConfigPollInterval,
 		disabledOrgs:            cfg.DisabledOrgs,
 		minRuleInterval:         cfg.MinRuleInterval,
+		recordingWriter:         cfg.RecordingWriter,
+		ring:                    cfg.Ring,
+		maintenanceWindowStore:  cfg.MaintenanceWindowStore,
 	}
 	return &sch
 }
@@ -323,6 +341,26 @@ func (sch *schedule) DeleteAlertRule(key models.AlertRuleKey) {
 	ruleInfo.stop()
 }
 
+// handOverAlertRule stops the evaluation of an alert rule that is now owned by another member of the HA cluster.
+func (sch *schedule) handOverAlertRule(key models.AlertRuleKey) {
+	ruleInfo, ok := sch.registry.del(key)
+	if !ok {
+		return
+	}
+	sch.log.Info("handing over alert rule", "uid", key.UID, "org_id", key.OrgID, "owner", sch.ring.Owner(key))
+	ruleInfo.stop()
+}
+
+// isHandedOver returns true if the evaluation of a stopped alert rule is continued by another member of the HA cluster.
+// This is the case if the rule has moved to another member or if the routine is stopped because this instance shuts down,
+// in which case the rule is still registered.
+func (sch *schedule) isHandedOver(key models.AlertRuleKey) bool {
+	if sch.ring == nil || !sch.ring.HasPeers() {
+		return false
+	}
+	return !sch.ring.IsOwner(key) || sch.registry.exists(key)
+}
+
 func (sch *schedule) adminConfigSync(ctx context.Context) error {
 	for {
 		select {
@@ -346,6 +384,9 @@ func (sch *schedule) adminConfigSync(ctx context.Context) error {
 
 func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	firstTick := true
+	// the rules evaluated by other members of the HA cluster at the last tick
+	var remoteRules map[models.AlertRuleKey]struct{}
 	for {
 		select {
 		case tick := <-sch.ticker.C:
@@ -364,6 +405,11 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 
 			alertRules := sch.getAlertRules(ctx, disabledOrgs)
 			sch.log.Debug("alert rules fetched", "count", len(alertRules), "disabled_orgs", disabledOrgs)
+			sch.updateMaintenanceWindows(ctx, disabledOrgs)
+
+			if sch.ring != nil && sch.ring.Sync() {
+				sch.log.Info("members of the HA cluster changed, rebalancing alert rules")
+			}
 
 			// registeredDefinitions is a map used for finding deleted alert rules
 			// initially it is assigned to all known alert rules from the previous cycle
@@ -378,11 +424,27 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			}
 
 			readyToRun := make([]readyToRunItem, 0)
+			var remote []*models.AlertRule
 			for _, item := range alertRules {
 				key := item.GetKey()
 				itemVersion := item.Version
+
+				if sch.ring != nil && !sch.ring.IsOwner(key) {
+					if sch.registry.exists(key) {
+						sch.handOverAlertRule(key)
+					}
+					remote = append(remote, item)
+					delete(registeredDefinitions, key)
+					continue
+				}
+
 				ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
+				// the rule was evaluated by another member of the HA cluster, continue with the state it has stored
+				if newRoutine && !firstTick && sch.ring != nil {
+					sch.stateManager.WarmRule(ctx, item)
+				}
+
 				// enforce minimum evaluation interval
 				if item.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
 					sch.log.Debug("interval adjusted", "rule_interval_seconds", item.IntervalSeconds, "min_interval_seconds", sch.minRuleInterval.Seconds(), "key", key)
@@ -413,6 +475,10 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				delete(registeredDefinitions, key)
 			}
 
+			if sch.ring != nil {
+				remoteRules = sch.syncRemoteRules(ctx, remote, remoteRules)
+			}
+
 			var step int64 = 0
 			if len(readyToRun) > 0 {
 				step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
@@ -434,6 +500,7 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 				sch.DeleteAlertRule(key)
 			}
 
+			firstTick = false
 			sch.metrics.SchedulePeriodicDuration.Observe(time.Since(start).Seconds())
 		case <-ctx.Done():
 			waitErr := dispatcherGroup.Wait()
@@ -444,7 +511,7 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			}
 
 			for _, v := range orgIds {
-				sch.saveAlertStates(ctx, sch.stateManager.GetAll(v))
+				sch.saveAlertStates(ctx, sch.localStates(v))
 			}
 
 			sch.stateManager.Close()
@@ -453,6 +520,42 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 	}
 }
 
+// syncRemoteRules loads the state of the rules evaluated by other members of the HA cluster from the database, so that
+// the API returns their alert instances and they can suppress the rules that depend on them on this instance as well.
+// The state of the rules that were evaluated by other members at the previous tick and no longer exist is dropped.
+// It returns the keys of the given rules.
+func (sch *schedule) syncRemoteRules(ctx context.Context, rules []*models.AlertRule, previous map[models.AlertRuleKey]struct{}) map[models.AlertRuleKey]struct{} {
+	current := make(map[models.AlertRuleKey]struct{}, len(rules))
+	for _, rule := range rules {
+		current[rule.GetKey()] = struct{}{}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok && !sch.registry.exists(key) {
+			sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+		}
+	}
+	if len(rules) > 0 {
+		sch.stateManager.SyncRules(ctx, rules)
+	}
+	return current
+}
+
+// localStates returns the states of the rules evaluated by this instance. The states of the rules evaluated by other
+// members of the HA cluster are only a copy of what they store in the database.
+func (sch *schedule) localStates(orgID int64) []*state.State {
+	states := sch.stateManager.GetAll(orgID)
+	if sch.ring == nil {
+		return states
+	}
+	local := make([]*state.State, 0, len(states))
+	for _, s := range states {
+		if sch.registry.exists(models.AlertRuleKey{OrgID: s.OrgID, UID: s.AlertRuleUID}) {
+			local = append(local, s)
+		}
+	}
+	return local
+}
+
 func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRuleKey, evalCh <-chan *evaluation, updateCh <-chan struct{}) error {
 	logger := sch.log.New("uid", key.UID, "org", key.OrgID)
 	logger.Debug("alert rule routine started")
@@ -530,10 +633,33 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 		logger := logger.New("version", r.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
 
+		if reason := sch.pauseReason(r, e.scheduledAt); reason != "" {
+			logger.Debug("skipping evaluation of paused alert rule", "reason", reason)
+			pausedStates := sch.stateManager.PauseRule(ctx, r, e.scheduledAt, reason)
+			sch.saveAlertStates(ctx, pausedStates)
+			notify(FromAlertStateToPostableAlerts(pausedStates, sch.stateManager, sch.appURL), logger)
+			return nil
+		}
+
+		if r.IsRecordingRule() {
+			err := sch.record(ctx, r, e.scheduledAt)
+			dur := sch.clock.Now().Sub(start)
+			evalTotal.Inc()
+			evalDuration.Observe(dur.Seconds())
+			if err != nil {
+				evalTotalFailures.Inc()
+				logger.Error("failed to evaluate recording rule", "duration", dur, "err", err)
+				return err
+			}
+			logger.Debug("recording rule evaluated", "duration", dur)
+			return nil
+		}
+
 		condition := models.Condition{
-			Condition: r.Condition,
-			OrgID:     r.OrgID,
-			Data:      r.Data,
+			Condition:        r.Condition,
+			OrgID:            r.OrgID,
+			Data:             r.Data,
+			LoadedDimensions: sch.stateManager.GetLoadedDimensions(r.OrgID, r.UID),
 		}
 		results, err := sch.evaluator.ConditionEval(&condition, e.scheduledAt, sch.expressionService)
 		dur := sch.clock.Now().Sub(start)
@@ -621,13 +747,38 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				}
 			}()
 		case <-grafanaCtx.Done():
-			clearState()
+			if sch.isHandedOver(key) {
+				// the new owner continues with the state stored in the database, therefore the alerts must not be expired
+				sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+				logger.Debug("alert rule handed over to another member of the HA cluster", "owner", sch.ring.Owner(key))
+			} else {
+				clearState()
+			}
 			logger.Debug("stopping alert rule routine")
 			return nil
 		}
 	}
 }
 
+// record evaluates the queries and expressions of a recording rule and writes the result of the condition as a metric.
+func (sch *schedule) record(ctx context.Context, r *models.AlertRule, scheduledAt time.Time) error {
+	if sch.recordingWriter == nil {
+		return errors.New("recording rules are not supported")
+	}
+	resp, err := sch.evaluator.QueriesAndExpressionsEval(r.OrgID, r.Data, scheduledAt, sch.expressionService)
+	if err != nil {
+		return err
+	}
+	res, ok := resp.Responses[r.Condition]
+	if !ok {
+		return fmt.Errorf("no result for condition %s", r.Condition)
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	return sch.recordingWriter.Write(ctx, r, scheduledAt, res.Frames)
+}
+
 func (sch *schedule) saveAlertStates(ctx context.Context, states []*state.State) {
 	sch.log.Debug("saving alert states", "count", len(states))
 	for _, s := range states {
@@ -23,11 +23,14 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
@@ -750,6 +753,157 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when rule is handed over to another member of the HA cluster", func(t *testing.T) {
+		fakeAM := store.NewFakeExternalAlertmanager(t)
+		defer fakeAM.Close()
+
+		orgID := rand.Int63()
+		s, err := sender.New(nil)
+		require.NoError(t, err)
+		err = s.ApplyConfig(&models.AdminConfiguration{OrgID: orgID, Alertmanagers: []string{fakeAM.Server.URL}})
+		require.NoError(t, err)
+		s.Run()
+		defer s.Stop()
+
+		require.Eventuallyf(t, func() bool {
+			return len(s.Alertmanagers()) == 1
+		}, 20*time.Second, 200*time.Millisecond, "external Alertmanager was not discovered.")
+
+		sch, ruleStore, _, _, _ := createSchedule(make(chan time.Time))
+		sch.senders[orgID] = s
+		membership := &sharding.FakeMembership{SelfName: "a", MemberNames: []string{"a"}}
+		sch.ring = sharding.NewRing(membership)
+
+		rule := CreateTestAlertRule(t, ruleStore, 10, orgID, eval.Alerting)
+		key := rule.GetKey()
+		sch.stateManager.Put([]*state.State{{
+			AlertRuleUID: rule.UID,
+			CacheId:      util.GenerateShortUID(),
+			OrgID:        rule.OrgID,
+			State:        eval.Alerting,
+			StartsAt:     sch.clock.Now(),
+			EndsAt:       sch.clock.Now().Add(time.Minute),
+		}})
+
+		ruleInfo, _ := sch.registry.getOrCreateInfo(context.Background(), key)
+		stopped := make(chan error)
+		go func() {
+			stopped <- sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
+		}()
+
+		// find a member that owns the rule
+		for i := 0; sch.ring.IsOwner(key); i++ {
+			membership.SetMembers("a", fmt.Sprintf("b-%d", i))
+			sch.ring.Sync()
+		}
+		sch.handOverAlertRule(key)
+
+		require.NoError(t, waitForErrChannel(t, stopped))
+		require.False(t, sch.registry.exists(key))
+		require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		require.Never(t, func() bool {
+			return fakeAM.AlertsCount() > 0
+		}, time.Second, 100*time.Millisecond, "Alerts of a rule that is handed over must not be expired")
+	})
+
+	t.Run("when rule is a recording rule", func(t *testing.T) {
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+		sch, ruleStore, instanceStore, _, _ := createSchedule(evalAppliedChan)
+		writer := &recording.FakeWriter{}
+		sch.recordingWriter = writer
+
+		rule := CreateTestAlertRule(t, ruleStore, 10, rand.Int63(), eval.Alerting)
+		rule.Record = &models.Record{Metric: "test_metric", Target: models.RecordTargetLive}
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan struct{}))
+		}()
+
+		expectedTime := time.UnixMicro(rand.Int63())
+		evalChan <- &evaluation{
+			scheduledAt: expectedTime,
+			version:     rule.Version,
+		}
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should write the result of the condition", func(t *testing.T) {
+			writes := writer.GetWrites()
+			require.Len(t, writes, 1)
+			require.Equal(t, rule.UID, writes[0].RuleUID)
+			require.Equal(t, expectedTime, writes[0].EvaluatedAt)
+			require.Len(t, writes[0].Frames, 1)
+			value, err := writes[0].Frames[0].Fields[0].FloatAt(0)
+			require.NoError(t, err)
+			require.Equal(t, float64(1), value)
+		})
+		t.Run("it should not create alert instances", func(t *testing.T) {
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+			for _, op := range instanceStore.RecordedOps {
+				_, ok := op.(models.SaveAlertInstanceCommand)
+				require.Falsef(t, ok, "Expected no %T request to instance store", models.SaveAlertInstanceCommand{})
+			}
+		})
+	})
+
+	t.Run("when rule is in an active maintenance window", func(t *testing.T) {
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+		sch, ruleStore, instanceStore, _, reg := createSchedule(evalAppliedChan)
+
+		rule := CreateTestAlertRule(t, ruleStore, 10, rand.Int63(), eval.Alerting)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan struct{}))
+		}()
+
+		firstEvaluation := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+		evalChan <- &evaluation{scheduledAt: firstEvaluation, version: rule.Version}
+		waitForTimeChannel(t, evalAppliedChan)
+
+		sch.maintenanceWindowStore = &store.FakeMaintenanceWindowStore{Windows: []*models.MaintenanceWindow{{
+			OrgID:        rule.OrgID,
+			Title:        "upgrade",
+			NamespaceUID: rule.NamespaceUID,
+			Ranges:       []models.MaintenanceTimeRange{{Start: firstEvaluation.Add(time.Second), End: firstEvaluation.Add(time.Hour)}},
+		}}}
+		sch.updateMaintenanceWindows(context.Background(), nil)
+		pausedAt := firstEvaluation.Add(10 * time.Second)
+		evalChan <- &evaluation{scheduledAt: pausedAt, version: rule.Version}
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should move the instances to the paused state", func(t *testing.T) {
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			require.Equal(t, eval.Paused, states[0].State)
+			require.Equal(t, pausedAt, states[0].EndsAt)
+		})
+		t.Run("it should save the paused instances", func(t *testing.T) {
+			var saved *models.SaveAlertInstanceCommand
+			for _, op := range instanceStore.RecordedOps {
+				if cmd, ok := op.(models.SaveAlertInstanceCommand); ok {
+					saved = &cmd
+				}
+			}
+			require.NotNil(t, saved)
+			require.Equal(t, models.InstanceStatePaused, saved.State)
+			require.Equal(t, pausedAt, saved.LastEvalTime)
+		})
+		t.Run("it should not evaluate the rule", func(t *testing.T) {
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(fmt.Sprintf(`
+				# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+				# TYPE grafana_alerting_rule_evaluations_total counter
+				grafana_alerting_rule_evaluations_total{org="%d"} 1
+			`, rule.OrgID)), "grafana_alerting_rule_evaluations_total")
+			require.NoError(t, err)
+		})
+	})
+
 	t.Run("when evaluation fails", func(t *testing.T) {
 		t.Run("it should increase failure counter", func(t *testing.T) {
 			t.Skip()
@@ -923,6 +1077,52 @@ func TestSchedule_alertRuleInfo(t *testing.T) {
 	})
 }
 
+func TestSchedule_syncRemoteRules(t *testing.T) {
+	ctx := context.Background()
+	ruleStore := store.NewFakeRuleStore(t)
+	instanceStore := &store.FakeInstanceStore{}
+	sch, _ := setupScheduler(t, ruleStore, instanceStore, store.NewFakeAdminConfigStore(t), nil)
+	sch.ring = sharding.NewRing(&sharding.FakeMembership{SelfName: "a", MemberNames: []string{"a", "b"}})
+
+	orgID := rand.Int63()
+	localRule := CreateTestAlertRule(t, ruleStore, 10, orgID, eval.Alerting)
+	remoteRule := CreateTestAlertRule(t, ruleStore, 10, orgID, eval.Alerting)
+	instanceStore.Instances = []*models.ListAlertInstancesQueryResult{{
+		RuleOrgID:    orgID,
+		RuleUID:      remoteRule.UID,
+		Labels:       models.InstanceLabels{"test": "remote"},
+		CurrentState: models.InstanceStateFiring,
+	}}
+	sch.registry.getOrCreateInfo(ctx, localRule.GetKey())
+	sch.stateManager.Put([]*state.State{{
+		AlertRuleUID: localRule.UID,
+		OrgID:        orgID,
+		CacheId:      util.GenerateShortUID(),
+		State:        eval.Alerting,
+	}})
+
+	synced := sch.syncRemoteRules(ctx, []*models.AlertRule{remoteRule}, nil)
+
+	t.Run("should load the state of the rules evaluated by other members", func(t *testing.T) {
+		states := sch.stateManager.GetStatesForRuleUID(orgID, remoteRule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Len(t, sch.stateManager.GetAll(orgID), 2)
+	})
+
+	t.Run("should only save the state of the rules evaluated by this instance", func(t *testing.T) {
+		states := sch.localStates(orgID)
+		require.Len(t, states, 1)
+		require.Equal(t, localRule.UID, states[0].AlertRuleUID)
+	})
+
+	t.Run("should drop the state of the rules that no longer exist", func(t *testing.T) {
+		sch.syncRemoteRules(ctx, nil, synced)
+		require.Empty(t, sch.stateManager.GetStatesForRuleUID(orgID, remoteRule.UID))
+		require.Len(t, sch.stateManager.GetStatesForRuleUID(orgID, localRule.UID), 1)
+	})
+}
+
 func TestSchedule_UpdateAlertRule(t *testing.T) {
 	t.Run("when rule exists", func(t *testing.T) {
 		t.Run("it should call Update", func(t *testing.T) {
@@ -1032,7 +1232,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 		Metrics:                 m.GetSchedulerMetrics(),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, mockstore.NewSQLStoreMock())
+	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
 	appUrl := &url.URL{
 		Scheme: "http",
 		Host:   "localhost",
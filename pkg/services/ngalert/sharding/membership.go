@@ -0,0 +1,39 @@
+package sharding
+
+import (
+	"github.com/prometheus/alertmanager/cluster"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// NewPeerMembership returns the membership of the HA cluster the Alertmanager peer is part of.
+// If HA is not configured, this replica is the only member.
+func NewPeerMembership(peer notifier.ClusterPeer) Membership {
+	p, ok := peer.(*cluster.Peer)
+	if !ok {
+		return singleMembership{}
+	}
+	return &peerMembership{peer: p}
+}
+
+type peerMembership struct {
+	peer *cluster.Peer
+}
+
+func (m *peerMembership) Self() string {
+	return m.peer.Name()
+}
+
+func (m *peerMembership) Members() []string {
+	peers := m.peer.Peers()
+	members := make([]string, 0, len(peers))
+	for _, p := range peers {
+		members = append(members, p.Name())
+	}
+	return members
+}
+
+type singleMembership struct{}
+
+func (singleMembership) Self() string      { return "" }
+func (singleMembership) Members() []string { return nil }
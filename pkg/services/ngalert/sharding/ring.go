@@ -0,0 +1,115 @@
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// tokensPerMember is the number of virtual nodes of each member on the ring.
+// More tokens spread the rules more evenly at the cost of a bigger ring.
+const tokensPerMember = 128
+
+// Membership provides the replicas that share the evaluation of alert rules.
+type Membership interface {
+	// Self returns the name of this replica.
+	Self() string
+	// Members returns the names of all live replicas, including this one.
+	Members() []string
+}
+
+type token struct {
+	hash   uint64
+	member string
+}
+
+// Ring assigns alert rules to replicas using consistent hashing, so that when a replica joins or leaves
+// only the rules of that replica are moved. The ring is rebuilt from the membership by Sync.
+type Ring struct {
+	membership Membership
+
+	mtx     sync.RWMutex
+	members []string
+	tokens  []token
+}
+
+func NewRing(membership Membership) *Ring {
+	r := &Ring{membership: membership}
+	r.Sync()
+	return r
+}
+
+// Sync rebuilds the ring if the members have changed since the last call and reports whether they have.
+func (r *Ring) Sync() bool {
+	members := r.membership.Members()
+	sort.Strings(members)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if equalMembers(r.members, members) {
+		return false
+	}
+	tokens := make([]token, 0, len(members)*tokensPerMember)
+	for _, m := range members {
+		for i := 0; i < tokensPerMember; i++ {
+			tokens = append(tokens, token{hash: hash(fmt.Sprintf("%s-%d", m, i)), member: m})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].hash < tokens[j].hash
+	})
+	r.members = members
+	r.tokens = tokens
+	return true
+}
+
+// Owner returns the name of the replica that evaluates the rule.
+// If there are no members, this replica is the owner.
+func (r *Ring) Owner(key models.AlertRuleKey) string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if len(r.tokens) == 0 {
+		return r.membership.Self()
+	}
+	h := hash(fmt.Sprintf("%d/%s", key.OrgID, key.UID))
+	i := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.tokens[i].member
+}
+
+// IsOwner returns true if this replica evaluates the rule.
+func (r *Ring) IsOwner(key models.AlertRuleKey) bool {
+	return r.Owner(key) == r.membership.Self()
+}
+
+// HasPeers returns true if there are other replicas to hand over the rules to.
+func (r *Ring) HasPeers() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return len(r.members) > 1
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func equalMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRing(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%5 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+
+	t.Run("should assign all rules to this replica if there are no members", func(t *testing.T) {
+		ring := NewRing(&FakeMembership{SelfName: "a"})
+		for _, key := range keys {
+			require.True(t, ring.IsOwner(key))
+		}
+		require.False(t, ring.HasPeers())
+	})
+
+	t.Run("should spread rules between members", func(t *testing.T) {
+		ring := NewRing(&FakeMembership{SelfName: "a", MemberNames: []string{"a", "b", "c"}})
+		require.True(t, ring.HasPeers())
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.Owner(key)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			require.InDeltaf(t, len(keys)/3, count, float64(len(keys))/10, "member %s owns %d rules", member, count)
+		}
+	})
+
+	t.Run("should not depend on the order of members", func(t *testing.T) {
+		ring1 := NewRing(&FakeMembership{SelfName: "a", MemberNames: []string{"a", "b", "c"}})
+		ring2 := NewRing(&FakeMembership{SelfName: "b", MemberNames: []string{"c", "b", "a"}})
+		for _, key := range keys {
+			require.Equal(t, ring1.Owner(key), ring2.Owner(key))
+		}
+	})
+
+	t.Run("should move only the rules of the member that left", func(t *testing.T) {
+		membership := &FakeMembership{SelfName: "a", MemberNames: []string{"a", "b", "c"}}
+		ring := NewRing(membership)
+		before := make(map[models.AlertRuleKey]string, len(keys))
+		for _, key := range keys {
+			before[key] = ring.Owner(key)
+		}
+
+		require.False(t, ring.Sync())
+		membership.SetMembers("a", "b")
+		require.True(t, ring.Sync())
+
+		for _, key := range keys {
+			owner := ring.Owner(key)
+			require.NotEqual(t, "c", owner)
+			if before[key] != "c" {
+				require.Equal(t, before[key], owner)
+			}
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package sharding
+
+import "sync"
+
+// FakeMembership is a membership with a fixed set of members that can be changed by tests.
+type FakeMembership struct {
+	mtx         sync.Mutex
+	SelfName    string
+	MemberNames []string
+}
+
+func (m *FakeMembership) Self() string {
+	return m.SelfName
+}
+
+func (m *FakeMembership) Members() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	result := make([]string, len(m.MemberNames))
+	copy(result, m.MemberNames)
+	return result
+}
+
+// SetMembers replaces the members.
+func (m *FakeMembership) SetMembers(members ...string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.MemberNames = members
+}
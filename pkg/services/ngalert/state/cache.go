@@ -150,6 +150,20 @@ func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
 	return ruleStates
 }
 
+// setRuleStates replaces all entries of the rule in the state cache by the given states.
+func (c *cache) setRuleStates(orgID int64, uid string, states []*State) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]map[string]*State)
+	}
+	ruleStates := make(map[string]*State, len(states))
+	for _, s := range states {
+		ruleStates[s.CacheId] = s
+	}
+	c.states[orgID][uid] = ruleStates
+}
+
 // removeByRuleUID deletes all entries in the state cache that match the given UID.
 func (c *cache) removeByRuleUID(orgID int64, uid string) {
 	c.mtxStates.Lock()
@@ -175,6 +189,7 @@ func (c *cache) recordMetrics() {
 		eval.Pending:  0,
 		eval.NoData:   0,
 		eval.Error:    0,
+		eval.Paused:   0,
 	}
 
 	for org, orgMap := range c.states {
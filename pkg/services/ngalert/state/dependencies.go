@@ -0,0 +1,86 @@
+package state
+
+import (
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// applyDependencies suppresses the alerting states of the rule while a firing instance satisfies one of its dependencies.
+// A state that becomes suppressed is resolved so that the alert that was already sent to the Alertmanager is ended.
+func (st *Manager) applyDependencies(alertRule *ngModels.AlertRule, states []*State) {
+	if len(alertRule.Dependencies) == 0 {
+		for _, s := range states {
+			s.SuppressedBy = ""
+		}
+		return
+	}
+
+	matchers := make([]labels.Matchers, len(alertRule.Dependencies))
+	for i, dependency := range alertRule.Dependencies {
+		m, err := dependency.LabelMatchers()
+		if err != nil {
+			// dependencies are validated when the rule is saved, so this should never happen
+			st.log.Error("failed to parse matchers of rule dependency", "uid", alertRule.UID, "error", err)
+			continue
+		}
+		matchers[i] = m
+	}
+
+	for _, s := range states {
+		wasSuppressed := s.SuppressedBy != ""
+		s.SuppressedBy = ""
+		if s.State != eval.Alerting {
+			continue
+		}
+		for i, dependency := range alertRule.Dependencies {
+			if matchers[i] == nil && len(dependency.Matchers) > 0 {
+				continue
+			}
+			if source := st.findSuppressingState(alertRule, dependency, matchers[i], s); source != nil {
+				s.SuppressedBy = source.AlertRuleUID
+				break
+			}
+		}
+		if s.SuppressedBy != "" && !wasSuppressed {
+			st.log.Debug("alert instance is suppressed by a dependency", "uid", alertRule.UID, "labels", s.Labels.String(), "suppressedBy", s.SuppressedBy)
+			s.Resolved = true
+			s.EndsAt = s.LastEvaluationTime
+		}
+	}
+}
+
+// findSuppressingState returns a firing state of another rule that satisfies the dependency for the given state, or nil if there is none.
+func (st *Manager) findSuppressingState(alertRule *ngModels.AlertRule, dependency ngModels.AlertRuleDependency, matchers labels.Matchers, s *State) *State {
+	var candidates []*State
+	if dependency.RuleUID != "" {
+		candidates = st.cache.getStatesForRuleUID(alertRule.OrgID, dependency.RuleUID)
+	} else {
+		candidates = st.cache.getAll(alertRule.OrgID)
+	}
+	for _, candidate := range candidates {
+		if candidate.AlertRuleUID == alertRule.UID || candidate.State != eval.Alerting {
+			continue
+		}
+		if !matchesDependency(candidate, matchers, dependency.Equal, s) {
+			continue
+		}
+		return candidate
+	}
+	return nil
+}
+
+func matchesDependency(source *State, matchers labels.Matchers, equal []string, target *State) bool {
+	for _, m := range matchers {
+		if !m.Matches(source.Labels[m.Name]) {
+			return false
+		}
+	}
+	for _, name := range equal {
+		if source.Labels[name] != target.Labels[name] {
+			return false
+		}
+	}
+	return true
+}
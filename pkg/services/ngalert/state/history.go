@@ -0,0 +1,58 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const historyCleanupInterval = time.Hour
+
+// HistoryCleaner periodically deletes the state history entries that are older than the configured retention.
+type HistoryCleaner struct {
+	store     store.StateHistoryStore
+	retention time.Duration
+	interval  time.Duration
+	log       log.Logger
+	timeNow   func() time.Time
+}
+
+func NewHistoryCleaner(historyStore store.StateHistoryStore, retention time.Duration, logger log.Logger) *HistoryCleaner {
+	return &HistoryCleaner{
+		store:     historyStore,
+		retention: retention,
+		interval:  historyCleanupInterval,
+		log:       logger,
+		timeNow:   time.Now,
+	}
+}
+
+// Run deletes outdated entries right away and then once per interval until the context is cancelled.
+// Nothing is deleted if no retention is configured.
+func (c *HistoryCleaner) Run(ctx context.Context) error {
+	if c.retention <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.cleanup(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *HistoryCleaner) cleanup(ctx context.Context) {
+	before := c.timeNow().Add(-c.retention)
+	deleted, err := c.store.DeleteAlertStateHistoryBefore(ctx, before)
+	if err != nil {
+		c.log.Error("failed to delete outdated state history", "before", before, "error", err)
+		return
+	}
+	c.log.Debug("deleted outdated state history", "before", before, "deleted", deleted)
+}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestHistoryCleaner(t *testing.T) {
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	t.Run("should delete entries older than the retention", func(t *testing.T) {
+		historyStore := &store.FakeStateHistoryStore{
+			Entries: []models.AlertStateHistoryEntry{
+				{RuleUID: "outdated", EvaluatedAt: now.Add(-retention - time.Second)},
+				{RuleUID: "retained", EvaluatedAt: now.Add(-retention)},
+				{RuleUID: "recent", EvaluatedAt: now},
+			},
+		}
+		cleaner := NewHistoryCleaner(historyStore, retention, log.NewNopLogger())
+		cleaner.timeNow = func() time.Time { return now }
+
+		cleaner.cleanup(context.Background())
+
+		require.Len(t, historyStore.Entries, 2)
+		require.Equal(t, "retained", historyStore.Entries[0].RuleUID)
+		require.Equal(t, "recent", historyStore.Entries[1].RuleUID)
+	})
+
+	t.Run("should not delete anything if retention is not set", func(t *testing.T) {
+		historyStore := &store.FakeStateHistoryStore{
+			Entries: []models.AlertStateHistoryEntry{
+				{RuleUID: "outdated", EvaluatedAt: now.Add(-365 * 24 * time.Hour)},
+			},
+		}
+		cleaner := NewHistoryCleaner(historyStore, 0, log.NewNopLogger())
+		cleaner.timeNow = func() time.Time { return now }
+
+		require.NoError(t, cleaner.Run(context.Background()))
+		require.Len(t, historyStore.Entries, 1)
+	})
+}
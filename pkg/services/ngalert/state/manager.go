@@ -2,18 +2,24 @@ package state
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -22,6 +28,8 @@ import (
 
 var ResendDelay = 30 * time.Second
 
+var errStaleInstance = errors.New("instance was not part of the last evaluations")
+
 // AlertInstanceManager defines the interface for querying the current alert instances.
 type AlertInstanceManager interface {
 	GetAll(orgID int64) []*State
@@ -38,11 +46,17 @@ type Manager struct {
 
 	ruleStore     store.RuleStore
 	instanceStore store.InstanceStore
+	historyStore  store.StateHistoryStore
 	sqlStore      sqlstore.Store
+	imageService  image.ImageService
+
+	// dryRun keeps the states only in memory. Instances, state history and annotations are not persisted.
+	dryRun  bool
+	timeNow func() time.Time
 }
 
 func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL, ruleStore store.RuleStore,
-	instanceStore store.InstanceStore, sqlStore sqlstore.Store) *Manager {
+	instanceStore store.InstanceStore, historyStore store.StateHistoryStore, sqlStore sqlstore.Store, imageService image.ImageService) *Manager {
 	manager := &Manager{
 		cache:         newCache(logger, metrics, externalURL),
 		quit:          make(chan struct{}),
@@ -51,12 +65,30 @@ func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL,
 		metrics:       metrics,
 		ruleStore:     ruleStore,
 		instanceStore: instanceStore,
+		historyStore:  historyStore,
 		sqlStore:      sqlStore,
+		imageService:  imageService,
+		timeNow:       time.Now,
 	}
 	go manager.recordMetrics()
 	return manager
 }
 
+// NewDryRunManager creates a manager that runs the evaluation results through the state machine without any side
+// effects. Nothing is persisted and no metrics are recorded. timeNow is used to determine whether an instance is
+// stale, so that evaluations at arbitrary times can be simulated. The manager does not have to be closed.
+func NewDryRunManager(logger log.Logger, externalURL *url.URL, timeNow func() time.Time) *Manager {
+	return &Manager{
+		cache:        newCache(logger, nil, externalURL),
+		quit:         make(chan struct{}),
+		ResendDelay:  ResendDelay,
+		log:          logger,
+		imageService: image.NotAvailableImageService{},
+		dryRun:       true,
+		timeNow:      timeNow,
+	}
+}
+
 func (st *Manager) Close() {
 	st.quit <- struct{}{}
 }
@@ -99,25 +131,7 @@ func (st *Manager) Warm(ctx context.Context) {
 				st.log.Error("rule not found for instance, ignoring", "rule", entry.RuleUID)
 				continue
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheId, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("error getting cacheId for entry", "msg", err.Error())
-			}
-			stateForEntry := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheId:              cacheId,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
-			states = append(states, stateForEntry)
+			states = append(states, st.stateFromInstance(entry, ruleForEntry))
 		}
 	}
 
@@ -126,6 +140,94 @@ func (st *Manager) Warm(ctx context.Context) {
 	}
 }
 
+// WarmRule replaces the states of the rule in the cache by the alert instances of the rule stored in the database.
+// It is used when the rule is handed over from another replica.
+func (st *Manager) WarmRule(ctx context.Context, alertRule *ngModels.AlertRule) {
+	st.RemoveByRuleUID(alertRule.OrgID, alertRule.UID)
+
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: alertRule.OrgID,
+		RuleUID:   alertRule.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		st.log.Error("unable to fetch previous state", "uid", alertRule.UID, "msg", err.Error())
+		return
+	}
+	for _, entry := range cmd.Result {
+		st.set(st.stateFromInstance(entry, alertRule))
+	}
+}
+
+// SyncRules replaces the states of the rules in the cache by the alert instances stored in the database. It is used for
+// the rules that are evaluated by other members of the HA cluster, so that their alert instances are returned by the
+// API and can suppress the rules that depend on them. The states are as recent as the last sync.
+func (st *Manager) SyncRules(ctx context.Context, rules []*ngModels.AlertRule) {
+	rulesByOrg := make(map[int64]map[string]*ngModels.AlertRule)
+	for _, rule := range rules {
+		if _, ok := rulesByOrg[rule.OrgID]; !ok {
+			rulesByOrg[rule.OrgID] = make(map[string]*ngModels.AlertRule)
+		}
+		rulesByOrg[rule.OrgID][rule.UID] = rule
+	}
+
+	for orgID, ruleByUID := range rulesByOrg {
+		cmd := ngModels.ListAlertInstancesQuery{
+			RuleOrgID: orgID,
+		}
+		if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+			st.log.Error("unable to fetch the state of the rules evaluated by other members of the HA cluster", "org_id", orgID, "msg", err.Error())
+			continue
+		}
+		statesByUID := make(map[string][]*State, len(ruleByUID))
+		for _, entry := range cmd.Result {
+			rule, ok := ruleByUID[entry.RuleUID]
+			if !ok {
+				continue
+			}
+			statesByUID[rule.UID] = append(statesByUID[rule.UID], st.stateFromInstance(entry, rule))
+		}
+		for uid := range ruleByUID {
+			st.cache.setRuleStates(orgID, uid, statesByUID[uid])
+		}
+	}
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.ListAlertInstancesQueryResult, alertRule *ngModels.AlertRule) *State {
+	cacheId, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("error getting cacheId for entry", "msg", err.Error())
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheId:              cacheId,
+		Labels:               map[string]string(entry.Labels),
+		ResultLabels:         resultLabelsFromInstance(entry.Labels, alertRule),
+		State:                translateInstanceState(entry.CurrentState),
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          alertRule.Annotations,
+	}
+}
+
+// resultLabelsFromInstance recovers the labels of the evaluation result from the labels of a saved instance by
+// removing the labels added from the rule. Result labels that were overridden by rule labels cannot be recovered.
+func resultLabelsFromInstance(labels ngModels.InstanceLabels, alertRule *ngModels.AlertRule) data.Labels {
+	result := make(data.Labels, len(labels))
+	for k, v := range labels {
+		if _, ok := alertRule.Labels[k]; ok {
+			continue
+		}
+		if k == ngModels.RuleUIDLabel || k == ngModels.NamespaceUIDLabel || k == prometheusModel.AlertNameLabel {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
 func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
 	return st.cache.getOrCreate(ctx, alertRule, result)
 }
@@ -151,20 +253,51 @@ func (st *Manager) RemoveByRuleUID(orgID int64, ruleUID string) {
 func (st *Manager) ProcessEvalResults(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results) []*State {
 	st.log.Debug("state manager processing evaluation results", "uid", alertRule.UID, "resultCount", len(results))
 	var states []*State
+	var previousStates []eval.State
 	processedResults := make(map[string]*State, len(results))
+	// all instances that start alerting in this evaluation share the same screenshot
+	takeImage := st.newImageOnce(ctx, alertRule)
 	for _, result := range results {
-		s := st.setNextState(ctx, alertRule, result)
+		s, previousState := st.setNextState(ctx, alertRule, result, takeImage)
 		states = append(states, s)
+		// the suppression is still the one of the previous evaluation until the dependencies are applied
+		previousStates = append(previousStates, historyState(previousState, s.SuppressedBy))
 		processedResults[s.CacheId] = s
 	}
-	st.staleResultsHandler(ctx, alertRule, processedResults)
+	st.applyDependencies(alertRule, states)
+
+	var transitions []ngModels.AlertStateHistoryEntry
+	for i, s := range states {
+		current := historyState(s.State, s.SuppressedBy)
+		if previousStates[i] == current {
+			continue
+		}
+		reason := s.Error
+		if s.SuppressedBy != "" {
+			reason = fmt.Errorf("suppressed by rule %s", s.SuppressedBy)
+		}
+		transitions = append(transitions, st.newStateHistoryEntry(alertRule, s.Labels, previousStates[i], current, s.LastEvaluationTime, reason, s.Results[len(s.Results)-1].Values))
+	}
+	transitions = append(transitions, st.staleResultsHandler(ctx, alertRule, processedResults)...)
+	st.saveStateHistory(ctx, alertRule, transitions)
 	return states
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
+// historyState returns the state of an alert instance as it is recorded in the state history. A suppressed instance
+// is recorded as normal since its alert is resolved.
+func historyState(state eval.State, suppressedBy string) eval.State {
+	if state == eval.Alerting && suppressedBy != "" {
+		return eval.Normal
+	}
+	return state
+}
+
+// Set the current state based on evaluation results. The state the alert instance had before is returned as well.
+// takeImage is called to get the screenshot of the panel when the instance starts alerting.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, takeImage func() *ngModels.Image) (*State, eval.State) {
 	currentState := st.getOrCreate(ctx, alertRule, result)
 
+	currentState.ResultLabels = result.Instance
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
 	currentState.Results = append(currentState.Results, Evaluation{
@@ -194,11 +327,45 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
+	if oldState != eval.Alerting && currentState.State == eval.Alerting {
+		currentState.Image = takeImage()
+	}
+
 	st.set(currentState)
-	if oldState != currentState.State {
+	if oldState != currentState.State && !st.dryRun {
 		go st.annotateState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, currentState.State, oldState)
 	}
-	return currentState
+	return currentState, oldState
+}
+
+// newImageOnce returns a function that takes a screenshot of the panel the rule is linked to the first time it is
+// called and returns the same image afterwards, so that a rule is rendered at most once per evaluation.
+func (st *Manager) newImageOnce(ctx context.Context, alertRule *ngModels.AlertRule) func() *ngModels.Image {
+	var (
+		once sync.Once
+		img  *ngModels.Image
+	)
+	return func() *ngModels.Image {
+		once.Do(func() {
+			img = st.newImage(ctx, alertRule)
+		})
+		return img
+	}
+}
+
+// newImage takes a screenshot of the panel the rule is linked to. It returns nil if the screenshot cannot be taken,
+// in which case the notifications are sent without an image.
+func (st *Manager) newImage(ctx context.Context, alertRule *ngModels.AlertRule) *ngModels.Image {
+	img, err := st.imageService.NewImage(ctx, alertRule)
+	if err != nil {
+		if errors.Is(err, image.ErrNoPanel) || errors.Is(err, image.ErrScreenshotsUnavailable) {
+			st.log.Debug("not taking screenshot", "uid", alertRule.UID, "reason", err)
+		} else {
+			st.log.Error("failed to take screenshot", "uid", alertRule.UID, "error", err)
+		}
+		return nil
+	}
+	return img
 }
 
 func (st *Manager) GetAll(orgID int64) []*State {
@@ -209,6 +376,24 @@ func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*Stat
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID)
 }
 
+// GetLoadedDimensions returns the labels of the results of the rule that met its condition at the previous
+// evaluation. States restored from the database have no results yet, so they count as loaded while they are
+// pending or alerting.
+func (st *Manager) GetLoadedDimensions(orgID int64, alertRuleUID string) []data.Labels {
+	var loaded []data.Labels
+	for _, s := range st.cache.getStatesForRuleUID(orgID, alertRuleUID) {
+		if len(s.Results) > 0 {
+			if s.Results[len(s.Results)-1].EvaluationState != eval.Alerting {
+				continue
+			}
+		} else if s.State != eval.Alerting && s.State != eval.Pending {
+			continue
+		}
+		loaded = append(loaded, s.ResultLabels)
+	}
+	return loaded
+}
+
 func (st *Manager) recordMetrics() {
 	// TODO: parameterize?
 	// Setting to a reasonable default scrape interval for Prometheus.
@@ -239,6 +424,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.Alerting
 	case state == ngModels.InstanceStateNormal:
 		return eval.Normal
+	case state == ngModels.InstanceStatePaused:
+		return eval.Paused
 	default:
 		return eval.Error
 	}
@@ -291,13 +478,19 @@ func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertR
 	}
 }
 
-func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.AlertRule, states map[string]*State) {
+// staleResultsHandler removes the states of instances that were not part of the last evaluations. It returns the
+// resulting transitions of firing instances to normal.
+func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.AlertRule, states map[string]*State) []ngModels.AlertStateHistoryEntry {
+	var transitions []ngModels.AlertStateHistoryEntry
 	allStates := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	for _, s := range allStates {
 		_, ok := states[s.CacheId]
-		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds) {
+		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds, st.timeNow()) {
 			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
+			if st.dryRun {
+				continue
+			}
 			ilbs := ngModels.InstanceLabels(s.Labels)
 			_, labelsHash, err := ilbs.StringAndHash()
 			if err != nil {
@@ -309,14 +502,71 @@ func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.
 			}
 
 			if s.State == eval.Alerting {
-				st.annotateState(ctx, alertRule, s.Labels, time.Now(), eval.Normal, s.State)
+				now := st.timeNow()
+				st.annotateState(ctx, alertRule, s.Labels, now, eval.Normal, s.State)
+				transitions = append(transitions, st.newStateHistoryEntry(alertRule, s.Labels, s.State, eval.Normal, now, errStaleInstance, nil))
 			}
 		}
 	}
+	return transitions
+}
+
+// saveStateHistory persists the given state transitions. Failures are logged but do not affect the evaluation.
+func (st *Manager) saveStateHistory(ctx context.Context, alertRule *ngModels.AlertRule, transitions []ngModels.AlertStateHistoryEntry) {
+	if len(transitions) == 0 || st.dryRun {
+		return
+	}
+	cmd := &ngModels.SaveAlertStateHistoryCommand{Entries: transitions}
+	if err := st.historyStore.SaveAlertStateHistory(ctx, cmd); err != nil {
+		st.log.Error("failed to save state history", "alertRuleUID", alertRule.UID, "transitions", len(transitions), "error", err)
+	}
+}
+
+func (st *Manager) newStateHistoryEntry(alertRule *ngModels.AlertRule, labels data.Labels, previous, current eval.State, evaluatedAt time.Time, reason error, values map[string]*float64) ngModels.AlertStateHistoryEntry {
+	entry := ngModels.AlertStateHistoryEntry{
+		OrgID:         alertRule.OrgID,
+		RuleUID:       alertRule.UID,
+		Labels:        ngModels.InstanceLabels(labels),
+		PreviousState: ngModels.InstanceStateType(previous.String()),
+		CurrentState:  ngModels.InstanceStateType(current.String()),
+		EvaluatedAt:   evaluatedAt,
+	}
+	if reason != nil {
+		entry.Reason = reason.Error()
+	}
+	if len(values) > 0 {
+		evalValues, err := encodeEvalValues(values)
+		if err != nil {
+			st.log.Warn("failed to encode evaluation values of state transition", "uid", alertRule.UID, "error", err)
+		}
+		entry.EvalValues = evalValues
+	}
+	return entry
+}
+
+// encodeEvalValues encodes the values as a JSON object. JSON has no representation for NaN and ±Inf, so they are
+// encoded as strings in the format of the Prometheus compatible API, and missing values as null.
+func encodeEvalValues(values map[string]*float64) (string, error) {
+	m := make(map[string]interface{}, len(values))
+	for refID, v := range values {
+		switch {
+		case v == nil:
+			m[refID] = nil
+		case math.IsNaN(*v) || math.IsInf(*v, 0):
+			m[refID] = strconv.FormatFloat(*v, 'e', -1, 64)
+		default:
+			m[refID] = *v
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
 }
 
-func isItStale(lastEval time.Time, intervalSeconds int64) bool {
-	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(time.Now())
+func isItStale(lastEval time.Time, intervalSeconds int64, now time.Time) bool {
+	return lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).Before(now)
 }
 
 func removePrivateLabels(labels data.Labels) data.Labels {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -37,7 +40,7 @@ func TestDashboardAnnotations(t *testing.T) {
 	_, dbstore := tests.SetupTestEnv(t, 1)
 
 	sqlStore := mockstore.NewSQLStoreMock()
-	st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore)
+	st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, dbstore, sqlStore, &image.NotAvailableImageService{})
 
 	fakeAnnoRepo := store.NewFakeAnnotationsRepo()
 	annotations.SetRepository(fakeAnnoRepo)
@@ -123,6 +126,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -177,6 +183,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_1":             "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label_1": "test",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -200,6 +209,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_2":             "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label_2": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -257,6 +269,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -318,6 +333,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -390,6 +408,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -484,6 +505,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Pending,
 					Results: []state.Evaluation{
 						{
@@ -565,6 +589,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.NoData,
 					Results: []state.Evaluation{
 						{
@@ -629,6 +656,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Pending,
 					Results: []state.Evaluation{
 						{
@@ -693,6 +723,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Pending,
 					Results: []state.Evaluation{
 						{
@@ -757,6 +790,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -821,6 +857,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.NoData,
 					Results: []state.Evaluation{
 						{
@@ -884,7 +923,8 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					State: eval.NoData,
+					ResultLabels: data.Labels{},
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -948,7 +988,8 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					State: eval.NoData,
+					ResultLabels: data.Labels{},
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1015,6 +1056,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -1073,6 +1117,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -1138,6 +1185,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -1203,6 +1253,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Pending,
 					Results: []state.Evaluation{
 						{
@@ -1292,6 +1345,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -1372,6 +1428,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"datasource_uid":               "datasource_uid_1",
 						"ref_id":                       "A",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Error,
 					Error: expr.QueryError{
 						RefID: "A",
@@ -1449,6 +1508,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Error: nil,
 					Results: []state.Evaluation{
@@ -1521,6 +1583,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Normal,
 					Error: nil,
 					Results: []state.Evaluation{
@@ -1603,6 +1668,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.Alerting,
 					Results: []state.Evaluation{
 						{
@@ -1690,6 +1758,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
+					ResultLabels: data.Labels{
+						"instance_label": "test",
+					},
 					State: eval.NoData,
 					Results: []state.Evaluation{
 						{
@@ -1752,6 +1823,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"job":                          "prod/grafana",
 					},
+					ResultLabels: data.Labels{
+						"cluster":   "us-central-1",
+						"namespace": "prod",
+						"pod":       "grafana",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -1770,7 +1846,7 @@ func TestProcessEvalResults(t *testing.T) {
 
 	for _, tc := range testCases {
 		ss := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, ss)
+		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &store.FakeStateHistoryStore{}, ss, &image.NotAvailableImageService{})
 		t.Run(tc.desc, func(t *testing.T) {
 			fakeAnnoRepo := store.NewFakeAnnotationsRepo()
 			annotations.SetRepository(fakeAnnoRepo)
@@ -1795,6 +1871,393 @@ func TestProcessEvalResults(t *testing.T) {
 	}
 }
 
+func TestProcessEvalResults_StateHistory(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	value, nan, negInf := 42.0, math.NaN(), math.Inf(-1)
+	alertRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		ExecErrState:    models.ErrorErrState,
+	}
+	instance := data.Labels{"instance_label": "test"}
+
+	historyStore := &store.FakeStateHistoryStore{}
+	st := state.NewManager(log.New("test_state_history"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, historyStore, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	results := []eval.Result{
+		{Instance: instance, State: eval.Normal, EvaluatedAt: evaluationTime},
+		{Instance: instance, State: eval.Alerting, EvaluatedAt: evaluationTime.Add(10 * time.Second), Values: map[string]eval.NumberValueCapture{
+			"B": {Var: "B", Value: &value},
+			"C": {Var: "C", Value: &nan},
+			"D": {Var: "D", Value: &negInf},
+			"E": {Var: "E"},
+		}},
+		{Instance: instance, State: eval.Alerting, EvaluatedAt: evaluationTime.Add(20 * time.Second)},
+		{Instance: instance, State: eval.Normal, EvaluatedAt: evaluationTime.Add(30 * time.Second)},
+		{Instance: instance, State: eval.Error, Error: errors.New("test error"), EvaluatedAt: evaluationTime.Add(40 * time.Second)},
+	}
+	for _, result := range results {
+		st.ProcessEvalResults(context.Background(), alertRule, eval.Results{result})
+	}
+
+	require.Len(t, historyStore.Entries, 3)
+
+	firing := historyStore.Entries[0]
+	assert.Equal(t, alertRule.OrgID, firing.OrgID)
+	assert.Equal(t, alertRule.UID, firing.RuleUID)
+	assert.Equal(t, "test", firing.Labels["instance_label"])
+	assert.Equal(t, models.InstanceStateNormal, firing.PreviousState)
+	assert.Equal(t, models.InstanceStateFiring, firing.CurrentState)
+	assert.Equal(t, evaluationTime.Add(10*time.Second), firing.EvaluatedAt)
+	// JSON has no representation for non-finite numbers
+	assert.JSONEq(t, `{"B":42,"C":"NaN","D":"-Inf","E":null}`, firing.EvalValues)
+	assert.Empty(t, firing.Reason)
+
+	resolved := historyStore.Entries[1]
+	assert.Equal(t, models.InstanceStateFiring, resolved.PreviousState)
+	assert.Equal(t, models.InstanceStateNormal, resolved.CurrentState)
+
+	failed := historyStore.Entries[2]
+	assert.Equal(t, models.InstanceStateNormal, failed.PreviousState)
+	assert.Equal(t, models.InstanceStateError, failed.CurrentState)
+	assert.Equal(t, "test error", failed.Reason)
+}
+
+func TestProcessEvalResults_Images(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test",
+		UID:             "test",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		For:             10 * time.Second,
+	}
+
+	images := &image.FakeImageService{Image: &models.Image{Token: "test-token"}}
+	st := state.NewManager(log.New("test_images"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock(), images)
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	evaluate := func(at time.Time, s eval.State) *state.State {
+		states := st.ProcessEvalResults(context.Background(), rule, eval.Results{{State: s, EvaluatedAt: at}})
+		require.Len(t, states, 1)
+		return states[0]
+	}
+
+	s := evaluate(evaluationTime, eval.Alerting)
+	require.Equal(t, eval.Pending, s.State)
+	require.Nil(t, s.Image)
+	require.Equal(t, 0, images.Calls)
+
+	s = evaluate(evaluationTime.Add(10*time.Second), eval.Alerting)
+	require.Equal(t, eval.Alerting, s.State)
+	require.Equal(t, images.Image, s.Image)
+	require.Equal(t, 1, images.Calls)
+
+	// the screenshot is only taken when the instance starts alerting
+	s = evaluate(evaluationTime.Add(20*time.Second), eval.Alerting)
+	require.Equal(t, images.Image, s.Image)
+	require.Equal(t, 1, images.Calls)
+
+	t.Run("should send notifications without an image if the screenshot fails", func(t *testing.T) {
+		images.Err = errors.New("failed")
+		evaluate(evaluationTime.Add(30*time.Second), eval.Normal)
+		evaluate(evaluationTime.Add(40*time.Second), eval.Alerting)
+		s := evaluate(evaluationTime.Add(50*time.Second), eval.Alerting)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Nil(t, s.Image)
+		require.Equal(t, 2, images.Calls)
+	})
+
+	t.Run("should take one screenshot for all instances that start alerting", func(t *testing.T) {
+		images := &image.FakeImageService{Image: &models.Image{Token: "shared-token"}}
+		st := state.NewManager(log.New("test_images"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock(), images)
+		rule := *rule
+		rule.For = 0
+
+		results := make(eval.Results, 0, 100)
+		for i := 0; i < 100; i++ {
+			results = append(results, eval.Result{
+				Instance:    data.Labels{"instance": strconv.Itoa(i)},
+				State:       eval.Alerting,
+				EvaluatedAt: evaluationTime,
+			})
+		}
+		states := st.ProcessEvalResults(context.Background(), &rule, results)
+		require.Len(t, states, 100)
+		for _, s := range states {
+			require.Equal(t, eval.Alerting, s.State)
+			require.Equal(t, images.Image, s.Image)
+		}
+		require.Equal(t, 1, images.Calls)
+	})
+}
+
+func TestProcessEvalResults_Dependencies(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	sourceRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "dc-down",
+		UID:             "dc-down",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+	dependentRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "service-down",
+		UID:             "service-down",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		Dependencies: []models.AlertRuleDependency{
+			{RuleUID: sourceRule.UID, Equal: []string{"dc"}},
+		},
+	}
+
+	historyStore := &store.FakeStateHistoryStore{}
+	st := state.NewManager(log.New("test_dependencies"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, historyStore, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	evaluate := func(rule *models.AlertRule, at time.Time, results map[string]eval.State) map[string]*state.State {
+		var evalResults eval.Results
+		for dc, s := range results {
+			evalResults = append(evalResults, eval.Result{Instance: data.Labels{"dc": dc}, State: s, EvaluatedAt: at})
+		}
+		byDC := make(map[string]*state.State)
+		for _, s := range st.ProcessEvalResults(context.Background(), rule, evalResults) {
+			byDC[s.Labels["dc"]] = s
+		}
+		return byDC
+	}
+
+	evaluate(sourceRule, evaluationTime, map[string]eval.State{"a": eval.Alerting, "b": eval.Normal})
+	states := evaluate(dependentRule, evaluationTime, map[string]eval.State{"a": eval.Alerting, "b": eval.Alerting})
+
+	t.Run("should suppress instances with the same labels as a firing instance of the rule", func(t *testing.T) {
+		require.True(t, states["a"].IsSuppressed())
+		require.Equal(t, sourceRule.UID, states["a"].SuppressedBy)
+		require.False(t, states["b"].IsSuppressed())
+	})
+
+	t.Run("should resolve instances when they become suppressed", func(t *testing.T) {
+		require.True(t, states["a"].Resolved)
+		require.Equal(t, evaluationTime, states["a"].EndsAt)
+		require.False(t, states["b"].Resolved)
+	})
+
+	t.Run("should record suppressed instances as normal in the state history", func(t *testing.T) {
+		var entries []models.AlertStateHistoryEntry
+		for _, entry := range historyStore.Entries {
+			if entry.RuleUID == dependentRule.UID {
+				entries = append(entries, entry)
+			}
+		}
+		require.Len(t, entries, 1)
+		require.Equal(t, "b", entries[0].Labels["dc"])
+		require.Equal(t, models.InstanceStateFiring, entries[0].CurrentState)
+	})
+
+	t.Run("should not send suppressed instances again", func(t *testing.T) {
+		states = evaluate(dependentRule, evaluationTime.Add(10*time.Second), map[string]eval.State{"a": eval.Alerting, "b": eval.Alerting})
+		require.True(t, states["a"].IsSuppressed())
+		require.False(t, states["a"].Resolved)
+		require.False(t, states["a"].NeedsSending(0))
+	})
+
+	t.Run("should lift the suppression when the instance of the rule stops firing", func(t *testing.T) {
+		evaluate(sourceRule, evaluationTime.Add(20*time.Second), map[string]eval.State{"a": eval.Normal, "b": eval.Normal})
+		states = evaluate(dependentRule, evaluationTime.Add(20*time.Second), map[string]eval.State{"a": eval.Alerting, "b": eval.Alerting})
+		require.False(t, states["a"].IsSuppressed())
+		require.Empty(t, states["a"].SuppressedBy)
+		require.True(t, states["a"].NeedsSending(0))
+
+		last := historyStore.Entries[len(historyStore.Entries)-1]
+		require.Equal(t, dependentRule.UID, last.RuleUID)
+		require.Equal(t, "a", last.Labels["dc"])
+		require.Equal(t, models.InstanceStateNormal, last.PreviousState)
+		require.Equal(t, models.InstanceStateFiring, last.CurrentState)
+	})
+
+	t.Run("should suppress instances by label matchers of any rule", func(t *testing.T) {
+		evaluate(sourceRule, evaluationTime.Add(30*time.Second), map[string]eval.State{"a": eval.Alerting, "b": eval.Normal})
+		matcherRule := models.CopyRule(dependentRule)
+		matcherRule.Dependencies = []models.AlertRuleDependency{{Matchers: []string{`alertname="dc-down"`}}}
+		states = evaluate(matcherRule, evaluationTime.Add(30*time.Second), map[string]eval.State{"a": eval.Alerting, "b": eval.Alerting})
+		require.True(t, states["a"].IsSuppressed())
+		require.True(t, states["b"].IsSuppressed())
+	})
+}
+
+func TestGetLoadedDimensions(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		Labels:          map[string]string{"team": "ops"},
+		IntervalSeconds: 10,
+		For:             time.Minute,
+	}
+
+	st := state.NewManager(log.New("test_loaded_dimensions"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	st.ProcessEvalResults(context.Background(), rule, eval.Results{
+		{Instance: data.Labels{"dc": "a"}, State: eval.Alerting, EvaluatedAt: evaluationTime},
+		{Instance: data.Labels{"dc": "b"}, State: eval.Normal, EvaluatedAt: evaluationTime},
+	})
+
+	t.Run("should return the result labels of the instances that met the condition", func(t *testing.T) {
+		require.Equal(t, []data.Labels{{"dc": "a"}}, st.GetLoadedDimensions(rule.OrgID, rule.UID))
+		require.Empty(t, st.GetLoadedDimensions(rule.OrgID, "other_rule_uid"))
+	})
+
+	t.Run("should not return the instances that no longer meet the condition", func(t *testing.T) {
+		st.ProcessEvalResults(context.Background(), rule, eval.Results{
+			{Instance: data.Labels{"dc": "a"}, State: eval.Normal, EvaluatedAt: evaluationTime.Add(10 * time.Second)},
+			{Instance: data.Labels{"dc": "b"}, State: eval.Normal, EvaluatedAt: evaluationTime.Add(10 * time.Second)},
+		})
+		require.Empty(t, st.GetLoadedDimensions(rule.OrgID, rule.UID))
+	})
+}
+
+func TestSyncRules(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	remoteRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "dc-down",
+		UID:             "dc-down",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+	dependentRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "service-down",
+		UID:             "service-down",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		Dependencies: []models.AlertRuleDependency{
+			{RuleUID: remoteRule.UID, Equal: []string{"dc"}},
+		},
+	}
+
+	instanceStore := &store.FakeInstanceStore{
+		Instances: []*models.ListAlertInstancesQueryResult{
+			{
+				RuleOrgID:         remoteRule.OrgID,
+				RuleUID:           remoteRule.UID,
+				Labels:            models.InstanceLabels{"dc": "a", models.RuleUIDLabel: remoteRule.UID},
+				CurrentState:      models.InstanceStateFiring,
+				CurrentStateSince: evaluationTime,
+				LastEvalTime:      evaluationTime,
+			},
+			{
+				RuleOrgID:    remoteRule.OrgID,
+				RuleUID:      "unknown",
+				Labels:       models.InstanceLabels{"dc": "b"},
+				CurrentState: models.InstanceStateFiring,
+			},
+		},
+	}
+	st := state.NewManager(log.New("test_sync_rules"), testMetrics.GetStateMetrics(), nil, nil, instanceStore, &store.FakeStateHistoryStore{}, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	st.Put([]*state.State{{OrgID: remoteRule.OrgID, AlertRuleUID: remoteRule.UID, CacheId: "outdated", State: eval.Normal}})
+	st.SyncRules(context.Background(), []*models.AlertRule{remoteRule})
+
+	t.Run("should replace the states of the rules by the instances in the database", func(t *testing.T) {
+		states := st.GetStatesForRuleUID(remoteRule.OrgID, remoteRule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Equal(t, "a", states[0].Labels["dc"])
+		require.Len(t, st.GetAll(remoteRule.OrgID), 1)
+	})
+
+	t.Run("should suppress the instances of rules that depend on the synced rules", func(t *testing.T) {
+		states := st.ProcessEvalResults(context.Background(), dependentRule, eval.Results{
+			{Instance: data.Labels{"dc": "a"}, State: eval.Alerting, EvaluatedAt: evaluationTime},
+		})
+		require.Len(t, states, 1)
+		require.Equal(t, remoteRule.UID, states[0].SuppressedBy)
+	})
+
+	t.Run("should remove the states of the rules without instances", func(t *testing.T) {
+		instanceStore.Instances = nil
+		st.SyncRules(context.Background(), []*models.AlertRule{remoteRule})
+		require.Empty(t, st.GetStatesForRuleUID(remoteRule.OrgID, remoteRule.UID))
+	})
+}
+
+func TestPauseRule(t *testing.T) {
+	evaluationTime := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test",
+		UID:             "test",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+	}
+
+	historyStore := &store.FakeStateHistoryStore{}
+	st := state.NewManager(log.New("test_pause"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, historyStore, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	annotations.SetRepository(store.NewFakeAnnotationsRepo())
+
+	st.ProcessEvalResults(context.Background(), rule, eval.Results{
+		{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: evaluationTime},
+		{Instance: data.Labels{"instance": "b"}, State: eval.Normal, EvaluatedAt: evaluationTime},
+	})
+	historyStore.Entries = nil
+
+	pausedAt := evaluationTime.Add(10 * time.Second)
+	paused := st.PauseRule(context.Background(), rule, pausedAt, "rule is paused")
+	require.Len(t, paused, 2)
+
+	byInstance := make(map[string]*state.State)
+	for _, s := range paused {
+		byInstance[s.Labels["instance"]] = s
+	}
+
+	t.Run("should move all instances to the paused state", func(t *testing.T) {
+		for _, s := range paused {
+			require.Equal(t, eval.Paused, s.State)
+			require.Equal(t, pausedAt, s.LastEvaluationTime)
+		}
+	})
+
+	t.Run("should resolve alerting instances", func(t *testing.T) {
+		require.True(t, byInstance["a"].Resolved)
+		require.Equal(t, pausedAt, byInstance["a"].EndsAt)
+		require.True(t, byInstance["a"].NeedsSending(0))
+		require.False(t, byInstance["b"].Resolved)
+		require.False(t, byInstance["b"].NeedsSending(0))
+	})
+
+	t.Run("should record the transitions with the reason", func(t *testing.T) {
+		require.Len(t, historyStore.Entries, 2)
+		for _, entry := range historyStore.Entries {
+			require.Equal(t, models.InstanceStatePaused, entry.CurrentState)
+			require.Equal(t, "rule is paused", entry.Reason)
+		}
+	})
+
+	t.Run("should not change instances that are already paused", func(t *testing.T) {
+		require.Empty(t, st.PauseRule(context.Background(), rule, pausedAt.Add(10*time.Second), "rule is paused"))
+	})
+
+	t.Run("should evaluate instances again when the rule is resumed", func(t *testing.T) {
+		states := st.ProcessEvalResults(context.Background(), rule, eval.Results{
+			{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: pausedAt.Add(20 * time.Second)},
+		})
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Equal(t, pausedAt.Add(20*time.Second), states[0].StartsAt)
+	})
+}
+
 func TestStaleResultsHandler(t *testing.T) {
 	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
 	if err != nil {
@@ -1859,6 +2322,9 @@ func TestStaleResultsHandler(t *testing.T) {
 						"alertname":                    rule.Title,
 						"test1":                        "testValue1",
 					},
+					ResultLabels: data.Labels{
+						"test1": "testValue1",
+					},
 					State: eval.Normal,
 					Results: []state.Evaluation{
 						{
@@ -1881,7 +2347,7 @@ func TestStaleResultsHandler(t *testing.T) {
 	for _, tc := range testCases {
 		ctx := context.Background()
 		sqlStore := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore)
+		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, dbstore, sqlStore, &image.NotAvailableImageService{})
 		st.Warm(ctx)
 		existingStatesForRule := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
 
@@ -1901,3 +2367,40 @@ func TestStaleResultsHandler(t *testing.T) {
 		assert.Equal(t, tc.finalStateCount, len(existingStatesForRule))
 	}
 }
+
+func TestWarmRule(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+	otherRule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	for _, r := range []*models.AlertRule{rule, otherRule} {
+		err := dbstore.SaveAlertInstance(ctx, &models.SaveAlertInstanceCommand{
+			RuleOrgID:         r.OrgID,
+			RuleUID:           r.UID,
+			Labels:            models.InstanceLabels{"test1": "testValue1"},
+			State:             models.InstanceStateFiring,
+			LastEvalTime:      evaluationTime,
+			CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+			CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	st := state.NewManager(log.New("test_warm_rule"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, dbstore, mockstore.NewSQLStoreMock(), &image.NotAvailableImageService{})
+	// a state that is not stored anymore must be replaced
+	st.Put([]*state.State{{AlertRuleUID: rule.UID, OrgID: rule.OrgID, CacheId: "outdated", State: eval.Normal}})
+
+	st.WarmRule(ctx, rule)
+
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Alerting, states[0].State)
+	require.Equal(t, data.Labels{"test1": "testValue1"}, states[0].Labels)
+	require.Equal(t, evaluationTime.Add(-1*time.Minute), states[0].StartsAt)
+	require.Empty(t, st.GetStatesForRuleUID(otherRule.OrgID, otherRule.UID))
+}
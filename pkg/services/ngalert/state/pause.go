@@ -0,0 +1,40 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// PauseRule moves the states of a rule whose evaluation is skipped to the Paused state. A state that was alerting is
+// resolved so that the alert that was already sent to the Alertmanager is ended. It returns the states that changed.
+func (st *Manager) PauseRule(ctx context.Context, alertRule *ngModels.AlertRule, pausedAt time.Time, reason string) []*State {
+	var paused []*State
+	var transitions []ngModels.AlertStateHistoryEntry
+	for _, s := range st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID) {
+		if s.State == eval.Paused {
+			continue
+		}
+		previous := s.State
+		s.State = eval.Paused
+		s.Resolved = previous == eval.Alerting
+		s.StartsAt = pausedAt
+		s.EndsAt = pausedAt
+		s.LastEvaluationTime = pausedAt
+		s.Error = nil
+		s.SuppressedBy = ""
+		s.Image = nil
+		st.set(s)
+		paused = append(paused, s)
+
+		transitions = append(transitions, st.newStateHistoryEntry(alertRule, s.Labels, previous, eval.Paused, pausedAt, errors.New(reason), nil))
+		if !st.dryRun {
+			go st.annotateState(ctx, alertRule, s.Labels, pausedAt, eval.Paused, previous)
+		}
+	}
+	st.saveStateHistory(ctx, alertRule, transitions)
+	return paused
+}
@@ -29,7 +29,14 @@ type State struct {
 	LastSentAt           time.Time
 	Annotations          map[string]string
 	Labels               data.Labels
-	Error                error
+	// ResultLabels are the labels of the evaluation result, without the labels added from the rule.
+	ResultLabels data.Labels
+	Error        error
+	// SuppressedBy is the UID of the rule whose firing instance suppresses this alerting instance because of a dependency.
+	// Suppressed instances are not sent to the Alertmanager.
+	SuppressedBy string
+	// Image is the screenshot of the panel the rule is linked to, taken when the instance started alerting.
+	Image *ngModels.Image
 }
 
 type Evaluation struct {
@@ -157,7 +164,7 @@ func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result)
 }
 
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
-	if a.State == eval.Pending || a.State == eval.Normal && !a.Resolved {
+	if a.State == eval.Pending || (a.State == eval.Normal || a.State == eval.Paused || a.IsSuppressed()) && !a.Resolved {
 		return false
 	}
 	// if LastSentAt is before or equal to LastEvaluationTime + resendDelay, send again
@@ -165,6 +172,11 @@ func (a *State) NeedsSending(resendDelay time.Duration) bool {
 	return nextSent.Before(a.LastEvaluationTime) || nextSent.Equal(a.LastEvaluationTime)
 }
 
+// IsSuppressed returns true if the instance is alerting but suppressed by a firing instance of a rule it depends on.
+func (a *State) IsSuppressed() bool {
+	return a.State == eval.Alerting && a.SuppressedBy != ""
+}
+
 func (a *State) Equals(b *State) bool {
 	return a.AlertRuleUID == b.AlertRuleUID &&
 		a.OrgID == b.OrgID &&
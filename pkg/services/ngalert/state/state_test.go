@@ -93,6 +93,29 @@ func TestNeedsSending(t *testing.T) {
 				LastSentAt:         evaluationTime.Add(-30 * time.Second),
 			},
 		},
+		{
+			name:        "state: alerting but suppressed does not send after a minute",
+			resendDelay: 1 * time.Minute,
+			expected:    false,
+			testState: &State{
+				State:              eval.Alerting,
+				SuppressedBy:       "dependency",
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         evaluationTime.Add(-1 * time.Minute),
+			},
+		},
+		{
+			name:        "state: alerting and suppressed + resolved sends after a minute",
+			resendDelay: 1 * time.Minute,
+			expected:    true,
+			testState: &State{
+				State:              eval.Alerting,
+				SuppressedBy:       "dependency",
+				Resolved:           true,
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         evaluationTime.Add(-1 * time.Minute),
+			},
+		},
 		{
 			name:        "state: normal but not resolved does not send after a minute",
 			resendDelay: 1 * time.Minute,
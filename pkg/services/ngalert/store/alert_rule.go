@@ -43,6 +43,7 @@ type RuleStore interface {
 	GetRuleGroups(ctx context.Context, query *ngmodels.ListRuleGroupsQuery) error
 	GetUserVisibleNamespaces(context.Context, int64, *models.SignedInUser) (map[string]*models.Folder, error)
 	GetNamespaceByTitle(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
+	GetNamespaceByUID(context.Context, string, int64, *models.SignedInUser, bool) (*models.Folder, error)
 	InsertAlertRules(ctx context.Context, rule []ngmodels.AlertRule) error
 	UpdateAlertRules(ctx context.Context, rule []UpdateRule) error
 }
@@ -116,11 +117,13 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
 		for i := range rules {
 			r := rules[i]
-			uid, err := GenerateNewAlertRuleUID(sess, r.OrgID, r.Title)
-			if err != nil {
-				return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.Title, err)
+			if r.UID == "" {
+				uid, err := GenerateNewAlertRuleUID(sess, r.OrgID, r.Title)
+				if err != nil {
+					return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.Title, err)
+				}
+				r.UID = uid
 			}
-			r.UID = uid
 			r.Version = 1
 			if err := st.validateAlertRule(r); err != nil {
 				return err
@@ -146,6 +149,9 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				For:              r.For,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Dependencies:     r.Dependencies,
+				Record:           r.Record,
+				IsPaused:         r.IsPaused,
 			})
 		}
 		if len(newRules) > 0 {
@@ -207,6 +213,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []UpdateRule) erro
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Dependencies:     r.New.Dependencies,
+				Record:           r.New.Record,
+				IsPaused:         r.New.IsPaused,
 			})
 		}
 		if len(newRules) > 0 {
@@ -344,6 +353,27 @@ func (st DBstore) GetNamespaceByTitle(ctx context.Context, namespace string, org
 	return folder, nil
 }
 
+// GetNamespaceByUID is a handler for retrieving a namespace by its UID. Alerting rules follow a Grafana folder-like structure which we call namespaces.
+func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user *models.SignedInUser, withCanSave bool) (*models.Folder, error) {
+	folder, err := st.FolderService.GetFolderByUID(ctx, user, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// if access control is disabled, check that the user is allowed to save in the folder.
+	if withCanSave && st.AccessControl.IsDisabled() {
+		g := guardian.New(ctx, folder.Id, orgID, user)
+		if canSave, err := g.CanSave(); err != nil || !canSave {
+			if err != nil {
+				st.Logger.Error("checking can save permission has failed", "userId", user.UserId, "username", user.Login, "namespace", uid, "orgId", orgID, "error", err)
+			}
+			return nil, ngmodels.ErrCannotEditNamespace
+		}
+	}
+
+	return folder, nil
+}
+
 // GetAlertRulesForScheduling returns alert rule info (identifier, interval, version state)
 // that is useful for it's scheduling.
 func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error {
@@ -413,5 +443,20 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 		return fmt.Errorf("%w: cannot have Panel ID without a Dashboard UID", ngmodels.ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
+	}
+
+	for _, dependency := range alertRule.Dependencies {
+		if err := dependency.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid dependency: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
+		if dependency.RuleUID != "" && dependency.RuleUID == alertRule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
+
 	return nil
 }
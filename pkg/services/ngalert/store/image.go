@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ImageStore is the interface for persisting screenshots of panels taken for alerts.
+type ImageStore interface {
+	// GetImage returns the image with the given token or models.ErrImageNotFound.
+	GetImage(ctx context.Context, token string) (*models.Image, error)
+	// SaveImage stores a new image. A token is generated for the image if it does not have one.
+	SaveImage(ctx context.Context, image *models.Image) error
+	// DeleteImagesBefore deletes the images created before the given time and returns them.
+	DeleteImagesBefore(ctx context.Context, before time.Time) ([]*models.Image, error)
+}
+
+// imageTokenLength is the length of generated image tokens. Images can be downloaded without authentication
+// by their token, so it must not be guessable.
+const imageTokenLength = 32
+
+// NewImageToken generates a random token for an image.
+func NewImageToken() (string, error) {
+	token, err := util.GetRandomString(imageTokenLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate image token: %w", err)
+	}
+	return token, nil
+}
+
+// GetImage is a handler for retrieving an image by its token.
+func (st DBstore) GetImage(ctx context.Context, token string) (*models.Image, error) {
+	var image models.Image
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Table("alert_image").Where("token = ?", token).Get(&image)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrImageNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// SaveImage is a handler for storing a new image.
+func (st DBstore) SaveImage(ctx context.Context, image *models.Image) error {
+	if image.Token == "" {
+		token, err := NewImageToken()
+		if err != nil {
+			return err
+		}
+		image.Token = token
+	}
+	if image.CreatedAt.IsZero() {
+		image.CreatedAt = time.Now().UTC()
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Table("alert_image").Insert(image)
+		return err
+	})
+}
+
+// DeleteImagesBefore is a handler for deleting outdated images.
+func (st DBstore) DeleteImagesBefore(ctx context.Context, before time.Time) ([]*models.Image, error) {
+	var images []*models.Image
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if err := sess.Table("alert_image").Where("created_at < ?", before).Find(&images); err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			return nil
+		}
+		_, err := sess.Table("alert_image").Where("created_at < ?", before).Delete(&models.Image{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestImageOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	t.Run("should generate a token and return the saved image", func(t *testing.T) {
+		image := &models.Image{Path: "/tmp/image.png", URL: "https://example.com/image.png"}
+		require.NoError(t, dbstore.SaveImage(ctx, image))
+		require.NotEmpty(t, image.Token)
+		require.NotZero(t, image.ID)
+		require.False(t, image.CreatedAt.IsZero())
+
+		result, err := dbstore.GetImage(ctx, image.Token)
+		require.NoError(t, err)
+		require.Equal(t, image.Path, result.Path)
+		require.Equal(t, image.URL, result.URL)
+		require.True(t, result.HasURL())
+	})
+
+	t.Run("should return ErrImageNotFound if token does not exist", func(t *testing.T) {
+		_, err := dbstore.GetImage(ctx, "does-not-exist")
+		require.ErrorIs(t, err, models.ErrImageNotFound)
+	})
+
+	t.Run("should delete the images created before the given time", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Second)
+		outdated := &models.Image{Path: "/tmp/outdated.png", CreatedAt: now.Add(-2 * time.Hour)}
+		recent := &models.Image{Path: "/tmp/recent.png", CreatedAt: now}
+		require.NoError(t, dbstore.SaveImage(ctx, outdated))
+		require.NoError(t, dbstore.SaveImage(ctx, recent))
+
+		deleted, err := dbstore.DeleteImagesBefore(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		paths := make([]string, 0, len(deleted))
+		for _, image := range deleted {
+			paths = append(paths, image.Path)
+		}
+		require.Contains(t, paths, outdated.Path)
+		require.NotContains(t, paths, recent.Path)
+
+		_, err = dbstore.GetImage(ctx, outdated.Token)
+		require.ErrorIs(t, err, models.ErrImageNotFound)
+		_, err = dbstore.GetImage(ctx, recent.Token)
+		require.NoError(t, err)
+	})
+}
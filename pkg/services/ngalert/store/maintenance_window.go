@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MaintenanceWindowStore is the interface for persisting the maintenance windows of alert rules.
+type MaintenanceWindowStore interface {
+	ListMaintenanceWindows(ctx context.Context, query *models.ListMaintenanceWindowsQuery) error
+	// GetMaintenanceWindow returns models.ErrMaintenanceWindowNotFound if the window does not exist.
+	GetMaintenanceWindow(ctx context.Context, query *models.GetMaintenanceWindowQuery) error
+	// SaveMaintenanceWindow inserts a new window or updates the existing window with the same UID.
+	// A UID is generated for the window if it does not have one.
+	SaveMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+}
+
+// ListMaintenanceWindows is a handler for retrieving the maintenance windows of an organisation, or of all
+// organisations if the query has no organisation ID.
+func (st DBstore) ListMaintenanceWindows(ctx context.Context, query *models.ListMaintenanceWindowsQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		windows := make([]*models.MaintenanceWindow, 0)
+		q := sess.Table("alert_maintenance_window")
+		if query.OrgID > 0 {
+			q = q.Where("org_id = ?", query.OrgID)
+		}
+		if len(query.ExcludeOrgs) > 0 {
+			q = q.NotIn("org_id", query.ExcludeOrgs)
+		}
+		if err := q.Asc("id").Find(&windows); err != nil {
+			return err
+		}
+		query.Result = windows
+		return nil
+	})
+}
+
+// GetMaintenanceWindow is a handler for retrieving a maintenance window by its UID and organisation ID.
+func (st DBstore) GetMaintenanceWindow(ctx context.Context, query *models.GetMaintenanceWindowQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var window models.MaintenanceWindow
+		exists, err := sess.Table("alert_maintenance_window").Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(&window)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrMaintenanceWindowNotFound
+		}
+		query.Result = &window
+		return nil
+	})
+}
+
+// SaveMaintenanceWindow is a handler for creating or updating a maintenance window.
+func (st DBstore) SaveMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	window.Updated = time.Now().UTC()
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if window.UID == "" {
+			window.UID = util.GenerateShortUID()
+		}
+		var existing models.MaintenanceWindow
+		exists, err := sess.Table("alert_maintenance_window").Where("org_id = ? AND uid = ?", window.OrgID, window.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if _, err := sess.Table("alert_maintenance_window").Insert(window); err != nil {
+				if st.SQLStore.Dialect.IsUniqueConstraintViolation(err) {
+					return fmt.Errorf("%w: a maintenance window with UID %q already exists", models.ErrMaintenanceWindowFailedValidation, window.UID)
+				}
+				return err
+			}
+			return nil
+		}
+		window.ID = existing.ID
+		_, err = sess.Table("alert_maintenance_window").ID(existing.ID).AllCols().Update(window)
+		return err
+	})
+}
+
+// DeleteMaintenanceWindow is a handler for deleting a maintenance window. It does nothing if the window does not exist.
+func (st DBstore) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_maintenance_window WHERE org_id = ? AND uid = ?", orgID, uid)
+		return err
+	})
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestMaintenanceWindowOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	start := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	window := &models.MaintenanceWindow{
+		OrgID:        1,
+		Title:        "database upgrade",
+		NamespaceUID: "folder",
+		Ranges:       []models.MaintenanceTimeRange{{Start: start, End: start.Add(time.Hour)}},
+	}
+
+	t.Run("should generate a UID and return the saved window", func(t *testing.T) {
+		require.NoError(t, dbstore.SaveMaintenanceWindow(ctx, window))
+		require.NotEmpty(t, window.UID)
+		require.NotZero(t, window.ID)
+
+		query := &models.GetMaintenanceWindowQuery{OrgID: 1, UID: window.UID}
+		require.NoError(t, dbstore.GetMaintenanceWindow(ctx, query))
+		require.Equal(t, window.Title, query.Result.Title)
+		require.Len(t, query.Result.Ranges, 1)
+		require.True(t, query.Result.Ranges[0].Start.Equal(start))
+	})
+
+	t.Run("should update the window with the same UID", func(t *testing.T) {
+		updated := &models.MaintenanceWindow{OrgID: 1, UID: window.UID, Title: "network upgrade", RuleUID: "rule", Ranges: window.Ranges}
+		require.NoError(t, dbstore.SaveMaintenanceWindow(ctx, updated))
+		require.Equal(t, window.ID, updated.ID)
+
+		query := &models.ListMaintenanceWindowsQuery{OrgID: 1}
+		require.NoError(t, dbstore.ListMaintenanceWindows(ctx, query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, "network upgrade", query.Result[0].Title)
+		require.Equal(t, "rule", query.Result[0].RuleUID)
+	})
+
+	t.Run("should exclude organisations", func(t *testing.T) {
+		query := &models.ListMaintenanceWindowsQuery{ExcludeOrgs: []int64{1}}
+		require.NoError(t, dbstore.ListMaintenanceWindows(ctx, query))
+		require.Empty(t, query.Result)
+	})
+
+	t.Run("should return ErrMaintenanceWindowNotFound after the window is deleted", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteMaintenanceWindow(ctx, 1, window.UID))
+		err := dbstore.GetMaintenanceWindow(ctx, &models.GetMaintenanceWindowQuery{OrgID: 1, UID: window.UID})
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+}
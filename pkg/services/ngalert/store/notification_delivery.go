@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// NotificationDeliveryStore is the interface for persisting the delivery attempts of notifications and the queue of
+// notifications whose delivery is retried.
+type NotificationDeliveryStore interface {
+	SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	GetNotificationDeliveries(ctx context.Context, query *models.GetNotificationDeliveriesQuery) error
+	// DeleteNotificationDeliveriesBefore deletes all delivery attempts that were made before the given time
+	// and returns the number of deleted attempts.
+	DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// SaveNotificationRetry queues a new notification or updates a queued notification.
+	SaveNotificationRetry(ctx context.Context, retry *models.NotificationRetry) error
+	// ClaimNotificationRetries returns the queued notifications of an organization that are due, oldest first.
+	// A notification is claimed only once, even by concurrent queries of different Grafana instances.
+	ClaimNotificationRetries(ctx context.Context, query *models.ClaimNotificationRetriesQuery) error
+	DeleteNotificationRetry(ctx context.Context, id int64) error
+}
+
+// SaveNotificationDelivery is a handler for storing a delivery attempt of a notification.
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	delivery.AttemptedAtUnix = delivery.AttemptedAt.UnixMilli()
+	delivery.LatencyMs = delivery.Latency.Milliseconds()
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Table("alert_notification_delivery").Insert(delivery)
+		return err
+	})
+}
+
+// GetNotificationDeliveries is a handler for retrieving delivery attempts of notifications, newest first.
+func (st DBstore) GetNotificationDeliveries(ctx context.Context, query *models.GetNotificationDeliveriesQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT * FROM alert_notification_delivery WHERE org_id = ?", query.OrgID)
+
+		if query.Receiver != "" {
+			addToQuery(" AND receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			addToQuery(" AND integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			addToQuery(" AND status = ?", query.Status)
+		}
+		if query.Fingerprint != "" {
+			// fingerprints are stored as a JSON array of hexadecimal strings, the API only accepts hexadecimal fingerprints
+			// so there is nothing to escape in the pattern
+			addToQuery(" AND fingerprints LIKE ?", `%"`+query.Fingerprint+`"%`)
+		}
+		if !query.From.IsZero() {
+			addToQuery(" AND attempted_at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			addToQuery(" AND attempted_at <= ?", query.To.UnixMilli())
+		}
+		addToQuery(" ORDER BY attempted_at DESC, id DESC")
+		if query.Limit > 0 {
+			s.WriteString(" " + st.SQLStore.Dialect.Limit(int64(query.Limit)))
+		}
+
+		deliveries := make([]*models.NotificationDelivery, 0)
+		if err := sess.SQL(s.String(), params...).Find(&deliveries); err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			delivery.AttemptedAt = time.UnixMilli(delivery.AttemptedAtUnix)
+			delivery.Latency = time.Duration(delivery.LatencyMs) * time.Millisecond
+		}
+		query.Result = deliveries
+		return nil
+	})
+}
+
+// DeleteNotificationDeliveriesBefore is a handler for deleting delivery attempts that are older than the given time.
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_delivery WHERE attempted_at < ?", before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+// SaveNotificationRetry is a handler for queueing a notification or updating a queued notification.
+func (st DBstore) SaveNotificationRetry(ctx context.Context, retry *models.NotificationRetry) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if retry.ID == 0 {
+			_, err := sess.Table("alert_notification_retry").Insert(retry)
+			return err
+		}
+		_, err := sess.Table("alert_notification_retry").ID(retry.ID).AllCols().Update(retry)
+		return err
+	})
+}
+
+// ClaimNotificationRetries is a handler for claiming the queued notifications that are due. A notification is
+// claimed by moving its next attempt to the end of the claim, which only succeeds for one of concurrent queries.
+func (st DBstore) ClaimNotificationRetries(ctx context.Context, query *models.ClaimNotificationRetriesQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		due := make([]*models.NotificationRetry, 0)
+		q := sess.Table("alert_notification_retry").
+			Where("org_id = ? AND next_attempt_at <= ?", query.OrgID, query.Now.UnixMilli()).
+			Asc("next_attempt_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		if err := q.Find(&due); err != nil {
+			return err
+		}
+
+		claimed := make([]*models.NotificationRetry, 0, len(due))
+		claimUntil := query.ClaimUntil.UnixMilli()
+		for _, retry := range due {
+			res, err := sess.Exec("UPDATE alert_notification_retry SET next_attempt_at = ? WHERE id = ? AND next_attempt_at = ?",
+				claimUntil, retry.ID, retry.NextAttemptAtUnix)
+			if err != nil {
+				return err
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				// claimed by another instance in the meantime
+				continue
+			}
+			retry.NextAttemptAtUnix = claimUntil
+			claimed = append(claimed, retry)
+		}
+		query.Result = claimed
+		return nil
+	})
+}
+
+// DeleteNotificationRetry is a handler for removing a notification from the retry queue.
+func (st DBstore) DeleteNotificationRetry(ctx context.Context, id int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_notification_retry WHERE id = ?", id)
+		return err
+	})
+}
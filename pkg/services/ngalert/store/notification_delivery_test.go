@@ -0,0 +1,149 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestNotificationDeliveryOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const orgID int64 = 1
+	attemptedAt := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	delivery := func(receiver, integration string, status models.NotificationDeliveryStatus, offset time.Duration, fingerprints ...string) *models.NotificationDelivery {
+		return &models.NotificationDelivery{
+			OrgID:          orgID,
+			Receiver:       receiver,
+			Integration:    integration,
+			IntegrationUID: receiver + "-" + integration,
+			Fingerprints:   fingerprints,
+			Status:         status,
+			Latency:        250 * time.Millisecond,
+			AttemptedAt:    attemptedAt.Add(offset),
+		}
+	}
+
+	failed := delivery("ops", "webhook", models.NotificationDeliveryFailure, 2*time.Minute, "c3d4")
+	failed.StatusCode = 503
+	failed.Error = "Webhook response status 503 Service Unavailable"
+	for _, d := range []*models.NotificationDelivery{
+		delivery("ops", "slack", models.NotificationDeliverySuccess, 0, "a1b2", "c3d4"),
+		delivery("ops", "webhook", models.NotificationDeliverySuccess, time.Minute, "a1b2"),
+		failed,
+		{OrgID: orgID + 1, Receiver: "ops", Fingerprints: []string{}, Status: models.NotificationDeliverySuccess, AttemptedAt: attemptedAt},
+	} {
+		require.NoError(t, dbstore.SaveNotificationDelivery(ctx, d))
+	}
+
+	t.Run("should return attempts of the organization, newest first", func(t *testing.T) {
+		q := &models.GetNotificationDeliveriesQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, "ops-webhook", q.Result[0].IntegrationUID)
+		require.Equal(t, []string{"c3d4"}, q.Result[0].Fingerprints)
+		require.Equal(t, models.NotificationDeliveryFailure, q.Result[0].Status)
+		require.Equal(t, 503, q.Result[0].StatusCode)
+		require.Equal(t, failed.Error, q.Result[0].Error)
+		require.Equal(t, 250*time.Millisecond, q.Result[0].Latency)
+		require.True(t, attemptedAt.Add(2*time.Minute).Equal(q.Result[0].AttemptedAt))
+	})
+
+	t.Run("should filter by integration, status, fingerprint, time range and limit", func(t *testing.T) {
+		q := &models.GetNotificationDeliveriesQuery{OrgID: orgID, Receiver: "ops", Integration: "webhook"}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		q = &models.GetNotificationDeliveriesQuery{OrgID: orgID, Status: models.NotificationDeliverySuccess, Limit: 1}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "webhook", q.Result[0].Integration)
+
+		q = &models.GetNotificationDeliveriesQuery{OrgID: orgID, Fingerprint: "c3d4"}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		q = &models.GetNotificationDeliveriesQuery{OrgID: orgID, From: attemptedAt.Add(time.Minute), To: attemptedAt.Add(time.Minute)}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "webhook", q.Result[0].Integration)
+	})
+
+	t.Run("should delete attempts made before the given time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationDeliveriesBefore(ctx, attemptedAt.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		q := &models.GetNotificationDeliveriesQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetNotificationDeliveries(ctx, q))
+		require.Len(t, q.Result, 2)
+	})
+}
+
+func TestNotificationRetryOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const orgID int64 = 1
+	now := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	retry := func(receiver string, nextAttempt time.Time) *models.NotificationRetry {
+		return &models.NotificationRetry{
+			OrgID:             orgID,
+			Receiver:          receiver,
+			Integration:       "webhook",
+			GroupKey:          "{}:{alertname=\"test\"}",
+			GroupLabels:       map[string]string{"alertname": "test"},
+			Alerts:            "[]",
+			NextAttemptAtUnix: nextAttempt.UnixMilli(),
+			CreatedAtUnix:     now.UnixMilli(),
+		}
+	}
+
+	due := retry("due", now.Add(-time.Minute))
+	require.NoError(t, dbstore.SaveNotificationRetry(ctx, due))
+	require.NoError(t, dbstore.SaveNotificationRetry(ctx, retry("later", now.Add(time.Minute))))
+	require.NotZero(t, due.ID)
+
+	t.Run("should claim due notifications only once", func(t *testing.T) {
+		q := &models.ClaimNotificationRetriesQuery{OrgID: orgID, Now: now, ClaimUntil: now.Add(5 * time.Minute)}
+		require.NoError(t, dbstore.ClaimNotificationRetries(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "due", q.Result[0].Receiver)
+		require.Equal(t, map[string]string{"alertname": "test"}, q.Result[0].GroupLabels)
+		require.Equal(t, now.Add(5*time.Minute).UnixMilli(), q.Result[0].NextAttemptAtUnix)
+
+		q = &models.ClaimNotificationRetriesQuery{OrgID: orgID, Now: now, ClaimUntil: now.Add(5 * time.Minute)}
+		require.NoError(t, dbstore.ClaimNotificationRetries(ctx, q))
+		require.Empty(t, q.Result)
+	})
+
+	t.Run("should update and delete queued notifications", func(t *testing.T) {
+		due.Attempts = 1
+		due.LastError = "unavailable"
+		due.NextAttemptAtUnix = now.UnixMilli()
+		require.NoError(t, dbstore.SaveNotificationRetry(ctx, due))
+
+		q := &models.ClaimNotificationRetriesQuery{OrgID: orgID, Now: now, ClaimUntil: now.Add(5 * time.Minute)}
+		require.NoError(t, dbstore.ClaimNotificationRetries(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, 1, q.Result[0].Attempts)
+		require.Equal(t, "unavailable", q.Result[0].LastError)
+
+		require.NoError(t, dbstore.DeleteNotificationRetry(ctx, due.ID))
+		q = &models.ClaimNotificationRetriesQuery{OrgID: orgID, Now: now.Add(time.Hour), ClaimUntil: now.Add(2 * time.Hour)}
+		require.NoError(t, dbstore.ClaimNotificationRetries(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "later", q.Result[0].Receiver)
+	})
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// StateHistoryStore is the interface for persisting state transitions of alert instances.
+type StateHistoryStore interface {
+	SaveAlertStateHistory(ctx context.Context, cmd *models.SaveAlertStateHistoryCommand) error
+	GetAlertStateHistory(ctx context.Context, query *models.GetAlertStateHistoryQuery) error
+	// DeleteAlertStateHistoryBefore deletes all entries that were evaluated before the given time
+	// and returns the number of deleted entries.
+	DeleteAlertStateHistoryBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SaveAlertStateHistory is a handler for storing state transitions of alert instances.
+func (st DBstore) SaveAlertStateHistory(ctx context.Context, cmd *models.SaveAlertStateHistoryCommand) error {
+	if len(cmd.Entries) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		s := strings.Builder{}
+		s.WriteString("INSERT INTO alert_state_history (org_id, rule_uid, labels, labels_hash, previous_state, current_state, reason, eval_values, evaluated_at) VALUES ")
+		params := make([]interface{}, 0, len(cmd.Entries)*9)
+		for i, entry := range cmd.Entries {
+			labels := entry.Labels
+			labelTupleJSON, labelsHash, err := labels.StringAndHash()
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			s.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			params = append(params, entry.OrgID, entry.RuleUID, labelTupleJSON, labelsHash, entry.PreviousState, entry.CurrentState, entry.Reason, entry.EvalValues, entry.EvaluatedAt.UnixMilli())
+		}
+		_, err := sess.Exec(append([]interface{}{s.String()}, params...)...)
+		return err
+	})
+}
+
+const (
+	// stateHistoryPageSize is the number of entries that are read from the database at once.
+	stateHistoryPageSize = 1000
+	// stateHistoryMaxRuleUIDs is the maximum number of rule UIDs in a single query, to stay below the limits
+	// of the databases for the number of bind parameters.
+	stateHistoryMaxRuleUIDs = 500
+)
+
+// GetAlertStateHistory is a handler for retrieving state transitions of alert instances, newest first.
+// The entries are read in pages, and label matchers are applied to each page, so that only the entries
+// that are returned are kept in memory.
+func (st DBstore) GetAlertStateHistory(ctx context.Context, query *models.GetAlertStateHistoryQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		chunks := [][]string{nil}
+		if len(query.RuleUIDs) > 0 {
+			chunks = chunks[:0]
+			for start := 0; start < len(query.RuleUIDs); start += stateHistoryMaxRuleUIDs {
+				end := start + stateHistoryMaxRuleUIDs
+				if end > len(query.RuleUIDs) {
+					end = len(query.RuleUIDs)
+				}
+				chunks = append(chunks, query.RuleUIDs[start:end])
+			}
+		}
+
+		result := make([]*models.AlertStateHistoryEntry, 0)
+		for _, ruleUIDs := range chunks {
+			entries, err := st.getAlertStateHistory(sess, query, ruleUIDs)
+			if err != nil {
+				return err
+			}
+			result = append(result, entries...)
+		}
+		if len(chunks) > 1 {
+			sort.Slice(result, func(i, j int) bool {
+				if result[i].EvaluatedAtUnix != result[j].EvaluatedAtUnix {
+					return result[i].EvaluatedAtUnix > result[j].EvaluatedAtUnix
+				}
+				return result[i].ID > result[j].ID
+			})
+			if query.Limit > 0 && len(result) > query.Limit {
+				result = result[:query.Limit]
+			}
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// getAlertStateHistory reads the entries of the given rules page by page, newest first, until the limit of the
+// query is reached or there are no more entries.
+func (st DBstore) getAlertStateHistory(sess *sqlstore.DBSession, query *models.GetAlertStateHistoryQuery, ruleUIDs []string) ([]*models.AlertStateHistoryEntry, error) {
+	result := make([]*models.AlertStateHistoryEntry, 0)
+	var last *models.AlertStateHistoryEntry
+	for {
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT * FROM alert_state_history WHERE org_id = ?", query.OrgID)
+
+		if len(ruleUIDs) > 0 {
+			args := make([]interface{}, 0, len(ruleUIDs))
+			in := make([]string, 0, len(ruleUIDs))
+			for _, ruleUID := range ruleUIDs {
+				args = append(args, ruleUID)
+				in = append(in, "?")
+			}
+			addToQuery(fmt.Sprintf(" AND rule_uid IN (%s)", strings.Join(in, ",")), args...)
+		}
+		if !query.From.IsZero() {
+			addToQuery(" AND evaluated_at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			addToQuery(" AND evaluated_at <= ?", query.To.UnixMilli())
+		}
+		// continue after the last entry of the previous page
+		if last != nil {
+			addToQuery(" AND (evaluated_at < ? OR (evaluated_at = ? AND id < ?))", last.EvaluatedAtUnix, last.EvaluatedAtUnix, last.ID)
+		}
+		addToQuery(" ORDER BY evaluated_at DESC, id DESC")
+		pageSize := stateHistoryPageSize
+		// without label matchers every entry is returned, so no more entries than needed are read
+		if remaining := query.Limit - len(result); query.Limit > 0 && len(query.Matchers) == 0 && remaining < pageSize {
+			pageSize = remaining
+		}
+		s.WriteString(" " + st.SQLStore.Dialect.Limit(int64(pageSize)))
+
+		page := make([]*models.AlertStateHistoryEntry, 0, pageSize)
+		if err := sess.SQL(s.String(), params...).Find(&page); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range page {
+			if !matchesLabels(query.Matchers, entry.Labels) {
+				continue
+			}
+			entry.EvaluatedAt = time.UnixMilli(entry.EvaluatedAtUnix)
+			result = append(result, entry)
+			if query.Limit > 0 && len(result) == query.Limit {
+				return result, nil
+			}
+		}
+		if len(page) < pageSize {
+			return result, nil
+		}
+		last = page[len(page)-1]
+	}
+}
+
+// DeleteAlertStateHistoryBefore is a handler for deleting state transitions that are older than the given time.
+func (st DBstore) DeleteAlertStateHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE evaluated_at < ?", before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+func matchesLabels(matchers labels.Matchers, lbs models.InstanceLabels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbs[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
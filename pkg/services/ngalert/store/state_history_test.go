@@ -0,0 +1,123 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestAlertStateHistoryOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const orgID int64 = 1
+	evaluatedAt := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	entry := func(ruleUID, job string, current models.InstanceStateType, offset time.Duration) models.AlertStateHistoryEntry {
+		return models.AlertStateHistoryEntry{
+			OrgID:         orgID,
+			RuleUID:       ruleUID,
+			Labels:        models.InstanceLabels{"job": job},
+			PreviousState: models.InstanceStateNormal,
+			CurrentState:  current,
+			Reason:        "reason",
+			EvalValues:    `{"B":1}`,
+			EvaluatedAt:   evaluatedAt.Add(offset),
+		}
+	}
+
+	err := dbstore.SaveAlertStateHistory(ctx, &models.SaveAlertStateHistoryCommand{
+		Entries: []models.AlertStateHistoryEntry{
+			entry("rule-1", "a", models.InstanceStatePending, 0),
+			entry("rule-1", "b", models.InstanceStateFiring, time.Minute),
+			entry("rule-2", "a", models.InstanceStateError, 2*time.Minute),
+			{OrgID: orgID + 1, RuleUID: "rule-1", Labels: models.InstanceLabels{}, EvaluatedAt: evaluatedAt},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("should return entries of the organization, newest first", func(t *testing.T) {
+		q := &models.GetAlertStateHistoryQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+		require.Equal(t, models.InstanceLabels{"job": "a"}, q.Result[0].Labels)
+		require.Equal(t, models.InstanceStateNormal, q.Result[0].PreviousState)
+		require.Equal(t, models.InstanceStateError, q.Result[0].CurrentState)
+		require.Equal(t, "reason", q.Result[0].Reason)
+		require.Equal(t, `{"B":1}`, q.Result[0].EvalValues)
+		require.True(t, evaluatedAt.Add(2*time.Minute).Equal(q.Result[0].EvaluatedAt))
+	})
+
+	t.Run("should filter by rules, matchers, time range and limit", func(t *testing.T) {
+		q := &models.GetAlertStateHistoryQuery{OrgID: orgID, RuleUIDs: []string{"rule-1"}}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "job", "a")
+		require.NoError(t, err)
+		q = &models.GetAlertStateHistoryQuery{OrgID: orgID, Matchers: labels.Matchers{matcher}, Limit: 1}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+
+		q = &models.GetAlertStateHistoryQuery{OrgID: orgID, From: evaluatedAt.Add(time.Minute), To: evaluatedAt.Add(time.Minute)}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceLabels{"job": "b"}, q.Result[0].Labels)
+	})
+
+	t.Run("should read entries of many rules in pages", func(t *testing.T) {
+		const pagedOrgID = orgID + 2
+		ruleUIDs := make([]string, 0, 600)
+		for i := 0; i < 600; i++ {
+			ruleUIDs = append(ruleUIDs, fmt.Sprintf("paged-%d", i))
+		}
+		// the only entry that matches is the oldest one, in the last page
+		entries := make([]models.AlertStateHistoryEntry, 0, 1200)
+		for i := 0; i < 1200; i++ {
+			job := "b"
+			if i == 0 {
+				job = "a"
+			}
+			e := entry(ruleUIDs[i%len(ruleUIDs)], job, models.InstanceStateFiring, time.Hour+time.Duration(i)*time.Second)
+			e.OrgID = pagedOrgID
+			entries = append(entries, e)
+		}
+		require.NoError(t, dbstore.SaveAlertStateHistory(ctx, &models.SaveAlertStateHistoryCommand{Entries: entries}))
+
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "job", "a")
+		require.NoError(t, err)
+		q := &models.GetAlertStateHistoryQuery{OrgID: pagedOrgID, RuleUIDs: ruleUIDs, Matchers: labels.Matchers{matcher}, Limit: 10}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "paged-0", q.Result[0].RuleUID)
+
+		q = &models.GetAlertStateHistoryQuery{OrgID: pagedOrgID, RuleUIDs: ruleUIDs, Limit: 700}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 700)
+		for i, e := range q.Result {
+			require.True(t, evaluatedAt.Add(time.Hour+time.Duration(1199-i)*time.Second).Equal(e.EvaluatedAt))
+		}
+	})
+
+	t.Run("should delete entries evaluated before the given time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteAlertStateHistoryBefore(ctx, evaluatedAt.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		q := &models.GetAlertStateHistoryQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetAlertStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/util"
@@ -267,6 +268,19 @@ func (f *FakeRuleStore) GetNamespaceByTitle(_ context.Context, title string, org
 	return nil, fmt.Errorf("not found")
 }
 
+func (f *FakeRuleStore) GetNamespaceByUID(_ context.Context, uid string, orgID int64, _ *models2.SignedInUser, _ bool) (*models2.Folder, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	folders := f.Folders[orgID]
+	for _, folder := range folders {
+		if folder.Uid == uid {
+			return folder, nil
+		}
+	}
+	return nil, models2.ErrFolderNotFound
+}
+
 func (f *FakeRuleStore) UpdateAlertRules(_ context.Context, q []UpdateRule) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -294,6 +308,8 @@ func (f *FakeRuleStore) InTransaction(ctx context.Context, fn func(c context.Con
 type FakeInstanceStore struct {
 	mtx         sync.Mutex
 	RecordedOps []interface{}
+	// Instances are returned by ListAlertInstances.
+	Instances []*models.ListAlertInstancesQueryResult
 }
 
 func (f *FakeInstanceStore) GetAlertInstance(_ context.Context, q *models.GetAlertInstanceQuery) error {
@@ -306,6 +322,12 @@ func (f *FakeInstanceStore) ListAlertInstances(_ context.Context, q *models.List
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
 	f.RecordedOps = append(f.RecordedOps, *q)
+	for _, instance := range f.Instances {
+		if instance.RuleOrgID != q.RuleOrgID || (q.RuleUID != "" && instance.RuleUID != q.RuleUID) {
+			continue
+		}
+		q.Result = append(q.Result, instance)
+	}
 	return nil
 }
 func (f *FakeInstanceStore) SaveAlertInstance(_ context.Context, q *models.SaveAlertInstanceCommand) error {
@@ -320,6 +342,276 @@ func (f *FakeInstanceStore) DeleteAlertInstance(_ context.Context, _ int64, _, _
 	return nil
 }
 
+type FakeStateHistoryStore struct {
+	mtx     sync.Mutex
+	Entries []models.AlertStateHistoryEntry
+}
+
+func (f *FakeStateHistoryStore) SaveAlertStateHistory(_ context.Context, cmd *models.SaveAlertStateHistoryCommand) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Entries = append(f.Entries, cmd.Entries...)
+	return nil
+}
+
+func (f *FakeStateHistoryStore) GetAlertStateHistory(_ context.Context, q *models.GetAlertStateHistoryQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		entry := f.Entries[i]
+		if entry.OrgID != q.OrgID || !containsRuleUID(q.RuleUIDs, entry.RuleUID) {
+			continue
+		}
+		if !q.From.IsZero() && entry.EvaluatedAt.Before(q.From) || !q.To.IsZero() && entry.EvaluatedAt.After(q.To) {
+			continue
+		}
+		if !matchesLabels(q.Matchers, entry.Labels) {
+			continue
+		}
+		q.Result = append(q.Result, &entry)
+		if q.Limit > 0 && len(q.Result) == q.Limit {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *FakeStateHistoryStore) DeleteAlertStateHistoryBefore(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	kept := f.Entries[:0]
+	for _, entry := range f.Entries {
+		if !entry.EvaluatedAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	deleted := int64(len(f.Entries) - len(kept))
+	f.Entries = kept
+	return deleted, nil
+}
+
+// FakeImageStore keeps images in memory.
+type FakeImageStore struct {
+	mtx    sync.Mutex
+	Images map[string]*models.Image
+}
+
+func NewFakeImageStore() *FakeImageStore {
+	return &FakeImageStore{Images: make(map[string]*models.Image)}
+}
+
+func (f *FakeImageStore) GetImage(_ context.Context, token string) (*models.Image, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	image, ok := f.Images[token]
+	if !ok {
+		return nil, models.ErrImageNotFound
+	}
+	return image, nil
+}
+
+func (f *FakeImageStore) SaveImage(_ context.Context, image *models.Image) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if image.Token == "" {
+		token, err := NewImageToken()
+		if err != nil {
+			return err
+		}
+		image.Token = token
+	}
+	if image.CreatedAt.IsZero() {
+		image.CreatedAt = time.Now().UTC()
+	}
+	f.Images[image.Token] = image
+	return nil
+}
+
+func (f *FakeImageStore) DeleteImagesBefore(_ context.Context, before time.Time) ([]*models.Image, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var deleted []*models.Image
+	for token, image := range f.Images {
+		if image.CreatedAt.Before(before) {
+			deleted = append(deleted, image)
+			delete(f.Images, token)
+		}
+	}
+	return deleted, nil
+}
+
+// FakeMaintenanceWindowStore keeps maintenance windows in memory.
+type FakeMaintenanceWindowStore struct {
+	mtx     sync.Mutex
+	Windows []*models.MaintenanceWindow
+}
+
+func (f *FakeMaintenanceWindowStore) ListMaintenanceWindows(_ context.Context, query *models.ListMaintenanceWindowsQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	query.Result = nil
+	for _, w := range f.Windows {
+		if query.OrgID > 0 && w.OrgID != query.OrgID {
+			continue
+		}
+		query.Result = append(query.Result, w)
+	}
+	return nil
+}
+
+func (f *FakeMaintenanceWindowStore) GetMaintenanceWindow(_ context.Context, query *models.GetMaintenanceWindowQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, w := range f.Windows {
+		if w.OrgID == query.OrgID && w.UID == query.UID {
+			query.Result = w
+			return nil
+		}
+	}
+	return models.ErrMaintenanceWindowNotFound
+}
+
+func (f *FakeMaintenanceWindowStore) SaveMaintenanceWindow(_ context.Context, window *models.MaintenanceWindow) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if window.UID == "" {
+		window.UID = util.GenerateShortUID()
+	}
+	for i, w := range f.Windows {
+		if w.OrgID == window.OrgID && w.UID == window.UID {
+			f.Windows[i] = window
+			return nil
+		}
+	}
+	f.Windows = append(f.Windows, window)
+	return nil
+}
+
+func (f *FakeMaintenanceWindowStore) DeleteMaintenanceWindow(_ context.Context, orgID int64, uid string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i, w := range f.Windows {
+		if w.OrgID == orgID && w.UID == uid {
+			f.Windows = append(f.Windows[:i], f.Windows[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FakeNotificationDeliveryStore keeps delivery attempts and queued notifications in memory.
+type FakeNotificationDeliveryStore struct {
+	mtx        sync.Mutex
+	Deliveries []models.NotificationDelivery
+	Retries    []*models.NotificationRetry
+	lastID     int64
+}
+
+func (f *FakeNotificationDeliveryStore) SaveNotificationDelivery(_ context.Context, delivery *models.NotificationDelivery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastID++
+	delivery.ID = f.lastID
+	f.Deliveries = append(f.Deliveries, *delivery)
+	return nil
+}
+
+func (f *FakeNotificationDeliveryStore) GetNotificationDeliveries(_ context.Context, q *models.GetNotificationDeliveriesQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i := len(f.Deliveries) - 1; i >= 0; i-- {
+		delivery := f.Deliveries[i]
+		if delivery.OrgID != q.OrgID {
+			continue
+		}
+		if q.Receiver != "" && delivery.Receiver != q.Receiver || q.Integration != "" && delivery.Integration != q.Integration || q.Status != "" && delivery.Status != q.Status {
+			continue
+		}
+		if q.Fingerprint != "" && !containsRuleUID(delivery.Fingerprints, q.Fingerprint) {
+			continue
+		}
+		if !q.From.IsZero() && delivery.AttemptedAt.Before(q.From) || !q.To.IsZero() && delivery.AttemptedAt.After(q.To) {
+			continue
+		}
+		q.Result = append(q.Result, &delivery)
+		if q.Limit > 0 && len(q.Result) == q.Limit {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *FakeNotificationDeliveryStore) DeleteNotificationDeliveriesBefore(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	kept := f.Deliveries[:0]
+	for _, delivery := range f.Deliveries {
+		if !delivery.AttemptedAt.Before(before) {
+			kept = append(kept, delivery)
+		}
+	}
+	deleted := int64(len(f.Deliveries) - len(kept))
+	f.Deliveries = kept
+	return deleted, nil
+}
+
+func (f *FakeNotificationDeliveryStore) SaveNotificationRetry(_ context.Context, retry *models.NotificationRetry) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i, r := range f.Retries {
+		if r.ID == retry.ID {
+			f.Retries[i] = retry
+			return nil
+		}
+	}
+	f.lastID++
+	retry.ID = f.lastID
+	f.Retries = append(f.Retries, retry)
+	return nil
+}
+
+func (f *FakeNotificationDeliveryStore) ClaimNotificationRetries(_ context.Context, q *models.ClaimNotificationRetriesQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q.Result = nil
+	for _, r := range f.Retries {
+		if r.OrgID != q.OrgID || r.NextAttemptAtUnix > q.Now.UnixMilli() {
+			continue
+		}
+		r.NextAttemptAtUnix = q.ClaimUntil.UnixMilli()
+		claimed := *r
+		q.Result = append(q.Result, &claimed)
+		if q.Limit > 0 && len(q.Result) == q.Limit {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *FakeNotificationDeliveryStore) DeleteNotificationRetry(_ context.Context, id int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i, r := range f.Retries {
+		if r.ID == id {
+			f.Retries = append(f.Retries[:i], f.Retries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func containsRuleUID(ruleUIDs []string, ruleUID string) bool {
+	if len(ruleUIDs) == 0 {
+		return true
+	}
+	for _, uid := range ruleUIDs {
+		if uid == ruleUID {
+			return true
+		}
+	}
+	return false
+}
+
 func NewFakeAdminConfigStore(t *testing.T) *FakeAdminConfigStore {
 	t.Helper()
 	return &FakeAdminConfigStore{Configs: map[int64]*models.AdminConfiguration{}}
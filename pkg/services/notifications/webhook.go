@@ -24,6 +24,16 @@ type Webhook struct {
 	ContentType string
 }
 
+// WebhookResponseError is returned when a webhook responds with a status code other than 2xx.
+type WebhookResponseError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e WebhookResponseError) Error() string {
+	return fmt.Sprintf("Webhook response status %v", e.Status)
+}
+
 var netTransport = &http.Transport{
 	TLSClientConfig: &tls.Config{
 		Renegotiation: tls.RenegotiateFreelyAsClient,
@@ -95,5 +105,5 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 	}
 
 	ns.log.Debug("Webhook failed", "url", webhook.Url, "statuscode", resp.Status, "body", string(body))
-	return fmt.Errorf("Webhook response status %v", resp.Status)
+	return WebhookResponseError{StatusCode: resp.StatusCode, Status: resp.Status}
 }
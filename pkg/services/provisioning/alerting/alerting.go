@@ -0,0 +1,455 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type OrgStore interface {
+	GetOrgById(context.Context, *models.GetOrgByIdQuery) error
+	SearchOrgs(context.Context, *models.SearchOrgsQuery) error
+}
+
+type RuleService interface {
+	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (ngmodels.AlertRule, ngmodels.Provenance, error)
+	CreateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance ngmodels.Provenance) error
+}
+
+type ContactPointService interface {
+	GetContactPoints(ctx context.Context, orgID int64) ([]definitions.EmbeddedContactPoint, error)
+	CreateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, provenance ngmodels.Provenance) (definitions.EmbeddedContactPoint, error)
+	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, provenance ngmodels.Provenance) error
+	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
+}
+
+type NotificationPolicyService interface {
+	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p ngmodels.Provenance) error
+}
+
+type TemplateService interface {
+	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, name string, provenance ngmodels.Provenance) error
+}
+
+type MuteTimingService interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error)
+	DeleteMuteTiming(ctx context.Context, name string, orgID int64, provenance ngmodels.Provenance) error
+}
+
+type MaintenanceWindowService interface {
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (ngmodels.MaintenanceWindow, ngmodels.Provenance, error)
+	CreateMaintenanceWindow(ctx context.Context, window ngmodels.MaintenanceWindow, provenance ngmodels.Provenance) (ngmodels.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, window ngmodels.MaintenanceWindow, provenance ngmodels.Provenance) (ngmodels.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, provenance ngmodels.Provenance) error
+}
+
+// ProvisionerConfig contains everything the alerting provisioner needs to apply the files found in Path.
+type ProvisionerConfig struct {
+	Path                     string
+	OrgStore                 OrgStore
+	DashboardStore           utils.DashboardStore
+	DashboardService         dashboards.DashboardProvisioningService
+	ProvenanceStore          provisioning.ProvisioningStore
+	RuleService              RuleService
+	ContactPointService      ContactPointService
+	PolicyService            NotificationPolicyService
+	TemplateService          TemplateService
+	MuteTimingService        MuteTimingService
+	MaintenanceWindowService MaintenanceWindowService
+}
+
+// Provision alert rules, contact points, notification policies, templates, mute timings and maintenance windows.
+// Objects provisioned from files in an earlier run that are no longer present in the files are removed.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	ap := newAlertingProvisioner(cfg, log.New("provisioning.alerting"))
+	return ap.applyChanges(ctx, cfg.Path)
+}
+
+// AlertingProvisioner is responsible for provisioning unified alerting resources.
+type AlertingProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	cfg         ProvisionerConfig
+}
+
+func newAlertingProvisioner(cfg ProvisionerConfig, log log.Logger) AlertingProvisioner {
+	return AlertingProvisioner{
+		log: log,
+		cfgProvider: &configReader{
+			orgStore: cfg.OrgStore,
+			log:      log,
+		},
+		cfg: cfg,
+	}
+}
+
+// provisionedObjects tracks the identifiers of the objects that are present in the provisioning files, per organization.
+type provisionedObjects struct {
+	rules              map[int64]map[string]struct{}
+	contactPoints      map[int64]map[string]struct{}
+	templates          map[int64]map[string]struct{}
+	muteTimes          map[int64]map[string]struct{}
+	policies           map[int64]struct{}
+	maintenanceWindows map[int64]map[string]struct{}
+}
+
+func newProvisionedObjects() *provisionedObjects {
+	return &provisionedObjects{
+		rules:              map[int64]map[string]struct{}{},
+		contactPoints:      map[int64]map[string]struct{}{},
+		templates:          map[int64]map[string]struct{}{},
+		muteTimes:          map[int64]map[string]struct{}{},
+		policies:           map[int64]struct{}{},
+		maintenanceWindows: map[int64]map[string]struct{}{},
+	}
+}
+
+func track(objects map[int64]map[string]struct{}, orgID int64, id string) {
+	if _, ok := objects[orgID]; !ok {
+		objects[orgID] = map[string]struct{}{}
+	}
+	objects[orgID][id] = struct{}{}
+}
+
+func isTracked(objects map[int64]map[string]struct{}, orgID int64, id string) bool {
+	_, ok := objects[orgID][id]
+	return ok
+}
+
+func (ap *AlertingProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	files, err := ap.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	provisioned := newProvisionedObjects()
+
+	// Templates and mute timings are referenced by contact points and policies, which are in turn
+	// referenced by rules, so every kind of object is applied for all files before the next one.
+	for _, file := range files {
+		if err := ap.provisionTemplates(ctx, file.Templates, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	for _, file := range files {
+		if err := ap.provisionMuteTimes(ctx, file.MuteTimes, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	for _, file := range files {
+		if err := ap.provisionContactPoints(ctx, file.ContactPoints, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	for _, file := range files {
+		if err := ap.provisionPolicies(ctx, file.Policies, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	for _, file := range files {
+		if err := ap.provisionRuleGroups(ctx, file.Groups, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	for _, file := range files {
+		if err := ap.provisionMaintenanceWindows(ctx, file.MaintenanceWindows, provisioned); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+
+	return ap.removeStaleObjects(ctx, provisioned)
+}
+
+func (ap *AlertingProvisioner) provisionTemplates(ctx context.Context, templates []template, provisioned *provisionedObjects) error {
+	for _, tmpl := range templates {
+		ap.log.Debug("Provisioning template", "org", tmpl.OrgID, "name", tmpl.Data.Name)
+		if _, err := ap.cfg.TemplateService.SetTemplate(ctx, tmpl.OrgID, tmpl.Data); err != nil {
+			return fmt.Errorf("failed to provision template '%s': %w", tmpl.Data.Name, err)
+		}
+		track(provisioned.templates, tmpl.OrgID, tmpl.Data.Name)
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionMuteTimes(ctx context.Context, muteTimes []muteTime, provisioned *provisionedObjects) error {
+	existing := map[int64]map[string]struct{}{}
+	for _, mt := range muteTimes {
+		if _, ok := existing[mt.OrgID]; !ok {
+			intervals, err := ap.cfg.MuteTimingService.GetMuteTimings(ctx, mt.OrgID)
+			if err != nil {
+				return err
+			}
+			existing[mt.OrgID] = map[string]struct{}{}
+			for _, interval := range intervals {
+				existing[mt.OrgID][interval.Name] = struct{}{}
+			}
+		}
+
+		ap.log.Debug("Provisioning mute timing", "org", mt.OrgID, "name", mt.MuteTime.Name)
+		var err error
+		if isTracked(existing, mt.OrgID, mt.MuteTime.Name) {
+			_, err = ap.cfg.MuteTimingService.UpdateMuteTiming(ctx, mt.MuteTime, mt.OrgID)
+		} else {
+			_, err = ap.cfg.MuteTimingService.CreateMuteTiming(ctx, mt.MuteTime, mt.OrgID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to provision mute timing '%s': %w", mt.MuteTime.Name, err)
+		}
+		track(existing, mt.OrgID, mt.MuteTime.Name)
+		track(provisioned.muteTimes, mt.OrgID, mt.MuteTime.Name)
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionContactPoints(ctx context.Context, contactPoints []contactPoint, provisioned *provisionedObjects) error {
+	existing := map[int64]map[string]struct{}{}
+	for _, cp := range contactPoints {
+		if _, ok := existing[cp.OrgID]; !ok {
+			stored, err := ap.cfg.ContactPointService.GetContactPoints(ctx, cp.OrgID)
+			if err != nil {
+				return err
+			}
+			existing[cp.OrgID] = map[string]struct{}{}
+			for _, receiver := range stored {
+				existing[cp.OrgID][receiver.UID] = struct{}{}
+			}
+		}
+
+		for _, receiver := range cp.Receivers {
+			ap.log.Debug("Provisioning contact point", "org", cp.OrgID, "name", receiver.Name, "uid", receiver.UID)
+			var err error
+			if isTracked(existing, cp.OrgID, receiver.UID) {
+				err = ap.cfg.ContactPointService.UpdateContactPoint(ctx, cp.OrgID, receiver, ngmodels.ProvenanceFile)
+			} else {
+				_, err = ap.cfg.ContactPointService.CreateContactPoint(ctx, cp.OrgID, receiver, ngmodels.ProvenanceFile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to provision contact point '%s' (uid: %s): %w", receiver.Name, receiver.UID, err)
+			}
+			track(existing, cp.OrgID, receiver.UID)
+			track(provisioned.contactPoints, cp.OrgID, receiver.UID)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionPolicies(ctx context.Context, policies []notificationPolicy, provisioned *provisionedObjects) error {
+	for _, policy := range policies {
+		if _, ok := provisioned.policies[policy.OrgID]; ok {
+			return fmt.Errorf("notification policy tree of organization %d is provisioned more than once", policy.OrgID)
+		}
+		ap.log.Debug("Provisioning notification policy tree", "org", policy.OrgID)
+		if err := ap.cfg.PolicyService.UpdatePolicyTree(ctx, policy.OrgID, policy.Policy, ngmodels.ProvenanceFile); err != nil {
+			return fmt.Errorf("failed to provision notification policy tree of organization %d: %w", policy.OrgID, err)
+		}
+		provisioned.policies[policy.OrgID] = struct{}{}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionRuleGroups(ctx context.Context, groups []alertRuleGroup, provisioned *provisionedObjects) error {
+	for _, group := range groups {
+		folderUID, err := ap.getOrCreateFolderUID(ctx, group.Folder, group.OrgID)
+		if err != nil {
+			return fmt.Errorf("failed to provision folder '%s' for rule group '%s': %w", group.Folder, group.Name, err)
+		}
+
+		for _, rule := range group.Rules {
+			rule.NamespaceUID = folderUID
+			ap.log.Debug("Provisioning alert rule", "org", rule.OrgID, "group", group.Name, "uid", rule.UID)
+			_, _, err := ap.cfg.RuleService.GetAlertRule(ctx, rule.OrgID, rule.UID)
+			switch {
+			case errors.Is(err, provisioning.ErrNotFound):
+				_, err = ap.cfg.RuleService.CreateAlertRule(ctx, rule, ngmodels.ProvenanceFile)
+			case err == nil:
+				_, err = ap.cfg.RuleService.UpdateAlertRule(ctx, rule, ngmodels.ProvenanceFile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to provision alert rule '%s' (uid: %s): %w", rule.Title, rule.UID, err)
+			}
+			track(provisioned.rules, rule.OrgID, rule.UID)
+		}
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) provisionMaintenanceWindows(ctx context.Context, windows []maintenanceWindow, provisioned *provisionedObjects) error {
+	for _, mw := range windows {
+		window := mw.Window
+		if mw.Folder != "" {
+			folderUID, err := ap.getOrCreateFolderUID(ctx, mw.Folder, window.OrgID)
+			if err != nil {
+				return fmt.Errorf("failed to provision folder '%s' for maintenance window '%s': %w", mw.Folder, window.UID, err)
+			}
+			window.NamespaceUID = folderUID
+		}
+
+		ap.log.Debug("Provisioning maintenance window", "org", window.OrgID, "uid", window.UID)
+		_, _, err := ap.cfg.MaintenanceWindowService.GetMaintenanceWindow(ctx, window.OrgID, window.UID)
+		switch {
+		case errors.Is(err, provisioning.ErrNotFound):
+			_, err = ap.cfg.MaintenanceWindowService.CreateMaintenanceWindow(ctx, window, ngmodels.ProvenanceFile)
+		case err == nil:
+			_, err = ap.cfg.MaintenanceWindowService.UpdateMaintenanceWindow(ctx, window, ngmodels.ProvenanceFile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to provision maintenance window '%s' (uid: %s): %w", window.Title, window.UID, err)
+		}
+		track(provisioned.maintenanceWindows, window.OrgID, window.UID)
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) getOrCreateFolderUID(ctx context.Context, folderName string, orgID int64) (string, error) {
+	cmd := &models.GetDashboardQuery{Slug: models.SlugifyTitle(folderName), OrgId: orgID}
+	err := ap.cfg.DashboardStore.GetDashboard(ctx, cmd)
+	if err != nil && !errors.Is(err, models.ErrDashboardNotFound) {
+		return "", err
+	}
+
+	// folder not found. create one.
+	if errors.Is(err, models.ErrDashboardNotFound) {
+		dash := &dashboards.SaveDashboardDTO{}
+		dash.Dashboard = models.NewDashboardFolder(folderName)
+		dash.Dashboard.IsFolder = true
+		dash.Overwrite = true
+		dash.OrgId = orgID
+		dbDash, err := ap.cfg.DashboardService.SaveFolderForProvisionedDashboards(ctx, dash)
+		if err != nil {
+			return "", err
+		}
+		return dbDash.Uid, nil
+	}
+
+	if !cmd.Result.IsFolder {
+		return "", fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+
+	return cmd.Result.Uid, nil
+}
+
+// removeStaleObjects removes all objects that were provisioned from files in an earlier run but are not present anymore.
+// The notification policy tree of an organization cannot be removed, it is only unlocked for changes from other sources.
+func (ap *AlertingProvisioner) removeStaleObjects(ctx context.Context, provisioned *provisionedObjects) error {
+	query := &models.SearchOrgsQuery{}
+	if err := ap.cfg.OrgStore.SearchOrgs(ctx, query); err != nil {
+		return err
+	}
+
+	for _, org := range query.Result {
+		orgID := org.Id
+
+		ruleUIDs, err := ap.fileProvisioned(ctx, orgID, (&ngmodels.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, uid := range ruleUIDs {
+			if isTracked(provisioned.rules, orgID, uid) {
+				continue
+			}
+			ap.log.Info("Deleting alert rule that is no longer provisioned", "org", orgID, "uid", uid)
+			if err := ap.cfg.RuleService.DeleteAlertRule(ctx, orgID, uid, ngmodels.ProvenanceFile); err != nil {
+				return fmt.Errorf("failed to delete alert rule %s: %w", uid, err)
+			}
+		}
+
+		if _, ok := provisioned.policies[orgID]; !ok {
+			route := &definitions.Route{}
+			provenance, err := ap.cfg.ProvenanceStore.GetProvenance(ctx, route, orgID)
+			if err != nil {
+				return err
+			}
+			if provenance == ngmodels.ProvenanceFile {
+				ap.log.Info("Unlocking notification policy tree that is no longer provisioned", "org", orgID)
+				if err := ap.cfg.ProvenanceStore.DeleteProvenance(ctx, route, orgID); err != nil {
+					return err
+				}
+			}
+		}
+
+		contactPointUIDs, err := ap.fileProvisioned(ctx, orgID, (&definitions.EmbeddedContactPoint{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, uid := range contactPointUIDs {
+			if isTracked(provisioned.contactPoints, orgID, uid) {
+				continue
+			}
+			ap.log.Info("Deleting contact point that is no longer provisioned", "org", orgID, "uid", uid)
+			if err := ap.cfg.ContactPointService.DeleteContactPoint(ctx, orgID, uid); err != nil {
+				return fmt.Errorf("failed to delete contact point %s: %w", uid, err)
+			}
+		}
+
+		muteTimeNames, err := ap.fileProvisioned(ctx, orgID, (&definitions.MuteTimeInterval{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, name := range muteTimeNames {
+			if isTracked(provisioned.muteTimes, orgID, name) {
+				continue
+			}
+			ap.log.Info("Deleting mute timing that is no longer provisioned", "org", orgID, "name", name)
+			if err := ap.cfg.MuteTimingService.DeleteMuteTiming(ctx, name, orgID, ngmodels.ProvenanceFile); err != nil {
+				return fmt.Errorf("failed to delete mute timing '%s': %w", name, err)
+			}
+		}
+
+		templateNames, err := ap.fileProvisioned(ctx, orgID, (&definitions.MessageTemplate{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, name := range templateNames {
+			if isTracked(provisioned.templates, orgID, name) {
+				continue
+			}
+			ap.log.Info("Deleting template that is no longer provisioned", "org", orgID, "name", name)
+			if err := ap.cfg.TemplateService.DeleteTemplate(ctx, orgID, name, ngmodels.ProvenanceFile); err != nil {
+				return fmt.Errorf("failed to delete template '%s': %w", name, err)
+			}
+		}
+
+		windowUIDs, err := ap.fileProvisioned(ctx, orgID, (&ngmodels.MaintenanceWindow{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, uid := range windowUIDs {
+			if isTracked(provisioned.maintenanceWindows, orgID, uid) {
+				continue
+			}
+			ap.log.Info("Deleting maintenance window that is no longer provisioned", "org", orgID, "uid", uid)
+			if err := ap.cfg.MaintenanceWindowService.DeleteMaintenanceWindow(ctx, orgID, uid, ngmodels.ProvenanceFile); err != nil {
+				return fmt.Errorf("failed to delete maintenance window %s: %w", uid, err)
+			}
+		}
+	}
+	return nil
+}
+
+// fileProvisioned returns the identifiers of all objects of the given type that were provisioned from files.
+func (ap *AlertingProvisioner) fileProvisioned(ctx context.Context, orgID int64, resourceType string) ([]string, error) {
+	provenances, err := ap.cfg.ProvenanceStore.GetProvenances(ctx, orgID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(provenances))
+	for id, provenance := range provenances {
+		if provenance == ngmodels.ProvenanceFile {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,296 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertingProvisioner(t *testing.T) {
+	t.Run("applies all objects with file provenance", func(t *testing.T) {
+		sut, fakes := createProvisionerSut(t)
+
+		err := sut.applyChanges(context.Background(), correctProperties)
+		require.NoError(t, err)
+
+		rule, ok := fakes.rules.rules["my-rule"]
+		require.True(t, ok)
+		require.Equal(t, "folder-uid", rule.NamespaceUID)
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &rule))
+
+		cp, ok := fakes.contactPoints.contactPoints["my-receiver"]
+		require.True(t, ok)
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &cp))
+
+		require.NotNil(t, fakes.policies.tree)
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &definitions.Route{}))
+
+		require.Contains(t, fakes.templates.templates, "my-template")
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &definitions.MessageTemplate{Name: "my-template"}))
+
+		mt, ok := fakes.muteTimings.muteTimings["weekends"]
+		require.True(t, ok)
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &mt))
+
+		window, ok := fakes.maintenanceWindows.windows["my-maintenance"]
+		require.True(t, ok)
+		require.Equal(t, "folder-uid", window.NamespaceUID)
+		require.Equal(t, ngmodels.ProvenanceFile, fakes.provenance(t, &window))
+	})
+
+	t.Run("updates objects that already exist", func(t *testing.T) {
+		sut, fakes := createProvisionerSut(t)
+
+		require.NoError(t, sut.applyChanges(context.Background(), correctProperties))
+		require.NoError(t, sut.applyChanges(context.Background(), correctProperties))
+
+		require.Equal(t, 2, fakes.rules.created)
+		require.Equal(t, 2, fakes.rules.updated)
+		require.Equal(t, 1, fakes.contactPoints.created)
+		require.Equal(t, 1, fakes.contactPoints.updated)
+		require.Equal(t, 1, fakes.muteTimings.created)
+		require.Equal(t, 1, fakes.muteTimings.updated)
+		require.Equal(t, 2, fakes.maintenanceWindows.created)
+		require.Equal(t, 2, fakes.maintenanceWindows.updated)
+	})
+
+	t.Run("removes objects that are no longer provisioned", func(t *testing.T) {
+		sut, fakes := createProvisionerSut(t)
+		require.NoError(t, sut.applyChanges(context.Background(), correctProperties))
+
+		// an object created through the API must survive
+		apiTemplate := definitions.MessageTemplate{Name: "api-template", Template: "template", Provenance: ngmodels.ProvenanceAPI}
+		_, err := fakes.templates.SetTemplate(context.Background(), 1, apiTemplate)
+		require.NoError(t, err)
+
+		err = sut.applyChanges(context.Background(), "./testdata/test-configs/does-not-exist")
+		require.NoError(t, err)
+
+		require.Empty(t, fakes.rules.rules)
+		require.Empty(t, fakes.contactPoints.contactPoints)
+		require.Empty(t, fakes.muteTimings.muteTimings)
+		require.Empty(t, fakes.maintenanceWindows.windows)
+		require.NotContains(t, fakes.templates.templates, "my-template")
+		require.Contains(t, fakes.templates.templates, "api-template")
+		// the policy tree stays, but is not locked anymore
+		require.NotNil(t, fakes.policies.tree)
+		require.Equal(t, ngmodels.ProvenanceNone, fakes.provenance(t, &definitions.Route{}))
+	})
+}
+
+type provisionerFakes struct {
+	prov               provisioning.ProvisioningStore
+	rules              *fakeRuleService
+	contactPoints      *fakeContactPointService
+	policies           *fakePolicyService
+	templates          *fakeTemplateService
+	muteTimings        *fakeMuteTimingService
+	maintenanceWindows *fakeMaintenanceWindowService
+}
+
+func (f *provisionerFakes) provenance(t *testing.T, o ngmodels.Provisionable) ngmodels.Provenance {
+	t.Helper()
+	p, err := f.prov.GetProvenance(context.Background(), o, 1)
+	require.NoError(t, err)
+	return p
+}
+
+func createProvisionerSut(t *testing.T) (AlertingProvisioner, *provisionerFakes) {
+	t.Helper()
+	prov := provisioning.NewFakeProvisioningStore()
+	fakes := &provisionerFakes{
+		prov:               prov,
+		rules:              &fakeRuleService{prov: prov, rules: map[string]ngmodels.AlertRule{}},
+		contactPoints:      &fakeContactPointService{prov: prov, contactPoints: map[string]definitions.EmbeddedContactPoint{}},
+		policies:           &fakePolicyService{prov: prov},
+		templates:          &fakeTemplateService{prov: prov, templates: map[string]string{}},
+		muteTimings:        &fakeMuteTimingService{prov: prov, muteTimings: map[string]definitions.MuteTimeInterval{}},
+		maintenanceWindows: &fakeMaintenanceWindowService{prov: prov, windows: map[string]ngmodels.MaintenanceWindow{}},
+	}
+
+	dashboardService := &dashboards.FakeDashboardProvisioning{}
+	dashboardService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+		Return(&models.Dashboard{Uid: "folder-uid", IsFolder: true}, nil)
+
+	cfg := ProvisionerConfig{
+		OrgStore:                 newFakeOrgStore(1),
+		DashboardStore:           &fakeDashboardStore{},
+		DashboardService:         dashboardService,
+		ProvenanceStore:          prov,
+		RuleService:              fakes.rules,
+		ContactPointService:      fakes.contactPoints,
+		PolicyService:            fakes.policies,
+		TemplateService:          fakes.templates,
+		MuteTimingService:        fakes.muteTimings,
+		MaintenanceWindowService: fakes.maintenanceWindows,
+	}
+	return newAlertingProvisioner(cfg, log.New("test logger")), fakes
+}
+
+type fakeDashboardStore struct{}
+
+func (f *fakeDashboardStore) GetDashboard(_ context.Context, _ *models.GetDashboardQuery) error {
+	return models.ErrDashboardNotFound
+}
+
+type fakeRuleService struct {
+	prov             provisioning.ProvisioningStore
+	rules            map[string]ngmodels.AlertRule
+	created, updated int
+}
+
+func (f *fakeRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (ngmodels.AlertRule, ngmodels.Provenance, error) {
+	rule, ok := f.rules[ruleUID]
+	if !ok {
+		return ngmodels.AlertRule{}, ngmodels.ProvenanceNone, provisioning.ErrNotFound
+	}
+	p, err := f.prov.GetProvenance(ctx, &rule, orgID)
+	return rule, p, err
+}
+
+func (f *fakeRuleService) CreateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error) {
+	f.created++
+	f.rules[rule.UID] = rule
+	return rule, f.prov.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+}
+
+func (f *fakeRuleService) UpdateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error) {
+	f.updated++
+	f.rules[rule.UID] = rule
+	return rule, f.prov.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+}
+
+func (f *fakeRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, _ ngmodels.Provenance) error {
+	rule := f.rules[ruleUID]
+	delete(f.rules, ruleUID)
+	return f.prov.DeleteProvenance(ctx, &rule, orgID)
+}
+
+type fakeContactPointService struct {
+	prov             provisioning.ProvisioningStore
+	contactPoints    map[string]definitions.EmbeddedContactPoint
+	created, updated int
+}
+
+func (f *fakeContactPointService) GetContactPoints(_ context.Context, _ int64) ([]definitions.EmbeddedContactPoint, error) {
+	result := make([]definitions.EmbeddedContactPoint, 0, len(f.contactPoints))
+	for _, cp := range f.contactPoints {
+		result = append(result, cp)
+	}
+	return result, nil
+}
+
+func (f *fakeContactPointService) CreateContactPoint(ctx context.Context, orgID int64, cp definitions.EmbeddedContactPoint, provenance ngmodels.Provenance) (definitions.EmbeddedContactPoint, error) {
+	f.created++
+	f.contactPoints[cp.UID] = cp
+	return cp, f.prov.SetProvenance(ctx, &cp, orgID, provenance)
+}
+
+func (f *fakeContactPointService) UpdateContactPoint(ctx context.Context, orgID int64, cp definitions.EmbeddedContactPoint, provenance ngmodels.Provenance) error {
+	f.updated++
+	f.contactPoints[cp.UID] = cp
+	return f.prov.SetProvenance(ctx, &cp, orgID, provenance)
+}
+
+func (f *fakeContactPointService) DeleteContactPoint(ctx context.Context, orgID int64, uid string) error {
+	delete(f.contactPoints, uid)
+	return f.prov.DeleteProvenance(ctx, &definitions.EmbeddedContactPoint{UID: uid}, orgID)
+}
+
+type fakePolicyService struct {
+	prov provisioning.ProvisioningStore
+	tree *definitions.Route
+}
+
+func (f *fakePolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p ngmodels.Provenance) error {
+	f.tree = &tree
+	return f.prov.SetProvenance(ctx, &tree, orgID, p)
+}
+
+type fakeTemplateService struct {
+	prov      provisioning.ProvisioningStore
+	templates map[string]string
+}
+
+func (f *fakeTemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.MessageTemplate) (definitions.MessageTemplate, error) {
+	f.templates[tmpl.Name] = tmpl.Template
+	return tmpl, f.prov.SetProvenance(ctx, &tmpl, orgID, tmpl.Provenance)
+}
+
+func (f *fakeTemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string, _ ngmodels.Provenance) error {
+	delete(f.templates, name)
+	return f.prov.DeleteProvenance(ctx, &definitions.MessageTemplate{Name: name}, orgID)
+}
+
+type fakeMuteTimingService struct {
+	prov             provisioning.ProvisioningStore
+	muteTimings      map[string]definitions.MuteTimeInterval
+	created, updated int
+}
+
+func (f *fakeMuteTimingService) GetMuteTimings(_ context.Context, _ int64) ([]definitions.MuteTimeInterval, error) {
+	result := make([]definitions.MuteTimeInterval, 0, len(f.muteTimings))
+	for _, mt := range f.muteTimings {
+		result = append(result, mt)
+	}
+	return result, nil
+}
+
+func (f *fakeMuteTimingService) CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error) {
+	f.created++
+	f.muteTimings[mt.Name] = mt
+	return mt, f.prov.SetProvenance(ctx, &mt, orgID, mt.Provenance)
+}
+
+func (f *fakeMuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (definitions.MuteTimeInterval, error) {
+	f.updated++
+	f.muteTimings[mt.Name] = mt
+	return mt, f.prov.SetProvenance(ctx, &mt, orgID, mt.Provenance)
+}
+
+func (f *fakeMuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64, _ ngmodels.Provenance) error {
+	mt := definitions.MuteTimeInterval{}
+	mt.Name = name
+	delete(f.muteTimings, name)
+	return f.prov.DeleteProvenance(ctx, &mt, orgID)
+}
+
+type fakeMaintenanceWindowService struct {
+	prov             provisioning.ProvisioningStore
+	windows          map[string]ngmodels.MaintenanceWindow
+	created, updated int
+}
+
+func (f *fakeMaintenanceWindowService) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (ngmodels.MaintenanceWindow, ngmodels.Provenance, error) {
+	window, ok := f.windows[uid]
+	if !ok {
+		return ngmodels.MaintenanceWindow{}, ngmodels.ProvenanceNone, provisioning.ErrNotFound
+	}
+	p, err := f.prov.GetProvenance(ctx, &window, orgID)
+	return window, p, err
+}
+
+func (f *fakeMaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, window ngmodels.MaintenanceWindow, provenance ngmodels.Provenance) (ngmodels.MaintenanceWindow, error) {
+	f.created++
+	f.windows[window.UID] = window
+	return window, f.prov.SetProvenance(ctx, &window, window.OrgID, provenance)
+}
+
+func (f *fakeMaintenanceWindowService) UpdateMaintenanceWindow(ctx context.Context, window ngmodels.MaintenanceWindow, provenance ngmodels.Provenance) (ngmodels.MaintenanceWindow, error) {
+	f.updated++
+	f.windows[window.UID] = window
+	return window, f.prov.SetProvenance(ctx, &window, window.OrgID, provenance)
+}
+
+func (f *fakeMaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, _ ngmodels.Provenance) error {
+	delete(f.windows, uid)
+	return f.prov.DeleteProvenance(ctx, &ngmodels.MaintenanceWindow{UID: uid}, orgID)
+}
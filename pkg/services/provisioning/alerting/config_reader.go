@@ -0,0 +1,111 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	orgStore utils.OrgStore
+	log      log.Logger
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*alertingFile, error) {
+	var alertFiles []*alertingFile
+	cr.log.Debug("Looking for alerting provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read alerting provisioning files from directory", "path", path, "error", err)
+		return alertFiles, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing alerting provisioning file", "path", path, "file.Name", file.Name())
+			alertFile, err := cr.parseConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if alertFile != nil {
+				alertFiles = append(alertFiles, alertFile)
+			}
+		}
+	}
+
+	if err := cr.checkOrgsExist(ctx, alertFiles); err != nil {
+		return nil, err
+	}
+
+	return alertFiles, nil
+}
+
+func (cr *configReader) parseConfig(path string, file os.FileInfo) (*alertingFile, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion == nil || apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("%s: unsupported alerting provisioning apiVersion, expected 1", filename)
+	}
+
+	var v1 *alertingFileV1
+	err = yaml.Unmarshal(yamlFile, &v1)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.mapToModel(filename)
+}
+
+func (cr *configReader) checkOrgsExist(ctx context.Context, alertFiles []*alertingFile) error {
+	orgIDs := map[int64]struct{}{}
+	for _, file := range alertFiles {
+		for _, group := range file.Groups {
+			orgIDs[group.OrgID] = struct{}{}
+		}
+		for _, cp := range file.ContactPoints {
+			orgIDs[cp.OrgID] = struct{}{}
+		}
+		for _, policy := range file.Policies {
+			orgIDs[policy.OrgID] = struct{}{}
+		}
+		for _, tmpl := range file.Templates {
+			orgIDs[tmpl.OrgID] = struct{}{}
+		}
+		for _, mt := range file.MuteTimes {
+			orgIDs[mt.OrgID] = struct{}{}
+		}
+		for _, mw := range file.MaintenanceWindows {
+			orgIDs[mw.Window.OrgID] = struct{}{}
+		}
+	}
+
+	for orgID := range orgIDs {
+		if err := utils.CheckOrgExists(ctx, cr.orgStore, orgID); err != nil {
+			return fmt.Errorf("failed to provision alerting resources of organization %d: %w", orgID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	correctProperties  = "./testdata/test-configs/correct-properties"
+	missingRuleUID     = "./testdata/test-configs/missing-rule-uid"
+	unsupportedVersion = "./testdata/test-configs/unsupported-version"
+	unknownOrg         = "./testdata/test-configs/unknown-org"
+)
+
+func TestAlertingConfigReader(t *testing.T) {
+	t.Run("can read correct properties", func(t *testing.T) {
+		_ = os.Setenv("TEST_TEAM", "ops")
+		defer func() { _ = os.Unsetenv("TEST_TEAM") }()
+		cfgProvider := &configReader{orgStore: newFakeOrgStore(1), log: log.New("test logger")}
+
+		files, err := cfgProvider.readConfig(context.Background(), correctProperties)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		file := files[0]
+
+		require.Len(t, file.Groups, 1)
+		group := file.Groups[0]
+		require.Equal(t, "my-group", group.Name)
+		require.Equal(t, "my-folder", group.Folder)
+		require.Equal(t, time.Minute, group.Interval)
+		require.Len(t, group.Rules, 2)
+		rule := group.Rules[0]
+		require.Equal(t, "my-rule", rule.UID)
+		require.Equal(t, "My rule", rule.Title)
+		require.Equal(t, "my-group", rule.RuleGroup)
+		require.Equal(t, int64(60), rule.IntervalSeconds)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, ngmodels.OK, rule.NoDataState)
+		require.Equal(t, ngmodels.ErrorErrState, rule.ExecErrState)
+		require.Equal(t, map[string]string{"team": "ops"}, rule.Labels)
+		require.Equal(t, "{{ $labels.instance }} is down", rule.Annotations["summary"])
+		require.Equal(t, []ngmodels.AlertRuleDependency{{RuleUID: "dc-down", Equal: []string{"dc"}}}, rule.Dependencies)
+		require.Len(t, rule.Data, 2)
+		require.Equal(t, ngmodels.Duration(10*time.Minute), rule.Data[0].RelativeTimeRange.From)
+		model := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(rule.Data[0].Model, &model))
+		require.Equal(t, "rate(up[$__rate_interval])", model["expr"])
+		require.False(t, rule.IsRecordingRule())
+		require.Equal(t, &ngmodels.Record{Metric: "instance:up:rate", Target: ngmodels.RecordTargetRemoteWrite}, group.Rules[1].Record)
+		require.False(t, rule.IsPaused)
+		require.True(t, group.Rules[1].IsPaused)
+
+		require.Len(t, file.ContactPoints, 1)
+		require.Len(t, file.ContactPoints[0].Receivers, 1)
+		receiver := file.ContactPoints[0].Receivers[0]
+		require.Equal(t, "my-receiver", receiver.UID)
+		require.Equal(t, "my-contact-point", receiver.Name)
+		require.True(t, receiver.DisableResolveMessage)
+		require.Equal(t, "ops@example.com", receiver.Settings.Get("addresses").MustString())
+
+		require.Len(t, file.Policies, 1)
+		require.Equal(t, "my-contact-point", file.Policies[0].Policy.Receiver)
+		require.Len(t, file.Policies[0].Policy.Routes, 1)
+
+		require.Len(t, file.Templates, 1)
+		require.Equal(t, `{{ define "my-template" }}{{ $value }}{{ end }}`, file.Templates[0].Data.Template)
+		require.Equal(t, ngmodels.ProvenanceFile, file.Templates[0].Data.Provenance)
+
+		require.Len(t, file.MuteTimes, 1)
+		require.Equal(t, int64(1), file.MuteTimes[0].OrgID)
+		require.Equal(t, "weekends", file.MuteTimes[0].MuteTime.Name)
+		require.Len(t, file.MuteTimes[0].MuteTime.TimeIntervals, 1)
+
+		require.Len(t, file.MaintenanceWindows, 2)
+		folderWindow := file.MaintenanceWindows[0]
+		require.Equal(t, "my-folder", folderWindow.Folder)
+		require.Equal(t, "my-maintenance", folderWindow.Window.UID)
+		require.Equal(t, []ngmodels.MaintenanceTimeRange{{
+			Start: time.Date(2022, 6, 1, 22, 0, 0, 0, time.UTC),
+			End:   time.Date(2022, 6, 2, 2, 0, 0, 0, time.UTC),
+		}}, folderWindow.Window.Ranges)
+		ruleWindow := file.MaintenanceWindows[1]
+		require.Equal(t, int64(1), ruleWindow.Window.OrgID)
+		require.Equal(t, "my-rule", ruleWindow.Window.RuleUID)
+		require.Len(t, ruleWindow.Window.TimeIntervals, 1)
+	})
+
+	t.Run("rules without uid are rejected", func(t *testing.T) {
+		cfgProvider := &configReader{orgStore: newFakeOrgStore(1), log: log.New("test logger")}
+
+		_, err := cfgProvider.readConfig(context.Background(), missingRuleUID)
+		require.ErrorContains(t, err, "has no uid")
+	})
+
+	t.Run("unsupported api versions are rejected", func(t *testing.T) {
+		cfgProvider := &configReader{orgStore: newFakeOrgStore(1), log: log.New("test logger")}
+
+		_, err := cfgProvider.readConfig(context.Background(), unsupportedVersion)
+		require.ErrorContains(t, err, "unsupported alerting provisioning apiVersion")
+	})
+
+	t.Run("unknown organizations are rejected", func(t *testing.T) {
+		cfgProvider := &configReader{orgStore: newFakeOrgStore(1), log: log.New("test logger")}
+
+		_, err := cfgProvider.readConfig(context.Background(), unknownOrg)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+
+	t.Run("missing directory is not an error", func(t *testing.T) {
+		cfgProvider := &configReader{orgStore: newFakeOrgStore(1), log: log.New("test logger")}
+
+		files, err := cfgProvider.readConfig(context.Background(), "./testdata/test-configs/does-not-exist")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+}
+
+type fakeOrgStore struct {
+	orgs []int64
+}
+
+func newFakeOrgStore(orgs ...int64) *fakeOrgStore {
+	return &fakeOrgStore{orgs: orgs}
+}
+
+func (f *fakeOrgStore) GetOrgById(_ context.Context, query *models.GetOrgByIdQuery) error {
+	for _, id := range f.orgs {
+		if id == query.Id {
+			query.Result = &models.Org{Id: id}
+			return nil
+		}
+	}
+	return models.ErrOrgNotFound
+}
+
+func (f *fakeOrgStore) SearchOrgs(_ context.Context, query *models.SearchOrgsQuery) error {
+	query.Result = make([]*models.OrgDTO, 0, len(f.orgs))
+	for _, id := range f.orgs {
+		query.Result = append(query.Result, &models.OrgDTO{Id: id})
+	}
+	return nil
+}
@@ -0,0 +1,468 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	prommodel "github.com/prometheus/common/model"
+)
+
+// alertingFile is normalized data object for alerting config data. Any config version should be mappable
+// to this type.
+type alertingFile struct {
+	Filename           string
+	Groups             []alertRuleGroup
+	ContactPoints      []contactPoint
+	Policies           []notificationPolicy
+	Templates          []template
+	MuteTimes          []muteTime
+	MaintenanceWindows []maintenanceWindow
+}
+
+type alertRuleGroup struct {
+	OrgID    int64
+	Name     string
+	Folder   string
+	Interval time.Duration
+	Rules    []models.AlertRule
+}
+
+type contactPoint struct {
+	OrgID     int64
+	Name      string
+	Receivers []definitions.EmbeddedContactPoint
+}
+
+type notificationPolicy struct {
+	OrgID  int64
+	Policy definitions.Route
+}
+
+type template struct {
+	OrgID int64
+	Data  definitions.MessageTemplate
+}
+
+type muteTime struct {
+	OrgID    int64
+	MuteTime definitions.MuteTimeInterval
+}
+
+type maintenanceWindow struct {
+	// Folder is the title of the folder whose rules are paused, it is resolved to NamespaceUID when provisioning.
+	Folder string
+	Window models.MaintenanceWindow
+}
+
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// alertingFileV1 is mapping for the first version of the alerting config files. This is mapped to its normalised version.
+type alertingFileV1 struct {
+	configVersion
+	Groups             []alertRuleGroupV1     `json:"groups" yaml:"groups"`
+	ContactPoints      []contactPointV1       `json:"contactPoints" yaml:"contactPoints"`
+	Policies           []notificationPolicyV1 `json:"policies" yaml:"policies"`
+	Templates          []templateV1           `json:"templates" yaml:"templates"`
+	MuteTimes          []muteTimeV1           `json:"muteTimes" yaml:"muteTimes"`
+	MaintenanceWindows []maintenanceWindowV1  `json:"maintenanceWindows" yaml:"maintenanceWindows"`
+}
+
+type alertRuleGroupV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Folder   values.StringValue `json:"folder" yaml:"folder"`
+	Interval values.StringValue `json:"interval" yaml:"interval"`
+	Rules    []alertRuleV1      `json:"rules" yaml:"rules"`
+}
+
+type alertRuleV1 struct {
+	UID          values.StringValue    `json:"uid" yaml:"uid"`
+	Title        values.StringValue    `json:"title" yaml:"title"`
+	Condition    values.StringValue    `json:"condition" yaml:"condition"`
+	Data         []queryV1             `json:"data" yaml:"data"`
+	DashboardUID values.StringValue    `json:"dashboardUid" yaml:"dashboardUid"`
+	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For          values.StringValue    `json:"for" yaml:"for"`
+	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
+	// Annotations are not interpolated, they usually contain templates referencing $labels and $values.
+	Annotations  map[string]string `json:"annotations" yaml:"annotations"`
+	Dependencies []dependencyV1    `json:"dependencies" yaml:"dependencies"`
+	Record       *recordV1         `json:"record" yaml:"record"`
+	IsPaused     values.BoolValue  `json:"isPaused" yaml:"isPaused"`
+}
+
+type recordV1 struct {
+	Metric values.StringValue `json:"metric" yaml:"metric"`
+	Target values.StringValue `json:"target" yaml:"target"`
+}
+
+type dependencyV1 struct {
+	RuleUID  values.StringValue   `json:"ruleUid" yaml:"ruleUid"`
+	Matchers []values.StringValue `json:"matchers" yaml:"matchers"`
+	Equal    []values.StringValue `json:"equal" yaml:"equal"`
+}
+
+type queryV1 struct {
+	RefID             values.StringValue  `json:"refId" yaml:"refId"`
+	QueryType         values.StringValue  `json:"queryType" yaml:"queryType"`
+	RelativeTimeRange relativeTimeRangeV1 `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     values.StringValue  `json:"datasourceUid" yaml:"datasourceUid"`
+	// Model is not interpolated, queries often contain variables such as $__interval.
+	Model queryModel `json:"model" yaml:"model"`
+}
+
+type relativeTimeRangeV1 struct {
+	From values.Int64Value `json:"from" yaml:"from"`
+	To   values.Int64Value `json:"to" yaml:"to"`
+}
+
+// queryModel holds the raw JSON of a query model defined in YAML.
+type queryModel struct {
+	raw json.RawMessage
+}
+
+func (m *queryModel) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var model interface{}
+	if err := unmarshal(&model); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(convertYAMLValue(model))
+	if err != nil {
+		return err
+	}
+	m.raw = raw
+	return nil
+}
+
+// convertYAMLValue converts the map[interface{}]interface{} produced by YAML into map[string]interface{},
+// so the value can be marshaled into JSON.
+func convertYAMLValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[fmt.Sprint(k)] = convertYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			result = append(result, convertYAMLValue(item))
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+type contactPointV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name      values.StringValue `json:"name" yaml:"name"`
+	Receivers []receiverV1       `json:"receivers" yaml:"receivers"`
+}
+
+type receiverV1 struct {
+	UID                   values.StringValue `json:"uid" yaml:"uid"`
+	Type                  values.StringValue `json:"type" yaml:"type"`
+	Settings              values.JSONValue   `json:"settings" yaml:"settings"`
+	DisableResolveMessage values.BoolValue   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+}
+
+type notificationPolicyV1 struct {
+	OrgID  values.Int64Value `json:"orgId" yaml:"orgId"`
+	Policy definitions.Route `json:",inline" yaml:",inline"`
+}
+
+type templateV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+	// Template is not interpolated, as the template language uses $ for its own variables.
+	Template string `json:"template" yaml:"template"`
+}
+
+type muteTimeV1 struct {
+	OrgID    values.Int64Value       `json:"orgId" yaml:"orgId"`
+	MuteTime config.MuteTimeInterval `json:",inline" yaml:",inline"`
+}
+
+type maintenanceWindowV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID     values.StringValue `json:"uid" yaml:"uid"`
+	Title   values.StringValue `json:"title" yaml:"title"`
+	Folder  values.StringValue `json:"folder" yaml:"folder"`
+	RuleUID values.StringValue `json:"ruleUid" yaml:"ruleUid"`
+	Ranges  []timeRangeV1      `json:"ranges" yaml:"ranges"`
+	// TimeIntervals use the syntax of the time intervals of mute timings.
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals" yaml:"time_intervals"`
+}
+
+type timeRangeV1 struct {
+	Start values.StringValue `json:"start" yaml:"start"`
+	End   values.StringValue `json:"end" yaml:"end"`
+}
+
+// mapToModel maps config syntax to normalized alertingFile object.
+func (fileV1 *alertingFileV1) mapToModel(filename string) (*alertingFile, error) {
+	file := &alertingFile{Filename: filename}
+
+	for _, groupV1 := range fileV1.Groups {
+		group, err := groupV1.mapToModel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		file.Groups = append(file.Groups, group)
+	}
+
+	for _, cpV1 := range fileV1.ContactPoints {
+		cp, err := cpV1.mapToModel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		file.ContactPoints = append(file.ContactPoints, cp)
+	}
+
+	for _, policyV1 := range fileV1.Policies {
+		file.Policies = append(file.Policies, notificationPolicy{
+			OrgID:  orgIDOrDefault(policyV1.OrgID),
+			Policy: policyV1.Policy,
+		})
+	}
+
+	for _, templateV1 := range fileV1.Templates {
+		file.Templates = append(file.Templates, template{
+			OrgID: orgIDOrDefault(templateV1.OrgID),
+			Data: definitions.MessageTemplate{
+				Name:       templateV1.Name.Value(),
+				Template:   templateV1.Template,
+				Provenance: models.ProvenanceFile,
+			},
+		})
+	}
+
+	for _, muteTimeV1 := range fileV1.MuteTimes {
+		file.MuteTimes = append(file.MuteTimes, muteTime{
+			OrgID: orgIDOrDefault(muteTimeV1.OrgID),
+			MuteTime: definitions.MuteTimeInterval{
+				MuteTimeInterval: muteTimeV1.MuteTime,
+				Provenance:       models.ProvenanceFile,
+			},
+		})
+	}
+
+	for _, windowV1 := range fileV1.MaintenanceWindows {
+		window, err := windowV1.mapToModel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		file.MaintenanceWindows = append(file.MaintenanceWindows, window)
+	}
+
+	return file, nil
+}
+
+func (groupV1 *alertRuleGroupV1) mapToModel() (alertRuleGroup, error) {
+	group := alertRuleGroup{
+		OrgID:  orgIDOrDefault(groupV1.OrgID),
+		Name:   groupV1.Name.Value(),
+		Folder: groupV1.Folder.Value(),
+	}
+	if group.Name == "" {
+		return alertRuleGroup{}, fmt.Errorf("rule group has no name")
+	}
+	if group.Folder == "" {
+		return alertRuleGroup{}, fmt.Errorf("rule group '%s' has no folder", group.Name)
+	}
+	if interval := groupV1.Interval.Value(); interval != "" {
+		parsed, err := prommodel.ParseDuration(interval)
+		if err != nil {
+			return alertRuleGroup{}, fmt.Errorf("rule group '%s' has an invalid interval: %w", group.Name, err)
+		}
+		group.Interval = time.Duration(parsed)
+	}
+	for _, ruleV1 := range groupV1.Rules {
+		rule, err := ruleV1.mapToModel(group.OrgID)
+		if err != nil {
+			return alertRuleGroup{}, fmt.Errorf("rule group '%s': %w", group.Name, err)
+		}
+		rule.RuleGroup = group.Name
+		rule.IntervalSeconds = int64(group.Interval.Seconds())
+		group.Rules = append(group.Rules, rule)
+	}
+	return group, nil
+}
+
+func (ruleV1 *alertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
+	rule := models.AlertRule{
+		OrgID:       orgID,
+		UID:         ruleV1.UID.Value(),
+		Title:       ruleV1.Title.Value(),
+		Condition:   ruleV1.Condition.Value(),
+		Labels:      ruleV1.Labels.Value(),
+		Annotations: ruleV1.Annotations,
+		IsPaused:    ruleV1.IsPaused.Value(),
+	}
+	if rule.UID == "" {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' has no uid", rule.Title)
+	}
+	if rule.Title == "" {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' has no title", rule.UID)
+	}
+	if rule.Condition == "" {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' has no condition", rule.UID)
+	}
+	if len(ruleV1.Data) == 0 {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' has no queries or expressions", rule.UID)
+	}
+
+	if dashboardUID := ruleV1.DashboardUID.Value(); dashboardUID != "" {
+		rule.DashboardUID = &dashboardUID
+		if panelID := ruleV1.PanelID.Value(); panelID != 0 {
+			rule.PanelID = &panelID
+		}
+	}
+
+	rule.NoDataState = models.NoData
+	if state := ruleV1.NoDataState.Value(); state != "" {
+		noDataState, err := models.NoDataStateFromString(state)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s': %w", rule.UID, err)
+		}
+		rule.NoDataState = noDataState
+	}
+
+	rule.ExecErrState = models.AlertingErrState
+	if state := ruleV1.ExecErrState.Value(); state != "" {
+		errState, err := models.ErrStateFromString(state)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s': %w", rule.UID, err)
+		}
+		rule.ExecErrState = errState
+	}
+
+	if forDuration := ruleV1.For.Value(); forDuration != "" {
+		parsed, err := prommodel.ParseDuration(forDuration)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' has an invalid 'for' duration: %w", rule.UID, err)
+		}
+		rule.For = time.Duration(parsed)
+	}
+
+	for _, queryV1 := range ruleV1.Data {
+		rule.Data = append(rule.Data, models.AlertQuery{
+			RefID:         queryV1.RefID.Value(),
+			QueryType:     queryV1.QueryType.Value(),
+			DatasourceUID: queryV1.DatasourceUID.Value(),
+			RelativeTimeRange: models.RelativeTimeRange{
+				From: models.Duration(time.Duration(queryV1.RelativeTimeRange.From.Value()) * time.Second),
+				To:   models.Duration(time.Duration(queryV1.RelativeTimeRange.To.Value()) * time.Second),
+			},
+			Model: queryV1.Model.raw,
+		})
+	}
+
+	for _, dependencyV1 := range ruleV1.Dependencies {
+		dependency := models.AlertRuleDependency{
+			RuleUID: dependencyV1.RuleUID.Value(),
+		}
+		for _, m := range dependencyV1.Matchers {
+			dependency.Matchers = append(dependency.Matchers, m.Value())
+		}
+		for _, e := range dependencyV1.Equal {
+			dependency.Equal = append(dependency.Equal, e.Value())
+		}
+		if err := dependency.Validate(); err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' has an invalid dependency: %w", rule.UID, err)
+		}
+		rule.Dependencies = append(rule.Dependencies, dependency)
+	}
+
+	if ruleV1.Record != nil {
+		rule.Record = &models.Record{
+			Metric: ruleV1.Record.Metric.Value(),
+			Target: models.RecordTarget(ruleV1.Record.Target.Value()),
+		}
+		if err := rule.Record.Validate(); err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' has an invalid record: %w", rule.UID, err)
+		}
+	}
+
+	return rule, nil
+}
+
+func (cpV1 *contactPointV1) mapToModel() (contactPoint, error) {
+	cp := contactPoint{
+		OrgID: orgIDOrDefault(cpV1.OrgID),
+		Name:  cpV1.Name.Value(),
+	}
+	if cp.Name == "" {
+		return contactPoint{}, fmt.Errorf("contact point has no name")
+	}
+	for _, receiverV1 := range cpV1.Receivers {
+		uid := receiverV1.UID.Value()
+		if uid == "" {
+			return contactPoint{}, fmt.Errorf("contact point '%s' has a receiver without uid", cp.Name)
+		}
+		settings := simplejson.New()
+		for k, v := range receiverV1.Settings.Value() {
+			settings.Set(k, v)
+		}
+		cp.Receivers = append(cp.Receivers, definitions.EmbeddedContactPoint{
+			UID:                   uid,
+			Name:                  cp.Name,
+			Type:                  receiverV1.Type.Value(),
+			DisableResolveMessage: receiverV1.DisableResolveMessage.Value(),
+			Settings:              settings,
+			Provenance:            string(models.ProvenanceFile),
+		})
+	}
+	return cp, nil
+}
+
+func (windowV1 *maintenanceWindowV1) mapToModel() (maintenanceWindow, error) {
+	window := maintenanceWindow{
+		Folder: windowV1.Folder.Value(),
+		Window: models.MaintenanceWindow{
+			OrgID:         orgIDOrDefault(windowV1.OrgID),
+			UID:           windowV1.UID.Value(),
+			Title:         windowV1.Title.Value(),
+			RuleUID:       windowV1.RuleUID.Value(),
+			TimeIntervals: windowV1.TimeIntervals,
+		},
+	}
+	if window.Window.UID == "" {
+		return maintenanceWindow{}, fmt.Errorf("maintenance window '%s' has no uid", window.Window.Title)
+	}
+	if (window.Folder == "") == (window.Window.RuleUID == "") {
+		return maintenanceWindow{}, fmt.Errorf("maintenance window '%s' must have either a folder or a rule uid", window.Window.UID)
+	}
+	for _, rangeV1 := range windowV1.Ranges {
+		start, err := time.Parse(time.RFC3339, rangeV1.Start.Value())
+		if err != nil {
+			return maintenanceWindow{}, fmt.Errorf("maintenance window '%s' has an invalid range start: %w", window.Window.UID, err)
+		}
+		end, err := time.Parse(time.RFC3339, rangeV1.End.Value())
+		if err != nil {
+			return maintenanceWindow{}, fmt.Errorf("maintenance window '%s' has an invalid range end: %w", window.Window.UID, err)
+		}
+		window.Window.Ranges = append(window.Window.Ranges, models.MaintenanceTimeRange{Start: start, End: end})
+	}
+	return window, nil
+}
+
+func orgIDOrDefault(orgID values.Int64Value) int64 {
+	if orgID.Value() < 1 {
+		return 1
+	}
+	return orgID.Value()
+}
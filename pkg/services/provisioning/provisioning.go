@@ -12,13 +12,17 @@ import (
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
+	alertingProvisioning "github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -29,6 +33,7 @@ func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, pluginStore p
 	dashboardService dashboardservice.DashboardProvisioningService,
 	datasourceService datasourceservice.DataSourceService,
 	alertingService *alerting.AlertNotificationService, pluginSettings pluginsettings.Service,
+	secretService secrets.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                     cfg,
@@ -41,10 +46,12 @@ func ProvideService(cfg *setting.Cfg, sqlStore *sqlstore.SQLStore, pluginStore p
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       prov_alerting.Provision,
 		dashboardService:        dashboardService,
 		datasourceService:       datasourceService,
 		alertingService:         alertingService,
 		pluginsSettings:         pluginSettings,
+		secretService:           secretService,
 	}
 	return s, nil
 }
@@ -56,6 +63,7 @@ type ProvisioningService interface {
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
+	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -68,6 +76,7 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionAlerting:       prov_alerting.Provision,
 	}
 }
 
@@ -100,11 +109,13 @@ type ProvisioningServiceImpl struct {
 	provisionNotifiers      func(context.Context, string, notifiers.Manager, notifiers.SQLStore, encryption.Internal, *notifications.NotificationService) error
 	provisionDatasources    func(context.Context, string, datasources.Store, utils.OrgStore) error
 	provisionPlugins        func(context.Context, string, plugins.Store, plugifaces.Store, pluginsettings.Service) error
+	provisionAlerting       func(context.Context, prov_alerting.ProvisionerConfig) error
 	mutex                   sync.Mutex
 	dashboardService        dashboardservice.DashboardProvisioningService
 	datasourceService       datasourceservice.DataSourceService
 	alertingService         *alerting.AlertNotificationService
 	pluginsSettings         pluginsettings.Service
+	secretService           secrets.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -123,6 +134,13 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	if ps.Cfg.UnifiedAlerting.IsEnabled() {
+		err = ps.ProvisionAlerting(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -208,6 +226,35 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 	return nil
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	st := &store.DBstore{
+		BaseInterval:    ps.Cfg.UnifiedAlerting.BaseInterval,
+		DefaultInterval: ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+		SQLStore:        ps.SQLStore,
+		Logger:          ps.log,
+	}
+	cfg := prov_alerting.ProvisionerConfig{
+		Path:                     alertingPath,
+		OrgStore:                 ps.SQLStore,
+		DashboardStore:           ps.SQLStore,
+		DashboardService:         ps.dashboardService,
+		ProvenanceStore:          st,
+		RuleService:              alertingProvisioning.NewAlertRuleService(st, st, st, int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()), ps.log),
+		ContactPointService:      alertingProvisioning.NewContactPointService(st, ps.secretService, st, st, ps.log),
+		PolicyService:            alertingProvisioning.NewNotificationPolicyService(st, st, st, ps.log),
+		TemplateService:          alertingProvisioning.NewTemplateService(st, st, st, ps.log),
+		MuteTimingService:        alertingProvisioning.NewMuteTimingService(st, st, st, ps.log),
+		MaintenanceWindowService: alertingProvisioning.NewMaintenanceWindowService(st, st, st, ps.log),
+	}
+	if err := ps.provisionAlerting(ctx, cfg); err != nil {
+		err = errutil.Wrap("Alerting provisioning error", err)
+		ps.log.Error("Failed to provision alerting", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
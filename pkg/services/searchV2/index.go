@@ -30,11 +30,20 @@ type eventStore interface {
 }
 
 type dashboardIndex struct {
-	mu         sync.RWMutex
-	loader     dashboardLoader
-	dashboards map[int64][]dashboard // orgId -> []dashboards
-	eventStore eventStore
-	logger     log.Logger
+	mu          sync.RWMutex
+	loader      dashboardLoader
+	dashboards  map[int64][]dashboard // orgId -> []dashboards
+	eventStore  eventStore
+	logger      log.Logger
+	lastEventID int64
+
+	// Snapshots are saved here when not empty
+	snapshotPath string
+	snapshot     snapshotState
+
+	// updated with mu
+	lastFullIndex time.Time
+	lastUpdate    time.Time
 }
 
 type dashboard struct {
@@ -46,14 +55,16 @@ type dashboard struct {
 	created  time.Time
 	updated  time.Time
 	info     *extract.DashboardInfo
+	tokens   dashboardTokens
 }
 
-func newDashboardIndex(dashLoader dashboardLoader, evStore eventStore) *dashboardIndex {
+func newDashboardIndex(dashLoader dashboardLoader, evStore eventStore, snapshotPath string) *dashboardIndex {
 	return &dashboardIndex{
-		loader:     dashLoader,
-		eventStore: evStore,
-		dashboards: map[int64][]dashboard{},
-		logger:     log.New("dashboardIndex"),
+		loader:       dashLoader,
+		eventStore:   evStore,
+		dashboards:   map[int64][]dashboard{},
+		logger:       log.New("dashboardIndex"),
+		snapshotPath: snapshotPath,
 	}
 }
 
@@ -64,6 +75,9 @@ func (i *dashboardIndex) run(ctx context.Context) error {
 	partialUpdateTicker := time.NewTicker(5 * time.Second)
 	defer partialUpdateTicker.Stop()
 
+	snapshotTicker := time.NewTicker(snapshotInterval)
+	defer snapshotTicker.Stop()
+
 	var lastEventID int64
 	lastEvent, err := i.eventStore.GetLastEvent(ctx)
 	if err != nil {
@@ -73,23 +87,41 @@ func (i *dashboardIndex) run(ctx context.Context) error {
 		lastEventID = lastEvent.Id
 	}
 
-	// Build on start for orgID 1 but keep lazy for others.
-	started := time.Now()
-	dashboards, err := i.getDashboards(ctx, 1)
-	if err != nil {
-		return fmt.Errorf("can't build dashboard search index for org ID 1: %w", err)
+	if i.loadSnapshot(lastEventID) {
+		// Only replay the events since the snapshot was saved.
+		started := time.Now()
+		i.applyIndexUpdates(ctx)
+		i.logger.Info("Dashboard index restored from snapshot", "snapshotRestoreElapsed", time.Since(started), "lastEventId", i.getLastEventID())
+	} else {
+		i.setLastEventID(lastEventID)
+
+		// Build on start for orgID 1 but keep lazy for others.
+		started := time.Now()
+		dashboards, err := i.getDashboards(ctx, 1)
+		if err != nil {
+			return fmt.Errorf("can't build dashboard search index for org ID 1: %w", err)
+		}
+		i.logger.Info("Indexing for main org finished", "mainOrgIndexElapsed", time.Since(started), "numDashboards", len(dashboards))
+		i.saveSnapshot()
 	}
-	i.logger.Info("Indexing for main org finished", "mainOrgIndexElapsed", time.Since(started), "numDashboards", len(dashboards))
 
 	for {
 		select {
 		case <-partialUpdateTicker.C:
-			lastEventID = i.applyIndexUpdates(ctx, lastEventID)
+			i.applyIndexUpdates(ctx)
 		case <-fullReIndexTicker.C:
 			started := time.Now()
 			i.reIndexFromScratch(ctx)
 			i.logger.Info("Full re-indexing finished", "fullReIndexElapsed", time.Since(started))
+			i.saveSnapshot()
+		case <-snapshotTicker.C:
+			if i.snapshotOutdated() {
+				i.saveSnapshot()
+			}
 		case <-ctx.Done():
+			if i.snapshotOutdated() {
+				i.saveSnapshot()
+			}
 			return ctx.Err()
 		}
 	}
@@ -103,6 +135,12 @@ func (i *dashboardIndex) reIndexFromScratch(ctx context.Context) {
 	}
 	i.mu.RUnlock()
 
+	i.reIndexOrgs(ctx, orgIDs)
+}
+
+// reIndexOrgs loads all dashboards for each org and returns the first error
+func (i *dashboardIndex) reIndexOrgs(ctx context.Context, orgIDs []int64) error {
+	var firstErr error
 	for _, orgID := range orgIDs {
 		started := time.Now()
 		ctx, cancel := context.WithTimeout(ctx, time.Minute)
@@ -110,24 +148,43 @@ func (i *dashboardIndex) reIndexFromScratch(ctx context.Context) {
 		if err != nil {
 			cancel()
 			i.logger.Error("Error re-indexing dashboards for organization", "orgId", orgID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 		cancel()
+		dashboards = tokenizeDashboards(dashboards)
 		i.logger.Info("Re-indexed dashboards for organization", "orgId", orgID, "orgReIndexElapsed", time.Since(started))
 		i.mu.Lock()
 		i.dashboards[orgID] = dashboards
+		i.lastFullIndex = time.Now()
 		i.mu.Unlock()
 	}
+	return firstErr
 }
 
-func (i *dashboardIndex) applyIndexUpdates(ctx context.Context, lastEventID int64) int64 {
+func (i *dashboardIndex) getLastEventID() int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.lastEventID
+}
+
+func (i *dashboardIndex) setLastEventID(id int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastEventID = id
+}
+
+func (i *dashboardIndex) applyIndexUpdates(ctx context.Context) {
+	lastEventID := i.getLastEventID()
 	events, err := i.eventStore.GetAllEventsAfter(context.Background(), lastEventID)
 	if err != nil {
 		i.logger.Error("can't load events", "error", err)
-		return lastEventID
+		return
 	}
 	if len(events) == 0 {
-		return lastEventID
+		return
 	}
 	started := time.Now()
 	for _, e := range events {
@@ -135,12 +192,15 @@ func (i *dashboardIndex) applyIndexUpdates(ctx context.Context, lastEventID int6
 		err := i.applyEventOnIndex(ctx, e)
 		if err != nil {
 			i.logger.Error("can't apply event", "error", err)
-			return lastEventID
+			break
 		}
 		lastEventID = e.Id
 	}
+	i.mu.Lock()
+	i.lastEventID = lastEventID
+	i.lastUpdate = time.Now()
+	i.mu.Unlock()
 	i.logger.Info("Index updates applied", "indexEventsAppliedElapsed", time.Since(started), "numEvents", len(events))
-	return lastEventID
 }
 
 func (i *dashboardIndex) applyEventOnIndex(ctx context.Context, e *store.EntityEvent) error {
@@ -182,6 +242,7 @@ func (i *dashboardIndex) applyDashboardEvent(ctx context.Context, orgID int64, d
 	if err != nil {
 		return err
 	}
+	dbDashboards = tokenizeDashboards(dbDashboards)
 
 	i.mu.Lock()
 	defer i.mu.Unlock()
@@ -241,7 +302,9 @@ func (i *dashboardIndex) getDashboards(ctx context.Context, orgId int64) ([]dash
 		if err != nil {
 			return nil, err
 		}
+		dashboards = tokenizeDashboards(dashboards)
 		i.dashboards[orgId] = dashboards
+		i.lastFullIndex = time.Now()
 	}
 	return dashboards, nil
 }
@@ -416,3 +479,82 @@ func loadDatasourceLookup(ctx context.Context, orgID int64, sql *sqlstore.SQLSto
 		return byName[key]
 	}, err
 }
+
+// IndexStats describes the dashboard index for admins
+type IndexStats struct {
+	Orgs          map[int64]int `json:"orgs"` // number of dashboards and folders in each indexed org
+	LastEventID   int64         `json:"lastEventId"`
+	LastFullIndex int64         `json:"lastFullIndex,omitempty"` // unix ms
+	LastUpdate    int64         `json:"lastUpdate,omitempty"`    // unix ms
+	Snapshot      SnapshotStats `json:"snapshot"`
+}
+
+type SnapshotStats struct {
+	Enabled     bool   `json:"enabled"`
+	Restored    bool   `json:"restored"` // the index was loaded from the snapshot on startup
+	Saved       int64  `json:"saved,omitempty"`
+	LastEventID int64  `json:"lastEventId"`
+	Size        int64  `json:"size,omitempty"`
+	Path        string `json:"path,omitempty"`
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func (i *dashboardIndex) stats() IndexStats {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	orgs := make(map[int64]int, len(i.dashboards))
+	for orgID, dashboards := range i.dashboards {
+		orgs[orgID] = len(dashboards)
+	}
+	return IndexStats{
+		Orgs:          orgs,
+		LastEventID:   i.lastEventID,
+		LastFullIndex: unixMilli(i.lastFullIndex),
+		LastUpdate:    unixMilli(i.lastUpdate),
+		Snapshot: SnapshotStats{
+			Enabled:     i.snapshotPath != "",
+			Restored:    i.snapshot.restored,
+			Saved:       unixMilli(i.snapshot.saved),
+			LastEventID: i.snapshot.lastEventID,
+			Size:        i.snapshot.size,
+			Path:        i.snapshotPath,
+		},
+	}
+}
+
+// forceReIndex rebuilds every indexed org (and the given org) from SQL and saves a new snapshot
+func (i *dashboardIndex) forceReIndex(ctx context.Context, orgID int64) error {
+	i.mu.RLock()
+	orgIDs := []int64{orgID}
+	for id := range i.dashboards {
+		if id != orgID {
+			orgIDs = append(orgIDs, id)
+		}
+	}
+	i.mu.RUnlock()
+
+	// Events before this point are included in the rebuilt index
+	lastEvent, err := i.eventStore.GetLastEvent(ctx)
+	if err != nil {
+		return err
+	}
+	if err := i.reIndexOrgs(ctx, orgIDs); err != nil {
+		return err
+	}
+	if lastEvent != nil {
+		i.mu.Lock()
+		if lastEvent.Id > i.lastEventID {
+			i.lastEventID = lastEvent.Id
+		}
+		i.mu.Unlock()
+	}
+	i.saveSnapshot()
+	return nil
+}
@@ -24,7 +24,7 @@ func TestDashboardIndexCreate(t *testing.T) {
 			},
 		},
 	}
-	index := newDashboardIndex(dashboardLoader, &store.MockEntityEventsService{})
+	index := newDashboardIndex(dashboardLoader, &store.MockEntityEventsService{}, "")
 	require.NotNil(t, index)
 	dashboards, err := index.getDashboards(context.Background(), 1)
 	require.NoError(t, err)
@@ -51,7 +51,7 @@ func TestDashboardIndexUpdate(t *testing.T) {
 			},
 		},
 	}
-	index := newDashboardIndex(dashboardLoader, nil)
+	index := newDashboardIndex(dashboardLoader, nil, "")
 	require.NotNil(t, index)
 	dashboards, err := index.getDashboards(context.Background(), 1)
 	require.NoError(t, err)
@@ -79,7 +79,7 @@ func TestDashboardIndexDelete(t *testing.T) {
 			},
 		},
 	}
-	index := newDashboardIndex(dashboardLoader, nil)
+	index := newDashboardIndex(dashboardLoader, nil, "")
 	require.NotNil(t, index)
 	dashboards, err := index.getDashboards(context.Background(), 1)
 	require.NoError(t, err)
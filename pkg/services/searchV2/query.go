@@ -0,0 +1,462 @@
+package searchV2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/grafana/grafana/pkg/services/searchV2/extract"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	defaultQueryLimit = 50
+	maxQueryLimit     = 1000
+	defaultFacetLimit = 50
+
+	// The UID used for the "General" folder in queries and results
+	generalFolderUID = "general"
+)
+
+// Supported facet fields
+const (
+	facetTags       = "tags"
+	facetLocation   = "location"
+	facetDatasource = "datasource"
+	facetPanelType  = "panel_type"
+)
+
+// Match weights.  A term must match at least one field, and the best
+// match for each term is added to the score
+const (
+	scoreTitleExact       = 3.0
+	scoreTitlePrefix      = 2.0
+	scorePanelTitleExact  = 1.5
+	scorePanelTitlePrefix = 1.0
+	scoreDescrExact       = 1.0
+	scoreDescrPrefix      = 0.5
+	scoreTitlePhrase      = 2.0
+)
+
+// SearchResultMeta is added to the custom metadata of the results frame
+type SearchResultMeta struct {
+	Count    int64   `json:"count"` // total number of hits before paging
+	MaxScore float64 `json:"max_score"`
+}
+
+// isEmpty is true for the query the frontend sends to load the whole index
+func (q DashboardQuery) isEmpty() bool {
+	return q.Query == "" && q.Location == "" && len(q.Tags) == 0 && q.Datasource == "" &&
+		q.PanelType == "" && q.Sort == "" && len(q.Facet) == 0 && q.From == 0 && q.Limit == 0
+}
+
+// tokenize splits text into lower case words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchTerm scores term against tokens: exact if any token is the term, else prefix if any token starts with it
+func matchTerm(term string, tokens []string, exact float64, prefix float64) float64 {
+	score := 0.0
+	for _, t := range tokens {
+		if t == term {
+			return exact
+		}
+		if score < prefix && strings.HasPrefix(t, term) {
+			score = prefix
+		}
+	}
+	return score
+}
+
+// dashboardTokens are the searchable words of a dashboard.  They are computed
+// once when the dashboard is indexed and saved with the snapshot
+type dashboardTokens struct {
+	Title       []string `json:"title,omitempty"`
+	PanelTitles []string `json:"panelTitles,omitempty"`
+	Description []string `json:"description,omitempty"`
+}
+
+func newDashboardTokens(dash *dashboard) dashboardTokens {
+	tokens := dashboardTokens{
+		Title:       tokenize(dash.info.Title),
+		Description: tokenize(dash.info.Description),
+	}
+	forEachPanel(dash, func(p panelSummary) {
+		tokens.PanelTitles = append(tokens.PanelTitles, tokenize(p.Title)...)
+		tokens.Description = append(tokens.Description, tokenize(p.Description)...)
+	})
+	return tokens
+}
+
+// tokenizeDashboards sets the tokens of the dashboards loaded into the index
+func tokenizeDashboards(dashboards []dashboard) []dashboard {
+	for idx := range dashboards {
+		dash := &dashboards[idx]
+		if dash.isFolder || dash.info == nil {
+			continue
+		}
+		dash.tokens = newDashboardTokens(dash)
+	}
+	return dashboards
+}
+
+// score returns how well the dashboard matches the terms, or false when any term does not match
+func (d dashboardTokens) score(terms []string) (float64, bool) {
+	total := 0.0
+	for _, term := range terms {
+		best := matchTerm(term, d.Title, scoreTitleExact, scoreTitlePrefix)
+		if s := matchTerm(term, d.PanelTitles, scorePanelTitleExact, scorePanelTitlePrefix); s > best {
+			best = s
+		}
+		if s := matchTerm(term, d.Description, scoreDescrExact, scoreDescrPrefix); s > best {
+			best = s
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+type searchHit struct {
+	dash     *dashboard
+	location string
+	score    float64
+}
+
+// queryDashboards runs the query against the dashboards the user can see
+func queryDashboards(dashboards []dashboard, q DashboardQuery) (data.Frames, error) {
+	sortField, desc, err := parseSortField(q)
+	if err != nil {
+		return nil, err
+	}
+
+	folderUIDs := make(map[int64]string)
+	for _, dash := range dashboards {
+		if dash.isFolder && dash.id > 0 {
+			folderUIDs[dash.id] = dash.uid
+		}
+	}
+
+	terms := tokenize(q.Query)
+	phrase := strings.ToLower(strings.TrimSpace(q.Query))
+	hits := make([]searchHit, 0)
+	for idx := range dashboards {
+		dash := &dashboards[idx]
+		if dash.isFolder || dash.info == nil {
+			continue
+		}
+		location, ok := folderUIDs[dash.folderID]
+		if !ok {
+			location = generalFolderUID
+		}
+		if q.Location != "" && q.Location != location {
+			continue
+		}
+		if !hasAllTags(dash, q.Tags) || !usesDatasource(dash, q.Datasource) || !hasPanelType(dash, q.PanelType) {
+			continue
+		}
+
+		score := 0.0
+		if len(terms) > 0 {
+			if score, ok = dash.tokens.score(terms); !ok {
+				continue
+			}
+			if len(terms) > 1 && strings.Contains(strings.ToLower(dash.info.Title), phrase) {
+				score += scoreTitlePhrase
+			}
+		}
+		hits = append(hits, searchHit{dash: dash, location: location, score: score})
+	}
+
+	sortHits(hits, sortField, desc)
+
+	frames := data.Frames{hitsToFrame(hits, q)}
+	for _, f := range q.Facet {
+		frame, err := facetFrame(hits, f)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// parseSortField returns the field to sort by.  Without an explicit sort, hits
+// are sorted by score when there are search terms and by name otherwise
+func parseSortField(q DashboardQuery) (string, bool, error) {
+	field := q.Sort
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+	switch field {
+	case "":
+		if len(tokenize(q.Query)) > 0 {
+			return "score", true, nil
+		}
+		return "name", false, nil
+	case "score", "name", "created", "updated":
+		return field, desc, nil
+	}
+	return "", false, fmt.Errorf("unsupported sort field: %q", q.Sort)
+}
+
+func sortHits(hits []searchHit, field string, desc bool) {
+	name := func(i int) string { return strings.ToLower(hits[i].dash.info.Title) }
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if desc {
+			a, b = b, a
+		}
+		switch field {
+		case "name":
+			if na, nb := strings.ToLower(a.dash.info.Title), strings.ToLower(b.dash.info.Title); na != nb {
+				return na < nb
+			}
+		case "score":
+			if a.score != b.score {
+				return a.score < b.score
+			}
+		case "created":
+			if !a.dash.created.Equal(b.dash.created) {
+				return a.dash.created.Before(b.dash.created)
+			}
+		case "updated":
+			if !a.dash.updated.Equal(b.dash.updated) {
+				return a.dash.updated.Before(b.dash.updated)
+			}
+		}
+		// ties are always by name ascending, then UID
+		if name(i) != name(j) {
+			return name(i) < name(j)
+		}
+		return hits[i].dash.uid < hits[j].dash.uid
+	})
+}
+
+func hitsToFrame(hits []searchHit, q DashboardQuery) *data.Frame {
+	from := q.From
+	if from < 0 {
+		from = 0
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	if limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+	end := from + limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	meta := SearchResultMeta{Count: int64(len(hits))}
+	for _, hit := range hits {
+		if hit.score > meta.MaxScore {
+			meta.MaxScore = hit.score
+		}
+	}
+
+	uid := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	name := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	url := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	location := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	tags := data.NewFieldFromFieldType(data.FieldTypeNullableString, 0)
+	panelCount := data.NewFieldFromFieldType(data.FieldTypeInt64, 0)
+	updated := data.NewFieldFromFieldType(data.FieldTypeTime, 0)
+	score := data.NewFieldFromFieldType(data.FieldTypeFloat64, 0)
+
+	uid.Name = "uid"
+	name.Name = "name"
+	url.Name = "url"
+	url.Config = &data.FieldConfig{
+		Links: []data.DataLink{
+			{Title: "link", URL: "${__value.text}"},
+		},
+	}
+	location.Name = "location"
+	tags.Name = "tags"
+	tags.Config = &data.FieldConfig{
+		Custom: map[string]interface{}{
+			// Table panel default styling
+			"displayMode": "json-view",
+		},
+	}
+	panelCount.Name = "panelCount"
+	updated.Name = "updated"
+	score.Name = "score"
+
+	for i := from; i < end; i++ {
+		hit := hits[i]
+		uid.Append(hit.dash.uid)
+		name.Append(hit.dash.info.Title)
+		url.Append(fmt.Sprintf("/d/%s/%s", hit.dash.uid, hit.dash.slug))
+		location.Append(hit.location)
+		tags.Append(toJSONString(hit.dash.info.Tags))
+		panelCount.Append(int64(len(hit.dash.info.Panels)))
+		updated.Append(hit.dash.updated)
+		score.Append(hit.score)
+	}
+
+	frame := data.NewFrame("search-results", uid, name, url, location, tags, panelCount, updated, score)
+	frame.SetMeta(&data.FrameMeta{Custom: meta})
+	return frame
+}
+
+// facetFrame counts the terms of a field across all hits.  Terms are sorted
+// by count, then by name
+func facetFrame(hits []searchHit, f FacetField) (*data.Frame, error) {
+	counter := simpleCounter{
+		values: make(map[string]int64, 30),
+	}
+	for _, hit := range hits {
+		var terms []string
+		switch f.Field {
+		case facetTags:
+			terms = hit.dash.info.Tags
+		case facetLocation:
+			terms = []string{hit.location}
+		case facetDatasource:
+			terms = datasourceUIDs(hit.dash)
+		case facetPanelType:
+			terms = panelTypes(hit.dash)
+		default:
+			return nil, fmt.Errorf("unsupported facet field: %q", f.Field)
+		}
+		for _, term := range uniqueStrings(terms) {
+			counter.add(term)
+		}
+	}
+
+	keys := make([]string, 0, len(counter.values))
+	for k := range counter.values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := counter.values[keys[i]], counter.values[keys[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultFacetLimit
+	}
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	term := data.NewFieldFromFieldType(data.FieldTypeString, 0)
+	count := data.NewFieldFromFieldType(data.FieldTypeInt64, 0)
+	term.Name = "term"
+	count.Name = "count"
+	for _, k := range keys {
+		term.Append(k)
+		count.Append(counter.values[k])
+	}
+	return data.NewFrame("facet-"+f.Field, term, count), nil
+}
+
+type panelSummary struct {
+	Title       string
+	Description string
+	Type        string
+	Datasources []string
+}
+
+// forEachPanel visits every panel, including the panels in collapsed rows
+func forEachPanel(dash *dashboard, cb func(p panelSummary)) {
+	for _, p := range dash.info.Panels {
+		for _, panel := range append([]extract.PanelInfo{p}, p.Collapsed...) {
+			info := panelSummary{
+				Title:       panel.Title,
+				Description: panel.Description,
+				Type:        panel.Type,
+			}
+			for _, ds := range panel.Datasource {
+				info.Datasources = append(info.Datasources, ds.UID)
+			}
+			cb(info)
+		}
+	}
+}
+
+func hasAllTags(dash *dashboard, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range dash.info.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func usesDatasource(dash *dashboard, uid string) bool {
+	if uid == "" {
+		return true
+	}
+	for _, ds := range datasourceUIDs(dash) {
+		if ds == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPanelType(dash *dashboard, panelType string) bool {
+	if panelType == "" {
+		return true
+	}
+	for _, t := range panelTypes(dash) {
+		if t == panelType {
+			return true
+		}
+	}
+	return false
+}
+
+func datasourceUIDs(dash *dashboard) []string {
+	uids := make([]string, 0, len(dash.info.Datasource))
+	for _, ds := range dash.info.Datasource {
+		uids = append(uids, ds.UID)
+	}
+	forEachPanel(dash, func(p panelSummary) {
+		uids = append(uids, p.Datasources...)
+	})
+	return uids
+}
+
+func panelTypes(dash *dashboard) []string {
+	types := make([]string, 0, len(dash.info.Panels))
+	forEachPanel(dash, func(p panelSummary) {
+		types = append(types, p.Type)
+	})
+	return types
+}
+
+// uniqueStrings returns the non empty values without duplicates, so each hit counts once per term
+func uniqueStrings(vals []string) []string {
+	seen := make(map[string]bool, len(vals))
+	res := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		res = append(res, v)
+	}
+	return res
+}
@@ -0,0 +1,194 @@
+package searchV2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/searchV2/extract"
+)
+
+var testQueryDashboards = tokenizeDashboards([]dashboard{
+	{
+		id:       0,
+		isFolder: true,
+		info:     &extract.DashboardInfo{Title: "General"},
+	},
+	{
+		id:       10,
+		uid:      "infra",
+		isFolder: true,
+		info:     &extract.DashboardInfo{Title: "Infrastructure"},
+	},
+	{
+		id:       1,
+		uid:      "node",
+		folderID: 10,
+		slug:     "node-exporter",
+		updated:  time.Unix(300, 0),
+		info: &extract.DashboardInfo{
+			Title:      "Node Exporter",
+			Tags:       []string{"linux", "prometheus"},
+			Datasource: []extract.DataSourceRef{{UID: "prom", Type: "prometheus"}},
+			Panels: []extract.PanelInfo{
+				{Title: "CPU usage", Type: "timeseries"},
+				{Title: "Disk", Type: "row", Collapsed: []extract.PanelInfo{
+					{Title: "Disk IO", Type: "timeseries", Datasource: []extract.DataSourceRef{{UID: "prom"}}},
+				}},
+			},
+		},
+	},
+	{
+		id:      2,
+		uid:     "logs",
+		slug:    "app-logs",
+		updated: time.Unix(200, 0),
+		info: &extract.DashboardInfo{
+			Title:       "App logs",
+			Description: "Errors for the node service",
+			Tags:        []string{"logs"},
+			Datasource:  []extract.DataSourceRef{{UID: "loki", Type: "loki"}},
+			Panels: []extract.PanelInfo{
+				{Title: "Errors", Type: "logs"},
+			},
+		},
+	},
+	{
+		id:      3,
+		uid:     "cpu",
+		slug:    "cpu",
+		updated: time.Unix(100, 0),
+		info: &extract.DashboardInfo{
+			Title: "CPU overview",
+			Tags:  []string{"linux"},
+			Panels: []extract.PanelInfo{
+				{Title: "Load", Type: "stat"},
+			},
+		},
+	},
+})
+
+func resultUIDs(t *testing.T, frames data.Frames) []string {
+	t.Helper()
+	require.NotEmpty(t, frames)
+	require.Equal(t, "search-results", frames[0].Name)
+	uids := make([]string, 0, frames[0].Rows())
+	for i := 0; i < frames[0].Rows(); i++ {
+		uids = append(uids, frames[0].Fields[0].At(i).(string))
+	}
+	return uids
+}
+
+func TestQueryDashboards(t *testing.T) {
+	tests := []struct {
+		name  string
+		query DashboardQuery
+		uids  []string
+		count int64
+	}{
+		{
+			name:  "no terms sorts by name",
+			query: DashboardQuery{Limit: 10},
+			uids:  []string{"logs", "cpu", "node"},
+			count: 3,
+		},
+		{
+			name:  "title match ranks above description match",
+			query: DashboardQuery{Query: "node"},
+			uids:  []string{"node", "logs"},
+			count: 2,
+		},
+		{
+			name:  "prefix match on panel titles including collapsed rows",
+			query: DashboardQuery{Query: "dis"},
+			uids:  []string{"node"},
+			count: 1,
+		},
+		{
+			name:  "all terms must match",
+			query: DashboardQuery{Query: "cpu load"},
+			uids:  []string{"cpu"},
+			count: 1,
+		},
+		{
+			name:  "tags filter",
+			query: DashboardQuery{Tags: []string{"linux", "prometheus"}},
+			uids:  []string{"node"},
+			count: 1,
+		},
+		{
+			name:  "location filter",
+			query: DashboardQuery{Location: "general"},
+			uids:  []string{"logs", "cpu"},
+			count: 2,
+		},
+		{
+			name:  "datasource filter",
+			query: DashboardQuery{Datasource: "loki"},
+			uids:  []string{"logs"},
+			count: 1,
+		},
+		{
+			name:  "panel type filter",
+			query: DashboardQuery{PanelType: "timeseries"},
+			uids:  []string{"node"},
+			count: 1,
+		},
+		{
+			name:  "sort by updated descending",
+			query: DashboardQuery{Sort: "-updated"},
+			uids:  []string{"node", "logs", "cpu"},
+			count: 3,
+		},
+		{
+			name:  "paging",
+			query: DashboardQuery{Sort: "updated", From: 1, Limit: 1},
+			uids:  []string{"logs"},
+			count: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, err := queryDashboards(testQueryDashboards, tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.uids, resultUIDs(t, frames))
+			require.Equal(t, tt.count, frames[0].Meta.Custom.(SearchResultMeta).Count)
+		})
+	}
+}
+
+func TestQueryDashboardsFacets(t *testing.T) {
+	frames, err := queryDashboards(testQueryDashboards, DashboardQuery{
+		Facet: []FacetField{{Field: "tags"}, {Field: "location"}, {Field: "panel_type", Limit: 1}},
+	})
+	require.NoError(t, err)
+	require.Len(t, frames, 4)
+
+	facet := func(frame *data.Frame) map[string]int64 {
+		counts := map[string]int64{}
+		for i := 0; i < frame.Rows(); i++ {
+			counts[frame.Fields[0].At(i).(string)] = frame.Fields[1].At(i).(int64)
+		}
+		return counts
+	}
+
+	require.Equal(t, "facet-tags", frames[1].Name)
+	require.Equal(t, map[string]int64{"linux": 2, "prometheus": 1, "logs": 1}, facet(frames[1]))
+	require.Equal(t, "linux", frames[1].Fields[0].At(0))
+
+	require.Equal(t, map[string]int64{"general": 2, "infra": 1}, facet(frames[2]))
+
+	// the panel type is counted once for the dashboard
+	require.Equal(t, map[string]int64{"logs": 1}, facet(frames[3]))
+}
+
+func TestQueryDashboardsErrors(t *testing.T) {
+	_, err := queryDashboards(testQueryDashboards, DashboardQuery{Sort: "nope"})
+	require.Error(t, err)
+
+	_, err = queryDashboards(testQueryDashboards, DashboardQuery{Facet: []FacetField{{Field: "nope"}}})
+	require.Error(t, err)
+}
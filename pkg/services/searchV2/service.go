@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"strconv"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
@@ -42,7 +45,7 @@ func ProvideService(cfg *setting.Cfg, sql *sqlstore.SQLStore, entityEventStore s
 			sql: sql,
 			ac:  ac,
 		},
-		dashboardIndex: newDashboardIndex(newSQLDashboardLoader(sql), entityEventStore),
+		dashboardIndex: newDashboardIndex(newSQLDashboardLoader(sql), entityEventStore, filepath.Join(cfg.DataPath, "search", snapshotFileName)),
 		logger:         log.New("searchV2"),
 	}
 }
@@ -58,6 +61,17 @@ func (s *StandardSearchService) Run(ctx context.Context) error {
 	return s.dashboardIndex.run(ctx)
 }
 
+func (s *StandardSearchService) HandleGetIndexStats(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, s.dashboardIndex.stats())
+}
+
+func (s *StandardSearchService) HandleReIndex(c *models.ReqContext) response.Response {
+	if err := s.dashboardIndex.forceReIndex(c.Req.Context(), c.OrgId); err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to rebuild the dashboard index", err)
+	}
+	return response.JSON(http.StatusOK, s.dashboardIndex.stats())
+}
+
 func (s *StandardSearchService) getUser(ctx context.Context, backendUser *backend.User, orgId int64) (*models.SignedInUser, error) {
 	// TODO: get user & user's permissions from the request context
 
@@ -105,7 +119,7 @@ func (s *StandardSearchService) getUser(ctx context.Context, backendUser *backen
 	return user, nil
 }
 
-func (s *StandardSearchService) DoDashboardQuery(ctx context.Context, user *backend.User, orgId int64, _ DashboardQuery) *backend.DataResponse {
+func (s *StandardSearchService) DoDashboardQuery(ctx context.Context, user *backend.User, orgId int64, query DashboardQuery) *backend.DataResponse {
 	rsp := &backend.DataResponse{}
 
 	dashboards, err := s.dashboardIndex.getDashboards(ctx, orgId)
@@ -126,8 +140,13 @@ func (s *StandardSearchService) DoDashboardQuery(ctx context.Context, user *back
 		return rsp
 	}
 
-	rsp.Frames = metaToFrame(dashboards)
+	// An empty query loads the whole index
+	if query.isEmpty() {
+		rsp.Frames = metaToFrame(dashboards)
+		return rsp
+	}
 
+	rsp.Frames, rsp.Error = queryDashboards(dashboards, query)
 	return rsp
 }
 
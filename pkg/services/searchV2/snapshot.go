@@ -0,0 +1,236 @@
+package searchV2
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/searchV2/extract"
+)
+
+const (
+	// Changes to the saved format or the extracted dashboard info must increment this
+	snapshotVersion = 1
+
+	snapshotFileName = "dashboard-index.json.gz"
+	snapshotInterval = time.Minute
+
+	// Entity events are deleted after 24 hours, so older snapshots can not be caught up
+	maxSnapshotAge = 20 * time.Hour
+)
+
+// The saved dashboard index.  It is only valid with all the events after LastEventID
+type indexSnapshot struct {
+	Version     int                                `json:"version"`
+	Created     time.Time                          `json:"created"`
+	LastEventID int64                              `json:"lastEventId"`
+	Orgs        map[int64][]dashboardSnapshotEntry `json:"orgs"`
+}
+
+type dashboardSnapshotEntry struct {
+	ID       int64                  `json:"id"`
+	UID      string                 `json:"uid"`
+	IsFolder bool                   `json:"isFolder,omitempty"`
+	FolderID int64                  `json:"folderId,omitempty"`
+	Slug     string                 `json:"slug"`
+	Created  time.Time              `json:"created"`
+	Updated  time.Time              `json:"updated"`
+	Info     *extract.DashboardInfo `json:"info"`
+	Tokens   dashboardTokens        `json:"tokens"`
+}
+
+// Where the last snapshot came from or went to.  Updated with mu
+type snapshotState struct {
+	saved       time.Time // last save or load
+	lastEventID int64     // the event ID in the last saved snapshot
+	size        int64
+	restored    bool // the index started from a snapshot
+}
+
+func (i *dashboardIndex) snapshotOutdated() bool {
+	if i.snapshotPath == "" {
+		return false
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.lastEventID != i.snapshot.lastEventID || i.lastFullIndex.After(i.snapshot.saved)
+}
+
+// saveSnapshot writes the index to disk.  Errors are logged since the index
+// still works without a snapshot
+func (i *dashboardIndex) saveSnapshot() {
+	if i.snapshotPath == "" {
+		return
+	}
+
+	started := time.Now()
+	i.mu.RLock()
+	snapshot := indexSnapshot{
+		Version:     snapshotVersion,
+		Created:     started,
+		LastEventID: i.lastEventID,
+		Orgs:        make(map[int64][]dashboardSnapshotEntry, len(i.dashboards)),
+	}
+	for orgID, dashboards := range i.dashboards {
+		entries := make([]dashboardSnapshotEntry, 0, len(dashboards))
+		for _, d := range dashboards {
+			entries = append(entries, dashboardSnapshotEntry{
+				ID:       d.id,
+				UID:      d.uid,
+				IsFolder: d.isFolder,
+				FolderID: d.folderID,
+				Slug:     d.slug,
+				Created:  d.created,
+				Updated:  d.updated,
+				Info:     d.info,
+				Tokens:   d.tokens,
+			})
+		}
+		snapshot.Orgs[orgID] = entries
+	}
+	i.mu.RUnlock()
+
+	size, err := writeSnapshot(i.snapshotPath, snapshot)
+	if err != nil {
+		i.logger.Error("Error saving dashboard index snapshot", "path", i.snapshotPath, "error", err)
+		return
+	}
+
+	i.mu.Lock()
+	i.snapshot.saved = started
+	i.snapshot.lastEventID = snapshot.LastEventID
+	i.snapshot.size = size
+	i.mu.Unlock()
+	i.logger.Debug("Saved dashboard index snapshot", "snapshotSaveElapsed", time.Since(started), "size", size)
+}
+
+// Writes the snapshot to a temporary file first, so a crash never leaves a partial snapshot
+func writeSnapshot(fpath string, snapshot indexSnapshot) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(fpath), 0750); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fpath), snapshotFileName+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// no-op after the rename
+		_ = os.Remove(tmp.Name())
+	}()
+
+	gz := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Rename(tmp.Name(), fpath)
+}
+
+func readSnapshot(fpath string) (*indexSnapshot, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path comes from the data path
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &indexSnapshot{}
+	if err := json.NewDecoder(gz).Decode(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// loadSnapshot restores the index from disk.  It returns false when there is no
+// usable snapshot and the index must be built from scratch
+func (i *dashboardIndex) loadSnapshot(currentEventID int64) bool {
+	if i.snapshotPath == "" {
+		return false
+	}
+	info, err := os.Stat(i.snapshotPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			i.logger.Warn("Can't read dashboard index snapshot", "path", i.snapshotPath, "error", err)
+		}
+		return false
+	}
+
+	snapshot, err := readSnapshot(i.snapshotPath)
+	if err == nil {
+		err = validateSnapshot(snapshot, currentEventID, time.Now())
+	}
+	if err != nil {
+		i.logger.Info("Ignoring dashboard index snapshot", "path", i.snapshotPath, "reason", err)
+		return false
+	}
+
+	dashboards := make(map[int64][]dashboard, len(snapshot.Orgs))
+	for orgID, entries := range snapshot.Orgs {
+		list := make([]dashboard, 0, len(entries))
+		for _, e := range entries {
+			info := e.Info
+			if info == nil {
+				info = &extract.DashboardInfo{}
+			}
+			list = append(list, dashboard{
+				id:       e.ID,
+				uid:      e.UID,
+				isFolder: e.IsFolder,
+				folderID: e.FolderID,
+				slug:     e.Slug,
+				created:  e.Created,
+				updated:  e.Updated,
+				info:     info,
+				tokens:   e.Tokens,
+			})
+		}
+		dashboards[orgID] = list
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dashboards = dashboards
+	i.lastEventID = snapshot.LastEventID
+	i.lastFullIndex = snapshot.Created
+	i.snapshot = snapshotState{
+		saved:       snapshot.Created,
+		lastEventID: snapshot.LastEventID,
+		size:        info.Size(),
+		restored:    true,
+	}
+	return true
+}
+
+func validateSnapshot(snapshot *indexSnapshot, currentEventID int64, now time.Time) error {
+	if snapshot.Version != snapshotVersion {
+		return fmt.Errorf("snapshot version %d is not %d", snapshot.Version, snapshotVersion)
+	}
+	if age := now.Sub(snapshot.Created); age > maxSnapshotAge {
+		return fmt.Errorf("snapshot is too old (%s)", age.Round(time.Second))
+	}
+	if snapshot.LastEventID > currentEventID {
+		// the events table was reset or belongs to another database
+		return fmt.Errorf("snapshot event ID %d is after the last event %d", snapshot.LastEventID, currentEventID)
+	}
+	return nil
+}
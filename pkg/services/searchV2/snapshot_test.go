@@ -0,0 +1,83 @@
+package searchV2
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/searchV2/extract"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+func TestDashboardIndexSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "search", snapshotFileName)
+	dashboardLoader := &testDashboardLoader{
+		dashboards: []dashboard{
+			{
+				id:   1,
+				uid:  "1",
+				slug: "one",
+				info: &extract.DashboardInfo{Title: "One", Tags: []string{"a"}},
+			},
+		},
+	}
+
+	index := newDashboardIndex(dashboardLoader, nil, snapshotPath)
+	_, err := index.getDashboards(context.Background(), 1)
+	require.NoError(t, err)
+	index.setLastEventID(5)
+	require.True(t, index.snapshotOutdated())
+	index.saveSnapshot()
+	require.False(t, index.snapshotOutdated())
+	require.Equal(t, int64(5), index.stats().Snapshot.LastEventID)
+
+	// A new index replays the events after the snapshot
+	events := &store.MockEntityEventsService{}
+	events.On("GetAllEventsAfter", mock.Anything, int64(5)).Return([]*store.EntityEvent{
+		{Id: 6, EntityId: "database/1/dashboard/2", EventType: store.EntityEventTypeCreate},
+	}, nil)
+	dashboardLoader.dashboards = []dashboard{
+		{
+			id:   2,
+			uid:  "2",
+			info: &extract.DashboardInfo{Title: "Two"},
+		},
+	}
+
+	restored := newDashboardIndex(dashboardLoader, events, snapshotPath)
+	require.True(t, restored.loadSnapshot(6))
+	restored.applyIndexUpdates(context.Background())
+
+	dashboards, err := restored.getDashboards(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, dashboards, 2)
+	require.Equal(t, "one", dashboards[0].slug)
+	require.Equal(t, []string{"a"}, dashboards[0].info.Tags)
+	require.Equal(t, []string{"one"}, dashboards[0].tokens.Title)
+	require.Equal(t, "2", dashboards[1].uid)
+	require.Equal(t, []string{"two"}, dashboards[1].tokens.Title)
+
+	stats := restored.stats()
+	require.Equal(t, int64(6), stats.LastEventID)
+	require.True(t, stats.Snapshot.Restored)
+	require.Equal(t, map[int64]int{1: 2}, stats.Orgs)
+	require.True(t, restored.snapshotOutdated())
+
+	// The events table was reset
+	require.False(t, newDashboardIndex(dashboardLoader, nil, snapshotPath).loadSnapshot(1))
+
+	// Without a path there is no snapshot
+	require.False(t, newDashboardIndex(dashboardLoader, nil, "").loadSnapshot(6))
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	now := time.Now()
+	require.NoError(t, validateSnapshot(&indexSnapshot{Version: snapshotVersion, Created: now, LastEventID: 3}, 3, now))
+	require.Error(t, validateSnapshot(&indexSnapshot{Version: snapshotVersion + 1, Created: now}, 3, now))
+	require.Error(t, validateSnapshot(&indexSnapshot{Version: snapshotVersion, Created: now.Add(-maxSnapshotAge - time.Minute)}, 3, now))
+	require.Error(t, validateSnapshot(&indexSnapshot{Version: snapshotVersion, Created: now, LastEventID: 4}, 3, now))
+}
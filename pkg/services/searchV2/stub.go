@@ -2,6 +2,10 @@ package searchV2
 
 import (
 	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -32,3 +36,11 @@ func (s *stubSearchService) DoDashboardQuery(ctx context.Context, user *backend.
 func (s *stubSearchService) Run(_ context.Context) error {
 	return nil
 }
+
+func (s *stubSearchService) HandleGetIndexStats(c *models.ReqContext) response.Response {
+	return response.Error(http.StatusForbidden, "feature not enabled", nil)
+}
+
+func (s *stubSearchService) HandleReIndex(c *models.ReqContext) response.Response {
+	return response.Error(http.StatusForbidden, "feature not enabled", nil)
+}
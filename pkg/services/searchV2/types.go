@@ -3,16 +3,37 @@ package searchV2
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 )
 
+type FacetField struct {
+	Field string `json:"field"`
+	Limit int    `json:"limit,omitempty"` // explicit page size
+}
+
 type DashboardQuery struct {
-	Query string
+	Query      string       `json:"query"`
+	Location   string       `json:"location,omitempty"`   // parent folder UID, "general" for the root folder
+	Tags       []string     `json:"tags,omitempty"`       // dashboards must have all the tags
+	Datasource string       `json:"datasource,omitempty"` // datasource UID used by the dashboard or any panel
+	PanelType  string       `json:"panel_type,omitempty"` // plugin ID of any panel
+	Sort       string       `json:"sort,omitempty"`       // field to sort by, "-" prefix for descending
+	Facet      []FacetField `json:"facet,omitempty"`
+	From       int          `json:"from,omitempty"`  // for paging
+	Limit      int          `json:"limit,omitempty"` // explicit page size
 }
 
 type SearchService interface {
 	registry.BackgroundService
 	DoDashboardQuery(ctx context.Context, user *backend.User, orgId int64, query DashboardQuery) *backend.DataResponse
+
+	// Index stats for admins
+	HandleGetIndexStats(c *models.ReqContext) response.Response
+
+	// Rebuild the index from SQL and save a new snapshot
+	HandleReIndex(c *models.ReqContext) response.Response
 }
@@ -24,4 +24,16 @@ func addKVStoreMigrations(mg *Migrator) {
 	mg.AddMigration("create kv_store table v1", NewAddTableMigration(kvStoreV1))
 
 	mg.AddMigration("add index kv_store.org_id-namespace-key", NewAddIndexMigration(kvStoreV1, kvStoreV1.Indices[0]))
+
+	mg.AddMigration("add version column to kv_store", NewAddColumnMigration(kvStoreV1, &Column{
+		Name: "version", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add expires column to kv_store", NewAddColumnMigration(kvStoreV1, &Column{
+		Name: "expires", Type: DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("add index kv_store.expires", NewAddIndexMigration(kvStoreV1, &Index{
+		Cols: []string{"expires"},
+	}))
 }
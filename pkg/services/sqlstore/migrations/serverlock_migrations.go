@@ -19,4 +19,16 @@ func addServerlockMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create server_lock table", migrator.NewAddTableMigration(serverLock))
 
 	mg.AddMigration("add index server_lock.operation_uid", migrator.NewAddIndexMigration(serverLock, serverLock.Indices[0]))
+
+	mg.AddMigration("add lease_owner column to server_lock", migrator.NewAddColumnMigration(serverLock, &migrator.Column{
+		Name: "lease_owner", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+
+	mg.AddMigration("add lease_expires column to server_lock", migrator.NewAddColumnMigration(serverLock, &migrator.Column{
+		Name: "lease_expires", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add lease_token column to server_lock", migrator.NewAddColumnMigration(serverLock, &migrator.Column{
+		Name: "lease_token", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
 
 type OrgListResponse []struct {
@@ -288,6 +289,10 @@ func (m *SQLStoreMock) NewSession(ctx context.Context) *sqlstore.DBSession {
 	return nil
 }
 
+func (m *SQLStoreMock) GetDialect() migrator.Dialect {
+	return nil
+}
+
 func (m *SQLStoreMock) WithDbSession(ctx context.Context, callback sqlstore.DBTransactionFunc) error {
 	return m.ExpectedError
 }
@@ -5,6 +5,9 @@ type RootStorageConfig struct {
 	Prefix string `json:"prefix"`
 	Name   string `json:"name"`
 
+	// Keep every version of the files written to this root
+	Versioned bool `json:"versioned,omitempty"`
+
 	// Depending on type, these will be configured
 	Disk *StorageLocalDiskConfig `json:"disk,omitempty"`
 	Git  *StorageGitConfig       `json:"git,omitempty"`
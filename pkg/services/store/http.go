@@ -1,10 +1,13 @@
 package store
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -15,6 +18,10 @@ type HTTPStorageService interface {
 	Read(c *models.ReqContext) response.Response
 	Delete(c *models.ReqContext) response.Response
 	Upload(c *models.ReqContext) response.Response
+	ListVersions(c *models.ReqContext) response.Response
+	ReadVersion(c *models.ReqContext) response.Response
+	DiffVersions(c *models.ReqContext) response.Response
+	RestoreVersion(c *models.ReqContext) response.Response
 }
 
 type httpStorage struct {
@@ -55,6 +62,9 @@ func (s *httpStorage) Read(c *models.ReqContext) response.Response {
 	// full path is api/storage/read/upload/example.jpg, but we only want the part after read
 	scope, path := getPathAndScope(c)
 	file, err := s.store.Read(c.Req.Context(), c.SignedInUser, scope+"/"+path)
+	if errors.Is(err, ErrAccessDenied) {
+		return response.Error(403, "cannot call read", err)
+	}
 	if err != nil {
 		return response.Error(400, "cannot call read", err)
 	}
@@ -82,6 +92,9 @@ func (s *httpStorage) List(c *models.ReqContext) response.Response {
 	params := web.Params(c.Req)
 	path := params["*"]
 	frame, err := s.store.List(c.Req.Context(), c.SignedInUser, path)
+	if errors.Is(err, ErrAccessDenied) {
+		return response.Error(403, "error reading path", err)
+	}
 	if err != nil {
 		return response.Error(400, "error reading path", err)
 	}
@@ -90,3 +103,70 @@ func (s *httpStorage) List(c *models.ReqContext) response.Response {
 	}
 	return response.JSONStreaming(http.StatusOK, frame)
 }
+
+func versionErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ErrAccessDenied):
+		return response.Error(403, err.Error(), err)
+	case errors.Is(err, ErrNotVersioned), errors.Is(err, ErrVersionNotJSON):
+		return response.Error(400, err.Error(), err)
+	case errors.Is(err, filestorage.ErrVersionNotFound):
+		return response.Error(404, "version not found", err)
+	}
+	return response.Error(500, message, err)
+}
+
+func (s *httpStorage) ListVersions(c *models.ReqContext) response.Response {
+	// full path is api/storage/versions/upload/example.json
+	scope, path := getPathAndScope(c)
+	frame, err := s.store.ListVersions(c.Req.Context(), c.SignedInUser, scope+"/"+path)
+	if err != nil {
+		return versionErrorResponse(err, "error listing versions")
+	}
+	return response.JSONStreaming(http.StatusOK, frame)
+}
+
+func (s *httpStorage) ReadVersion(c *models.ReqContext) response.Response {
+	// full path is api/storage/version/upload/example.json?version=xyz
+	scope, path := getPathAndScope(c)
+	file, err := s.store.ReadVersion(c.Req.Context(), c.SignedInUser, scope+"/"+path, c.Query("version"))
+	if err != nil {
+		return versionErrorResponse(err, "cannot read version")
+	}
+	if file == nil {
+		return response.Error(404, "version not found", nil)
+	}
+	// set the correct content type for svg
+	if strings.HasSuffix(path, ".svg") {
+		c.Resp.Header().Set("Content-Type", "image/svg+xml")
+	}
+	return response.Respond(200, file.Contents)
+}
+
+func (s *httpStorage) DiffVersions(c *models.ReqContext) response.Response {
+	// full path is api/storage/diff/upload/example.json?base=abc&new=xyz&diffType=json
+	scope, path := getPathAndScope(c)
+	diffType := dashdiffs.ParseDiffType(c.Query("diffType"))
+	result, err := s.store.DiffVersions(c.Req.Context(), c.SignedInUser, scope+"/"+path, c.Query("base"), c.Query("new"), diffType)
+	if err != nil {
+		return versionErrorResponse(err, "unable to compute diff")
+	}
+	if diffType == dashdiffs.DiffDelta {
+		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
+	}
+	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
+}
+
+func (s *httpStorage) RestoreVersion(c *models.ReqContext) response.Response {
+	// full path is api/storage/restore/upload/example.json?version=xyz
+	scope, path := getPathAndScope(c)
+	err := s.store.RestoreVersion(c.Req.Context(), c.SignedInUser, scope+"/"+path, c.Query("version"))
+	if err != nil {
+		return versionErrorResponse(err, "cannot restore version")
+	}
+	return response.JSON(200, map[string]string{
+		"message": "Restored version",
+		"path":    scope + "/" + path,
+		"version": c.Query("version"),
+	})
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"mime/multipart"
@@ -10,6 +11,8 @@ import (
 	"path/filepath"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/filestorage"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -17,10 +20,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
+	"gocloud.dev/blob"
 )
 
 var grafanaStorageLogger = log.New("grafanaStorageLogger")
 
+var (
+	ErrNotVersioned   = errors.New("storage does not keep versions")
+	ErrVersionNotJSON = errors.New("only JSON versions can be compared")
+	ErrAccessDenied   = errors.New("access denied")
+)
+
 const RootPublicStatic = "public-static"
 const MAX_UPLOAD_SIZE = 1024 * 1024 // 1MB
 type StorageService interface {
@@ -35,6 +45,18 @@ type StorageService interface {
 	Upload(ctx context.Context, user *models.SignedInUser, form *multipart.Form) (*Response, error)
 
 	Delete(ctx context.Context, user *models.SignedInUser, path string) error
+
+	// List the saved versions of a file, newest first
+	ListVersions(ctx context.Context, user *models.SignedInUser, path string) (*data.Frame, error)
+
+	// Read the contents of a saved version
+	ReadVersion(ctx context.Context, user *models.SignedInUser, path string, version string) (*filestorage.File, error)
+
+	// Compare two saved versions of a JSON file
+	DiffVersions(ctx context.Context, user *models.SignedInUser, path string, base string, new string, diffType dashdiffs.DiffType) (*dashdiffs.Result, error)
+
+	// Save the contents of an old version as the newest version
+	RestoreVersion(ctx context.Context, user *models.SignedInUser, path string, version string) error
 }
 
 type standardStorageService struct {
@@ -78,11 +100,46 @@ func ProvideService(sql *sqlstore.SQLStore, features featuremgmt.FeatureToggles,
 			},
 		}).setBuiltin(true))
 	}
+
+	// versioning is opt-in per root, read only roots are never written and have no history
+	versioned := make(map[string]bool, len(cfg.StorageVersionedRoots))
+	for _, prefix := range cfg.StorageVersionedRoots {
+		versioned[prefix] = true
+	}
+	for _, root := range roots {
+		meta := root.Meta()
+		if !versioned[meta.Config.Prefix] {
+			continue
+		}
+		if meta.ReadOnly {
+			grafanaStorageLogger.Warn("read only storage can not be versioned", "prefix", meta.Config.Prefix)
+			continue
+		}
+		root.setVersioned(newHistoryStorage(sql, storage, meta.Config.Prefix))
+	}
+
 	s := newStandardStorageService(roots)
 	s.sql = sql
 	return s
 }
 
+// newHistoryStorage returns where the versions of a root are kept.  The SQL database
+// is preferred so that the history is shared, with a local folder as fallback
+func newHistoryStorage(sql *sqlstore.SQLStore, storage string, prefix string) filestorage.FileStorage {
+	if sql != nil {
+		return filestorage.NewDbStorage(grafanaStorageLogger, sql, nil, filestorage.Join("history", prefix)+filestorage.Delimiter)
+	}
+
+	history := filepath.Join(storage, "history", prefix)
+	_ = os.MkdirAll(history, 0700)
+	bucket, err := blob.OpenBucket(context.Background(), fmt.Sprintf("file://%s", history))
+	if err != nil {
+		grafanaStorageLogger.Warn("error loading history storage", "prefix", prefix, "err", err)
+		return nil
+	}
+	return filestorage.NewCdkBlobStorage(grafanaStorageLogger, bucket, "", nil)
+}
+
 func newStandardStorageService(roots []storageRuntime) *standardStorageService {
 	res := &nestedTree{
 		roots: roots,
@@ -98,13 +155,44 @@ func (s *standardStorageService) Run(ctx context.Context) error {
 	return nil
 }
 
+// canRead checks that the user can read the files of the root of the path, every member of an organization can.
+// Internal callers without a user, like the Grafana data source, can read the read only roots
+func (s *standardStorageService) canRead(user *models.SignedInUser, path string) error {
+	if user == nil {
+		if meta, ok := s.tree.getRootMeta(path); ok && meta.ReadOnly {
+			return nil
+		}
+		return ErrAccessDenied
+	}
+	if user.OrgRole == "" && !user.IsGrafanaAdmin {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// canWrite checks that the user can change the files of the root of the path, editors can write to every
+// root that is not read only
+func (s *standardStorageService) canWrite(user *models.SignedInUser, path string) error {
+	if user == nil || !user.HasRole(models.ROLE_EDITOR) {
+		return ErrAccessDenied
+	}
+	if meta, ok := s.tree.getRootMeta(path); ok && meta.ReadOnly {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
 func (s *standardStorageService) List(ctx context.Context, user *models.SignedInUser, path string) (*data.Frame, error) {
-	// apply access control here
+	if err := s.canRead(user, path); err != nil {
+		return nil, err
+	}
 	return s.tree.ListFolder(ctx, path)
 }
 
 func (s *standardStorageService) Read(ctx context.Context, user *models.SignedInUser, path string) (*filestorage.File, error) {
-	// TODO: permission check!
+	if err := s.canRead(user, path); err != nil {
+		return nil, err
+	}
 	return s.tree.GetFile(ctx, path)
 }
 
@@ -163,10 +251,15 @@ func (s *standardStorageService) Upload(ctx context.Context, user *models.Signed
 				err:        true,
 			}, nil
 		}
-		err = upload.Upsert(ctx, &filestorage.UpsertFileCommand{
+		cmd := &filestorage.UpsertFileCommand{
 			Path:     path,
 			Contents: data,
-		})
+		}
+		if versioned, ok := upload.(filestorage.VersionedFileStorage); ok {
+			err = versioned.UpsertVersion(ctx, cmd, versionInfo(user, "Uploaded "+fileHeader.Filename))
+		} else {
+			err = upload.Upsert(ctx, cmd)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -189,3 +282,108 @@ func (s *standardStorageService) Delete(ctx context.Context, user *models.Signed
 	}
 	return nil
 }
+
+func versionInfo(user *models.SignedInUser, message string) filestorage.VersionInfo {
+	info := filestorage.VersionInfo{Message: message}
+	if user != nil {
+		info.Author = user.Login
+	}
+	return info
+}
+
+func (s *standardStorageService) ListVersions(ctx context.Context, user *models.SignedInUser, path string) (*data.Frame, error) {
+	if err := s.canRead(user, path); err != nil {
+		return nil, err
+	}
+	root, path := s.tree.getVersionedRoot(path)
+	if root == nil {
+		return nil, ErrNotVersioned
+	}
+	versions, err := root.ListVersions(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	count := len(versions)
+	ids := data.NewFieldFromFieldType(data.FieldTypeString, count)
+	authors := data.NewFieldFromFieldType(data.FieldTypeString, count)
+	messages := data.NewFieldFromFieldType(data.FieldTypeString, count)
+	created := data.NewFieldFromFieldType(data.FieldTypeTime, count)
+	fsize := data.NewFieldFromFieldType(data.FieldTypeInt64, count)
+	ids.Name = "version"
+	authors.Name = "author"
+	messages.Name = "message"
+	created.Name = "created"
+	fsize.Name = "size"
+	fsize.Config = &data.FieldConfig{
+		Unit: "bytes",
+	}
+	for i, v := range versions {
+		ids.Set(i, v.ID)
+		authors.Set(i, v.Author)
+		messages.Set(i, v.Message)
+		created.Set(i, v.Created)
+		fsize.Set(i, v.Size)
+	}
+	return data.NewFrame("", ids, authors, messages, created, fsize), nil
+}
+
+func (s *standardStorageService) ReadVersion(ctx context.Context, user *models.SignedInUser, path string, version string) (*filestorage.File, error) {
+	if err := s.canRead(user, path); err != nil {
+		return nil, err
+	}
+	root, path := s.tree.getVersionedRoot(path)
+	if root == nil {
+		return nil, ErrNotVersioned
+	}
+	return root.GetVersion(ctx, path, version)
+}
+
+func (s *standardStorageService) readJSONVersion(ctx context.Context, root filestorage.VersionedFileStorage, path string, version string) (*simplejson.Json, error) {
+	file, err := root.GetVersion(ctx, path, version)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, filestorage.ErrVersionNotFound
+	}
+	js, err := simplejson.NewJson(file.Contents)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVersionNotJSON, err.Error())
+	}
+	return js, nil
+}
+
+func (s *standardStorageService) DiffVersions(ctx context.Context, user *models.SignedInUser, path string, base string, new string, diffType dashdiffs.DiffType) (*dashdiffs.Result, error) {
+	if err := s.canRead(user, path); err != nil {
+		return nil, err
+	}
+	root, path := s.tree.getVersionedRoot(path)
+	if root == nil {
+		return nil, ErrNotVersioned
+	}
+	baseData, err := s.readJSONVersion(ctx, root, path, base)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := s.readJSONVersion(ctx, root, path, new)
+	if err != nil {
+		return nil, err
+	}
+	result, err := dashdiffs.CalculateDiff(ctx, &dashdiffs.Options{DiffType: diffType}, baseData, newData)
+	if errors.Is(err, dashdiffs.ErrNilDiff) {
+		return &dashdiffs.Result{}, nil
+	}
+	return result, err
+}
+
+func (s *standardStorageService) RestoreVersion(ctx context.Context, user *models.SignedInUser, path string, version string) error {
+	if err := s.canWrite(user, path); err != nil {
+		return err
+	}
+	root, path := s.tree.getVersionedRoot(path)
+	if root == nil {
+		return ErrNotVersioned
+	}
+	return root.RestoreVersion(ctx, path, version, versionInfo(user, ""))
+}
@@ -10,11 +10,15 @@ import (
 	"testing"
 
 	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/testdatasource"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
 )
 
 func TestListFiles(t *testing.T) {
@@ -65,3 +69,83 @@ func TestUpload(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, res.path, "upload")
 }
+
+func TestVersionedRootsFromConfig(t *testing.T) {
+	features := featuremgmt.WithFeatures(featuremgmt.FlagStorageLocalUpload)
+	cfg := &setting.Cfg{DataPath: t.TempDir(), StaticRootPath: t.TempDir()}
+	s := ProvideService(nil, features, cfg).(*standardStorageService)
+	root, _ := s.tree.getVersionedRoot("upload")
+	require.Nil(t, root)
+
+	cfg.StorageVersionedRoots = []string{"upload", RootPublicStatic}
+	s = ProvideService(nil, features, cfg).(*standardStorageService)
+	root, _ = s.tree.getVersionedRoot("upload")
+	require.NotNil(t, root)
+	meta, _ := s.tree.getRootMeta("upload")
+	require.True(t, meta.Config.Versioned)
+
+	// read only roots are never versioned
+	static, _ := s.tree.getVersionedRoot(RootPublicStatic)
+	require.Nil(t, static)
+}
+
+func TestFileVersions(t *testing.T) {
+	ctx := context.Background()
+	history, err := blob.OpenBucket(ctx, "mem://")
+	require.NoError(t, err)
+	roots := []storageRuntime{
+		newDiskStorage("upload", "Local file upload", &StorageLocalDiskConfig{
+			Path:  t.TempDir(),
+			Roots: []string{"/"},
+		}).setVersioned(filestorage.NewCdkBlobStorage(grafanaStorageLogger, history, "", nil)),
+	}
+	store := newStandardStorageService(roots)
+	require.True(t, roots[0].Meta().Config.Versioned)
+
+	root, _ := store.tree.getVersionedRoot("upload")
+	require.NotNil(t, root)
+	user := &models.SignedInUser{Login: "admin", OrgRole: models.ROLE_EDITOR}
+	for _, body := range []string{`{"title":"a"}`, `{"title":"b"}`} {
+		err = root.UpsertVersion(ctx, &filestorage.UpsertFileCommand{Path: "/dash.json", Contents: []byte(body)}, versionInfo(user, "save"))
+		require.NoError(t, err)
+	}
+
+	frame, err := store.ListVersions(ctx, user, "upload/dash.json")
+	require.NoError(t, err)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, "admin", frame.Fields[1].At(0))
+	newest := frame.Fields[0].At(0).(string)
+	oldest := frame.Fields[0].At(1).(string)
+
+	file, err := store.ReadVersion(ctx, user, "upload/dash.json", oldest)
+	require.NoError(t, err)
+	require.Equal(t, `{"title":"a"}`, string(file.Contents))
+
+	diff, err := store.DiffVersions(ctx, user, "upload/dash.json", oldest, newest, dashdiffs.DiffDelta)
+	require.NoError(t, err)
+	require.Contains(t, string(diff.Delta), "title")
+
+	diff, err = store.DiffVersions(ctx, user, "upload/dash.json", oldest, oldest, dashdiffs.DiffDelta)
+	require.NoError(t, err)
+	require.Empty(t, diff.Delta)
+
+	require.NoError(t, store.RestoreVersion(ctx, user, "upload/dash.json", oldest))
+	file, err = store.Read(ctx, user, "upload/dash.json")
+	require.NoError(t, err)
+	require.Equal(t, `{"title":"a"}`, string(file.Contents))
+
+	require.ErrorIs(t, store.RestoreVersion(ctx, user, "upload/dash.json", "1"), filestorage.ErrVersionNotFound)
+	_, err = store.ListVersions(ctx, user, "other/dash.json")
+	require.ErrorIs(t, err, ErrNotVersioned)
+
+	viewer := &models.SignedInUser{Login: "viewer", OrgRole: models.ROLE_VIEWER}
+	_, err = store.ListVersions(ctx, viewer, "upload/dash.json")
+	require.NoError(t, err)
+	require.ErrorIs(t, store.RestoreVersion(ctx, viewer, "upload/dash.json", oldest), ErrAccessDenied)
+	_, err = store.ListVersions(ctx, nil, "upload/dash.json")
+	require.ErrorIs(t, err, ErrAccessDenied)
+	_, err = store.ReadVersion(ctx, nil, "upload/dash.json", oldest)
+	require.ErrorIs(t, err, ErrAccessDenied)
+	_, err = store.DiffVersions(ctx, nil, "upload/dash.json", oldest, newest, dashdiffs.DiffDelta)
+	require.ErrorIs(t, err, ErrAccessDenied)
+}
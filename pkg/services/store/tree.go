@@ -36,6 +36,26 @@ func (t *nestedTree) getRoot(path string) (filestorage.FileStorage, string) {
 	return root, filestorage.Delimiter + path
 }
 
+// getRootMeta returns the meta data of the root of the path
+func (t *nestedTree) getRootMeta(path string) (RootStorageMeta, bool) {
+	rootKey, _ := splitFirstSegment(path)
+	for _, root := range t.roots {
+		if root.Meta().Config.Prefix == rootKey {
+			return root.Meta(), true
+		}
+	}
+	return RootStorageMeta{}, false
+}
+
+// getVersionedRoot returns the root storage when it keeps versions
+func (t *nestedTree) getVersionedRoot(path string) (filestorage.VersionedFileStorage, string) {
+	root, path := t.getRoot(path)
+	if versioned, ok := root.(filestorage.VersionedFileStorage); ok {
+		return versioned, path
+	}
+	return nil, path
+}
+
 func (t *nestedTree) GetFile(ctx context.Context, path string) (*filestorage.File, error) {
 	if path == "" {
 		return nil, nil // not found
@@ -46,6 +46,9 @@ type storageRuntime interface {
 
 	// Different storage knows how to handle comments and tracking
 	Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error)
+
+	// Keep the versions of the files in the history storage
+	setVersioned(history filestorage.FileStorage) *baseStorageRuntime
 }
 
 type baseStorageRuntime struct {
@@ -82,6 +85,15 @@ func (t *baseStorageRuntime) setBuiltin(val bool) *baseStorageRuntime {
 	return t
 }
 
+// setVersioned saves the history of every file to the history storage
+func (t *baseStorageRuntime) setVersioned(history filestorage.FileStorage) *baseStorageRuntime {
+	if t.store != nil && history != nil {
+		t.store = filestorage.NewVersionedStorage(grafanaStorageLogger, t.store, history)
+		t.meta.Config.Versioned = true
+	}
+	return t
+}
+
 type RootStorageMeta struct {
 	ReadOnly bool          `json:"editable,omitempty"`
 	Builtin  bool          `json:"builtin,omitempty"`
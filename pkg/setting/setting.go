@@ -379,6 +379,9 @@ type Cfg struct {
 	// Data sources
 	DataSourceLimit int
 
+	// Storage
+	StorageVersionedRoots []string
+
 	// Snapshots
 	SnapshotPublicMode bool
 
@@ -1005,6 +1008,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	}
 
 	cfg.readDataSourcesSettings()
+	cfg.readStorageSettings()
 
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 
@@ -1536,6 +1540,11 @@ func (cfg *Cfg) readDataSourcesSettings() {
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
 }
 
+func (cfg *Cfg) readStorageSettings() {
+	storage := cfg.Raw.Section("storage")
+	cfg.StorageVersionedRoots = util.SplitString(storage.Key("versioned_roots").String())
+}
+
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
 	var originGlobs []glob.Glob
 	allowedOrigins := originPatterns
@@ -48,6 +48,11 @@ const (
 	schedulereDefaultExecuteAlerts          = true
 	schedulerDefaultMaxAttempts             = 3
 	schedulerDefaultLegacyMinInterval       = 1
+	stateHistoryDefaultRetention            = 30 * 24 * time.Hour
+	screenshotsDefaultCaptureTimeout        = 10 * time.Second
+	screenshotsDefaultRetention             = 24 * time.Hour
+	deliveryLogDefaultRetention             = 30 * 24 * time.Hour
+	deliveryRetryQueueDefaultMaxAttempts    = 10
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -77,6 +82,25 @@ type UnifiedAlertingSettings struct {
 	BaseInterval time.Duration
 	// DefaultRuleEvaluationInterval default interval between evaluations of a rule.
 	DefaultRuleEvaluationInterval time.Duration
+	// StateHistoryRetention is how long state transitions of alert instances are kept.
+	StateHistoryRetention time.Duration
+	// RecordingRulesRemoteWriteURL is the Prometheus remote write endpoint recording rules write their metrics to.
+	RecordingRulesRemoteWriteURL      string
+	RecordingRulesRemoteWriteUser     string
+	RecordingRulesRemoteWritePassword string
+	// HARuleSharding splits the evaluation of alert rules between the members of the HA cluster.
+	HARuleSharding bool
+	// ScreenshotsCapture enables screenshots of the panels alert rules are linked to.
+	ScreenshotsCapture                    bool
+	ScreenshotsCaptureTimeout             time.Duration
+	ScreenshotsUploadExternalImageStorage bool
+	// ScreenshotsRetention is how long screenshots are kept before they are deleted.
+	ScreenshotsRetention time.Duration
+	// DeliveryLogRetention is how long the delivery attempts of notifications are kept.
+	DeliveryLogRetention time.Duration
+	// DeliveryRetryQueue enables the retry queue for notifications that could not be delivered.
+	DeliveryRetryQueue            bool
+	DeliveryRetryQueueMaxAttempts int
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -244,6 +268,53 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
 	}
 
+	uaStateHistoryRetention, err := gtime.ParseDuration(valueAsString(ua, "state_history_retention", stateHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaStateHistoryRetention <= 0 {
+		return fmt.Errorf("value of setting 'state_history_retention' should be greater than 0")
+	}
+	uaCfg.StateHistoryRetention = uaStateHistoryRetention
+
+	uaCfg.HARuleSharding = ua.Key("ha_rule_sharding").MustBool(false)
+
+	uaCfg.RecordingRulesRemoteWriteURL = valueAsString(ua, "recording_rules_remote_write_url", "")
+	uaCfg.RecordingRulesRemoteWriteUser = valueAsString(ua, "recording_rules_remote_write_user", "")
+	uaCfg.RecordingRulesRemoteWritePassword = valueAsString(ua, "recording_rules_remote_write_password", "")
+
+	screenshots := iniFile.Section("unified_alerting.screenshots")
+	uaCfg.ScreenshotsCapture = screenshots.Key("capture").MustBool(false)
+	uaCfg.ScreenshotsCaptureTimeout, err = gtime.ParseDuration(valueAsString(screenshots, "capture_timeout", screenshotsDefaultCaptureTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.ScreenshotsCaptureTimeout <= 0 {
+		return fmt.Errorf("value of setting 'capture_timeout' should be greater than 0")
+	}
+	uaCfg.ScreenshotsUploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(false)
+	uaCfg.ScreenshotsRetention, err = gtime.ParseDuration(valueAsString(screenshots, "retention", screenshotsDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.ScreenshotsRetention <= 0 {
+		return fmt.Errorf("value of setting 'retention' should be greater than 0")
+	}
+
+	delivery := iniFile.Section("unified_alerting.delivery")
+	uaCfg.DeliveryLogRetention, err = gtime.ParseDuration(valueAsString(delivery, "log_retention", deliveryLogDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.DeliveryLogRetention <= 0 {
+		return fmt.Errorf("value of setting 'log_retention' should be greater than 0")
+	}
+	uaCfg.DeliveryRetryQueue = delivery.Key("retry_queue_enabled").MustBool(false)
+	uaCfg.DeliveryRetryQueueMaxAttempts = delivery.Key("retry_queue_max_attempts").MustInt(deliveryRetryQueueDefaultMaxAttempts)
+	if uaCfg.DeliveryRetryQueueMaxAttempts <= 0 {
+		return fmt.Errorf("value of setting 'retry_queue_max_attempts' should be greater than 0")
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
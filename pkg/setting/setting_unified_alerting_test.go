@@ -25,6 +25,10 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 0)
 		require.Equal(t, 200*time.Millisecond, cfg.UnifiedAlerting.HAGossipInterval)
 		require.Equal(t, 60*time.Second, cfg.UnifiedAlerting.HAPushPullInterval)
+		require.Equal(t, 30*24*time.Hour, cfg.UnifiedAlerting.StateHistoryRetention)
+		require.Equal(t, 30*24*time.Hour, cfg.UnifiedAlerting.DeliveryLogRetention)
+		require.False(t, cfg.UnifiedAlerting.DeliveryRetryQueue)
+		require.Equal(t, 10, cfg.UnifiedAlerting.DeliveryRetryQueueMaxAttempts)
 	}
 
 	// With peers set, it correctly parses them.
@@ -29,12 +29,20 @@ type SearchDebugInfo struct {
 	Response *SearchResponseInfo `json:"response"`
 }
 
+// HighlightPreTag and HighlightPostTag surround the highlighted matches of a query in the hits.
+const (
+	HighlightPreTag  = "@HIGHLIGHT@"
+	HighlightPostTag = "@/HIGHLIGHT@"
+)
+
 // SearchRequest represents a search request
 type SearchRequest struct {
-	Index       string
-	Interval    intervalv2.Interval
-	Size        int
-	Sort        map[string]interface{}
+	Index    string
+	Interval intervalv2.Interval
+	Size     int
+	Sort     map[string]interface{}
+	// SortOrder is the order of the fields in Sort, hits are sorted by the first field first
+	SortOrder   []string
 	Query       *Query
 	Aggs        AggArray
 	CustomProps map[string]interface{}
@@ -45,8 +53,15 @@ func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 	root := make(map[string]interface{})
 
 	root["size"] = r.Size
-	if len(r.Sort) > 0 {
+	if len(r.Sort) == 1 {
 		root["sort"] = r.Sort
+	} else if len(r.Sort) > 1 {
+		// the order of the fields of an object is not preserved
+		sort := make([]map[string]interface{}, 0, len(r.SortOrder))
+		for _, field := range r.SortOrder {
+			sort = append(sort, map[string]interface{}{field: r.Sort[field]})
+		}
+		root["sort"] = sort
 	}
 
 	for key, value := range r.CustomProps {
@@ -14,6 +14,7 @@ type SearchRequestBuilder struct {
 	index        string
 	size         int
 	sort         map[string]interface{}
+	sortOrder    []string
 	queryBuilder *QueryBuilder
 	aggBuilders  []AggBuilder
 	customProps  map[string]interface{}
@@ -38,6 +39,7 @@ func (b *SearchRequestBuilder) Build() (*SearchRequest, error) {
 		Interval:    b.interval,
 		Size:        b.size,
 		Sort:        b.sort,
+		SortOrder:   b.sortOrder,
 		CustomProps: b.customProps,
 	}
 
@@ -80,11 +82,36 @@ func (b *SearchRequestBuilder) SortDesc(field, unmappedType string) *SearchReque
 		props["unmapped_type"] = unmappedType
 	}
 
+	if _, exists := b.sort[field]; !exists {
+		b.sortOrder = append(b.sortOrder, field)
+	}
 	b.sort[field] = props
 
 	return b
 }
 
+// SearchAfter sets the sort values of the last hit of the previous page, to return the next page of hits
+func (b *SearchRequestBuilder) SearchAfter(values []interface{}) *SearchRequestBuilder {
+	if len(values) > 0 {
+		b.customProps["search_after"] = values
+	}
+	return b
+}
+
+// AddHighlight highlights the matches of the query in all fields of the hits, surrounded by the highlight tags
+func (b *SearchRequestBuilder) AddHighlight() *SearchRequestBuilder {
+	b.customProps["highlight"] = map[string]interface{}{
+		"fields": map[string]interface{}{
+			"*": map[string]interface{}{},
+		},
+		"pre_tags":  []string{HighlightPreTag},
+		"post_tags": []string{HighlightPostTag},
+		// highlight whole values instead of fragments of them
+		"fragment_size": 2147483647,
+	}
+	return b
+}
+
 // AddDocValueField adds a doc value field to the search request
 func (b *SearchRequestBuilder) AddDocValueField(field string) *SearchRequestBuilder {
 	// fields field not supported on version >= 5
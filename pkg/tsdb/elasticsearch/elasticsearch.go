@@ -86,6 +86,16 @@ func newInstanceSettings() datasource.InstanceFactoryFunc {
 			return nil, errors.New("elasticsearch time field name is required")
 		}
 
+		logMessageField, ok := jsonData["logMessageField"].(string)
+		if !ok {
+			logMessageField = ""
+		}
+
+		logLevelField, ok := jsonData["logLevelField"].(string)
+		if !ok {
+			logLevelField = ""
+		}
+
 		interval, ok := jsonData["interval"].(string)
 		if !ok {
 			interval = ""
@@ -128,6 +138,8 @@ func newInstanceSettings() datasource.InstanceFactoryFunc {
 			MaxConcurrentShardRequests: int64(maxConcurrentShardRequests),
 			ESVersion:                  version,
 			TimeField:                  timeField,
+			LogMessageField:            logMessageField,
+			LogLevelField:              logLevelField,
 			Interval:                   interval,
 			TimeInterval:               timeInterval,
 			IncludeFrozen:              includeFrozen,
@@ -1,6 +1,7 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"errors"
 	"regexp"
 	"sort"
@@ -20,6 +21,10 @@ const (
 	percentilesType   = "percentiles"
 	extendedStatsType = "extended_stats"
 	topMetricsType    = "top_metrics"
+	// Document types
+	rawDocumentType = "raw_document"
+	rawDataType     = "raw_data"
+	logsType        = "logs"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	histogramType   = "histogram"
@@ -29,16 +34,18 @@ const (
 )
 
 type responseParser struct {
-	Responses []*es.SearchResponse
-	Targets   []*Query
-	DebugInfo *es.SearchDebugInfo
+	Responses        []*es.SearchResponse
+	Targets          []*Query
+	DebugInfo        *es.SearchDebugInfo
+	ConfiguredFields es.ConfiguredFields
 }
 
-var newResponseParser = func(responses []*es.SearchResponse, targets []*Query, debugInfo *es.SearchDebugInfo) *responseParser {
+var newResponseParser = func(responses []*es.SearchResponse, targets []*Query, debugInfo *es.SearchDebugInfo, configuredFields es.ConfiguredFields) *responseParser {
 	return &responseParser{
-		Responses: responses,
-		Targets:   targets,
-		DebugInfo: debugInfo,
+		Responses:        responses,
+		Targets:          targets,
+		DebugInfo:        debugInfo,
+		ConfiguredFields: configuredFields,
 	}
 }
 
@@ -72,6 +79,11 @@ func (rp *responseParser) getTimeSeries() (*backend.QueryDataResponse, error) {
 			continue
 		}
 
+		if isDocumentQuery(target) {
+			result.Responses[target.RefID] = rp.processDocuments(res, target, debugInfo)
+			continue
+		}
+
 		queryRes := backend.DataResponse{}
 
 		props := make(map[string]string)
@@ -719,3 +731,263 @@ func getErrorFromElasticResponse(response *es.SearchResponse) string {
 
 	return errorString
 }
+
+// maxFlattenDepth is the maximum depth of nested objects that are flattened into fields. Deeper objects are
+// encoded as JSON.
+const maxFlattenDepth = 10
+
+func isDocumentQuery(target *Query) bool {
+	if len(target.BucketAggs) > 0 || len(target.Metrics) == 0 {
+		return false
+	}
+	return target.Metrics[0].Type == rawDataType || target.Metrics[0].Type == logsType
+}
+
+// processDocuments returns a frame with one row per hit. Nested objects are flattened into fields with dotted names,
+// the time field comes first. Logs frames additionally have the message field second and a level field with the
+// standard severity of the configured level field.
+func (rp *responseParser) processDocuments(res *es.SearchResponse, target *Query, debugInfo *simplejson.Json) backend.DataResponse {
+	timeField := target.TimeField
+	if timeField == "" {
+		timeField = rp.ConfiguredFields.TimeField
+	}
+	isLogs := target.Metrics[0].Type == logsType
+
+	var hits []map[string]interface{}
+	if res.Hits != nil {
+		hits = res.Hits.Hits
+	}
+	docs := make([]map[string]interface{}, 0, len(hits))
+	fieldNames := make(map[string]struct{})
+	searchWords := make(map[string]struct{})
+	for _, hit := range hits {
+		doc := map[string]interface{}{
+			"_id":    hit["_id"],
+			"_type":  hit["_type"],
+			"_index": hit["_index"],
+		}
+		if source, ok := hit["_source"].(map[string]interface{}); ok {
+			flatten(source, "", 0, doc)
+		}
+		// the time requested as doc value field is formatted consistently
+		if fields, ok := hit["fields"].(map[string]interface{}); ok {
+			if values, ok := fields[timeField].([]interface{}); ok && len(values) > 0 {
+				doc[timeField] = values[0]
+			}
+		}
+		if highlight, ok := hit["highlight"].(map[string]interface{}); ok {
+			for _, fragments := range highlight {
+				addSearchWords(fragments, searchWords)
+			}
+		}
+		for name := range doc {
+			fieldNames[name] = struct{}{}
+		}
+		docs = append(docs, doc)
+	}
+
+	// the configured level field is replaced by the mapped "level" field, which must be the only field with that name
+	hasLevel := isLogs && rp.ConfiguredFields.LogLevelField != ""
+	names := make([]string, 0, len(fieldNames))
+	for name := range fieldNames {
+		if name == timeField || isLogs && name == rp.ConfiguredFields.LogMessageField {
+			continue
+		}
+		if hasLevel && (name == rp.ConfiguredFields.LogLevelField || name == "level") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	times := make([]*time.Time, 0, len(docs))
+	for _, doc := range docs {
+		times = append(times, parseDocumentTime(doc[timeField]))
+	}
+	fields := []*data.Field{data.NewField(timeField, nil, times)}
+	if isLogs && rp.ConfiguredFields.LogMessageField != "" {
+		fields = append(fields, newDocumentField(rp.ConfiguredFields.LogMessageField, docs))
+	}
+	if hasLevel {
+		levels := make([]*string, 0, len(docs))
+		for _, doc := range docs {
+			level := logLevel(doc[rp.ConfiguredFields.LogLevelField])
+			levels = append(levels, &level)
+		}
+		fields = append(fields, data.NewField("level", nil, levels))
+	}
+	for _, name := range names {
+		fields = append(fields, newDocumentField(name, docs))
+	}
+
+	custom := map[string]interface{}{}
+	if debugInfo != nil {
+		custom = debugInfo.MustMap()
+	}
+	frame := data.NewFrame("", fields...)
+	frame.RefID = target.RefID
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable, Custom: custom}
+	if isLogs {
+		frame.Meta.PreferredVisualization = data.VisTypeLogs
+		words := make([]string, 0, len(searchWords))
+		for word := range searchWords {
+			words = append(words, word)
+		}
+		sort.Strings(words)
+		custom["searchWords"] = words
+		if len(hits) > 0 {
+			// the sort values of the last hit request the next page
+			custom["searchAfter"] = hits[len(hits)-1]["sort"]
+		}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// flatten adds the values of a document to the result, the fields of nested objects with dotted names.
+func flatten(doc map[string]interface{}, prefix string, depth int, result map[string]interface{}) {
+	for key, value := range doc {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && depth < maxFlattenDepth {
+			flatten(nested, name, depth+1, result)
+			continue
+		}
+		result[name] = value
+	}
+}
+
+// addSearchWords adds the highlighted matches of the fragments to the search words.
+func addSearchWords(fragments interface{}, searchWords map[string]struct{}) {
+	values, ok := fragments.([]interface{})
+	if !ok {
+		return
+	}
+	for _, v := range values {
+		fragment, ok := v.(string)
+		if !ok {
+			continue
+		}
+		for {
+			start := strings.Index(fragment, es.HighlightPreTag)
+			if start < 0 {
+				break
+			}
+			fragment = fragment[start+len(es.HighlightPreTag):]
+			end := strings.Index(fragment, es.HighlightPostTag)
+			if end < 0 {
+				break
+			}
+			searchWords[fragment[:end]] = struct{}{}
+			fragment = fragment[end+len(es.HighlightPostTag):]
+		}
+	}
+}
+
+// parseDocumentTime parses times formatted as string or as milliseconds since epoch.
+func parseDocumentTime(value interface{}) *time.Time {
+	var t time.Time
+	switch v := value.(type) {
+	case float64:
+		t = time.Unix(0, int64(v*float64(time.Millisecond))).UTC()
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil
+			}
+			parsed = time.UnixMilli(ms).UTC()
+		}
+		t = parsed
+	default:
+		return nil
+	}
+	return &t
+}
+
+// newDocumentField returns a field with the values of the documents. The field is numeric or boolean if all
+// values are, otherwise it has string values and values that are not strings are encoded as JSON.
+func newDocumentField(name string, docs []map[string]interface{}) *data.Field {
+	numeric, boolean := true, true
+	for _, doc := range docs {
+		switch doc[name].(type) {
+		case nil:
+		case float64:
+			boolean = false
+		case bool:
+			numeric = false
+		default:
+			numeric, boolean = false, false
+		}
+	}
+
+	switch {
+	case numeric:
+		values := make([]*float64, 0, len(docs))
+		for _, doc := range docs {
+			var value *float64
+			if v, ok := doc[name].(float64); ok {
+				value = &v
+			}
+			values = append(values, value)
+		}
+		return data.NewField(name, nil, values)
+	case boolean:
+		values := make([]*bool, 0, len(docs))
+		for _, doc := range docs {
+			var value *bool
+			if v, ok := doc[name].(bool); ok {
+				value = &v
+			}
+			values = append(values, value)
+		}
+		return data.NewField(name, nil, values)
+	default:
+		values := make([]*string, 0, len(docs))
+		for _, doc := range docs {
+			var value *string
+			switch v := doc[name].(type) {
+			case nil:
+			case string:
+				value = &v
+			default:
+				// arrays and objects nested too deep
+				if b, err := json.Marshal(v); err == nil {
+					s := string(b)
+					value = &s
+				}
+			}
+			values = append(values, value)
+		}
+		return data.NewField(name, nil, values)
+	}
+}
+
+// logLevel maps the value of a level field to the standard log levels of Grafana, including syslog severities.
+func logLevel(value interface{}) string {
+	var level string
+	switch v := value.(type) {
+	case string:
+		level = strings.ToLower(strings.TrimSpace(v))
+	case float64:
+		level = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	switch level {
+	case "emerg", "emergency", "alert", "crit", "critical", "fatal", "0", "1", "2":
+		return "critical"
+	case "err", "eror", "error", "3":
+		return "error"
+	case "warn", "warning", "4":
+		return "warning"
+	case "info", "information", "informational", "notice", "5", "6":
+		return "info"
+	case "debug", "dbug", "7":
+		return "debug"
+	case "trace":
+		return "trace"
+	default:
+		return "unknown"
+	}
+}
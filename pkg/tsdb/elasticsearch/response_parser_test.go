@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1059,6 +1060,153 @@ func TestResponseParser(t *testing.T) {
 		v, _ = frame.FloatAt(1, 1)
 		assert.Equal(t, 2., v)
 	})
+
+	t.Run("With logs", func(t *testing.T) {
+		targets := map[string]string{
+			"A": `{
+				"timeField": "@timestamp",
+				"query": "error",
+				"metrics": [{ "type": "logs", "id": "1" }]
+			}`,
+		}
+		response := `{
+			"responses": [
+				{
+					"hits": {
+						"hits": [
+							{
+								"_id": "b",
+								"_index": "logs-2018.05.15",
+								"_source": {
+									"@timestamp": "2018-05-15T17:52:00.000Z",
+									"line": "an error occurred",
+									"lvl": "ERR",
+									"host": { "name": "web-1", "os": { "family": "linux" } },
+									"tags": ["a", "b"],
+									"retries": 3
+								},
+								"fields": { "@timestamp": ["2018-05-15T17:52:00.123Z"] },
+								"highlight": { "line": ["an @HIGHLIGHT@error@/HIGHLIGHT@ occurred"] },
+								"sort": [1526406720123, 5]
+							},
+							{
+								"_id": "a",
+								"_index": "logs-2018.05.15",
+								"_source": {
+									"@timestamp": 1526406660000,
+									"line": "starting",
+									"lvl": 6,
+									"level": "debug",
+									"host": { "name": "web-2" }
+								},
+								"sort": [1526406660000, 2]
+							}
+						]
+					}
+				}
+			]
+		}`
+		rp, err := newResponseParserForTest(targets, response)
+		require.NoError(t, err)
+		result, err := rp.getTimeSeries()
+		require.NoError(t, err)
+
+		frames := result.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		frame := frames[0]
+		assert.Equal(t, "A", frame.RefID)
+		assert.Equal(t, data.VisTypeLogs, string(frame.Meta.PreferredVisualization))
+		require.Equal(t, 2, frame.Rows())
+
+		names := make([]string, 0, len(frame.Fields))
+		for _, f := range frame.Fields {
+			names = append(names, f.Name)
+		}
+		// the configured level field and the "level" field of the documents are replaced by the mapped level
+		assert.Equal(t, []string{"@timestamp", "line", "level", "_id", "_index", "_type", "host.name", "host.os.family", "retries", "tags"}, names)
+
+		first := time.Date(2018, 5, 15, 17, 52, 0, 123000000, time.UTC)
+		second := time.Date(2018, 5, 15, 17, 51, 0, 0, time.UTC)
+		assert.Equal(t, &first, frame.Fields[0].At(0))
+		assert.Equal(t, &second, frame.Fields[0].At(1))
+
+		row := func(name string, i int) interface{} {
+			field, _ := frame.FieldByName(name)
+			return field.At(i)
+		}
+		line := "an error occurred"
+		assert.Equal(t, &line, row("line", 0))
+		errorLevel, infoLevel := "error", "info"
+		assert.Equal(t, &errorLevel, row("level", 0))
+		assert.Equal(t, &infoLevel, row("level", 1))
+		linux := "linux"
+		assert.Equal(t, &linux, row("host.os.family", 0))
+		assert.Nil(t, row("host.os.family", 1))
+		tags := `["a","b"]`
+		assert.Equal(t, &tags, row("tags", 0))
+		retries := 3.
+		assert.Equal(t, &retries, row("retries", 0))
+
+		custom := frame.Meta.Custom.(map[string]interface{})
+		assert.Equal(t, []string{"error"}, custom["searchWords"])
+		assert.Equal(t, []interface{}{1526406660000., 2.}, custom["searchAfter"])
+	})
+
+	t.Run("With raw data", func(t *testing.T) {
+		targets := map[string]string{
+			"A": `{
+				"timeField": "@timestamp",
+				"metrics": [{ "type": "raw_data", "id": "1" }]
+			}`,
+		}
+		response := `{
+			"responses": [
+				{
+					"hits": {
+						"hits": [
+							{ "_id": "a", "_index": "metrics", "_source": { "@timestamp": "1526406660000", "up": true, "value": 1.5 } }
+						]
+					}
+				}
+			]
+		}`
+		rp, err := newResponseParserForTest(targets, response)
+		require.NoError(t, err)
+		result, err := rp.getTimeSeries()
+		require.NoError(t, err)
+
+		frame := result.Responses["A"].Frames[0]
+		assert.Equal(t, data.VisTypeTable, string(frame.Meta.PreferredVisualization))
+		require.Len(t, frame.Fields, 6)
+		assert.Equal(t, "@timestamp", frame.Fields[0].Name)
+		ts := time.Date(2018, 5, 15, 17, 51, 0, 0, time.UTC)
+		assert.Equal(t, &ts, frame.Fields[0].At(0))
+		up, _ := frame.FieldByName("up")
+		assert.Equal(t, data.FieldTypeNullableBool, up.Type())
+		value, _ := frame.FieldByName("value")
+		assert.Equal(t, data.FieldTypeNullableFloat64, value.Type())
+		assert.NotContains(t, frame.Meta.Custom, "searchWords")
+	})
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := map[interface{}]string{
+		"EMERG":   "critical",
+		"fatal":   "critical",
+		"eror":    "error",
+		"Warning": "warning",
+		"notice":  "info",
+		"dbug":    "debug",
+		"trace":   "trace",
+		2.:        "critical",
+		4.:        "warning",
+		7.:        "debug",
+		"verbose": "unknown",
+		nil:       "unknown",
+	}
+	for value, level := range tests {
+		assert.Equal(t, level, logLevel(value), value)
+	}
 }
 
 func newResponseParserForTest(tsdbQueries map[string]string, responseBody string) (*responseParser, error) {
@@ -1092,5 +1240,5 @@ func newResponseParserForTest(tsdbQueries map[string]string, responseBody string
 		return nil, err
 	}
 
-	return newResponseParser(response.Responses, queries, nil), nil
+	return newResponseParser(response.Responses, queries, nil, es.ConfiguredFields{TimeField: "@timestamp", LogMessageField: "line", LogLevelField: "lvl"}), nil
 }
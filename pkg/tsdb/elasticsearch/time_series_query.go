@@ -13,6 +13,9 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
+// defaultDocumentsSize is the number of documents returned by raw data and logs queries without a size.
+const defaultDocumentsSize = 500
+
 type timeSeriesQuery struct {
 	client             es.Client
 	dataQueries        []backend.DataQuery
@@ -58,7 +61,7 @@ func (e *timeSeriesQuery) execute() (*backend.QueryDataResponse, error) {
 		return &backend.QueryDataResponse{}, err
 	}
 
-	rp := newResponseParser(res.Responses, queries, res.DebugInfo)
+	rp := newResponseParser(res.Responses, queries, res.DebugInfo, e.client.GetConfiguredFields())
 	return rp.getTimeSeries()
 }
 
@@ -80,16 +83,31 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 	}
 
 	if len(q.BucketAggs) == 0 {
-		if len(q.Metrics) == 0 || q.Metrics[0].Type != "raw_document" {
+		if len(q.Metrics) == 0 {
 			result.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("invalid query, missing metrics and aggregations"),
 			}
 			return nil
 		}
 		metric := q.Metrics[0]
-		b.Size(metric.Settings.Get("size").MustInt(500))
-		b.SortDesc("@timestamp", "boolean")
-		b.AddDocValueField("@timestamp")
+		switch metric.Type {
+		case rawDocumentType:
+			b.Size(metric.Settings.Get("size").MustInt(500))
+			b.SortDesc("@timestamp", "boolean")
+			b.AddDocValueField("@timestamp")
+		case rawDataType:
+			addDocumentsQuery(b, q.TimeField, intSetting(metric.Settings, "size", defaultDocumentsSize))
+		case logsType:
+			addDocumentsQuery(b, q.TimeField, intSetting(metric.Settings, "limit", defaultDocumentsSize))
+			if q.RawQuery != "" {
+				b.AddHighlight()
+			}
+			b.SearchAfter(metric.Settings.Get("searchAfter").MustArray())
+		default:
+			result.Responses[q.RefID] = backend.DataResponse{
+				Error: fmt.Errorf("invalid query, missing metrics and aggregations"),
+			}
+		}
 		return nil
 	}
 
@@ -182,6 +200,26 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 	return nil
 }
 
+// addDocumentsQuery requests the newest documents. Documents with the same time are sorted by index order,
+// so that the sort values of the last document can be used to request the next page.
+func addDocumentsQuery(b *es.SearchRequestBuilder, timeField string, size int) {
+	b.Size(size)
+	b.SortDesc(timeField, "boolean")
+	b.SortDesc("_doc", "")
+	b.AddDocValueField(timeField)
+}
+
+// intSetting returns the integer value of a setting, which may be encoded as string by the browser.
+func intSetting(settings *simplejson.Json, name string, defaultValue int) int {
+	if value, err := settings.Get(name).Int(); err == nil && value > 0 {
+		return value
+	}
+	if value, err := strconv.Atoi(settings.Get(name).MustString()); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 func setFloatPath(settings *simplejson.Json, path ...string) {
 	if stringValue, err := settings.GetPath(path...).String(); err == nil {
 		if value, err := strconv.ParseFloat(stringValue, 64); err == nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 	"github.com/stretchr/testify/assert"
@@ -377,6 +378,62 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 			require.Equal(t, sr.Size, 1337)
 		})
 
+		t.Run("With raw data metric", func(t *testing.T) {
+			c := newFakeClient("5.0.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "raw_data", "settings": { "size": "1337" }	}]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 1337, sr.Size)
+			require.Equal(t, []string{"@timestamp", "_doc"}, sr.SortOrder)
+			require.Equal(t, []string{"@timestamp"}, sr.CustomProps["docvalue_fields"])
+			require.NotContains(t, sr.CustomProps, "highlight")
+		})
+
+		t.Run("With logs metric", func(t *testing.T) {
+			c := newFakeClient("7.10.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"query": "error",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "logs", "settings": { "limit": 100, "searchAfter": [1526406600000, 42] } }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 100, sr.Size)
+			require.Equal(t, []interface{}{json.Number("1526406600000"), json.Number("42")}, sr.CustomProps["search_after"])
+			highlight := sr.CustomProps["highlight"].(map[string]interface{})
+			require.Equal(t, []string{es.HighlightPreTag}, highlight["pre_tags"])
+			require.Equal(t, []string{es.HighlightPostTag}, highlight["post_tags"])
+
+			body, err := json.Marshal(sr)
+			require.NoError(t, err)
+			payload, err := simplejson.NewJson(body)
+			require.NoError(t, err)
+			require.Equal(t, "desc", payload.Get("sort").GetIndex(0).GetPath("@timestamp", "order").MustString())
+			require.Equal(t, "desc", payload.Get("sort").GetIndex(1).GetPath("_doc", "order").MustString())
+		})
+
+		t.Run("With logs metric without limit", func(t *testing.T) {
+			c := newFakeClient("7.10.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "logs" }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 500, sr.Size)
+			require.NotContains(t, sr.CustomProps, "highlight")
+			require.NotContains(t, sr.CustomProps, "search_after")
+		})
+
 		t.Run("With date histogram agg", func(t *testing.T) {
 			c := newFakeClient("5.0.0")
 			_, err := executeTsdbQuery(c, `{
@@ -1212,6 +1269,10 @@ func (c *fakeClient) GetTimeField() string {
 	return c.timeField
 }
 
+func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
+	return es.ConfiguredFields{TimeField: c.timeField, LogMessageField: "line", LogLevelField: "lvl"}
+}
+
 func (c *fakeClient) GetMinInterval(queryInterval string) (time.Duration, error) {
 	return 15 * time.Second, nil
 }
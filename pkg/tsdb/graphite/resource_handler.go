@@ -0,0 +1,112 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newResourceMux provides the Graphite API endpoints the query editor uses to browse metrics and tags.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleResourceReq("metrics/find", http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleResourceReq("tags/autoComplete/tags", http.MethodGet))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleResourceReq("tags/autoComplete/values", http.MethodGet))
+	mux.HandleFunc("/functions", s.handleResourceReq("functions", http.MethodGet))
+	mux.HandleFunc("/version", s.handleResourceReq("version", http.MethodGet))
+	return mux
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// handleResourceReq forwards resource calls to the given endpoint of the Graphite API using the HTTP client of the
+// datasource. The query parameters and the body of the call are passed on as they are.
+func (s *Service) handleResourceReq(endpoint string, methods ...string) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !isAllowedMethod(req.Method, methods) {
+			s.writeResponse(rw, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", req.Method))
+			return
+		}
+
+		ctx := req.Context()
+		pluginContext := httpadapter.PluginConfigFromContext(ctx)
+		dsInfo, err := s.getDSInfo(pluginContext)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		u, err := url.Parse(dsInfo.URL)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		u.Path = path.Join(u.Path, endpoint)
+		u.RawQuery = req.URL.RawQuery
+
+		graphiteReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), req.Body)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to create request: %v", err))
+			return
+		}
+		if contentType := req.Header.Get("Content-Type"); contentType != "" {
+			graphiteReq.Header.Set("Content-Type", contentType)
+		}
+
+		ctx, span := s.tracer.Start(ctx, "graphite resource")
+		span.SetAttributes("endpoint", endpoint, attribute.Key("endpoint").String(endpoint))
+		span.SetAttributes("datasource_id", dsInfo.Id, attribute.Key("datasource_id").Int64(dsInfo.Id))
+		span.SetAttributes("org_id", pluginContext.OrgID, attribute.Key("org_id").Int64(pluginContext.OrgID))
+		defer span.End()
+		s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+		res, err := dsInfo.HTTPClient.Do(graphiteReq)
+		if err != nil {
+			s.writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("request to graphite failed: %v", err))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				s.logger.Warn("Failed to close response body", "err", err)
+			}
+		}()
+
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		if contentType := res.Header.Get("Content-Type"); contentType != "" {
+			rw.Header().Set("Content-Type", contentType)
+		}
+		rw.WriteHeader(res.StatusCode)
+		if _, err := rw.Write(body); err != nil {
+			s.logger.Error("Unable to write HTTP response", "error", err)
+		}
+	}
+}
+
+func (s *Service) writeResponse(rw http.ResponseWriter, code int, msg string) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write([]byte(msg)); err != nil {
+		s.logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
+
+func isAllowedMethod(method string, methods []string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
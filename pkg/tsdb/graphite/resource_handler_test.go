@@ -0,0 +1,115 @@
+package graphite
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/graphite/metrics/find":
+			_, _ = w.Write([]byte(`[{"text": "apps", "expandable": 1}]`))
+		case "/graphite/version":
+			_, _ = w.Write([]byte(`"1.1.8"`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tracer, err := tracing.InitializeTracerForTest()
+	require.NoError(t, err)
+	service := &Service{
+		logger: log.New("tsdb.graphite"),
+		tracer: tracer,
+		im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL + "/graphite", Id: settings.ID}, nil
+		}),
+	}
+	service.resourceHandler = httpadapter.New(service.newResourceMux())
+
+	callResource := func(t *testing.T, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		req.PluginContext = backend.PluginContext{OrgID: 1, DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}}
+		sender := &fakeSender{}
+		require.NoError(t, service.CallResource(context.Background(), req, sender))
+		require.NotNil(t, sender.resp)
+		return sender.resp
+	}
+
+	t.Run("should find metrics with the body of the call", func(t *testing.T) {
+		resp := callResource(t, &backend.CallResourceRequest{
+			Method:  http.MethodPost,
+			Path:    "metrics/find",
+			URL:     "metrics/find",
+			Headers: map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+			Body:    []byte("query=apps.*&from=-1h"),
+		})
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `[{"text": "apps", "expandable": 1}]`, string(resp.Body))
+		assert.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
+
+		req := requests[len(requests)-1]
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+		assert.Equal(t, "query=apps.*&from=-1h", bodies[len(bodies)-1])
+	})
+
+	t.Run("should pass on query parameters", func(t *testing.T) {
+		resp := callResource(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "tags/autoComplete/values",
+			URL:    "tags/autoComplete/values?tag=host&valuePrefix=web",
+		})
+		assert.Equal(t, http.StatusNotFound, resp.Status)
+
+		req := requests[len(requests)-1]
+		assert.Equal(t, "/graphite/tags/autoComplete/values", req.URL.Path)
+		assert.Equal(t, "host", req.URL.Query().Get("tag"))
+		assert.Equal(t, "web", req.URL.Query().Get("valuePrefix"))
+	})
+
+	t.Run("should return the version", func(t *testing.T) {
+		resp := callResource(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "version", URL: "version"})
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.Equal(t, `"1.1.8"`, string(resp.Body))
+	})
+
+	t.Run("should reject other resources and methods", func(t *testing.T) {
+		count := len(requests)
+		resp := callResource(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "render", URL: "render?target=apps.*"})
+		assert.Equal(t, http.StatusNotFound, resp.Status)
+		resp = callResource(t, &backend.CallResourceRequest{Method: http.MethodPost, Path: "functions", URL: "functions"})
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+		assert.Len(t, requests, count)
+	})
+}
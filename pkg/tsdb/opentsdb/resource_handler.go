@@ -0,0 +1,89 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// newResourceMux provides the OpenTSDB API endpoints the query editor uses for suggestions.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/suggest", s.handleResourceReq("api/suggest"))
+	mux.HandleFunc("/aggregators", s.handleResourceReq("api/aggregators"))
+	mux.HandleFunc("/filters", s.handleResourceReq("api/config/filters"))
+	return mux
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// handleResourceReq forwards resource calls to the given endpoint of the OpenTSDB API using the HTTP client of the
+// datasource. The query parameters of the call are passed on as they are.
+func (s *Service) handleResourceReq(endpoint string) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			s.writeResponse(rw, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", req.Method))
+			return
+		}
+
+		ctx := req.Context()
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		u, err := url.Parse(dsInfo.URL)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		u.Path = path.Join(u.Path, endpoint)
+		u.RawQuery = req.URL.RawQuery
+
+		tsdbReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to create request: %v", err))
+			return
+		}
+
+		res, err := dsInfo.HTTPClient.Do(tsdbReq)
+		if err != nil {
+			s.writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("request to opentsdb failed: %v", err))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				s.logger.Warn("Failed to close response body", "err", err)
+			}
+		}()
+
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			s.writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		if contentType := res.Header.Get("Content-Type"); contentType != "" {
+			rw.Header().Set("Content-Type", contentType)
+		}
+		rw.WriteHeader(res.StatusCode)
+		if _, err := rw.Write(body); err != nil {
+			s.logger.Error("Unable to write HTTP response", "error", err)
+		}
+	}
+}
+
+func (s *Service) writeResponse(rw http.ResponseWriter, code int, msg string) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write([]byte(msg)); err != nil {
+		s.logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,96 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/suggest":
+			_, _ = w.Write([]byte(`["cpu.user", "cpu.system"]`))
+		case "/api/aggregators":
+			_, _ = w.Write([]byte(`["avg", "sum"]`))
+		case "/api/config/filters":
+			_, _ = w.Write([]byte(`{"literal_or": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		logger: log.New("tsdb.opentsdb"),
+		im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil
+		}),
+	}
+	service.resourceHandler = httpadapter.New(service.newResourceMux())
+
+	callResource := func(t *testing.T, method, path, url string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}},
+			Method:        method,
+			Path:          path,
+			URL:           url,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.resp)
+		return sender.resp
+	}
+
+	t.Run("should suggest metrics", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "suggest", "suggest?type=metrics&q=cpu&max=1000")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `["cpu.user", "cpu.system"]`, string(resp.Body))
+
+		query := requests[len(requests)-1].URL.Query()
+		assert.Equal(t, "metrics", query.Get("type"))
+		assert.Equal(t, "cpu", query.Get("q"))
+	})
+
+	t.Run("should return aggregators and filters", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "aggregators", "aggregators")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `["avg", "sum"]`, string(resp.Body))
+
+		resp = callResource(t, http.MethodGet, "filters", "filters")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `{"literal_or": {}}`, string(resp.Body))
+	})
+
+	t.Run("should reject other resources and methods", func(t *testing.T) {
+		count := len(requests)
+		resp := callResource(t, http.MethodGet, "query", "query")
+		assert.Equal(t, http.StatusNotFound, resp.Status)
+		resp = callResource(t, http.MethodPost, "suggest", "suggest")
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+		assert.Len(t, requests, count)
+	})
+}
@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ExemplarSampling is the strategy to reduce the exemplars of a query to the ones that are shown.
+type ExemplarSampling string
+
+const (
+	// ExemplarSamplingNone does not query exemplars at all.
+	ExemplarSamplingNone ExemplarSampling = "none"
+	// ExemplarSamplingAll returns all exemplars.
+	ExemplarSamplingAll ExemplarSampling = "all"
+	// ExemplarSamplingDeviation returns per step the exemplar with the highest value and the exemplars whose values
+	// are at least two standard deviations below the previous one. This is the default.
+	ExemplarSamplingDeviation ExemplarSampling = "deviation"
+	// ExemplarSamplingTopN returns per step the exemplars with the highest values, at most the exemplar limit.
+	ExemplarSamplingTopN ExemplarSampling = "topN"
+)
+
+// defaultExemplarLimit is the number of exemplars per step of the top-N sampling if the query does not set a limit.
+const defaultExemplarLimit = 1
+
+func parseExemplarSampling(sampling string) (ExemplarSampling, error) {
+	switch s := ExemplarSampling(sampling); s {
+	case "":
+		return ExemplarSamplingDeviation, nil
+	case ExemplarSamplingNone, ExemplarSamplingAll, ExemplarSamplingDeviation, ExemplarSamplingTopN:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid exemplar sampling %q", sampling)
+	}
+}
+
+// sampleExemplarEvents returns the exemplars selected by the sampling strategy of the query, ordered by step.
+func sampleExemplarEvents(events []ExemplarEvent, query *PrometheusQuery) []ExemplarEvent {
+	switch query.ExemplarSampling {
+	case ExemplarSamplingNone:
+		return nil
+	case ExemplarSamplingAll:
+		return events
+	}
+
+	// Create bucketed exemplars based on aligned timestamp
+	bucketedExemplars := make(map[string][]ExemplarEvent)
+	values := make([]float64, 0, len(events))
+	for _, event := range events {
+		alignedTs := fmt.Sprintf("%.0f", math.Floor(float64(event.Time.Unix())/query.Step.Seconds())*query.Step.Seconds())
+		bucketedExemplars[alignedTs] = append(bucketedExemplars[alignedTs], event)
+		values = append(values, event.Value)
+	}
+
+	buckets := make([]string, 0, len(bucketedExemplars))
+	for bucketTimes := range bucketedExemplars {
+		buckets = append(buckets, bucketTimes)
+	}
+	sort.Strings(buckets)
+
+	if query.ExemplarSampling == ExemplarSamplingTopN {
+		limit := query.ExemplarLimit
+		if limit <= 0 {
+			limit = defaultExemplarLimit
+		}
+		sampled := make([]ExemplarEvent, 0, len(buckets))
+		for _, bucket := range buckets {
+			exemplarsInBucket := bucketedExemplars[bucket]
+			sort.SliceStable(exemplarsInBucket, func(i, j int) bool {
+				return exemplarsInBucket[i].Value > exemplarsInBucket[j].Value
+			})
+			if int64(len(exemplarsInBucket)) > limit {
+				exemplarsInBucket = exemplarsInBucket[:limit]
+			}
+			sampled = append(sampled, exemplarsInBucket...)
+		}
+		return sampled
+	}
+
+	// Calculate standard deviation
+	standardDeviation := deviation(values)
+
+	// Sample exemplars based ona value, so we are not showing too many of them
+	sampled := make([]ExemplarEvent, 0, len(buckets))
+	for _, bucket := range buckets {
+		exemplarsInBucket := bucketedExemplars[bucket]
+		if len(exemplarsInBucket) == 1 {
+			sampled = append(sampled, exemplarsInBucket[0])
+			continue
+		}
+
+		bucketValues := make([]float64, 0, len(exemplarsInBucket))
+		for _, exemplar := range exemplarsInBucket {
+			bucketValues = append(bucketValues, exemplar.Value)
+		}
+		sort.Slice(bucketValues, func(i, j int) bool {
+			return bucketValues[i] > bucketValues[j]
+		})
+
+		sampledBucketValues := make([]float64, 0)
+		for _, value := range bucketValues {
+			if len(sampledBucketValues) == 0 {
+				sampledBucketValues = append(sampledBucketValues, value)
+			} else {
+				// Then take values only when at least 2 standard deviation distance to previously taken value
+				prev := sampledBucketValues[len(sampledBucketValues)-1]
+				if standardDeviation != 0 && prev-value >= float64(2)*standardDeviation {
+					sampledBucketValues = append(sampledBucketValues, value)
+				}
+			}
+		}
+		for _, valueBucket := range sampledBucketValues {
+			for _, exemplar := range exemplarsInBucket {
+				if exemplar.Value == valueBucket {
+					sampled = append(sampled, exemplar)
+				}
+			}
+		}
+	}
+	return sampled
+}
+
+func deviation(values []float64) float64 {
+	var sum, mean, sd float64
+	valuesLen := float64(len(values))
+	for _, value := range values {
+		sum += value
+	}
+	mean = sum / valuesLen
+	for j := 0; j < len(values); j++ {
+		sd += math.Pow(values[j]-mean, 2)
+	}
+	return math.Sqrt(sd / (valuesLen - 1))
+}
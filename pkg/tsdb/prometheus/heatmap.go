@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+)
+
+// heatmapFormat is the format of queries that return histograms for heatmaps.
+const heatmapFormat = "heatmap"
+
+// frameTypeHeatmapBuckets is the type of frames with a field per bucket, the frontend does not convert them again.
+const frameTypeHeatmapBuckets data.FrameType = "heatmap-buckets"
+
+type histogramBucket struct {
+	upperBound float64
+	series     *model.SampleStream
+}
+
+type histogram struct {
+	metric  model.Metric
+	buckets []histogramBucket
+}
+
+// matrixToHeatmapFrames converts the series of histogram buckets into one frame per histogram with a field per
+// bucket. The fields are ordered by the upper bound of the buckets and have the count of the bucket itself instead
+// of the cumulative count of the `le` series. Series that are not buckets are converted as usual.
+func matrixToHeatmapFrames(matrix model.Matrix, query *PrometheusQuery, frames data.Frames) data.Frames {
+	histograms := make(map[model.Fingerprint]*histogram)
+	order := make([]model.Fingerprint, 0)
+	other := model.Matrix{}
+	for _, series := range matrix {
+		le, ok := series.Metric[model.BucketLabel]
+		if !ok {
+			other = append(other, series)
+			continue
+		}
+		upperBound, err := strconv.ParseFloat(string(le), 64)
+		if err != nil {
+			other = append(other, series)
+			continue
+		}
+
+		metric := series.Metric.Clone()
+		delete(metric, model.BucketLabel)
+		fp := metric.Fingerprint()
+		h, ok := histograms[fp]
+		if !ok {
+			h = &histogram{metric: metric}
+			histograms[fp] = h
+			order = append(order, fp)
+		}
+		h.buckets = append(h.buckets, histogramBucket{upperBound: upperBound, series: series})
+	}
+
+	frames = matrixToDataFrames(other, query, frames)
+	for _, fp := range order {
+		frames = append(frames, histogramToHeatmapFrame(histograms[fp], query))
+	}
+	return frames
+}
+
+func histogramToHeatmapFrame(h *histogram, query *PrometheusQuery) *data.Frame {
+	sort.SliceStable(h.buckets, func(i, j int) bool {
+		return h.buckets[i].upperBound < h.buckets[j].upperBound
+	})
+
+	// the buckets usually have the same timestamps, missing samples are null
+	index := make(map[model.Time]int)
+	times := make([]model.Time, 0)
+	for _, b := range h.buckets {
+		for _, sample := range b.series.Values {
+			if _, ok := index[sample.Timestamp]; !ok {
+				index[sample.Timestamp] = 0
+				times = append(times, sample.Timestamp)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for i, t := range times {
+		index[t] = i
+	}
+
+	timeField := data.NewFieldFromFieldType(data.FieldTypeTime, len(times))
+	timeField.Name = data.TimeSeriesTimeFieldName
+	timeField.Config = &data.FieldConfig{Interval: float64(query.Step.Milliseconds())}
+	for i, t := range times {
+		timeField.Set(i, t.Time().UTC())
+	}
+
+	tags := make(map[string]string, len(h.metric))
+	for k, v := range h.metric {
+		tags[string(k)] = string(v)
+	}
+
+	fields := make([]*data.Field, 0, len(h.buckets)+1)
+	fields = append(fields, timeField)
+	// lower is the cumulative count of the closest lower bucket with a sample at each time
+	lower := make([]*float64, len(times))
+	for _, b := range h.buckets {
+		cumulative := make([]*float64, len(times))
+		for _, sample := range b.series.Values {
+			value := float64(sample.Value)
+			if !math.IsNaN(value) {
+				cumulative[index[sample.Timestamp]] = &value
+			}
+		}
+
+		valueField := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, len(times))
+		for i, value := range cumulative {
+			if value == nil {
+				continue
+			}
+			count := *value
+			if lower[i] != nil {
+				count -= *lower[i]
+			}
+			valueField.Set(i, &count)
+			lower[i] = value
+		}
+
+		le := string(b.series.Metric[model.BucketLabel])
+		valueField.Name = le
+		valueField.Labels = tags
+		valueField.Config = &data.FieldConfig{DisplayNameFromDS: le}
+		fields = append(fields, valueField)
+	}
+
+	frame := newDataFrame(formatLegend(h.metric, query), "matrix", fields...)
+	frame.Meta.Type = frameTypeHeatmapBuckets
+	return frame
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	p "github.com/prometheus/common/model"
@@ -114,6 +115,31 @@ func TestPrometheus_timeSeriesQuery_parseTimeSeriesQuery(t *testing.T) {
 		require.Equal(t, false, models[0].ExemplarQuery)
 	})
 
+	t.Run("parsing query model with exemplar sampling", func(t *testing.T) {
+		timeRange := backend.TimeRange{
+			From: now,
+			To:   now.Add(12 * time.Hour),
+		}
+		dsInfo := &DatasourceInfo{}
+
+		models, err := service.parseTimeSeriesQuery(queryContext(`{"expr": "go_goroutines", "exemplar": true}`, timeRange), dsInfo)
+		require.NoError(t, err)
+		require.Equal(t, ExemplarSamplingDeviation, models[0].ExemplarSampling)
+
+		models, err = service.parseTimeSeriesQuery(queryContext(`{"expr": "go_goroutines", "exemplar": true, "exemplarSampling": "topN", "exemplarLimit": 3}`, timeRange), dsInfo)
+		require.NoError(t, err)
+		require.Equal(t, ExemplarSamplingTopN, models[0].ExemplarSampling)
+		require.Equal(t, int64(3), models[0].ExemplarLimit)
+		require.True(t, models[0].ExemplarQuery)
+
+		models, err = service.parseTimeSeriesQuery(queryContext(`{"expr": "go_goroutines", "exemplar": true, "exemplarSampling": "none"}`, timeRange), dsInfo)
+		require.NoError(t, err)
+		require.False(t, models[0].ExemplarQuery)
+
+		_, err = service.parseTimeSeriesQuery(queryContext(`{"expr": "go_goroutines", "exemplarSampling": "random"}`, timeRange), dsInfo)
+		require.EqualError(t, err, `invalid exemplar sampling "random"`)
+	})
+
 	t.Run("parsing query model with step", func(t *testing.T) {
 		timeRange := backend.TimeRange{
 			From: now,
@@ -596,6 +622,99 @@ func TestPrometheus_parseTimeSeriesResponse(t *testing.T) {
 		require.Equal(t, res[0].Fields[1].At(1), 0.003535405)
 	})
 
+	t.Run("exemplars response should be sampled with the strategy of the query", func(t *testing.T) {
+		start := time.Date(2022, 3, 11, 12, 0, 0, 0, time.UTC)
+		exemplar := func(traceID string, value float64, offset time.Duration) apiv1.Exemplar {
+			return apiv1.Exemplar{
+				Labels:    p.LabelSet{"traceID": p.LabelValue(traceID)},
+				Value:     p.SampleValue(value),
+				Timestamp: p.TimeFromUnixNano(start.Add(offset).UnixNano()),
+			}
+		}
+		exemplars := []apiv1.ExemplarQueryResult{
+			{
+				SeriesLabels: p.LabelSet{"__name__": "tns_request_duration_seconds_bucket"},
+				Exemplars: []apiv1.Exemplar{
+					exemplar("a", 0.1, 0),
+					exemplar("b", 0.3, 10*time.Second),
+					exemplar("c", 0.2, 20*time.Second),
+					exemplar("d", 0.5, time.Minute),
+				},
+			},
+		}
+
+		traceIDs := func(t *testing.T, sampling ExemplarSampling, limit int64) []string {
+			t.Helper()
+			query := &PrometheusQuery{Step: time.Minute, ExemplarSampling: sampling, ExemplarLimit: limit}
+			res, err := parseTimeSeriesResponse(map[TimeSeriesQueryType]interface{}{ExemplarQueryType: exemplars}, query)
+			require.NoError(t, err)
+			require.Len(t, res, 1)
+			field, _ := res[0].FieldByName("traceID")
+			ids := make([]string, 0, field.Len())
+			for i := 0; i < field.Len(); i++ {
+				ids = append(ids, field.At(i).(string))
+			}
+			return ids
+		}
+
+		require.Equal(t, []string{"a", "b", "c", "d"}, traceIDs(t, ExemplarSamplingAll, 0))
+		require.Equal(t, []string{"b", "d"}, traceIDs(t, ExemplarSamplingTopN, 0))
+		require.Equal(t, []string{"b", "c", "d"}, traceIDs(t, ExemplarSamplingTopN, 2))
+		require.Equal(t, []string{"b", "d"}, traceIDs(t, ExemplarSamplingDeviation, 0))
+	})
+
+	t.Run("histogram response should be converted to heatmap buckets", func(t *testing.T) {
+		bucket := func(le string, values ...float64) *p.SampleStream {
+			samples := make([]p.SamplePair, 0, len(values))
+			for i, v := range values {
+				samples = append(samples, p.SamplePair{Value: p.SampleValue(v), Timestamp: p.Time(int64(i+1) * 1000)})
+			}
+			return &p.SampleStream{Metric: p.Metric{"le": p.LabelValue(le), "job": "app"}, Values: samples}
+		}
+		value := map[TimeSeriesQueryType]interface{}{
+			RangeQueryType: p.Matrix{
+				bucket("+Inf", 10, 12),
+				bucket("0.5", 6, 8),
+				bucket("0.1", 2, 2),
+				bucket("1", 9, math.NaN()),
+				&p.SampleStream{Metric: p.Metric{"job": "app"}, Values: []p.SamplePair{{Value: 1, Timestamp: 1000}}},
+			},
+		}
+		query := &PrometheusQuery{Format: heatmapFormat, Step: time.Second, LegendFormat: "{{job}}"}
+		res, err := parseTimeSeriesResponse(value, query)
+		require.NoError(t, err)
+
+		require.Len(t, res, 2)
+		// series that are not buckets are not changed
+		require.Len(t, res[0].Fields, 2)
+
+		frame := res[1]
+		require.Equal(t, "app", frame.Name)
+		require.Equal(t, frameTypeHeatmapBuckets, frame.Meta.Type)
+		names := make([]string, 0, len(frame.Fields))
+		for _, f := range frame.Fields {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{"Time", "0.1", "0.5", "1", "+Inf"}, names)
+		require.Equal(t, data.Labels{"job": "app"}, frame.Fields[1].Labels)
+
+		counts := func(field *data.Field) []interface{} {
+			values := make([]interface{}, 0, field.Len())
+			for i := 0; i < field.Len(); i++ {
+				if v := field.At(i).(*float64); v != nil {
+					values = append(values, *v)
+				} else {
+					values = append(values, nil)
+				}
+			}
+			return values
+		}
+		require.Equal(t, []interface{}{2., 2.}, counts(frame.Fields[1]))
+		require.Equal(t, []interface{}{4., 6.}, counts(frame.Fields[2]))
+		require.Equal(t, []interface{}{3., nil}, counts(frame.Fields[3]))
+		require.Equal(t, []interface{}{1., 4.}, counts(frame.Fields[4]))
+	})
+
 	t.Run("matrix response should be parsed normally", func(t *testing.T) {
 		values := []p.SamplePair{
 			{Value: 1, Timestamp: 1000},
@@ -17,16 +17,19 @@ type DatasourceInfo struct {
 type clientGetter func(map[string]string) (apiv1.API, error)
 
 type PrometheusQuery struct {
-	Expr          string
-	Step          time.Duration
-	LegendFormat  string
-	Start         time.Time
-	End           time.Time
-	RefId         string
-	InstantQuery  bool
-	RangeQuery    bool
-	ExemplarQuery bool
-	UtcOffsetSec  int64
+	Expr             string
+	Step             time.Duration
+	LegendFormat     string
+	Start            time.Time
+	End              time.Time
+	RefId            string
+	InstantQuery     bool
+	RangeQuery       bool
+	ExemplarQuery    bool
+	UtcOffsetSec     int64
+	ExemplarSampling ExemplarSampling
+	ExemplarLimit    int64
+	Format           string
 }
 
 type ExemplarEvent struct {
@@ -46,4 +49,10 @@ type QueryModel struct {
 	ExemplarQuery  bool   `json:"exemplar"`
 	IntervalFactor int64  `json:"intervalFactor"`
 	UtcOffsetSec   int64  `json:"utcOffsetSec"`
+	// ExemplarSampling is one of none, all, deviation or topN. Deviation is used if it is empty.
+	ExemplarSampling string `json:"exemplarSampling"`
+	// ExemplarLimit is the number of exemplars per step of the topN sampling.
+	ExemplarLimit int64 `json:"exemplarLimit"`
+	// Format is time_series, table or heatmap. Histograms are converted to buckets for heatmaps.
+	Format string `json:"format"`
 }
@@ -0,0 +1,88 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+)
+
+// rowReader reads the rows of a query into frames, so that results can be delivered in chunks of bounded size.
+// At most limit rows are read, or all rows if limit is not positive.
+type rowReader struct {
+	rows       *sql.Rows
+	names      []string
+	scanner    *sqlutil.ScanRow
+	converters []sqlutil.Converter
+	limit      int64
+	read       int64
+	truncated  bool
+	done       bool
+}
+
+func newRowReader(rows *sql.Rows, limit int64, converters ...sqlutil.Converter) (*rowReader, error) {
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	scanner, converters, err := sqlutil.MakeScanRow(types, names, converters...)
+	if err != nil {
+		return nil, err
+	}
+	return &rowReader{
+		rows:       rows,
+		names:      names,
+		scanner:    scanner,
+		converters: converters,
+		limit:      limit,
+	}, nil
+}
+
+// next returns a frame with the next rows, at most size rows or all remaining rows if size is not positive.
+// The reader is done once all rows or the row limit are read; the frame returned last may be empty.
+func (r *rowReader) next(ctx context.Context, size int64) (*data.Frame, error) {
+	frame := sqlutil.NewFrame(r.names, r.converters...)
+	for n := int64(0); !r.done && (size <= 0 || n < size); n++ {
+		// the driver cancels the query as well, this stops reading rows that were already received
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.rows.Next() {
+			r.done = true
+			break
+		}
+		if r.limit > 0 && r.read == r.limit {
+			r.truncated = true
+			r.done = true
+			break
+		}
+
+		row := r.scanner.NewScannableRow()
+		if err := r.rows.Scan(row...); err != nil {
+			return nil, err
+		}
+		if err := sqlutil.Append(frame, row, r.converters...); err != nil {
+			return nil, err
+		}
+		r.read++
+	}
+
+	if err := r.rows.Err(); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// truncatedNotice returns the notice of results that were cut off by the row limit.
+func (r *rowReader) truncatedNotice() data.Notice {
+	return data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Results have been limited to %d rows because the row limit was reached. Increase the row limit of the query or narrow down the query to see all rows.", r.limit),
+	}
+}
@@ -64,6 +64,8 @@ type JsonData struct {
 	Encrypt             string `json:"encrypt"`
 	Servername          string `json:"servername"`
 	TimeInterval        string `json:"timeInterval"`
+	// QueryTimeout is the maximum duration of queries in seconds, queries are not limited if it is not positive.
+	QueryTimeout int `json:"queryTimeout"`
 }
 
 type DataSourceInfo struct {
@@ -102,6 +104,8 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// RowLimit limits the rows of the query below the row limit of the datasource.
+	RowLimit int64 `json:"rowLimit"`
 }
 
 func (e *DataSourceHandler) transformQueryError(err error) error {
@@ -118,6 +122,23 @@ func (e *DataSourceHandler) transformQueryError(err error) error {
 	return e.queryResultTransformer.TransformQueryError(err)
 }
 
+// queryRowLimit returns the row limit of a query, which cannot exceed the row limit of the datasource.
+func (e *DataSourceHandler) queryRowLimit(queryJson QueryJson) int64 {
+	if queryJson.RowLimit > 0 && (e.rowLimit <= 0 || queryJson.RowLimit < e.rowLimit) {
+		return queryJson.RowLimit
+	}
+	return e.rowLimit
+}
+
+// withQueryTimeout returns a context that is cancelled after the query timeout of the datasource. Cancelling the
+// context cancels the query in the database.
+func (e *DataSourceHandler) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.dsInfo.JsonData.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(e.dsInfo.JsonData.QueryTimeout)*time.Second)
+}
+
 func NewQueryDataHandler(config DataPluginConfiguration, queryResultTransformer SqlQueryResultTransformer,
 	macroEngine SQLMacroEngine, log log.Logger) (*DataSourceHandler, error) {
 	log.Debug("Creating engine...")
@@ -256,12 +277,19 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
+	queryContext, cancel := e.withQueryTimeout(queryContext)
+	defer cancel()
+
 	session := e.engine.NewSession()
 	defer session.Close()
 	db := session.DB()
 
 	rows, err := db.QueryContext(queryContext, interpolatedQuery)
 	if err != nil {
+		if ctxErr := queryContext.Err(); ctxErr != nil {
+			errAppendDebug("query cancelled", ctxErr, interpolatedQuery)
+			return
+		}
 		errAppendDebug("db query error", e.transformQueryError(err), interpolatedQuery)
 		return
 	}
@@ -279,8 +307,17 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	reader, err := newRowReader(rows.Rows, e.queryRowLimit(queryJson), sqlutil.ToConverters(stringConverters...)...)
+	if err != nil {
+		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
+		return
+	}
+	frame, err := reader.next(queryContext, 0)
 	if err != nil {
+		if ctxErr := queryContext.Err(); ctxErr != nil {
+			errAppendDebug("query cancelled", ctxErr, interpolatedQuery)
+			return
+		}
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
 	}
@@ -364,6 +401,9 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		}
 	}
 
+	if reader.truncated {
+		frame.AppendNotices(reader.truncatedNotice())
+	}
 	queryResult.dataResponse.Frames = data.Frames{frame}
 	ch <- queryResult
 }
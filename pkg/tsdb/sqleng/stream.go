@@ -0,0 +1,179 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+)
+
+const (
+	// streamPathPrefix is the prefix of the channel paths of chunked queries.
+	streamPathPrefix = "query/"
+	// defaultChunkSize is the number of rows per chunk if the query does not set it.
+	defaultChunkSize = 1000
+)
+
+// streamQuery is the data of a chunked query: the query model together with the time range and interval of the query.
+type streamQuery struct {
+	RefID         string `json:"refId"`
+	IntervalMs    int64  `json:"intervalMs"`
+	MaxDataPoints int64  `json:"maxDataPoints"`
+	// ChunkSize is the maximum number of rows per frame.
+	ChunkSize int64 `json:"chunkSize"`
+	TimeRange struct {
+		// From and To are in milliseconds since epoch.
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	} `json:"timeRange"`
+}
+
+// ChunkMeta is stored in the custom meta data of the frames of chunked queries.
+type ChunkMeta struct {
+	// Chunk is the index of the frame, starting at 0.
+	Chunk int `json:"chunk"`
+	// Done is true for the last frame of the query.
+	Done bool `json:"done"`
+}
+
+// parseStreamQuery returns the query of a chunked query stream.
+func parseStreamQuery(b json.RawMessage) (backend.DataQuery, QueryJson, int64, error) {
+	var sq streamQuery
+	if err := json.Unmarshal(b, &sq); err != nil {
+		return backend.DataQuery{}, QueryJson{}, 0, fmt.Errorf("error unmarshal stream query: %w", err)
+	}
+	queryJson := QueryJson{Format: "table"}
+	if err := json.Unmarshal(b, &queryJson); err != nil {
+		return backend.DataQuery{}, QueryJson{}, 0, fmt.Errorf("error unmarshal query json: %w", err)
+	}
+	if queryJson.RawSql == "" {
+		return backend.DataQuery{}, QueryJson{}, 0, errors.New("query model property rawSql is empty")
+	}
+	// time series are converted after all rows are read, only tables can be sent in chunks
+	if queryJson.Format != "table" {
+		return backend.DataQuery{}, QueryJson{}, 0, fmt.Errorf("chunked results are not supported for the %q format", queryJson.Format)
+	}
+
+	chunkSize := sq.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	query := backend.DataQuery{
+		RefID:         sq.RefID,
+		JSON:          b,
+		Interval:      time.Duration(sq.IntervalMs) * time.Millisecond,
+		MaxDataPoints: sq.MaxDataPoints,
+		TimeRange: backend.TimeRange{
+			From: time.UnixMilli(sq.TimeRange.From).UTC(),
+			To:   time.UnixMilli(sq.TimeRange.To).UTC(),
+		},
+	}
+	return query, queryJson, chunkSize, nil
+}
+
+// SubscribeStream allows subscriptions to chunked queries, the query is the data of the channel.
+func (e *DataSourceHandler) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if !strings.HasPrefix(req.Path, streamPathPrefix) {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, fmt.Errorf("expected %s in channel path", streamPathPrefix)
+	}
+	if _, _, _, err := parseStreamQuery(req.Data); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+	return &backend.SubscribeStreamResponse{
+		Status: backend.SubscribeStreamStatusOK,
+	}, nil
+}
+
+// PublishStream rejects all publications, chunked queries are read only.
+func (e *DataSourceHandler) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// RunStream executes a table query and sends the rows in frames of at most the chunk size of the query, so that the
+// memory used does not depend on the size of the result. The last frame is marked as done in its ChunkMeta and has
+// a notice if the rows were limited by the row limit.
+func (e *DataSourceHandler) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	query, queryJson, chunkSize, err := parseStreamQuery(req.Data)
+	if err != nil {
+		return err
+	}
+
+	interpolatedQuery, err := Interpolate(query, query.TimeRange, e.dsInfo.JsonData.TimeInterval, queryJson.RawSql)
+	if err != nil {
+		return fmt.Errorf("interpolation failed: %w", e.transformQueryError(err))
+	}
+	interpolatedQuery, err = e.macroEngine.Interpolate(&query, query.TimeRange, interpolatedQuery)
+	if err != nil {
+		return fmt.Errorf("interpolation failed: %w", e.transformQueryError(err))
+	}
+
+	ctx, cancel := e.withQueryTimeout(ctx)
+	defer cancel()
+
+	session := e.engine.NewSession()
+	defer session.Close()
+
+	rows, err := session.DB().QueryContext(ctx, interpolatedQuery)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("query cancelled: %w", ctxErr)
+		}
+		return fmt.Errorf("db query error: %w", e.transformQueryError(err))
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			e.log.Warn("Failed to close rows", "err", err)
+		}
+	}()
+
+	qm, err := e.newProcessCfg(query, ctx, rows, interpolatedQuery)
+	if err != nil {
+		return fmt.Errorf("failed to get configurations: %w", err)
+	}
+	stringConverters := e.queryResultTransformer.GetConverterList()
+	reader, err := newRowReader(rows.Rows, e.queryRowLimit(queryJson), sqlutil.ToConverters(stringConverters...)...)
+	if err != nil {
+		return fmt.Errorf("convert frame from rows error: %w", err)
+	}
+
+	for chunk := 0; ; chunk++ {
+		frame, err := reader.next(ctx, chunkSize)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("query cancelled: %w", ctxErr)
+			}
+			return fmt.Errorf("convert frame from rows error: %w", err)
+		}
+		// every chunk is converted, so that all frames of the query have the same schema
+		if err := convertSQLTimeColumnsToEpochMS(frame, qm); err != nil {
+			return fmt.Errorf("converting time columns failed: %w", err)
+		}
+
+		frame.RefID = query.RefID
+		frame.Meta = &data.FrameMeta{
+			ExecutedQueryString: interpolatedQuery,
+			Custom:              ChunkMeta{Chunk: chunk, Done: reader.done},
+		}
+		if reader.done && reader.truncated {
+			frame.AppendNotices(reader.truncatedNotice())
+		}
+		if err := sender.SendFrame(frame, data.IncludeAll); err != nil {
+			return err
+		}
+		if reader.done {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const countQuery = `SELECT time, value FROM counts ORDER BY time`
+
+// sqliteQueryResultTransformer scans the integer columns of sqlite, which does not know the types of columns before
+// the first row is read.
+type sqliteQueryResultTransformer struct {
+	testQueryResultTransformer
+}
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return []sqlutil.StringConverter{
+		{
+			Name:          "handle INTEGER",
+			InputScanKind: reflect.Interface,
+			InputTypeName: "INTEGER",
+			Replacer: &sqlutil.StringFieldReplacer{
+				OutputFieldType: data.FieldTypeNullableInt64,
+				ReplaceFunc: func(in *string) (interface{}, error) {
+					if in == nil {
+						return nil, nil
+					}
+					v, err := strconv.ParseInt(*in, 10, 64)
+					return &v, err
+				},
+			},
+		},
+	}
+}
+
+type testMacroEngine struct{}
+
+func (m *testMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	return sql, nil
+}
+
+type fakeStreamPacketSender struct {
+	frames []*data.Frame
+}
+
+func (s *fakeStreamPacketSender) Send(packet *backend.StreamPacket) error {
+	frame := &data.Frame{}
+	if err := json.Unmarshal(packet.Data, frame); err != nil {
+		return err
+	}
+	s.frames = append(s.frames, frame)
+	return nil
+}
+
+func newTestHandler(t *testing.T, rowLimit int64) *DataSourceHandler {
+	t.Helper()
+	handler, err := NewQueryDataHandler(DataPluginConfiguration{
+		DriverName:       "sqlite3",
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		RowLimit:         rowLimit,
+	}, &sqliteQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+	require.NoError(t, err)
+	t.Cleanup(handler.Dispose)
+
+	// 25 rows, one per minute
+	_, err = handler.engine.Exec(`CREATE TABLE counts (time INTEGER NOT NULL, value INTEGER NOT NULL)`)
+	require.NoError(t, err)
+	for i := 1; i <= 25; i++ {
+		_, err = handler.engine.Exec(`INSERT INTO counts (time, value) VALUES (?, ?)`, i*60, i)
+		require.NoError(t, err)
+	}
+	return handler
+}
+
+func TestQueryRowLimit(t *testing.T) {
+	handler := newTestHandler(t, 20)
+	from := time.Date(2022, 3, 11, 12, 0, 0, 0, time.UTC)
+
+	query := func(t *testing.T, rowLimit int64, format string) backend.DataResponse {
+		t.Helper()
+		model, err := json.Marshal(QueryJson{RawSql: countQuery, Format: format, RowLimit: rowLimit})
+		require.NoError(t, err)
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: model, TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)}}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("should limit rows to the row limit of the query", func(t *testing.T) {
+		res := query(t, 5, "table")
+		require.NoError(t, res.Error)
+		frame := res.Frames[0]
+		require.Equal(t, 5, frame.Rows())
+		require.Len(t, frame.Meta.Notices, 1)
+		assert.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+		assert.Contains(t, frame.Meta.Notices[0].Text, "limited to 5 rows")
+	})
+
+	t.Run("should not exceed the row limit of the datasource", func(t *testing.T) {
+		res := query(t, 100, "table")
+		require.NoError(t, res.Error)
+		require.Equal(t, 20, res.Frames[0].Rows())
+		assert.Contains(t, res.Frames[0].Meta.Notices[0].Text, "limited to 20 rows")
+	})
+
+	t.Run("should keep the notice of time series", func(t *testing.T) {
+		res := query(t, 5, "time_series")
+		require.NoError(t, res.Error)
+		require.Equal(t, 5, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Meta.Notices, 1)
+	})
+
+	t.Run("should not add a notice if all rows are returned", func(t *testing.T) {
+		res := query(t, 25, "table")
+		require.NoError(t, res.Error)
+		require.Equal(t, 20, res.Frames[0].Rows())
+
+		handler := newTestHandler(t, 0)
+		model, err := json.Marshal(QueryJson{RawSql: countQuery, Format: "table", RowLimit: 25})
+		require.NoError(t, err)
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: model, TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)}}},
+		})
+		require.NoError(t, err)
+		frame := resp.Responses["A"].Frames[0]
+		require.Equal(t, 25, frame.Rows())
+		assert.Empty(t, frame.Meta.Notices)
+	})
+
+	t.Run("should report cancelled queries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		model, err := json.Marshal(QueryJson{RawSql: countQuery, Format: "table"})
+		require.NoError(t, err)
+		resp, err := handler.QueryData(ctx, &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: model, TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)}}},
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, resp.Responses["A"].Error, context.Canceled)
+		assert.Contains(t, resp.Responses["A"].Error.Error(), "query cancelled")
+	})
+}
+
+func TestRunStream(t *testing.T) {
+	handler := newTestHandler(t, 22)
+
+	runStream := func(t *testing.T, query string) (*fakeStreamPacketSender, error) {
+		t.Helper()
+		packets := &fakeStreamPacketSender{}
+		err := handler.RunStream(context.Background(), &backend.RunStreamRequest{
+			Path: "query/A",
+			Data: json.RawMessage(query),
+		}, backend.NewStreamSender(packets))
+		return packets, err
+	}
+
+	t.Run("should send rows in chunks", func(t *testing.T) {
+		model, err := json.Marshal(map[string]interface{}{"refId": "A", "rawSql": countQuery, "format": "table", "chunkSize": 10})
+		require.NoError(t, err)
+		packets, err := runStream(t, string(model))
+		require.NoError(t, err)
+
+		require.Len(t, packets.frames, 3)
+		for i, rows := range []int{10, 10, 2} {
+			frame := packets.frames[i]
+			assert.Equal(t, "A", frame.RefID)
+			assert.Equal(t, rows, frame.Rows())
+			assert.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+			meta := frame.Meta.Custom.(map[string]interface{})
+			assert.Equal(t, float64(i), meta["chunk"])
+			assert.Equal(t, i == 2, meta["done"])
+		}
+		assert.Empty(t, packets.frames[0].Meta.Notices)
+		require.Len(t, packets.frames[2].Meta.Notices, 1)
+		assert.Contains(t, packets.frames[2].Meta.Notices[0].Text, "limited to 22 rows")
+	})
+
+	t.Run("should end with an empty chunk if the rows fill the last chunk", func(t *testing.T) {
+		model, err := json.Marshal(map[string]interface{}{"refId": "A", "rawSql": countQuery, "format": "table", "chunkSize": 11, "rowLimit": 22})
+		require.NoError(t, err)
+		packets, err := runStream(t, string(model))
+		require.NoError(t, err)
+
+		require.Len(t, packets.frames, 3)
+		assert.Equal(t, 0, packets.frames[2].Rows())
+		assert.Len(t, packets.frames[2].Fields, 2)
+	})
+
+	t.Run("should reject time series", func(t *testing.T) {
+		_, err := runStream(t, `{"rawSql": "SELECT 1", "format": "time_series"}`)
+		require.EqualError(t, err, `chunked results are not supported for the "time_series" format`)
+	})
+
+	t.Run("should only subscribe to queries", func(t *testing.T) {
+		resp, err := handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "query/A", Data: json.RawMessage(`{"rawSql": "SELECT 1"}`)})
+		require.NoError(t, err)
+		assert.Equal(t, backend.SubscribeStreamStatusOK, resp.Status)
+
+		resp, err = handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "tail/A", Data: json.RawMessage(`{"rawSql": "SELECT 1"}`)})
+		require.Error(t, err)
+		assert.Equal(t, backend.SubscribeStreamStatusNotFound, resp.Status)
+	})
+}
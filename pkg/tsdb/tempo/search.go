@@ -0,0 +1,192 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// defaultSearchLimit is the maximum number of traces returned by searches without a limit, same as in the browser.
+const defaultSearchLimit = 20
+
+// searchResponse is the response of the search API of Tempo.
+type searchResponse struct {
+	Traces []traceSearchMetadata `json:"traces"`
+}
+
+type traceSearchMetadata struct {
+	TraceID         string `json:"traceID"`
+	RootServiceName string `json:"rootServiceName"`
+	RootTraceName   string `json:"rootTraceName"`
+	// StartTimeUnixNano is a string because it does not fit into a JSON number.
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	DurationMs        int64  `json:"durationMs"`
+}
+
+func (s *Service) querySearch(ctx context.Context, dsInfo *datasourceInfo, model *QueryModel, timeRange backend.TimeRange) (*data.Frame, error) {
+	params, err := searchParams(model, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", dsInfo.URL+"/api/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	s.tlog.Debug("Tempo search request", "url", request.URL.String())
+
+	body, resp, err := s.doRequest(dsInfo, request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to search traces Status: %s Body: %s", resp.Status, string(body))
+	}
+
+	var result searchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tempo search response: %w", err)
+	}
+	return searchResponseToFrame(result)
+}
+
+// searchParams returns the query parameters of the search API for tag based and TraceQL queries.
+func searchParams(model *QueryModel, timeRange backend.TimeRange) (url.Values, error) {
+	params := url.Values{}
+
+	if model.QueryType == queryTypeTraceQL {
+		if strings.TrimSpace(model.TraceID) == "" {
+			return nil, fmt.Errorf("TraceQL query is empty")
+		}
+		params.Set("q", model.TraceID)
+	} else {
+		tags := model.Search
+		if model.ServiceName != "" {
+			tags += fmt.Sprintf(" service.name=%q", model.ServiceName)
+		}
+		if model.SpanName != "" {
+			tags += fmt.Sprintf(" name=%q", model.SpanName)
+		}
+		if tags = strings.TrimSpace(tags); tags != "" {
+			params.Set("tags", tags)
+		}
+	}
+
+	for _, d := range []struct{ name, value string }{{"minDuration", model.MinDuration}, {"maxDuration", model.MaxDuration}} {
+		if d.value == "" {
+			continue
+		}
+		// Tempo accepts Go durations, the browser removes whitespace as well
+		value := strings.Join(strings.Fields(d.value), "")
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", d.name, value, err)
+		}
+		params.Set(d.name, value)
+	}
+
+	limit := model.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d: must be positive", limit)
+	}
+	params.Set("limit", strconv.FormatInt(limit, 10))
+
+	if !timeRange.From.IsZero() && !timeRange.To.IsZero() {
+		params.Set("start", strconv.FormatInt(timeRange.From.Unix(), 10))
+		params.Set("end", strconv.FormatInt(timeRange.To.Unix(), 10))
+	}
+	return params, nil
+}
+
+// searchResponseToFrame returns a table frame with one row per matching trace, the most recent trace first.
+func searchResponseToFrame(result searchResponse) (*data.Frame, error) {
+	type trace struct {
+		traceSearchMetadata
+		start time.Time
+	}
+	traces := make([]trace, 0, len(result.Traces))
+	for _, t := range result.Traces {
+		nanos, err := strconv.ParseInt(t.StartTimeUnixNano, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time %q of trace %s: %w", t.StartTimeUnixNano, t.TraceID, err)
+		}
+		traces = append(traces, trace{traceSearchMetadata: t, start: time.Unix(0, nanos).UTC()})
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].start.After(traces[j].start)
+	})
+
+	traceIDs := make([]string, 0, len(traces))
+	names := make([]string, 0, len(traces))
+	starts := make([]time.Time, 0, len(traces))
+	durations := make([]int64, 0, len(traces))
+	for _, t := range traces {
+		traceIDs = append(traceIDs, t.TraceID)
+		names = append(names, strings.TrimSpace(t.RootServiceName+" "+t.RootTraceName))
+		starts = append(starts, t.start)
+		durations = append(durations, t.DurationMs)
+	}
+
+	frame := data.NewFrame("Traces",
+		data.NewField("traceID", nil, traceIDs).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Trace ID"}),
+		data.NewField("traceName", nil, names).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Trace name"}),
+		data.NewField("startTime", nil, starts).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Start time"}),
+		data.NewField("duration", nil, durations).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms"}),
+	)
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return frame, nil
+}
+
+// CallResource proxies the lookups of tag names and tag values to the search API of Tempo:
+// `tags` returns the names of all tags and `tag/<name>/values` the values of a tag.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	if req.Method != http.MethodGet {
+		return fmt.Errorf("invalid resource method: %s", req.Method)
+	}
+	if !isTagResource(strings.SplitN(req.URL, "?", 2)[0]) {
+		return fmt.Errorf("invalid resource URL: %s", req.URL)
+	}
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", dsInfo.URL+"/api/search/"+req.URL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	body, resp, err := s.doRequest(dsInfo, request)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status: resp.StatusCode,
+		Headers: map[string][]string{
+			"content-type": {resp.Header.Get("Content-Type")},
+		},
+		Body: body,
+	})
+}
+
+func isTagResource(path string) bool {
+	if path == "tags" {
+		return true
+	}
+	parts := strings.Split(path, "/")
+	return len(parts) == 3 && parts[0] == "tag" && parts[1] != "" && parts[1] != "." && parts[1] != ".." && parts[2] == "values"
+}
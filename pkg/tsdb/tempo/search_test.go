@@ -0,0 +1,183 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const searchResponseJSON = `{"traces": [
+	{"traceID": "2f3e0cee77ae5dc9c17ade3689eb2e54", "rootServiceName": "shop-backend", "rootTraceName": "update-billing", "startTimeUnixNano": "1647000000000000000", "durationMs": 640},
+	{"traceID": "1b4b1d9c8e3f5a27", "rootServiceName": "shop-backend", "rootTraceName": "checkout", "startTimeUnixNano": "1647000060000000000", "durationMs": 1250}
+]}`
+
+func setupTempoService(t *testing.T, handler http.HandlerFunc) (*Service, backend.PluginContext) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	service := &Service{
+		tlog: log.New("tempo-test"),
+		im: datasource.NewInstanceManager(func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+			return &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil
+		}),
+	}
+	return service, backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1}}
+}
+
+func TestQueryData(t *testing.T) {
+	traceProto, err := ioutil.ReadFile("testData/tempo_proto_response")
+	require.NoError(t, err)
+
+	var searches []*http.Request
+	service, pluginCtx := setupTempoService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/search":
+			searches = append(searches, r)
+			_, _ = w.Write([]byte(searchResponseJSON))
+		case "/api/traces/abc":
+			_, _ = w.Write(traceProto)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	from := time.Date(2022, 3, 11, 12, 0, 0, 0, time.UTC)
+	timeRange := backend.TimeRange{From: from, To: from.Add(time.Hour)}
+	resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: pluginCtx,
+		Queries: []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"query": "abc"}`)},
+			{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"queryType": "nativeSearch", "serviceName": "shop-backend", "search": "http.status_code=500", "minDuration": "1 s", "limit": 5}`)},
+			{RefID: "C", TimeRange: timeRange, JSON: []byte(`{"queryType": "traceql", "query": "{ .http.status_code = 500 }"}`)},
+			{RefID: "D", JSON: []byte(`{"queryType": "nativeSearch", "maxDuration": "soon"}`)},
+			{RefID: "E", JSON: []byte(`{"query": "missing"}`)},
+			{RefID: "F", JSON: []byte(`{"queryType": "serviceMap"}`)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 6)
+
+	t.Run("should fetch traces by ID", func(t *testing.T) {
+		require.NoError(t, resp.Responses["A"].Error)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		assert.Equal(t, "A", resp.Responses["A"].Frames[0].RefID)
+		assert.Equal(t, "Trace", resp.Responses["A"].Frames[0].Name)
+	})
+
+	t.Run("should search traces by tags", func(t *testing.T) {
+		require.NoError(t, resp.Responses["B"].Error)
+		frame := resp.Responses["B"].Frames[0]
+		assert.Equal(t, "B", frame.RefID)
+		assert.Equal(t, 2, frame.Rows())
+
+		require.Len(t, searches, 2)
+		params := searches[0].URL.Query()
+		assert.Equal(t, `http.status_code=500 service.name="shop-backend"`, params.Get("tags"))
+		assert.Equal(t, "1s", params.Get("minDuration"))
+		assert.Equal(t, "5", params.Get("limit"))
+		assert.Equal(t, "1647000000", params.Get("start"))
+		assert.Equal(t, "1647003600", params.Get("end"))
+	})
+
+	t.Run("should search traces with TraceQL", func(t *testing.T) {
+		require.NoError(t, resp.Responses["C"].Error)
+		params := searches[1].URL.Query()
+		assert.Equal(t, "{ .http.status_code = 500 }", params.Get("q"))
+		assert.Equal(t, "20", params.Get("limit"))
+		assert.Empty(t, params.Get("tags"))
+	})
+
+	t.Run("should return errors per query", func(t *testing.T) {
+		assert.EqualError(t, resp.Responses["D"].Error, `invalid maxDuration "soon": time: invalid duration "soon"`)
+		assert.Contains(t, resp.Responses["E"].Error.Error(), "failed to get trace with id: missing")
+		assert.EqualError(t, resp.Responses["F"].Error, "unsupported query type: serviceMap")
+	})
+}
+
+func TestSearchResponseToFrame(t *testing.T) {
+	var result searchResponse
+	require.NoError(t, json.Unmarshal([]byte(searchResponseJSON), &result))
+
+	frame, err := searchResponseToFrame(result)
+	require.NoError(t, err)
+	assert.Equal(t, data.VisTypeTable, string(frame.Meta.PreferredVisualization))
+	require.Equal(t, 2, frame.Rows())
+
+	// the most recent trace comes first
+	assert.Equal(t, []interface{}{"1b4b1d9c8e3f5a27", "shop-backend checkout", time.Unix(1647000060, 0).UTC(), int64(1250)}, frame.RowCopy(0))
+	assert.Equal(t, []interface{}{"2f3e0cee77ae5dc9c17ade3689eb2e54", "shop-backend update-billing", time.Unix(1647000000, 0).UTC(), int64(640)}, frame.RowCopy(1))
+	assert.Equal(t, "ms", frame.Fields[3].Config.Unit)
+
+	t.Run("should fail on invalid start times", func(t *testing.T) {
+		_, err := searchResponseToFrame(searchResponse{Traces: []traceSearchMetadata{{TraceID: "abc", StartTimeUnixNano: "yesterday"}}})
+		require.Error(t, err)
+	})
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	service, pluginCtx := setupTempoService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/search/tags":
+			_, _ = w.Write([]byte(`{"tagNames": ["http.method", "service.name"]}`))
+		case "/api/search/tag/service.name/values":
+			_, _ = w.Write([]byte(`{"tagValues": ["shop-backend"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	callResource := func(t *testing.T, url string) (*backend.CallResourceResponse, error) {
+		t.Helper()
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Method:        http.MethodGet,
+			URL:           url,
+		}, sender)
+		return sender.resp, err
+	}
+
+	t.Run("should return tag names", func(t *testing.T) {
+		resp, err := callResource(t, "tags")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `{"tagNames": ["http.method", "service.name"]}`, string(resp.Body))
+	})
+
+	t.Run("should return tag values", func(t *testing.T) {
+		resp, err := callResource(t, "tag/service.name/values")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `{"tagValues": ["shop-backend"]}`, string(resp.Body))
+	})
+
+	t.Run("should reject other resources", func(t *testing.T) {
+		for _, url := range []string{"search", "tag/../../traces/abc/values", "tag/service.name"} {
+			_, err := callResource(t, url)
+			require.Error(t, err, url)
+		}
+	})
+}
@@ -16,6 +16,11 @@ import (
 	"go.opentelemetry.io/collector/model/otlp"
 )
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+)
+
 type Service struct {
 	im   instancemgmt.InstanceManager
 	tlog log.Logger
@@ -33,8 +38,27 @@ type datasourceInfo struct {
 	URL        string
 }
 
+const (
+	// queryTypeTraceID fetches a single trace by its ID. It is the default query type.
+	queryTypeTraceID = "traceId"
+	// queryTypeSearch searches traces by tags, duration and time range.
+	queryTypeSearch = "nativeSearch"
+	// queryTypeTraceQL searches traces with a TraceQL expression.
+	queryTypeTraceQL = "traceql"
+)
+
 type QueryModel struct {
+	QueryType string `json:"queryType"`
+	// TraceID is the ID of the trace to fetch, or the TraceQL expression of TraceQL queries.
 	TraceID string `json:"query"`
+
+	// Search is a logfmt encoded list of tags the traces have to match.
+	Search      string `json:"search"`
+	ServiceName string `json:"serviceName"`
+	SpanName    string `json:"spanName"`
+	MinDuration string `json:"minDuration"`
+	MaxDuration string `json:"maxDuration"`
+	Limit       int64  `json:"limit"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -59,28 +83,69 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
-	queryRes := backend.DataResponse{}
-	refID := req.Queries[0].RefID
 
-	model := &QueryModel{}
-	err := json.Unmarshal(req.Queries[0].JSON, model)
+	dsInfo, err := s.getDSInfo(req.PluginContext)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
 
-	dsInfo, err := s.getDSInfo(req.PluginContext)
+	for _, q := range req.Queries {
+		model := &QueryModel{}
+		if err := json.Unmarshal(q.JSON, model); err != nil {
+			result.Responses[q.RefID] = backend.DataResponse{Error: fmt.Errorf("failed to unmarshal query: %w", err)}
+			continue
+		}
+
+		var frame *data.Frame
+		switch model.QueryType {
+		case "", queryTypeTraceID:
+			frame, err = s.queryTrace(ctx, dsInfo, model.TraceID)
+		case queryTypeSearch, queryTypeTraceQL:
+			frame, err = s.querySearch(ctx, dsInfo, model, q.TimeRange)
+		default:
+			err = fmt.Errorf("unsupported query type: %s", model.QueryType)
+		}
+		if err != nil {
+			result.Responses[q.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+		frame.RefID = q.RefID
+		result.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{frame}}
+	}
+	return result, nil
+}
+
+func (s *Service) queryTrace(ctx context.Context, dsInfo *datasourceInfo, traceID string) (*data.Frame, error) {
+	request, err := s.createRequest(ctx, dsInfo, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, resp, err := s.doRequest(dsInfo, request)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", traceID, resp.Status, string(body))
+	}
 
-	request, err := s.createRequest(ctx, dsInfo, model.TraceID)
+	otTrace, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(body)
 	if err != nil {
-		return result, err
+		return nil, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
 	}
 
+	frame, err := TraceToFrame(otTrace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform trace %v to data frame: %w", traceID, err)
+	}
+	return frame, nil
+}
+
+// doRequest sends the request to Tempo and returns the response together with its body, which is already closed.
+func (s *Service) doRequest(dsInfo *datasourceInfo, request *http.Request) ([]byte, *http.Response, error) {
 	resp, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return result, fmt.Errorf("failed get to tempo: %w", err)
+		return nil, nil, fmt.Errorf("failed get to tempo: %w", err)
 	}
 
 	defer func() {
@@ -91,30 +156,9 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", model.TraceID, resp.Status, string(body))
-		result.Responses[refID] = queryRes
-		return result, nil
+		return nil, nil, err
 	}
-
-	otTrace, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(body)
-
-	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
-	}
-
-	frame, err := TraceToFrame(otTrace)
-	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to transform trace %v to data frame: %w", model.TraceID, err)
-	}
-	frame.RefID = refID
-	frames := []*data.Frame{frame}
-	queryRes.Frames = frames
-	result.Responses[refID] = queryRes
-	return result, nil
+	return body, resp, nil
 }
 
 func (s *Service) createRequest(ctx context.Context, dsInfo *datasourceInfo, traceID string) (*http.Request, error) {
@@ -54,12 +54,19 @@ export const downsamplingTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.max, label: 'Max', description: 'Fill with the maximum value' },
   { value: ReducerID.mean, label: 'Mean', description: 'Fill with the average value' },
   { value: ReducerID.sum, label: 'Sum', description: 'Fill with the sum of all values' },
+  { value: ReducerID.first, label: 'First', description: 'Fill with the first value' },
+  { value: ReducerID.last, label: 'Last', description: 'Fill with the last value' },
+  { value: ReducerID.count, label: 'Count', description: 'Fill with the number of values' },
+  { value: 'median', label: 'Median', description: 'Fill with the median value' },
+  { value: 'p95', label: 'p95', description: 'Fill with the 95th percentile value' },
+  { value: 'p99', label: 'p99', description: 'Fill with the 99th percentile value' },
 ];
 
 export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'Fill by interpolating between the last and next known values' },
 ];
 
 /**
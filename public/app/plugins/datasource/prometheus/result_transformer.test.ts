@@ -1,4 +1,11 @@
-import { DataFrame, FieldType, DataQueryRequest, DataQueryResponse, MutableDataFrame } from '@grafana/data';
+import {
+  DataFrame,
+  DataFrameType,
+  FieldType,
+  DataQueryRequest,
+  DataQueryResponse,
+  MutableDataFrame,
+} from '@grafana/data';
 
 import { transform, transformV2, transformDFToTable } from './result_transformer';
 import { PromQuery } from './types';
@@ -253,6 +260,39 @@ describe('Prometheus Result Transformer', () => {
       expect(series.data[0].fields[3].values.toArray()).toEqual([10, 0, 10]);
     });
 
+    it('results with heatmap format converted by the backend should not be transformed again', () => {
+      const options = {
+        targets: [
+          {
+            format: 'heatmap',
+            refId: 'A',
+          },
+        ],
+      } as unknown as DataQueryRequest<PromQuery>;
+      const response = {
+        state: 'Done',
+        data: [
+          new MutableDataFrame({
+            refId: 'A',
+            meta: {
+              type: DataFrameType.HeatmapBuckets,
+            },
+            fields: [
+              { name: 'Time', type: FieldType.time, values: [4, 5, 6] },
+              { name: '1', type: FieldType.number, values: [0, 10, 10] },
+              { name: '2', type: FieldType.number, values: [30, 0, 10] },
+            ],
+          }),
+        ],
+      } as unknown as DataQueryResponse;
+
+      const series = transformV2(response, options, {});
+      expect(series.data.length).toEqual(1);
+      expect(series.data[0].meta?.type).toEqual(DataFrameType.HeatmapBuckets);
+      expect(series.data[0].fields[1].values.toArray()).toEqual([0, 10, 10]);
+      expect(series.data[0].fields[2].values.toArray()).toEqual([30, 0, 10]);
+    });
+
     it('Retains exemplar frames when data returned is a heatmap', () => {
       const options = {
         targets: [
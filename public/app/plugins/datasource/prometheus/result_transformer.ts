@@ -62,7 +62,8 @@ const isTableResult = (dataFrame: DataFrame, options: DataQueryRequest<PromQuery
 
 const isHeatmapResult = (dataFrame: DataFrame, options: DataQueryRequest<PromQuery>): boolean => {
   const target = options.targets.find((target) => target.refId === dataFrame.refId);
-  return target?.format === 'heatmap';
+  // Frames of histograms converted by the backend already have a field per bucket
+  return target?.format === 'heatmap' && dataFrame.meta?.type !== DataFrameType.HeatmapBuckets;
 };
 
 // V2 result trasnformer used to transform query results from queries that were run trough prometheus backend
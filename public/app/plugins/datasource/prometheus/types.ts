@@ -8,6 +8,10 @@ export interface PromQuery extends DataQuery {
   instant?: boolean;
   range?: boolean;
   exemplar?: boolean;
+  /** Sampling of the exemplars: none, all, deviation or topN, deviation if not set */
+  exemplarSampling?: 'none' | 'all' | 'deviation' | 'topN';
+  /** Number of exemplars per step of the topN sampling */
+  exemplarLimit?: number;
   hinting?: boolean;
   interval?: string;
   intervalFactor?: number;